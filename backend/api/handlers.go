@@ -1,10 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -20,6 +24,15 @@ import (
 	"github.com/browserwing/browserwing/models"
 	"github.com/browserwing/browserwing/pkg/logger"
 	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/services/bundle"
+	"github.com/browserwing/browserwing/services/codegen"
+	"github.com/browserwing/browserwing/services/datarun"
+	"github.com/browserwing/browserwing/services/importer"
+	"github.com/browserwing/browserwing/services/params"
+	"github.com/browserwing/browserwing/services/runqueue"
+	"github.com/browserwing/browserwing/services/secrets"
+	"github.com/browserwing/browserwing/services/sinks"
+	"github.com/browserwing/browserwing/services/validator"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/go-rod/rod/lib/proto"
@@ -42,9 +55,11 @@ type Handler struct {
 	executor       *executor2.Executor // Executor 实例
 	config         *config.Config
 	llmManager     *llm.Manager
-	mcpServer      MCPHTTPHandler // MCP 服务器（使用 interface{} 避免循环依赖）
-	agentManager   interface{}    // Agent 管理器（用于 LLM 配置更新后的热加载）
-	scheduler      interface{}    // 定时任务调度器
+	mcpServer      MCPHTTPHandler   // MCP 服务器（使用 interface{} 避免循环依赖）
+	agentManager   interface{}      // Agent 管理器（用于 LLM 配置更新后的热加载）
+	scheduler      interface{}      // 定时任务调度器
+	dataRuns       *datarun.Runner  // 数据驱动运行调度器
+	secrets        *secrets.Manager // 密钥管理器
 }
 
 func NewHandler(
@@ -53,7 +68,7 @@ func NewHandler(
 	cfg *config.Config,
 	llmMgr *llm.Manager,
 ) *Handler {
-	return &Handler{
+	h := &Handler{
 		db:             db,
 		browserManager: browserMgr,
 		executor:       executor2.NewExecutor(browserMgr), // 初始化 Executor
@@ -61,6 +76,8 @@ func NewHandler(
 		llmManager:     llmMgr,
 		mcpServer:      nil, // 将在主程序中设置
 	}
+	h.dataRuns = datarun.NewRunner(db, h.playDataRow)
+	return h
 }
 
 // ============= 浏览器控制相关 API =============
@@ -463,18 +480,20 @@ func (h *Handler) ClearInPageRecordingState(c *gin.Context) {
 // SaveScript 保存脚本
 func (h *Handler) SaveScript(c *gin.Context) {
 	var req struct {
-		ID                    string                  `json:"id"` // 可选，更新时使用
-		Name                  string                  `json:"name" binding:"required"`
-		Description           string                  `json:"description"`
-		URL                   string                  `json:"url" binding:"required"`
-		Actions               []models.ScriptAction   `json:"actions" binding:"required"`
-		DownloadedFiles       []models.DownloadedFile `json:"downloaded_files"` // 下载的文件列表
-		Tags                  []string                `json:"tags"`
-		IsMCPCommand          *bool                   `json:"is_mcp_command"`
-		MCPCommandName        string                  `json:"mcp_command_name"`
-		MCPCommandDescription string                  `json:"mcp_command_description"`
-		MCPInputSchema        map[string]interface{}  `json:"mcp_input_schema"`
-		Variables             map[string]string       `json:"variables"`
+		ID                    string                   `json:"id"` // 可选，更新时使用
+		Name                  string                   `json:"name" binding:"required"`
+		Description           string                   `json:"description"`
+		URL                   string                   `json:"url" binding:"required"`
+		Actions               []models.ScriptAction    `json:"actions" binding:"required"`
+		DownloadedFiles       []models.DownloadedFile  `json:"downloaded_files"` // 下载的文件列表
+		Tags                  []string                 `json:"tags"`
+		IsMCPCommand          *bool                    `json:"is_mcp_command"`
+		MCPCommandName        string                   `json:"mcp_command_name"`
+		MCPCommandDescription string                   `json:"mcp_command_description"`
+		MCPInputSchema        map[string]interface{}   `json:"mcp_input_schema"`
+		Variables             map[string]string        `json:"variables"`
+		Parameters            []models.ScriptParameter `json:"parameters"`
+		OutputSinks           []models.OutputSink      `json:"output_sinks"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -505,6 +524,8 @@ func (h *Handler) SaveScript(c *gin.Context) {
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 		Variables:       req.Variables,
+		Parameters:      req.Parameters,
+		OutputSinks:     req.OutputSinks,
 	}
 
 	// 如果提供了 MCP 相关字段，则设置
@@ -520,8 +541,18 @@ func (h *Handler) SaveScript(c *gin.Context) {
 	if req.MCPInputSchema != nil {
 		script.MCPInputSchema = req.MCPInputSchema
 	}
+	if !applyParameterDefinitions(c, script) || !validateOutputSinks(c, script.OutputSinks) {
+		return
+	}
+
+	// 静态校验：strict=true 时存在错误则拒绝保存，否则随结果返回
+	report := validator.Validate(script)
+	if !report.Valid && c.Query("strict") == "true" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.scriptValidationFailed", "validation": report})
+		return
+	}
 
-	if err := h.db.SaveScript(script); err != nil {
+	if err := h.db.SaveScriptWithRevision(script, requestAuthor(c), ""); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveScriptFailed"})
 		return
 	}
@@ -530,8 +561,9 @@ func (h *Handler) SaveScript(c *gin.Context) {
 	h.syncMCPRegistration(c, script)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "success.scriptSaved",
-		"script":  script,
+		"message":    "success.scriptSaved",
+		"script":     script,
+		"validation": report,
 	})
 }
 
@@ -634,16 +666,19 @@ func (h *Handler) UpdateScript(c *gin.Context) {
 	}
 
 	var req struct {
-		Name                  string                 `json:"name"`
-		Description           string                 `json:"description"`
-		URL                   string                 `json:"url"`
-		Actions               []models.ScriptAction  `json:"actions"`
-		Tags                  []string               `json:"tags"`
-		IsMCPCommand          *bool                  `json:"is_mcp_command"`
-		MCPCommandName        *string                `json:"mcp_command_name"`
-		MCPCommandDescription *string                `json:"mcp_command_description"`
-		MCPInputSchema        map[string]interface{} `json:"mcp_input_schema"`
-		Variables             map[string]string      `json:"variables"`
+		Name                  string                   `json:"name"`
+		Description           string                   `json:"description"`
+		URL                   string                   `json:"url"`
+		Actions               []models.ScriptAction    `json:"actions"`
+		Tags                  []string                 `json:"tags"`
+		IsMCPCommand          *bool                    `json:"is_mcp_command"`
+		MCPCommandName        *string                  `json:"mcp_command_name"`
+		MCPCommandDescription *string                  `json:"mcp_command_description"`
+		MCPInputSchema        map[string]interface{}   `json:"mcp_input_schema"`
+		Variables             map[string]string        `json:"variables"`
+		Parameters            []models.ScriptParameter `json:"parameters"`
+		OutputSinks           []models.OutputSink      `json:"output_sinks"`
+		Summary               string                   `json:"summary"` // 变更摘要（可选，为空时自动生成）
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -671,6 +706,12 @@ func (h *Handler) UpdateScript(c *gin.Context) {
 	if req.Variables != nil {
 		script.Variables = req.Variables
 	}
+	if req.Parameters != nil {
+		script.Parameters = req.Parameters
+	}
+	if req.OutputSinks != nil {
+		script.OutputSinks = req.OutputSinks
+	}
 	if req.Tags != nil {
 		script.Tags = req.Tags
 	}
@@ -688,8 +729,18 @@ func (h *Handler) UpdateScript(c *gin.Context) {
 	if req.MCPInputSchema != nil {
 		script.MCPInputSchema = req.MCPInputSchema
 	}
+	if !applyParameterDefinitions(c, script) || !validateOutputSinks(c, script.OutputSinks) {
+		return
+	}
 
-	if err := h.db.UpdateScript(script); err != nil {
+	// 静态校验：strict=true 时存在错误则拒绝保存，否则随结果返回
+	report := validator.Validate(script)
+	if !report.Valid && c.Query("strict") == "true" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.scriptValidationFailed", "validation": report})
+		return
+	}
+
+	if err := h.db.UpdateScriptWithRevision(script, requestAuthor(c), req.Summary); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.updateScriptFailed"})
 		return
 	}
@@ -698,8 +749,9 @@ func (h *Handler) UpdateScript(c *gin.Context) {
 	h.syncMCPRegistration(c, script)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "success.scriptUpdated",
-		"script":  script,
+		"message":    "success.scriptUpdated",
+		"script":     script,
+		"validation": report,
 	})
 }
 
@@ -720,6 +772,149 @@ func (h *Handler) DeleteScript(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "success.scriptDeleted"})
 }
 
+// ListScriptRevisions 列出脚本历史版本
+func (h *Handler) ListScriptRevisions(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := h.db.GetScript(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	revisions, err := h.db.ListScriptRevisions(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.listScriptRevisionsFailed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+		"total":     len(revisions),
+	})
+}
+
+// GetScriptRevision 获取脚本的指定历史版本
+func (h *Handler) GetScriptRevision(c *gin.Context) {
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	result, err := h.db.GetScriptRevision(c.Param("id"), revision)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptRevisionNotFound"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DiffScriptRevisions 逐个操作比较两个历史版本
+// 默认 to 为最新版本，from 为 to 的上一个版本（为 0 时与空脚本比较）
+func (h *Handler) DiffScriptRevisions(c *gin.Context) {
+	id := c.Param("id")
+
+	script, err := h.db.GetScript(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	to := script.Revision
+	if s := c.Query("to"); s != "" {
+		if to, err = strconv.Atoi(s); err != nil || to <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+			return
+		}
+	}
+	from := to - 1
+	if s := c.Query("from"); s != "" {
+		if from, err = strconv.Atoi(s); err != nil || from < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+			return
+		}
+	}
+
+	load := func(revision int) (*models.Script, bool) {
+		if revision <= 0 {
+			return nil, true
+		}
+		result, err := h.db.GetScriptRevision(id, revision)
+		if err != nil {
+			return nil, false
+		}
+		return result.Script, true
+	}
+	oldScript, ok := load(from)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptRevisionNotFound"})
+		return
+	}
+	newScript, ok := load(to)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptRevisionNotFound"})
+		return
+	}
+
+	diff := models.DiffScripts(oldScript, newScript)
+	diff.FromRevision = from
+	diff.ToRevision = to
+	c.JSON(http.StatusOK, gin.H{
+		"diff":    diff,
+		"summary": diff.Summary(),
+	})
+}
+
+// RestoreScriptRevision 将脚本恢复到指定历史版本（恢复结果作为新版本追加）
+func (h *Handler) RestoreScriptRevision(c *gin.Context) {
+	id := c.Param("id")
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	current, err := h.db.GetScript(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+	result, err := h.db.GetScriptRevision(id, revision)
+	if err != nil || result.Script == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptRevisionNotFound"})
+		return
+	}
+
+	script := result.Script
+	script.ID = current.ID
+	script.CreatedAt = current.CreatedAt
+	summary := fmt.Sprintf("Restored from revision %d", revision)
+	if err := h.db.UpdateScriptWithRevision(script, requestAuthor(c), summary); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.restoreScriptRevisionFailed"})
+		return
+	}
+
+	// 同步 MCP 注册状态
+	h.syncMCPRegistration(c, script)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "success.scriptRevisionRestored",
+		"script":  script,
+	})
+}
+
+// requestAuthor 获取当前请求的操作人（用户名，API Key 认证时为 api_key:<id>）
+func requestAuthor(c *gin.Context) string {
+	if username := c.GetString("username"); username != "" {
+		return username
+	}
+	if keyID := c.GetString("api_key_id"); keyID != "" {
+		return "api_key:" + keyID
+	}
+	return ""
+}
+
 // PlayScript 回放脚本
 func (h *Handler) PlayScript(c *gin.Context) {
 	id := c.Param("id")
@@ -730,8 +925,9 @@ func (h *Handler) PlayScript(c *gin.Context) {
 
 	// 解析请求体中的参数
 	var req struct {
-		Params     map[string]string `json:"params"`
-		InstanceID string            `json:"instance_id"` // 指定实例ID，空字符串表示使用当前实例
+		Params      map[string]string `json:"params"`
+		InstanceID  string            `json:"instance_id"`  // 指定实例ID，空字符串表示使用当前实例
+		ExecutionID string            `json:"execution_id"` // 预先指定执行ID（可用于在回放过程中取消），为空时自动生成
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		// 如果没有请求体或解析失败,使用空参数
@@ -761,61 +957,31 @@ func (h *Handler) PlayScript(c *gin.Context) {
 	// 创建脚本副本并合并参数
 	scriptToRun := script.Copy()
 
-	// 合并参数：先使用脚本预设变量，再用外部传入的参数覆盖
-	mergedParams := make(map[string]string)
-
-	// 1. 首先添加脚本的预设变量
-	if scriptToRun.Variables != nil {
-		for key, value := range scriptToRun.Variables {
-			mergedParams[key] = value
-		}
-		for key := range scriptToRun.Variables {
-			if _, ok := req.Params[key]; ok {
-				scriptToRun.Variables[key] = req.Params[key]
-			}
-		}
+	// 合并参数：先使用脚本预设变量，再用外部传入的参数覆盖（按参数定义校验）
+	// 占位符由 Player 在执行时统一解析（可引用回放过程中抓取的数据）
+	if !resolveScriptParams(c, scriptToRun, req.Params) {
+		return
 	}
 
-	// 2. 外部传入的参数会覆盖预设变量
-	for key, value := range req.Params {
-		mergedParams[key] = value
+	// 如果用户提供了 url 参数，直接作为起始 URL
+	if urlParam, ok := req.Params["url"]; ok && urlParam != "" {
+		scriptToRun.URL = urlParam
 	}
 
-	// 如果有参数（包括预设变量和外部参数），替换占位符
-	if len(mergedParams) > 0 {
-
-		// 如果用户提供了 url 参数,使用它;否则替换 URL 中的占位符
-		if urlParam, ok := mergedParams["url"]; ok && urlParam != "" {
-			scriptToRun.URL = urlParam
-		} else {
-			scriptToRun.URL = replacePlaceholders(scriptToRun.URL, mergedParams)
-		}
-
-		// 复制 actions 数组以避免修改原始数据
-		scriptToRun.Actions = make([]models.ScriptAction, len(script.Actions))
-		copy(scriptToRun.Actions, script.Actions)
-
-		// 替换所有 action 中的占位符
-		for i := range scriptToRun.Actions {
-			scriptToRun.Actions[i].Selector = replacePlaceholders(scriptToRun.Actions[i].Selector, mergedParams)
-			scriptToRun.Actions[i].XPath = replacePlaceholders(scriptToRun.Actions[i].XPath, mergedParams)
-			scriptToRun.Actions[i].Value = replacePlaceholders(scriptToRun.Actions[i].Value, mergedParams)
-			scriptToRun.Actions[i].URL = replacePlaceholders(scriptToRun.Actions[i].URL, mergedParams)
-			scriptToRun.Actions[i].JSCode = replacePlaceholders(scriptToRun.Actions[i].JSCode, mergedParams)
-
-			// 替换文件路径中的占位符
-			if len(scriptToRun.Actions[i].FilePaths) > 0 {
-				newFilePaths := make([]string, len(scriptToRun.Actions[i].FilePaths))
-				for j, path := range scriptToRun.Actions[i].FilePaths {
-					newFilePaths[j] = replacePlaceholders(path, mergedParams)
-				}
-				scriptToRun.Actions[i].FilePaths = newFilePaths
-			}
-		}
+	// 执行回放（请求断开或调用取消接口时中止回放）
+	ctx := c.Request.Context()
+	if req.ExecutionID != "" {
+		ctx = browser.WithExecutionID(ctx, req.ExecutionID)
+	}
+	result, page, err := h.browserManager.PlayScript(ctx, scriptToRun, req.InstanceID)
+	if errors.Is(err, browser.ErrExecutionCancelled) {
+		logger.Warn(c.Request.Context(), "Script playback cancelled: %v", err)
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "error.scriptExecutionCancelled",
+			"result": result,
+		})
+		return
 	}
-
-	// 执行回放
-	result, page, err := h.browserManager.PlayScript(c.Request.Context(), scriptToRun, req.InstanceID)
 	if err != nil {
 		logger.Error(c.Request.Context(), "Failed to play script: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -863,6 +1029,364 @@ func (h *Handler) GetPlayResult(c *gin.Context) {
 	})
 }
 
+// ============= 数据驱动运行相关处理器 =============
+
+// StartDataRun 上传 CSV/JSONL/JSON 数据集，对每一行执行一次脚本（行的列映射为脚本变量）
+// 参数：format、concurrency、on_row_error（continue/stop）、retry_count、instance_id，可通过查询参数或表单字段传入
+func (h *Handler) StartDataRun(c *gin.Context) {
+	script, err := h.db.GetScript(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	param := func(name string) string {
+		return c.DefaultQuery(name, c.PostForm(name))
+	}
+	opts := datarun.Options{
+		InstanceID: param("instance_id"),
+		OnRowError: models.OnErrorAction(param("on_row_error")),
+	}
+	if v := param("concurrency"); v != "" {
+		if opts.Concurrency, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+			return
+		}
+	}
+	if v := param("retry_count"); v != "" {
+		if opts.RetryCount, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+			return
+		}
+	}
+
+	var data []byte
+	if fileHeader, ferr := c.FormFile("file"); ferr == nil {
+		opts.DatasetName = fileHeader.Filename
+		f, err := fileHeader.Open()
+		if err == nil {
+			data, err = io.ReadAll(f)
+			f.Close()
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidDataset"})
+			return
+		}
+	} else if data, err = io.ReadAll(c.Request.Body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidDataset"})
+		return
+	}
+
+	format, err := datarun.ParseFormat(param("format"), opts.DatasetName, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	ds, err := datarun.Parse(data, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidDataset", "details": err.Error()})
+		return
+	}
+
+	// 检查浏览器是否运行
+	if !h.browserManager.IsInstanceRunning(opts.InstanceID) {
+		logger.Info(c, "Browser not running, starting...")
+		if err := h.browserManager.StartInstance(c, opts.InstanceID); err != nil {
+			logger.Error(c.Request.Context(), "Failed to start browser: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error.startDataRunFailed"})
+			return
+		}
+	}
+
+	run, err := h.dataRuns.Start(script, ds, opts)
+	if err != nil {
+		if errors.Is(err, datarun.ErrInvalidOptions) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.startDataRunFailed"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "success.dataRunStarted",
+		"run":     run,
+	})
+}
+
+// playDataRow 执行数据驱动运行中的一行，结束后关闭回放页面
+func (h *Handler) playDataRow(ctx context.Context, script *models.Script, instanceID, executionID string) (*models.PlayResult, error) {
+	ctx = runqueue.WithSource(browser.WithExecutionID(ctx, executionID), runqueue.SourceDataRun)
+	result, page, err := h.browserManager.PlayScript(ctx, script, instanceID)
+	if page != nil {
+		if closeErr := h.browserManager.CloseActivePage(ctx, page); closeErr != nil {
+			logger.Warn(ctx, "Failed to close page: %v", closeErr)
+		}
+	}
+	return result, err
+}
+
+// ListDataRuns 列出数据驱动运行记录（不含行明细，支持 script_id 过滤）
+func (h *Handler) ListDataRuns(c *gin.Context) {
+	runs, err := h.db.ListDataRuns(c.Query("script_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getDataRunsFailed"})
+		return
+	}
+
+	summaries := make([]models.DataRunSummary, len(runs))
+	for i, run := range runs {
+		summaries[i] = run.Summary()
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"runs":  summaries,
+		"total": len(summaries),
+	})
+}
+
+// GetDataRun 获取数据驱动运行记录（包含每行的状态和抓取数据）
+func (h *Handler) GetDataRun(c *gin.Context) {
+	run, err := h.db.GetDataRun(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.dataRunNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// ExportDataRun 下载数据驱动运行结果（format=csv 或 jsonl，默认 csv）
+func (h *Handler) ExportDataRun(c *gin.Context) {
+	run, err := h.db.GetDataRun(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.dataRunNotFound"})
+		return
+	}
+
+	var buf bytes.Buffer
+	var contentType string
+	format := c.DefaultQuery("format", "csv")
+	switch format {
+	case "csv":
+		contentType = "text/csv; charset=utf-8"
+		err = datarun.WriteCSV(&buf, run)
+	case "jsonl":
+		contentType = "application/x-ndjson"
+		err = datarun.WriteJSONL(&buf, run)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.exportDataRunFailed"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="data-run-%s.%s"`, run.ID, format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// CancelDataRun 取消正在执行的数据驱动运行
+func (h *Handler) CancelDataRun(c *gin.Context) {
+	if err := h.dataRuns.Cancel(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.dataRunNotRunning"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.dataRunCancelled"})
+}
+
+// DeleteDataRun 删除数据驱动运行记录（执行中的运行需先取消）
+func (h *Handler) DeleteDataRun(c *gin.Context) {
+	id := c.Param("id")
+	if h.dataRuns.IsActive(id) {
+		c.JSON(http.StatusConflict, gin.H{"error": "error.dataRunStillRunning"})
+		return
+	}
+	if err := h.db.DeleteDataRun(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteDataRunFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.dataRunDeleted"})
+}
+
+// ============= 数据集相关处理器 =============
+
+// ListDatasets 列出输出目标写入的数据集
+func (h *Handler) ListDatasets(c *gin.Context) {
+	datasets, err := h.db.ListDatasets()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.listDatasetsFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"datasets": datasets})
+}
+
+// datasetQuery 解析数据集查询条件（script_id、RFC3339 格式的 since/until）
+func datasetQuery(c *gin.Context) (models.DatasetQuery, bool) {
+	query := models.DatasetQuery{ScriptID: c.Query("script_id")}
+	for param, target := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "details": fmt.Sprintf("%s must be an RFC3339 time", param)})
+			return query, false
+		}
+		*target = t
+	}
+	return query, true
+}
+
+// QueryDatasetRecords 分页查询数据集记录（默认最新的在前，order=asc 时按写入顺序）
+func (h *Handler) QueryDatasetRecords(c *gin.Context) {
+	query, ok := datasetQuery(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 1000 {
+		pageSize = 50
+	}
+	query.Offset = (page - 1) * pageSize
+	query.Limit = pageSize
+	query.Desc = c.Query("order") != "asc"
+
+	records, total, err := h.db.QueryDatasetRecords(c.Param("name"), query)
+	if errors.Is(err, storage.ErrDatasetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.datasetNotFound"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.queryDatasetFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"records":   records,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// ExportDataset 按写入顺序导出数据集记录（format=csv|jsonl，支持与查询相同的过滤条件）
+func (h *Handler) ExportDataset(c *gin.Context) {
+	query, ok := datasetQuery(c)
+	if !ok {
+		return
+	}
+	name := c.Param("name")
+	records, _, err := h.db.QueryDatasetRecords(name, query)
+	if errors.Is(err, storage.ErrDatasetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.datasetNotFound"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.queryDatasetFailed"})
+		return
+	}
+
+	var buf bytes.Buffer
+	var contentType string
+	format := c.DefaultQuery("format", "csv")
+	switch format {
+	case "csv":
+		contentType = "text/csv; charset=utf-8"
+		err = sinks.WriteCSV(&buf, records)
+	case "jsonl":
+		contentType = "application/x-ndjson"
+		err = sinks.WriteJSONL(&buf, records)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.exportDatasetFailed"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="dataset-%s.%s"`, name, format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// DeleteDataset 删除数据集及其全部记录
+func (h *Handler) DeleteDataset(c *gin.Context) {
+	err := h.db.DeleteDataset(c.Param("name"))
+	if errors.Is(err, storage.ErrDatasetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.datasetNotFound"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteDatasetFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.datasetDeleted"})
+}
+
+// ============= 密钥相关处理器 =============
+
+// ListSecrets 列出密钥（只返回名称和描述，不返回值）
+func (h *Handler) ListSecrets(c *gin.Context) {
+	if !h.secrets.Enabled() {
+		c.JSON(http.StatusOK, gin.H{"secrets": []*models.Secret{}, "enabled": false})
+		return
+	}
+	list, err := h.secrets.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.listSecretsFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"secrets": list, "enabled": true})
+}
+
+// SetSecret 创建或更新密钥（值加密保存，脚本中通过 ${secret:name} 引用）
+func (h *Handler) SetSecret(c *gin.Context) {
+	var req struct {
+		Value       string `json:"value" binding:"required"`
+		Description string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	secret, err := h.secrets.Set(c.Param("name"), req.Value, req.Description)
+	switch {
+	case errors.Is(err, secrets.ErrDisabled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.secretsDisabled", "details": err.Error()})
+		return
+	case errors.Is(err, secrets.ErrInvalidName):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidSecretName", "details": err.Error()})
+		return
+	case err != nil:
+		logger.Error(c.Request.Context(), "Failed to save secret: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveSecretFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.secretSaved", "secret": secret})
+}
+
+// DeleteSecret 删除密钥
+func (h *Handler) DeleteSecret(c *gin.Context) {
+	err := h.secrets.Delete(c.Param("name"))
+	switch {
+	case errors.Is(err, secrets.ErrDisabled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.secretsDisabled", "details": err.Error()})
+		return
+	case errors.Is(err, secrets.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.secretNotFound"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteSecretFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.secretDeleted"})
+}
+
 // ============= LLM 配置管理相关处理器 =============
 
 // ListLLMConfigs 列出所有 LLM 配置
@@ -1155,6 +1679,11 @@ func (h *Handler) SetAgentManager(agentManager interface{}) {
 	h.agentManager = agentManager
 }
 
+// SetSecrets 设置密钥管理器
+func (h *Handler) SetSecrets(secrets *secrets.Manager) {
+	h.secrets = secrets
+}
+
 // GenerateMCPConfig 使用 LLM 自动生成 MCP 配置
 func (h *Handler) GenerateMCPConfig(c *gin.Context) {
 	id := c.Param("id")
@@ -1241,8 +1770,11 @@ func (h *Handler) ToggleScriptMCPCommand(c *gin.Context) {
 	script.MCPCommandName = req.MCPCommandName
 	script.MCPCommandDescription = req.MCPCommandDescription
 	script.MCPInputSchema = req.MCPInputSchema
+	if !applyParameterDefinitions(c, script) {
+		return
+	}
 
-	if err := h.db.UpdateScript(script); err != nil {
+	if err := h.db.UpdateScriptWithRevision(script, requestAuthor(c), ""); err != nil {
 		c.JSON(500, gin.H{"error": "error.updateScriptFailed"})
 		return
 	}
@@ -1521,7 +2053,7 @@ func (h *Handler) BatchSetGroup(c *gin.Context) {
 		}
 		script.Group = req.Group
 		script.UpdatedAt = time.Now()
-		if err := h.db.UpdateScript(script); err != nil {
+		if err := h.db.UpdateScriptWithRevision(script, requestAuthor(c), ""); err != nil {
 			continue
 		}
 		successCount++
@@ -1568,7 +2100,7 @@ func (h *Handler) BatchAddTags(c *gin.Context) {
 
 		script.Tags = newTags
 		script.UpdatedAt = time.Now()
-		if err := h.db.UpdateScript(script); err != nil {
+		if err := h.db.UpdateScriptWithRevision(script, requestAuthor(c), ""); err != nil {
 			continue
 		}
 		successCount++
@@ -1630,6 +2162,7 @@ func (h *Handler) ListScriptExecutions(c *gin.Context) {
 	scriptID := c.Query("script_id")    // 按脚本ID过滤
 	searchQuery := c.Query("search")    // 搜索脚本名称
 	successFilter := c.Query("success") // 按成功/失败过滤
+	statusFilter := c.Query("status")   // 按执行状态过滤（running, success, failed, cancelled）
 
 	// 获取所有执行记录
 	executions, err := h.db.ListScriptExecutions(scriptID)
@@ -1656,6 +2189,11 @@ func (h *Handler) ListScriptExecutions(c *gin.Context) {
 			}
 		}
 
+		// 状态过滤
+		if statusFilter != "" && string(exec.Status) != statusFilter {
+			continue
+		}
+
 		if exec.VideoPath != "" {
 			exec.VideoPath = "/files/" + exec.VideoPath
 		}
@@ -1698,6 +2236,185 @@ func (h *Handler) GetScriptExecution(c *gin.Context) {
 	c.JSON(http.StatusOK, execution)
 }
 
+// GetScriptExecutionArtifact 获取执行记录的失败现场文件（如失败截图）
+func (h *Handler) GetScriptExecutionArtifact(c *gin.Context) {
+	path, err := h.browserManager.GetTraceArtifactPath(c.Param("id"), c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.executionArtifactNotFound"})
+		return
+	}
+	c.File(path)
+}
+
+// ListRunningScriptExecutions 列出正在执行的脚本回放
+func (h *Handler) ListRunningScriptExecutions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"executions": h.browserManager.ListRunningExecutions(),
+	})
+}
+
+// CancelScriptExecution 取消正在执行的脚本回放（关闭回放页面，执行记录状态为 cancelled）
+func (h *Handler) CancelScriptExecution(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.browserManager.CancelExecution(id); err != nil {
+		if errors.Is(err, browser.ErrExecutionNotRunning) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "error.executionNotRunning"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info(c.Request.Context(), "Script execution cancelled: %s", id)
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "success.scriptExecutionCancelled",
+		"execution_id": id,
+	})
+}
+
+// StartScriptDebug 以调试模式回放脚本（支持断点、单步执行和失败后修改重试）
+func (h *Handler) StartScriptDebug(c *gin.Context) {
+	id := c.Param("id")
+
+	var req struct {
+		models.DebugOptions
+		Params     map[string]string `json:"params"`
+		InstanceID string            `json:"instance_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		req.Params = make(map[string]string)
+	}
+
+	if !h.browserManager.IsInstanceRunning(req.InstanceID) {
+		logger.Info(c, "Browser not running, starting...")
+		if err := h.browserManager.StartInstance(c, req.InstanceID); err != nil {
+			logger.Error(c.Request.Context(), "Failed to start browser: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error.playScriptFailed"})
+			return
+		}
+	}
+
+	script, err := h.db.GetScript(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	scriptToRun := script.Copy()
+	if !resolveScriptParams(c, scriptToRun, req.Params) {
+		return
+	}
+	if urlParam, ok := req.Params["url"]; ok && urlParam != "" {
+		scriptToRun.URL = urlParam
+	}
+
+	executionID, err := h.browserManager.StartDebugSession(scriptToRun, req.InstanceID, req.DebugOptions)
+	if err != nil {
+		logger.Error(c.Request.Context(), "Failed to start debug session: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.playScriptFailed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "success.debugSessionStarted",
+		"execution_id": executionID,
+	})
+}
+
+// GetScriptDebugState 获取调试会话状态
+func (h *Handler) GetScriptDebugState(c *gin.Context) {
+	state, err := h.browserManager.GetDebugState(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.debugSessionNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+// SendScriptDebugCommand 向暂停中的调试会话发送命令（step、resume、skip、retry、pause）
+func (h *Handler) SendScriptDebugCommand(c *gin.Context) {
+	var cmd models.DebugCommand
+	if err := c.ShouldBindJSON(&cmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	if err := h.browserManager.SendDebugCommand(c.Param("id"), cmd); err != nil {
+		switch {
+		case errors.Is(err, browser.ErrDebugSessionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "error.debugSessionNotFound"})
+		case errors.Is(err, browser.ErrDebugNotPaused):
+			c.JSON(http.StatusConflict, gin.H{"error": "error.debugNotPaused"})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success.debugCommandSent"})
+}
+
+// SetScriptDebugBreakpoints 设置调试会话的断点
+func (h *Handler) SetScriptDebugBreakpoints(c *gin.Context) {
+	var req struct {
+		Breakpoints []int `json:"breakpoints"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	if err := h.browserManager.SetDebugBreakpoints(c.Param("id"), req.Breakpoints); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.debugSessionNotFound"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success.debugBreakpointsUpdated"})
+}
+
+// StreamScriptDebugEvents 通过 SSE 推送调试会话状态变化，回放结束后关闭
+func (h *Handler) StreamScriptDebugEvents(c *gin.Context) {
+	events, unsubscribe, err := h.browserManager.SubscribeDebugSession(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.debugSessionNotFound"})
+		return
+	}
+	defer unsubscribe()
+
+	w := c.Writer
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.streamingNotSupported"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Transfer-Encoding", "chunked")
+	c.Header("X-Accel-Buffering", "no")
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case state, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(state)
+			if err != nil {
+				logger.Warn(ctx, "Failed to marshal debug state: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
 // DeleteScriptExecution 删除执行记录
 func (h *Handler) DeleteScriptExecution(c *gin.Context) {
 	id := c.Param("id")
@@ -1706,6 +2423,9 @@ func (h *Handler) DeleteScriptExecution(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteExecutionRecordFailed"})
 		return
 	}
+	if err := h.browserManager.DeleteTraceArtifacts(id); err != nil {
+		logger.Warn(c.Request.Context(), "Failed to delete execution trace artifacts: %v", err)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "success.executionRecordDeleted"})
 }
@@ -1730,6 +2450,9 @@ func (h *Handler) BatchDeleteScriptExecutions(c *gin.Context) {
 	for _, id := range req.IDs {
 		if err := h.db.DeleteScriptExecution(id); err == nil {
 			successCount++
+			if err := h.browserManager.DeleteTraceArtifacts(id); err != nil {
+				logger.Warn(c.Request.Context(), "Failed to delete execution trace artifacts: %v", err)
+			}
 		}
 	}
 
@@ -1787,22 +2510,41 @@ func (h *Handler) UpdateRecordingConfig(c *gin.Context) {
 
 // ============= 辅助函数 =============
 
-// replacePlaceholders 替换字符串中的占位符
-// 支持 ${field} 格式，例如 ${keyword}, ${page}, ${category} 等
-func replacePlaceholders(text string, params map[string]string) string {
-	if text == "" {
-		return text
+// resolveScriptParams 合并脚本预设变量和外部传入的参数（外部参数优先），并按参数定义校验
+// 校验失败时返回 400 和出错的参数列表
+func resolveScriptParams(c *gin.Context, script *models.Script, values map[string]string) bool {
+	vars, err := params.ResolveStrings(script, values)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "error.invalidScriptParams",
+			"details": err.Error(),
+			"fields":  params.FieldErrors(err),
+		})
+		return false
 	}
+	script.Variables = vars
+	return true
+}
 
-	// 替换所有占位符
-	result := text
-	for key, value := range params {
-		placeholder := fmt.Sprintf("${%s}", key)
-		result = strings.ReplaceAll(result, placeholder, value)
+// applyParameterDefinitions 检查参数定义，定义了参数时由参数定义生成 MCP 输入 schema
+func applyParameterDefinitions(c *gin.Context, script *models.Script) bool {
+	if err := params.ValidateDefinitions(script.Parameters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParameterDefinition", "details": err.Error()})
+		return false
+	}
+	if len(script.Parameters) > 0 {
+		script.MCPInputSchema = params.Schema(script.Parameters)
 	}
+	return true
+}
 
-	// 注意：这里不移除未替换的占位符，保留它们以便调试
-	return result
+// validateOutputSinks 检查输出目标配置
+func validateOutputSinks(c *gin.Context, outputSinks []models.OutputSink) bool {
+	if err := sinks.Validate(outputSinks); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidOutputSink", "details": err.Error()})
+		return false
+	}
+	return true
 }
 
 // syncMCPRegistration 同步 MCP 命令注册状态
@@ -2931,8 +3673,8 @@ func (h *Handler) ExportScriptsSkill(c *gin.Context) {
 		scriptIDs[i] = script.ID
 	}
 
-	// 生成 SKILL.md 内容
-	skillContent := generateSkillMD(scripts, host, isExportAll, scriptIDs)
+	// 生成 SKILL.md 内容（脚本中出现的密钥值替换为 ******）
+	skillContent := h.secrets.Redact(generateSkillMD(scripts, host, isExportAll, scriptIDs))
 
 	fileName := fmt.Sprintf("SKILL_%s.md", time.Now().Format("20060102150405"))
 
@@ -2942,6 +3684,248 @@ func (h *Handler) ExportScriptsSkill(c *gin.Context) {
 	c.String(http.StatusOK, skillContent)
 }
 
+// ValidateScript 静态校验脚本，返回每个操作的错误和警告
+// 请求体可选，提交的字段会覆盖已保存的脚本（用于校验编辑中尚未保存的修改）
+func (h *Handler) ValidateScript(c *gin.Context) {
+	script, err := h.db.GetScript(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if len(bytes.TrimSpace(body)) > 0 {
+		draft := script.Copy()
+		if err := json.Unmarshal(body, draft); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+			return
+		}
+		script = draft
+	}
+
+	c.JSON(http.StatusOK, validator.Validate(script))
+}
+
+// ExportScriptCode 将脚本导出为 go-rod、Playwright 或 Puppeteer 源码
+// target 参数指定目标框架；download=true 时以附件形式返回源码文件，否则返回源码和待人工处理的步骤列表
+func (h *Handler) ExportScriptCode(c *gin.Context) {
+	target, err := codegen.ParseTarget(c.DefaultQuery("target", string(codegen.TargetRod)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	script, err := h.db.GetScript(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	result, err := codegen.Generate(script, target)
+	if err != nil {
+		logger.Error(c.Request.Context(), "Failed to generate code for script %s: %v", script.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.exportCodeFailed"})
+		return
+	}
+
+	if download, _ := strconv.ParseBool(c.Query("download")); download {
+		c.Header("Content-Disposition", "attachment; filename="+result.FileName)
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(result.Code))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportScriptsBundle 导出脚本包（用于在不同 BrowserWing 服务之间迁移脚本）
+// 查询参数：ids（逗号分隔，为空时导出全部）、format（zip/json，默认 zip）、include_tasks、include_browser_configs
+func (h *Handler) ExportScriptsBundle(c *gin.Context) {
+	format := c.DefaultQuery("format", "zip")
+	if format != "zip" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	opts := bundle.ExportOptions{}
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			opts.ScriptIDs = append(opts.ScriptIDs, id)
+		}
+	}
+	opts.IncludeTasks, _ = strconv.ParseBool(c.DefaultQuery("include_tasks", "false"))
+	opts.IncludeBrowserConfigs, _ = strconv.ParseBool(c.DefaultQuery("include_browser_configs", "false"))
+
+	b, err := bundle.Export(h.db, opts)
+	if err != nil {
+		if errors.Is(err, bundle.ErrNoScripts) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.noScriptsToExport"})
+			return
+		}
+		logger.Error(c.Request.Context(), "Failed to export scripts bundle: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.exportBundleFailed"})
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "application/zip"
+	if format == "json" {
+		contentType = "application/json; charset=utf-8"
+		err = b.WriteJSON(&buf)
+	} else {
+		err = b.WriteZip(&buf)
+	}
+	if err != nil {
+		logger.Error(c.Request.Context(), "Failed to write scripts bundle: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.exportBundleFailed"})
+		return
+	}
+
+	fileName := fmt.Sprintf("browserwing_scripts_%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Disposition", "attachment; filename="+fileName)
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// ImportScriptsBundle 导入脚本包
+// 支持 multipart 上传（字段 file）或直接以请求体提交 zip/JSON；conflict 参数指定冲突处理方式：skip、overwrite、rename
+func (h *Handler) ImportScriptsBundle(c *gin.Context) {
+	conflict, err := bundle.ParseConflictMode(c.DefaultQuery("conflict", c.PostForm("conflict")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	var data []byte
+	if fileHeader, ferr := c.FormFile("file"); ferr == nil {
+		f, err := fileHeader.Open()
+		if err == nil {
+			data, err = io.ReadAll(f)
+			f.Close()
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidBundle"})
+			return
+		}
+	} else if data, err = io.ReadAll(c.Request.Body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidBundle"})
+		return
+	}
+
+	b, err := bundle.Read(data)
+	if err != nil {
+		if errors.Is(err, bundle.ErrUnsupportedVersion) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.unsupportedBundleVersion", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidBundle", "details": err.Error()})
+		return
+	}
+
+	assetsDir := "./data"
+	if h.config != nil && h.config.AssetsDir != "" {
+		assetsDir = h.config.AssetsDir
+	}
+	report, err := bundle.Import(h.db, b, bundle.ImportOptions{
+		Conflict: conflict,
+		FilesDir: filepath.Join(assetsDir, "uploads"),
+		Author:   requestAuthor(c),
+	})
+	if err != nil {
+		logger.Error(c.Request.Context(), "Failed to import scripts bundle: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.importBundleFailed", "details": err.Error()})
+		return
+	}
+
+	// 同步 MCP 注册状态
+	for _, id := range report.Imported(bundle.KindScript) {
+		if script, err := h.db.GetScript(id); err == nil {
+			h.syncMCPRegistration(c, script)
+		}
+	}
+
+	// 重新加载导入的定时任务
+	if h.scheduler != nil {
+		type Scheduler interface {
+			ReloadTask(string) error
+		}
+		if scheduler, ok := h.scheduler.(Scheduler); ok {
+			for _, id := range report.Imported(bundle.KindScheduledTask) {
+				if err := scheduler.ReloadTask(id); err != nil {
+					logger.Warn(c.Request.Context(), "Failed to reload imported task in scheduler: %v", err)
+				}
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "success.bundleImported",
+		"report":  report,
+	})
+}
+
+// ImportRecording 导入 Chrome DevTools Recorder 或 Selenium IDE 录制文件
+// 支持 multipart 上传（字段 file）或直接以请求体提交；format 参数指定格式（默认自动识别），dry_run=true 时只返回转换结果不保存
+func (h *Handler) ImportRecording(c *gin.Context) {
+	format, err := importer.ParseFormat(c.DefaultQuery("format", c.PostForm("format")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", c.PostForm("dry_run")))
+
+	var data []byte
+	if fileHeader, ferr := c.FormFile("file"); ferr == nil {
+		f, err := fileHeader.Open()
+		if err == nil {
+			data, err = io.ReadAll(f)
+			f.Close()
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRecording"})
+			return
+		}
+	} else if data, err = io.ReadAll(c.Request.Body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRecording"})
+		return
+	}
+
+	result, err := importer.Import(data, format)
+	if err != nil {
+		if errors.Is(err, importer.ErrUnknownFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.unknownRecordingFormat", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRecording", "details": err.Error()})
+		return
+	}
+
+	if !dryRun {
+		now := time.Now()
+		summary := fmt.Sprintf("Imported from %s recording", result.Format)
+		for _, script := range result.Scripts {
+			script.ID = uuid.New().String()
+			script.CreatedAt = now
+			script.UpdatedAt = now
+			if err := h.db.SaveScriptWithRevision(script, requestAuthor(c), summary); err != nil {
+				logger.Error(c.Request.Context(), "Failed to save imported script %s: %v", script.Name, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "error.importRecordingFailed"})
+				return
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "success.recordingImported",
+		"format":   result.Format,
+		"scripts":  result.Scripts,
+		"unmapped": result.Unmapped,
+		"warnings": result.Warnings,
+		"saved":    !dryRun,
+	})
+}
+
 // GetScriptsSummary 获取脚本摘要信息（用于 Claude Skills）
 func (h *Handler) GetScriptsSummary(c *gin.Context) {
 	// 获取脚本 ID 列表（可选）
@@ -3241,7 +4225,10 @@ func generateSkillMD(scripts []*models.Script, host string, isExportAll bool, sc
 			if !hasParams && len(script.Variables) > 0 {
 				sb.WriteString("**Parameters:**\n")
 				for varName, varValue := range script.Variables {
-					if varValue != "" {
+					if len(secrets.References(varValue)) > 0 {
+						// 引用密钥的变量不展示默认值
+						sb.WriteString(fmt.Sprintf("- `%s` - Default: stored secret\n", varName))
+					} else if varValue != "" {
 						sb.WriteString(fmt.Sprintf("- `%s` - Default: `%s`\n", varName, varValue))
 					} else {
 						sb.WriteString(fmt.Sprintf("- `%s` **(required)**\n", varName))
@@ -5689,6 +6676,9 @@ func (h *Handler) CreateScheduledTask(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.agentPromptRequired"})
 		return
 	}
+	if !validateOutputSinks(c, task.OutputSinks) {
+		return
+	}
 
 	// 如果有脚本ID，加载脚本名称
 	if task.ScriptID != "" {
@@ -5829,6 +6819,9 @@ func (h *Handler) UpdateScheduledTask(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.agentPromptRequired"})
 		return
 	}
+	if !validateOutputSinks(c, task.OutputSinks) {
+		return
+	}
 
 	// 如果有脚本ID，加载脚本名称
 	if task.ScriptID != "" {
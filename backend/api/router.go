@@ -131,6 +131,32 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			// Claude Skills 导出
 			scripts.POST("/export/skill", handler.ExportScriptsSkill) // 导出 SKILL.md
 			scripts.GET("/summary", handler.GetScriptsSummary)        // 获取脚本摘要（用于 Claude Skills）
+
+			// 源码导出（go-rod、Playwright、Puppeteer）
+			scripts.GET("/:id/export/code", handler.ExportScriptCode) // 导出可运行源码（?target=&download=）
+
+			// 静态校验
+			scripts.POST("/:id/validate", handler.ValidateScript) // 校验脚本（可在请求体中提交未保存的修改）
+
+			// 脚本包导出/导入（用于在不同服务之间迁移脚本）
+			scripts.GET("/export", handler.ExportScriptsBundle)  // 导出脚本包（zip/JSON）
+			scripts.POST("/import", handler.ImportScriptsBundle) // 导入脚本包
+
+			// 第三方录制导入（Chrome DevTools Recorder、Selenium IDE）
+			scripts.POST("/import/recording", handler.ImportRecording) // 上传录制文件并转换为脚本
+
+			// 调试回放（结束调试使用 /executions/:id/cancel）
+			scripts.POST("/:id/debug", handler.StartScriptDebug)                      // 以调试模式回放脚本
+			scripts.GET("/debug/:id", handler.GetScriptDebugState)                   // 获取调试会话状态
+			scripts.GET("/debug/:id/events", handler.StreamScriptDebugEvents)        // SSE 推送调试状态
+			scripts.POST("/debug/:id/command", handler.SendScriptDebugCommand)       // 发送调试命令
+			scripts.PUT("/debug/:id/breakpoints", handler.SetScriptDebugBreakpoints) // 设置断点
+
+			// 历史版本
+			scripts.GET("/:id/revisions", handler.ListScriptRevisions)                      // 列出历史版本
+			scripts.GET("/:id/revisions/diff", handler.DiffScriptRevisions)                 // 比较两个版本（?from=&to=）
+			scripts.GET("/:id/revisions/:revision", handler.GetScriptRevision)              // 获取指定版本
+			scripts.POST("/:id/revisions/:revision/restore", handler.RestoreScriptRevision) // 恢复到指定版本
 		}
 
 		// PlayScript接口使用JWT或ApiKey认证（支持内部和外部调用）
@@ -138,6 +164,11 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 		scriptsPlay.Use(JWTOrApiKeyAuthenticationMiddleware(handler.config, handler.db))
 		{
 			scriptsPlay.POST("/:id/play", handler.PlayScript)
+			scriptsPlay.POST("/:id/data-runs", handler.StartDataRun)                               // 按数据集逐行执行脚本
+			scriptsPlay.GET("/executions/running", handler.ListRunningScriptExecutions)            // 列出正在执行的回放
+			scriptsPlay.GET("/executions/:id", handler.GetScriptExecution)                         // 获取执行记录（包含步骤执行轨迹）
+			scriptsPlay.GET("/executions/:id/artifacts/:name", handler.GetScriptExecutionArtifact) // 获取失败现场文件（截图）
+			scriptsPlay.POST("/executions/:id/cancel", handler.CancelScriptExecution)              // 取消正在执行的回放
 		}
 
 		// 脚本执行记录相关
@@ -149,6 +180,33 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			executions.POST("/batch/delete", handler.BatchDeleteScriptExecutions) // 批量删除
 		}
 
+		// 数据驱动运行记录相关
+		dataRuns := api.Group("/data-runs")
+		{
+			dataRuns.GET("", handler.ListDataRuns)              // 列出运行记录（?script_id=）
+			dataRuns.GET("/:id", handler.GetDataRun)            // 获取运行记录（包含每行结果）
+			dataRuns.GET("/:id/export", handler.ExportDataRun)  // 下载运行结果（?format=csv|jsonl）
+			dataRuns.POST("/:id/cancel", handler.CancelDataRun) // 取消运行
+			dataRuns.DELETE("/:id", handler.DeleteDataRun)      // 删除运行记录
+		}
+
+		// 密钥管理（值加密保存，接口不返回值）
+		secretsGroup := api.Group("/secrets")
+		{
+			secretsGroup.GET("", handler.ListSecrets)           // 列出密钥
+			secretsGroup.PUT("/:name", handler.SetSecret)       // 创建或更新密钥
+			secretsGroup.DELETE("/:name", handler.DeleteSecret) // 删除密钥
+		}
+
+		// 数据集（脚本和定时任务的输出目标写入的历史记录）
+		datasets := api.Group("/datasets")
+		{
+			datasets.GET("", handler.ListDatasets)                      // 列出数据集
+			datasets.GET("/:name/records", handler.QueryDatasetRecords) // 查询记录（?script_id=&since=&until=&page=&page_size=&order=asc）
+			datasets.GET("/:name/export", handler.ExportDataset)        // 导出记录（?format=csv|jsonl）
+			datasets.DELETE("/:name", handler.DeleteDataset)            // 删除数据集
+		}
+
 		// MCP 服务相关（管理接口）
 		mcp := api.Group("/mcp")
 		{
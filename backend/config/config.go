@@ -17,6 +17,8 @@ type Config struct {
 	AssetsDir string               `json:"assets_dir,omitempty" yaml:"assets_dir,omitempty" toml:"assets_dir,omitempty"`
 	Log       *logger.LoggerConfig `json:"log,omitempty" yaml:"log,omitempty" toml:"log,omitempty"`
 	Auth      *AuthConfig          `json:"auth,omitempty" yaml:"auth,omitempty" toml:"auth,omitempty"`
+	Playback  *PlaybackConfig      `json:"playback,omitempty" yaml:"playback,omitempty" toml:"playback,omitempty"`
+	Secrets   *SecretsConfig       `json:"secrets,omitempty" yaml:"secrets,omitempty" toml:"secrets,omitempty"`
 }
 
 type ServerConfig struct {
@@ -45,6 +47,47 @@ type BrowserConfig struct {
 	ControlURL  string `json:"control_url,omitempty" toml:"control_url,omitempty"` // 远程 Chrome DevTools URL，例如：ws://192.168.1.100:9222 或 http://192.168.1.100:9222
 }
 
+// PlaybackConfig 脚本回放的默认等待配置（单位：毫秒）
+type PlaybackConfig struct {
+	NavigationDelay int `json:"navigation_delay" toml:"navigation_delay"`   // 打开起始 URL 后的固定等待（脚本未配置 start_wait 时使用，0 表示不等待）
+	StabilizeDelay  int `json:"stabilize_delay" toml:"stabilize_delay"`     // 等待页面 JavaScript 初始化的额外时长（0 表示不等待）
+	WaitTimeout     int `json:"wait_timeout" toml:"wait_timeout"`           // 事件等待的默认超时（不大于 0 时使用 DefaultWaitTimeout）
+	NetworkIdleTime int `json:"network_idle_time" toml:"network_idle_time"` // network_idle 等待的默认空闲时长
+
+	MaxRunsPerInstance int `json:"max_runs_per_instance" toml:"max_runs_per_instance"` // 每个浏览器实例同时执行的回放数上限（超出时排队，0 表示使用默认值 3）
+}
+
+// SecretsConfig 密钥存储配置
+type SecretsConfig struct {
+	Key string `json:"key" toml:"key"` // 加密密钥（口令），为空时不启用密钥存储；环境变量 BROWSERWING_SECRETS_KEY 优先
+}
+
+// SecretsKey 返回密钥存储的加密密钥（环境变量优先于配置文件）
+func (c *Config) SecretsKey() string {
+	if key := os.Getenv("BROWSERWING_SECRETS_KEY"); key != "" {
+		return key
+	}
+	if c.Secrets != nil {
+		return c.Secrets.Key
+	}
+	return ""
+}
+
+// DefaultWaitTimeout 事件等待的默认超时（毫秒）
+const DefaultWaitTimeout = 30000
+
+// DefaultPlaybackConfig 返回默认回放配置（与早期版本的固定等待时长保持一致）
+func DefaultPlaybackConfig() *PlaybackConfig {
+	return &PlaybackConfig{
+		NavigationDelay: 2000,
+		StabilizeDelay:  1000,
+		WaitTimeout:     DefaultWaitTimeout,
+		NetworkIdleTime: 500,
+
+		MaxRunsPerInstance: 3,
+	}
+}
+
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -104,6 +147,7 @@ func Load(path string) (*Config, error) {
 				DefaultUsername: "admin",
 				DefaultPassword: "admin123",
 			},
+			Playback: DefaultPlaybackConfig(),
 		}
 		// 如果错误是文件不存在，则将defConfig写到本地的path位置
 		if os.IsNotExist(err) {
@@ -115,7 +159,8 @@ func Load(path string) (*Config, error) {
 		return defConfig, nil
 	}
 
-	var cfg Config
+	// 预先填充默认值，配置文件中未出现的字段保持默认
+	cfg := Config{Playback: DefaultPlaybackConfig()}
 	err = toml.Unmarshal(data, &cfg)
 	if err != nil {
 		return nil, err
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/browserwing/browserwing/pkg/dom"
 	"github.com/browserwing/browserwing/pkg/logger"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
@@ -689,79 +690,7 @@ func (e *Executor) Extract(ctx context.Context, opts *ExtractOptions) (*Operatio
 
 // extractElementData 提取元素数据
 func (e *Executor) extractElementData(elem *rod.Element, opts *ExtractOptions) (map[string]interface{}, error) {
-	data := make(map[string]interface{})
-
-	switch opts.Type {
-	case "text":
-		text, err := elem.Text()
-		if err != nil {
-			return nil, err
-		}
-		data["text"] = text
-
-	case "html":
-		html, err := elem.HTML()
-		if err != nil {
-			return nil, err
-		}
-		data["html"] = html
-
-	case "attribute":
-		if opts.Attr != "" {
-			attr, err := elem.Attribute(opts.Attr)
-			if err != nil || attr == nil {
-				return nil, err
-			}
-			data[opts.Attr] = *attr
-		}
-
-	case "property":
-		if opts.Attr != "" {
-			prop, err := elem.Property(opts.Attr)
-			if err != nil {
-				return nil, err
-			}
-			data[opts.Attr] = prop.String()
-		}
-
-	default:
-		// 提取指定字段
-		if len(opts.Fields) > 0 {
-			for _, field := range opts.Fields {
-				switch field {
-				case "text":
-					if text, err := elem.Text(); err == nil {
-						data["text"] = text
-					}
-				case "html":
-					if html, err := elem.HTML(); err == nil {
-						data["html"] = html
-					}
-				case "value":
-					if val, err := elem.Property("value"); err == nil {
-						data["value"] = val.String()
-					}
-				case "href":
-					if href, err := elem.Attribute("href"); err == nil && href != nil {
-						data["href"] = *href
-					}
-				case "src":
-					if src, err := elem.Attribute("src"); err == nil && src != nil {
-						data["src"] = *src
-					}
-				}
-			}
-		} else {
-			// 默认提取文本
-			text, err := elem.Text()
-			if err != nil {
-				return nil, err
-			}
-			data["text"] = text
-		}
-	}
-
-	return data, nil
+	return dom.ExtractElementData(elem, opts.Type, opts.Attr, opts.Fields)
 }
 
 // findElement 查找元素（支持多种方式），带超时支持
@@ -913,7 +842,7 @@ func (e *Executor) findElementByRefID(ctx context.Context, page *rod.Page, refID
 	}
 	
 	// 策略 3：使用 role + name + nth（fallback）
-	xpath := buildXPathFromRole(refData.Role, refData.Name)
+	xpath := dom.BuildXPathFromRole(refData.Role, refData.Name)
 	logger.Info(ctx, "[findElementByRefID] Built XPath: %s", xpath)
 	
 	elements, err := page.ElementsX(xpath)
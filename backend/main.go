@@ -20,8 +20,11 @@ import (
 	"github.com/browserwing/browserwing/mcp"
 	"github.com/browserwing/browserwing/models"
 	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/pkg/secretbox"
 	"github.com/browserwing/browserwing/scheduler"
 	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/services/secrets"
+	"github.com/browserwing/browserwing/services/sinks"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -98,6 +101,12 @@ func main() {
 		log.Println("✓ System prompts checked and updated")
 	}
 
+	// 初始化密钥存储（配置了密钥时加密保存密钥和 LLM API Key，并从日志中脱敏密钥值）
+	secretManager, err := initSecrets(db, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize secrets store: %v", err)
+	}
+
 	// 初始化默认浏览器实例
 	err = initDefaultBrowserInstance(db, cfg)
 	if err != nil {
@@ -128,6 +137,9 @@ func main() {
 
 	// 初始化浏览器管理器
 	browserManager := browser.NewManager(cfg, db, llmManager)
+	browserManager.SetSecrets(secretManager)
+	outputWriter := initOutputWriter(db, cfg, secretManager)
+	browserManager.SetOutputWriter(outputWriter)
 	log.Println("✓ Browser manager initialized successfully")
 
 	// 初始化 MCP 服务器 (使用 mcp-go 库)
@@ -171,6 +183,7 @@ func main() {
 
 	// 将 Agent 管理器注入到 Handler (用于 LLM 配置更新后的热加载)
 	handler.SetAgentManager(agentManager)
+	handler.SetSecrets(secretManager)
 
 	// 初始化定时任务执行器（使用真实的浏览器管理器和 Agent 管理器）
 	scriptPlayer := scheduler.NewRealScriptPlayer(db, browserManager)
@@ -179,6 +192,7 @@ func main() {
 
 	// 初始化定时任务调度器
 	taskScheduler := scheduler.NewScheduler(db, taskExecutor)
+	taskScheduler.SetOutputWriter(outputWriter)
 	err = taskScheduler.Start()
 	if err != nil {
 		log.Printf("Warning: Failed to start scheduler: %v", err)
@@ -316,6 +330,42 @@ func openBrowser(url string) {
 	_ = cmd.Start() // 不阻塞，忽略错误（有些环境可能没有 GUI）
 }
 
+// initOutputWriter 初始化抓取数据输出写入器（文件写入资源目录下的 outputs 目录）
+func initOutputWriter(db *storage.BoltDB, cfg *config.Config, secretManager *secrets.Manager) *sinks.Writer {
+	assetsDir := "./data"
+	if cfg.AssetsDir != "" {
+		assetsDir = cfg.AssetsDir
+	}
+	writer := sinks.NewWriter(assetsDir, db)
+	writer.SetSecrets(secretManager)
+	return writer
+}
+
+// initSecrets 初始化密钥存储（未配置密钥时返回未启用的管理器）
+func initSecrets(db *storage.BoltDB, cfg *config.Config) (*secrets.Manager, error) {
+	key := cfg.SecretsKey()
+	if key == "" {
+		log.Println("Secrets store disabled (set secrets.key or BROWSERWING_SECRETS_KEY to enable)")
+		return secrets.NewManager(db, nil)
+	}
+
+	box, err := secretbox.New(key)
+	if err != nil {
+		return nil, err
+	}
+	// 加密保存 LLM API Key（同时加密已保存的明文 API Key）
+	if err := db.SetCipher(box); err != nil {
+		return nil, fmt.Errorf("failed to encrypt LLM API keys: %w", err)
+	}
+	manager, err := secrets.NewManager(db, box)
+	if err != nil {
+		return nil, err
+	}
+	logger.SetRedactor(manager.Redact)
+	log.Println("✓ Secrets store initialized successfully")
+	return manager, nil
+}
+
 // initDefaultBrowserInstance 初始化默认浏览器实例
 func initDefaultBrowserInstance(db *storage.BoltDB, cfg *config.Config) error {
 	// 检查是否已存在默认实例
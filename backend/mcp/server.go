@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +16,9 @@ import (
 	"github.com/browserwing/browserwing/models"
 	"github.com/browserwing/browserwing/pkg/logger"
 	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/services/params"
+	"github.com/browserwing/browserwing/services/runqueue"
+	"github.com/browserwing/browserwing/services/validator"
 	"github.com/browserwing/browserwing/storage"
 )
 
@@ -166,6 +168,17 @@ func (s *MCPServer) registerAllTools() error {
 
 // registerTool 注册单个脚本为工具
 func (s *MCPServer) registerTool(script *models.Script) error {
+	// 脚本定义了参数时，输入 schema 由参数定义生成（支持枚举、列表、正则等完整约束）
+	if len(script.Parameters) > 0 {
+		schema, err := json.Marshal(params.Schema(script.Parameters))
+		if err != nil {
+			return fmt.Errorf("failed to build input schema: %w", err)
+		}
+		tool := mcpgo.NewToolWithRawSchema(script.MCPCommandName, script.MCPCommandDescription, schema)
+		s.mcpServer.AddTool(tool, s.createToolHandler(script))
+		return nil
+	}
+
 	opts := []mcpgo.ToolOption{
 		mcpgo.WithDescription(script.MCPCommandDescription),
 	}
@@ -219,6 +232,16 @@ func (s *MCPServer) createToolHandler(script *models.Script) func(ctx context.Co
 		logger.Info(ctx, "Executing MCP command: %s (script: %s)", script.MCPCommandName, script.Name)
 		logger.Info(ctx, "MCP command arguments: %v", request.Params.Arguments)
 
+		// 创建脚本副本并合并参数（按参数定义校验），占位符由 Player 在执行时统一解析
+		var arguments map[string]interface{}
+		if request.Params.Arguments != nil {
+			arguments, _ = request.Params.Arguments.(map[string]interface{})
+		}
+		scriptToRun, err := s.prepareScript(script, arguments)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
 		// 检查浏览器是否运行
 		if !s.browserMgr.IsRunning() {
 			logger.Info(ctx, "Browser not running, starting...")
@@ -228,55 +251,8 @@ func (s *MCPServer) createToolHandler(script *models.Script) func(ctx context.Co
 			logger.Info(ctx, "Browser started successfully")
 		}
 
-		// 创建脚本副本并替换占位符
-		scriptToRun := script.Copy()
-
-		// 合并参数：先使用脚本预设变量，再用外部传入的参数覆盖
-		params := make(map[string]string)
-
-		// 1. 首先添加脚本的预设变量
-		if scriptToRun.Variables != nil {
-			for key, value := range scriptToRun.Variables {
-				params[key] = value
-			}
-		}
-
-		// 2. 外部传入的参数会覆盖预设变量
-		if request.Params.Arguments != nil {
-			if argsMap, ok := request.Params.Arguments.(map[string]interface{}); ok {
-				for key, value := range argsMap {
-					params[key] = fmt.Sprintf("%v", value)
-				}
-				for key := range scriptToRun.Variables {
-					if _, ok := params[key]; ok {
-						scriptToRun.Variables[key] = params[key]
-					}
-				}
-			}
-		}
-
-		// 替换 URL 中的占位符
-		if urlParam, ok := params["url"]; ok && urlParam != "" {
-			scriptToRun.URL = urlParam
-		} else {
-			scriptToRun.URL = s.replacePlaceholders(scriptToRun.URL, params)
-		}
-
-		// 替换所有 action 中的占位符
-		for i := range scriptToRun.Actions {
-			scriptToRun.Actions[i].Selector = s.replacePlaceholders(scriptToRun.Actions[i].Selector, params)
-			scriptToRun.Actions[i].XPath = s.replacePlaceholders(scriptToRun.Actions[i].XPath, params)
-			scriptToRun.Actions[i].Value = s.replacePlaceholders(scriptToRun.Actions[i].Value, params)
-			scriptToRun.Actions[i].URL = s.replacePlaceholders(scriptToRun.Actions[i].URL, params)
-			scriptToRun.Actions[i].JSCode = s.replacePlaceholders(scriptToRun.Actions[i].JSCode, params)
-
-			for j := range scriptToRun.Actions[i].FilePaths {
-				scriptToRun.Actions[i].FilePaths[j] = s.replacePlaceholders(scriptToRun.Actions[i].FilePaths[j], params)
-			}
-		}
-
-		// 执行脚本（使用当前实例，传空字符串）
-		playResult, page, err := s.browserMgr.PlayScript(ctx, scriptToRun, "")
+		// 执行脚本（使用当前实例，传空字符串；MCP 请求取消时回放随之中止）
+		playResult, page, err := s.browserMgr.PlayScript(runqueue.WithSource(ctx, runqueue.SourceMCP), scriptToRun, "")
 		if err != nil {
 			return mcpgo.NewToolResultError(fmt.Sprintf("Failed to execute script: %v", err)), nil
 		}
@@ -294,8 +270,9 @@ func (s *MCPServer) createToolHandler(script *models.Script) func(ctx context.Co
 
 		// 构建返回结果，将 extracted_data 放在 data 字段中以便 Agent 处理
 		resultData := map[string]interface{}{
-			"success": playResult.Success,
-			"message": playResult.Message,
+			"success":      playResult.Success,
+			"message":      playResult.Message,
+			"execution_id": playResult.ExecutionID,
 		}
 
 		// 如果有抓取的数据，将其放在 data 字段中
@@ -308,6 +285,15 @@ func (s *MCPServer) createToolHandler(script *models.Script) func(ctx context.Co
 			logger.Info(ctx, "[MCP Script Tool] No extracted data to return")
 		}
 
+		// 如果脚本包含断言，返回断言结果
+		if len(playResult.Assertions) > 0 {
+			resultData["assertions"] = map[string]interface{}{
+				"passed":  playResult.AssertionsPassed,
+				"failed":  playResult.AssertionsFailed,
+				"results": playResult.Assertions,
+			}
+		}
+
 		return mcpgo.NewToolResultJSON(resultData)
 	}
 }
@@ -321,23 +307,39 @@ func getKeysFromMap(m map[string]interface{}) []string {
 	return keys
 }
 
-// replacePlaceholders 替换字符串中的占位符
-func (s *MCPServer) replacePlaceholders(text string, params map[string]string) string {
-	if text == "" {
-		return text
+// prepareScript 创建脚本副本并合并变量：预设变量 < 输入 schema 声明的参数（未传时为空）< 调用参数
+// 没有任何来源（预设变量、参数、抓取结果）的占位符解析为空字符串
+// 脚本定义了参数时按参数定义校验并规范化调用参数
+func (s *MCPServer) prepareScript(script *models.Script, arguments map[string]interface{}) (*models.Script, error) {
+	scriptToRun := script.Copy()
+
+	vars, err := params.Resolve(scriptToRun, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	// schema 中声明但未传入的参数解析为空字符串（保持可选参数的原有行为）
+	if props, ok := script.MCPInputSchema["properties"].(map[string]interface{}); ok {
+		for propName := range props {
+			if _, exists := vars[propName]; !exists {
+				vars[propName] = ""
+			}
+		}
 	}
+	scriptToRun.Variables = vars
 
-	result := text
-	for key, value := range params {
-		placeholder := fmt.Sprintf("${%s}", key)
-		result = strings.ReplaceAll(result, placeholder, value)
+	// 没有任何来源的占位符解析为空字符串，避免把 ${name} 原样输入到页面中（保持 MCP 调用的原有行为）
+	for _, name := range validator.UndefinedVariables(scriptToRun) {
+		logger.Warn(context.Background(), "Placeholder ${%s} is not declared in the input schema, resolving it to an empty string", name)
+		vars[name] = ""
 	}
 
-	// 清理未替换的占位符
-	re := regexp.MustCompile(`\$\{([^}]+)\}`)
-	result = re.ReplaceAllString(result, "")
+	// 如果提供了 url 参数，直接作为起始 URL
+	if urlParam, ok := vars["url"]; ok && urlParam != "" {
+		scriptToRun.URL = urlParam
+	}
 
-	return result
+	return scriptToRun, nil
 }
 
 // RegisterScript 注册脚本为 MCP 命令
@@ -419,6 +421,12 @@ func (s *MCPServer) CallTool(ctx context.Context, name string, arguments map[str
 
 	logger.Info(ctx, "CallTool: Executing MCP command: %s (script: %s), arguments %+v", name, script.Name, arguments)
 
+	// 创建脚本副本并合并参数（按参数定义校验），占位符由 Player 在执行时统一解析
+	scriptToRun, err := s.prepareScript(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+
 	// 检查浏览器是否运行
 	if !s.browserMgr.IsRunning() {
 		logger.Info(ctx, "Browser not running, starting...")
@@ -427,44 +435,8 @@ func (s *MCPServer) CallTool(ctx context.Context, name string, arguments map[str
 		}
 	}
 
-	// 创建脚本副本并替换占位符
-	scriptToRun := script.Copy()
-	params := make(map[string]string)
-	for key, value := range scriptToRun.Variables {
-		params[key] = value
-	}
-
-	for key, value := range arguments {
-		params[key] = fmt.Sprintf("%v", value)
-	}
-
-	for key := range scriptToRun.Variables {
-		if _, ok := params[key]; ok {
-			scriptToRun.Variables[key] = params[key]
-		}
-	}
-
-	// 替换占位符
-	if urlParam, ok := params["url"]; ok && urlParam != "" {
-		scriptToRun.URL = urlParam
-	} else {
-		scriptToRun.URL = s.replacePlaceholders(scriptToRun.URL, params)
-	}
-
-	for i := range scriptToRun.Actions {
-		scriptToRun.Actions[i].Selector = s.replacePlaceholders(scriptToRun.Actions[i].Selector, params)
-		scriptToRun.Actions[i].XPath = s.replacePlaceholders(scriptToRun.Actions[i].XPath, params)
-		scriptToRun.Actions[i].Value = s.replacePlaceholders(scriptToRun.Actions[i].Value, params)
-		scriptToRun.Actions[i].URL = s.replacePlaceholders(scriptToRun.Actions[i].URL, params)
-		scriptToRun.Actions[i].JSCode = s.replacePlaceholders(scriptToRun.Actions[i].JSCode, params)
-
-		for j := range scriptToRun.Actions[i].FilePaths {
-			scriptToRun.Actions[i].FilePaths[j] = s.replacePlaceholders(scriptToRun.Actions[i].FilePaths[j], params)
-		}
-	}
-
 	// 执行脚本（使用当前实例，传空字符串）
-	playResult, page, err := s.browserMgr.PlayScript(ctx, scriptToRun, "")
+	playResult, page, err := s.browserMgr.PlayScript(runqueue.WithSource(ctx, runqueue.SourceMCP), scriptToRun, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute script: %w", err)
 	}
@@ -482,8 +454,9 @@ func (s *MCPServer) CallTool(ctx context.Context, name string, arguments map[str
 
 	// 构建返回结果，将 extracted_data 放在 data 字段中以便 Agent 处理
 	result := map[string]interface{}{
-		"success": playResult.Success,
-		"message": playResult.Message,
+		"success":      playResult.Success,
+		"message":      playResult.Message,
+		"execution_id": playResult.ExecutionID,
 	}
 
 	// 如果有抓取的数据，将其放在 data 字段中
@@ -496,6 +469,15 @@ func (s *MCPServer) CallTool(ctx context.Context, name string, arguments map[str
 		logger.Info(ctx, "[MCP CallTool] No extracted data to return")
 	}
 
+	// 如果脚本包含断言，返回断言结果
+	if len(playResult.Assertions) > 0 {
+		result["assertions"] = map[string]interface{}{
+			"passed":  playResult.AssertionsPassed,
+			"failed":  playResult.AssertionsFailed,
+			"results": playResult.Assertions,
+		}
+	}
+
 	return result, nil
 }
 
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+func TestPrepareScriptUndeclaredPlaceholders(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	s := &MCPServer{}
+	script := &models.Script{
+		URL: "https://example.com/search?q=${query}",
+		Actions: []models.ScriptAction{
+			{Type: "input", Selector: "#q", Value: "${query} ${suffix}"},
+			{Type: "input", Selector: "#lang", Value: "${lang|default:en}"},
+			{Type: "extract_text", Selector: "h1", VariableName: "title"},
+			{Type: "input", Selector: "#note", Value: "${title}"},
+		},
+		MCPInputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"query": map[string]interface{}{"type": "string"}},
+		},
+	}
+
+	prepared, err := s.prepareScript(script, map[string]interface{}{"query": "shoes"})
+	if err != nil {
+		t.Fatalf("prepareScript: %v", err)
+	}
+	vars := prepared.Variables
+	if vars["query"] != "shoes" {
+		t.Errorf("query = %q, want shoes", vars["query"])
+	}
+	// 未声明的占位符解析为空字符串，不会把 ${suffix} 原样输入到页面中
+	if value, ok := vars["suffix"]; !ok || value != "" {
+		t.Errorf("suffix = %q (set %v), want empty", value, ok)
+	}
+	// 有默认值或由抓取设置的变量保持由回放时解析
+	for _, name := range []string{"lang", "title"} {
+		if _, ok := vars[name]; ok {
+			t.Errorf("%s should not be preset", name)
+		}
+	}
+	if _, ok := script.Variables["suffix"]; ok {
+		t.Errorf("original script variables modified")
+	}
+}
@@ -27,6 +27,9 @@ type BrowserInstance struct {
 	LaunchArgs []string `json:"launch_args,omitempty"` // 启动参数
 	Proxy      string   `json:"proxy,omitempty"`       // 代理地址
 
+	// 同时执行的脚本回放数上限（0 表示使用全局配置 playback.max_runs_per_instance，修改后重新启动实例生效）
+	MaxConcurrentRuns int `json:"max_concurrent_runs,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
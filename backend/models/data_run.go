@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+)
+
+// DataRun 数据驱动运行记录（对数据集中的每一行执行一次脚本）
+type DataRun struct {
+	ID             string `json:"id"`
+	ScriptID       string `json:"script_id"`
+	ScriptName     string `json:"script_name"`
+	ScriptRevision int    `json:"script_revision,omitempty"`
+	InstanceID     string `json:"instance_id,omitempty"` // 浏览器实例 ID（为空时使用当前实例，"*" 表示各行分配到任意运行中的实例）
+
+	// 数据集信息
+	DatasetName string   `json:"dataset_name,omitempty"` // 上传的文件名
+	Format      string   `json:"format"`                 // csv, jsonl, json
+	Columns     []string `json:"columns"`                // 数据集列名（按文件中的顺序）
+
+	// 运行配置
+	Concurrency int           `json:"concurrency"`           // 同时执行的行数
+	OnRowError  OnErrorAction `json:"on_row_error"`          // 单行失败后的处理方式: continue（默认）, stop
+	RetryCount  int           `json:"retry_count,omitempty"` // 单行失败后的重试次数
+
+	Status    ExecutionStatus `json:"status"`
+	Message   string          `json:"message,omitempty"`
+	TotalRows int             `json:"total_rows"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Skipped   int             `json:"skipped"` // 运行停止或取消后未执行的行
+
+	Rows []DataRunRow `json:"rows"`
+
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time,omitempty"`
+	Duration  int64     `json:"duration"` // 耗时（毫秒）
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DataRowStatus 数据行执行状态
+type DataRowStatus string
+
+const (
+	DataRowPending DataRowStatus = "pending" // 等待执行
+	DataRowRunning DataRowStatus = "running" // 执行中
+	DataRowSuccess DataRowStatus = "success" // 执行成功
+	DataRowFailed  DataRowStatus = "failed"  // 执行失败（包括断言失败）
+	DataRowSkipped DataRowStatus = "skipped" // 运行停止或取消，未执行
+)
+
+// DataRunRow 单行的执行结果
+type DataRunRow struct {
+	Index         int                    `json:"index"`     // 行号（从 0 开始，不含表头）
+	Variables     map[string]string      `json:"variables"` // 该行映射的变量
+	Status        DataRowStatus          `json:"status"`
+	Attempts      int                    `json:"attempts,omitempty"`     // 执行次数（包括重试）
+	ExecutionID   string                 `json:"execution_id,omitempty"` // 最后一次执行的脚本执行记录 ID
+	Error         string                 `json:"error,omitempty"`
+	ExtractedData map[string]interface{} `json:"extracted_data,omitempty"`
+	StartTime     time.Time              `json:"start_time,omitempty"`
+	EndTime       time.Time              `json:"end_time,omitempty"`
+	Duration      int64                  `json:"duration,omitempty"` // 耗时（毫秒）
+}
+
+// DataRunSummary 数据驱动运行列表项（不含行明细）
+type DataRunSummary struct {
+	ID          string          `json:"id"`
+	ScriptID    string          `json:"script_id"`
+	ScriptName  string          `json:"script_name"`
+	DatasetName string          `json:"dataset_name,omitempty"`
+	Status      ExecutionStatus `json:"status"`
+	TotalRows   int             `json:"total_rows"`
+	Succeeded   int             `json:"succeeded"`
+	Failed      int             `json:"failed"`
+	Skipped     int             `json:"skipped"`
+	StartTime   time.Time       `json:"start_time"`
+	Duration    int64           `json:"duration"`
+}
+
+// Summary 返回不含行明细的列表项
+func (r *DataRun) Summary() DataRunSummary {
+	return DataRunSummary{
+		ID:          r.ID,
+		ScriptID:    r.ScriptID,
+		ScriptName:  r.ScriptName,
+		DatasetName: r.DatasetName,
+		Status:      r.Status,
+		TotalRows:   r.TotalRows,
+		Succeeded:   r.Succeeded,
+		Failed:      r.Failed,
+		Skipped:     r.Skipped,
+		StartTime:   r.StartTime,
+		Duration:    r.Duration,
+	}
+}
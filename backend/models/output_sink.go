@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// OutputSinkType 输出目标类型
+type OutputSinkType string
+
+const (
+	SinkCSV     OutputSinkType = "csv"     // 追加到资源目录下的 CSV 文件
+	SinkJSONL   OutputSinkType = "jsonl"   // 追加到资源目录下的 JSONL 文件
+	SinkWebhook OutputSinkType = "webhook" // POST 到 Webhook URL（失败时重试）
+	SinkDataset OutputSinkType = "dataset" // 写入数据库中的命名数据集（只追加）
+)
+
+// OutputSink 抓取数据的输出目标
+// 脚本或定时任务执行完成后（执行失败或断言失败也会输出，记录中 success 为 false），抓取数据逐条写入各输出目标
+type OutputSink struct {
+	Type       OutputSinkType    `json:"type"`
+	Path       string            `json:"path,omitempty"`        // csv、jsonl：相对于资源目录下 outputs 目录的文件路径
+	URL        string            `json:"url,omitempty"`         // webhook：请求地址（支持 ${secret:name}）
+	Headers    map[string]string `json:"headers,omitempty"`     // webhook：附加请求头（支持 ${secret:name}）
+	RetryCount *int              `json:"retry_count,omitempty"` // webhook：失败后的重试次数（为空时默认 3，设为 0 不重试）
+	Dataset    string            `json:"dataset,omitempty"`     // dataset：数据集名称
+	Fields     []string          `json:"fields,omitempty"`      // 只输出这些抓取字段（为空时输出全部）
+}
+
+// OutputRecord 写入输出目标的一条记录
+type OutputRecord struct {
+	ID          string                 `json:"id,omitempty"` // 数据集中的记录 ID（按写入顺序递增）
+	Timestamp   time.Time              `json:"timestamp"`
+	ScriptID    string                 `json:"script_id"`
+	ScriptName  string                 `json:"script_name"`
+	ExecutionID string                 `json:"execution_id,omitempty"`
+	TaskID      string                 `json:"task_id,omitempty"` // 由定时任务输出时的任务 ID
+	Success     bool                   `json:"success"`
+	Error       string                 `json:"error,omitempty"` // 执行失败时的错误信息
+	Data        map[string]interface{} `json:"data"`
+}
+
+// DatasetInfo 数据集概要
+type DatasetInfo struct {
+	Name    string    `json:"name"`
+	Count   int       `json:"count"`
+	FirstAt time.Time `json:"first_at,omitempty"` // 最早一条记录的时间
+	LastAt  time.Time `json:"last_at,omitempty"`  // 最近一条记录的时间
+}
+
+// DatasetQuery 数据集记录查询条件
+type DatasetQuery struct {
+	ScriptID string
+	Since    time.Time // 为零值时不限制
+	Until    time.Time // 为零值时不限制
+	Offset   int
+	Limit    int  // 为 0 时不限制
+	Desc     bool // 按时间倒序（最新的在前）
+}
@@ -46,6 +46,9 @@ type ScheduledTask struct {
 	ScriptVariables  map[string]string `json:"script_variables,omitempty"`   // 脚本变量
 	BrowserInstanceID string           `json:"browser_instance_id,omitempty"` // 浏览器实例 ID（可选）
 
+	// 执行结果的输出目标（脚本和 Agent 任务都支持，脚本自身声明的输出目标另外写入）
+	OutputSinks []OutputSink `json:"output_sinks,omitempty"`
+
 	// Agent 执行配置（当 execution_type 为 agent 时使用）
 	AgentPrompt   string `json:"agent_prompt,omitempty"`    // Agent 提示词
 	AgentLLMID    string `json:"agent_llm_id,omitempty"`    // 使用的 LLM 配置 ID
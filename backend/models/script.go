@@ -20,7 +20,7 @@ type ScriptAction struct {
 	// =========================
 	// 原有字段（保持不变）
 	// =========================
-	Type      string            `json:"type"`      // click, input, select, navigate, wait, sleep, extract_text, extract_attribute, extract_html, execute_js, upload_file, scroll, keyboard, open_tab, switch_tab, switch_active_tab, ai_control
+	Type      string            `json:"type"`      // click, input, select, navigate, wait, sleep, extract_text, extract_attribute, extract_html, execute_js, upload_file, scroll, keyboard, open_tab, switch_tab, switch_active_tab, ai_control, loop, foreach, assert_text, assert_visible, assert_url, assert_count, assert_attribute, assert_variable, call_script, extract_list, paginate, wait_for, if, set_viewport
 	Timestamp int64             `json:"timestamp"` // 时间戳（毫秒）
 	Selector  string            `json:"selector"`  // CSS选择器
 	XPath     string            `json:"xpath"`     // XPath选择器（更可靠）
@@ -43,6 +43,12 @@ type ScriptAction struct {
 	VariableName  string `json:"variable_name,omitempty"`  // 变量名
 	ExtractedData string `json:"extracted_data,omitempty"` // 回放时填充
 
+	// 列表抓取相关字段（用于 extract_list 类型，Selector/XPath 为列表项容器）
+	Fields map[string]ExtractField `json:"fields,omitempty"` // 字段名 -> 字段定义
+
+	// 抓取结果的值转换（用于抓取操作、execute_js 和 capture_xhr，过滤器链如 "regex:([\d.,]+)|number|default:0"，结果保留类型）
+	Transform string `json:"transform,omitempty"`
+
 	// 文件上传相关字段
 	FilePaths   []string `json:"file_paths,omitempty"`
 	FileNames   []string `json:"file_names,omitempty"`
@@ -65,6 +71,12 @@ type ScriptAction struct {
 	ScreenshotWidth  int    `json:"screenshot_width,omitempty"`  // 截图区域宽度（region模式）
 	ScreenshotHeight int    `json:"screenshot_height,omitempty"` // 截图区域高度（region模式）
 
+	// 视口相关字段（用于 set_viewport 类型）
+	ViewportWidth     int     `json:"viewport_width,omitempty"`      // 视口宽度
+	ViewportHeight    int     `json:"viewport_height,omitempty"`     // 视口高度
+	DeviceScaleFactor float64 `json:"device_scale_factor,omitempty"` // 设备像素比（默认 1）
+	Mobile            bool    `json:"mobile,omitempty"`              // 是否模拟移动设备
+
 	// AI控制相关字段（用于 ai_control 类型）
 	AIControlPrompt      string `json:"ai_control_prompt,omitempty"`       // AI控制的提示词
 	AIControlXPath       string `json:"ai_control_xpath,omitempty"`        // 可选的元素XPath（用于提示词上下文）
@@ -72,6 +84,40 @@ type ScriptAction struct {
 
 	Condition *ActionCondition `json:"condition,omitempty"`
 
+	// 断言相关字段（用于 assert_* 类型，期望值使用 Value）
+	AssertOperator string `json:"assert_operator,omitempty"` // equals, not_equals, contains, not_contains, starts_with, ends_with, matches, >, <, >=, <=
+
+	// 子脚本调用相关字段（用于 call_script 类型，先导航到 URL，为空时使用子脚本的起始 URL）
+	ScriptID      string            `json:"script_id,omitempty"`      // 被调用脚本 ID
+	ScriptName    string            `json:"script_name,omitempty"`    // 被调用脚本名称（ScriptID 为空时按名称查找）
+	InputMapping  map[string]string `json:"input_mapping,omitempty"`  // 传入子脚本的变量：子脚本变量名 -> 值（支持 ${...}）
+	OutputMapping map[string]string `json:"output_mapping,omitempty"` // 子脚本抓取结果映射：子脚本变量名 -> 当前脚本变量名（为空时全部合并）
+
+	// 失败处理相关字段
+	Label       string       `json:"label,omitempty"`        // 步骤标签（用于 on_error=goto 跳转）
+	ErrorPolicy *ErrorPolicy `json:"error_policy,omitempty"` // 失败处理策略（为空时使用脚本级默认策略）
+
+	// 循环相关字段（用于 loop / foreach 类型）
+	Actions       []ScriptAction   `json:"actions,omitempty"`        // 循环体内嵌套执行的操作
+	LoopCount     int              `json:"loop_count,omitempty"`     // loop: 固定循环次数
+	LoopCondition *ActionCondition `json:"loop_condition,omitempty"` // loop: 条件成立时继续循环（while）
+	MaxIterations int              `json:"max_iterations,omitempty"` // 最大迭代次数（防止死循环，条件循环默认 100）
+	ListVariable  string           `json:"list_variable,omitempty"`  // foreach: 遍历的列表变量名（为空时遍历 Selector/XPath 匹配的元素）
+	IndexVariable string           `json:"index_variable,omitempty"` // 当前迭代索引的变量名（默认 index）
+	ItemVariable  string           `json:"item_variable,omitempty"`  // 当前迭代元素的变量名（默认 item）
+
+	// 条件分支相关字段（用于 if 类型，Condition 成立时执行 Actions，否则执行 ElseActions）
+	ElseActions []ScriptAction `json:"else_actions,omitempty"`
+
+	// 分页相关字段（用于 paginate 类型，Actions 为每页执行的操作块）
+	Pagination *PaginationConfig `json:"pagination,omitempty"`
+
+	// 事件等待相关字段（wait_for 类型的等待条件；navigate 类型导航完成后的等待条件）
+	WaitFor *WaitCondition `json:"wait_for,omitempty"`
+
+	// 元素定位路径（元素位于嵌套 iframe 或 Shadow DOM 中时，从顶层文档依次进入的 iframe / shadow host，Selector/XPath 相对于最内层上下文）
+	FramePath []FrameHop `json:"frame_path,omitempty"`
+
 	// =========================
 	// 新增字段（v2，自愈核心）
 	// =========================
@@ -109,6 +155,8 @@ func (a *ScriptAction) CopyWithoutSemanticInfo() *ScriptAction {
 		JSCode:           a.JSCode,
 		VariableName:     a.VariableName,
 		ExtractedData:    a.ExtractedData,
+		Fields:           a.Fields,
+		Transform:        a.Transform,
 		FilePaths:        a.FilePaths,
 		FileNames:        a.FileNames,
 		Description:      a.Description,
@@ -123,19 +171,207 @@ func (a *ScriptAction) CopyWithoutSemanticInfo() *ScriptAction {
 		ScreenshotMode:       a.ScreenshotMode,
 		ScreenshotWidth:      a.ScreenshotWidth,
 		ScreenshotHeight:     a.ScreenshotHeight,
+		ViewportWidth:        a.ViewportWidth,
+		ViewportHeight:       a.ViewportHeight,
+		DeviceScaleFactor:    a.DeviceScaleFactor,
+		Mobile:               a.Mobile,
 		AIControlPrompt:      a.AIControlPrompt,
 		AIControlXPath:       a.AIControlXPath,
 		AIControlLLMConfigID: a.AIControlLLMConfigID,
 		Condition:            a.Condition,
+		AssertOperator:       a.AssertOperator,
+		ScriptID:             a.ScriptID,
+		ScriptName:           a.ScriptName,
+		InputMapping:         a.InputMapping,
+		OutputMapping:        a.OutputMapping,
+		Label:                a.Label,
+		ErrorPolicy:          a.ErrorPolicy,
+		Actions:              copyActionsWithoutSemanticInfo(a.Actions),
+		ElseActions:          copyActionsWithoutSemanticInfo(a.ElseActions),
+		LoopCount:            a.LoopCount,
+		LoopCondition:        a.LoopCondition,
+		MaxIterations:        a.MaxIterations,
+		ListVariable:         a.ListVariable,
+		IndexVariable:        a.IndexVariable,
+		ItemVariable:         a.ItemVariable,
+		Pagination:           a.Pagination,
+		WaitFor:              a.WaitFor,
+		FramePath:            a.FramePath,
+	}
+}
+
+// copyActionsWithoutSemanticInfo 复制嵌套操作列表（去除语义信息）
+func copyActionsWithoutSemanticInfo(actions []ScriptAction) []ScriptAction {
+	if len(actions) == 0 {
+		return nil
+	}
+	copied := make([]ScriptAction, len(actions))
+	for i, action := range actions {
+		copied[i] = *action.CopyWithoutSemanticInfo()
+	}
+	return copied
+}
+
+// ExtractField 列表抓取的字段定义
+type ExtractField struct {
+	Selector  string `json:"selector,omitempty"`  // 相对列表项容器的 CSS 选择器（为空时使用容器本身）
+	Type      string `json:"type,omitempty"`      // 提取类型: text（默认）, html, attribute, property
+	Attribute string `json:"attribute,omitempty"` // 属性名（type=attribute/property 时使用）
+	Multiple  bool   `json:"multiple,omitempty"`  // 是否提取所有匹配元素（结果为数组）
+	Transform string `json:"transform,omitempty"` // 值转换（过滤器链，如 "trim|lower"、"number"）
+}
+
+// PaginationConfig 分页抓取配置（NextSelector/NextXPath 点击翻页，或 URLTemplate 按页码跳转）
+type PaginationConfig struct {
+	NextSelector   string `json:"next_selector,omitempty"`   // 下一页按钮 CSS 选择器
+	NextXPath      string `json:"next_xpath,omitempty"`      // 下一页按钮 XPath
+	URLTemplate    string `json:"url_template,omitempty"`    // 分页 URL 模板，使用 ${page} 引用页码（如 https://example.com/list?p=${page}）
+	StartPage      int    `json:"start_page,omitempty"`      // 起始页码（URL 模板模式，默认 1）
+	MaxPages       int    `json:"max_pages,omitempty"`       // 最大页数（默认 50）
+	WaitAfter      int    `json:"wait_after,omitempty"`      // 翻页后等待时长（毫秒，默认 1000）
+	ChangeSelector string `json:"change_selector,omitempty"` // 用于判断页面内容是否变化的区域选择器（默认 body）
+}
+
+// WaitType 事件等待类型
+type WaitType string
+
+const (
+	WaitSelectorVisible WaitType = "selector_visible" // 元素出现且可见
+	WaitSelectorHidden  WaitType = "selector_hidden"  // 元素消失或不可见
+	WaitURLMatches      WaitType = "url_matches"      // 页面 URL 匹配正则（无效正则时按子串匹配）
+	WaitNetworkIdle     WaitType = "network_idle"     // 持续 IdleTime 毫秒没有进行中的网络请求
+	WaitJSPredicate     WaitType = "js_predicate"     // JS 表达式结果为真
+	WaitTextPresent     WaitType = "text_present"     // 页面（或 Selector 指定区域）出现指定文本
+)
+
+// WaitCondition 事件等待条件
+type WaitCondition struct {
+	Type       WaitType `json:"type"`
+	Selector   string   `json:"selector,omitempty"`   // CSS 选择器（selector_visible / selector_hidden / text_present 的查找范围）
+	XPath      string   `json:"xpath,omitempty"`      // XPath（优先于 Selector）
+	Pattern    string   `json:"pattern,omitempty"`    // url_matches: URL 正则
+	Expression string   `json:"expression,omitempty"` // js_predicate: JS 表达式
+	Text       string   `json:"text,omitempty"`       // text_present: 等待出现的文本
+	IdleTime   int      `json:"idle_time,omitempty"`  // network_idle: 空闲时长（毫秒，默认使用全局配置）
+	Timeout    int      `json:"timeout,omitempty"`    // 超时时长（毫秒，默认使用全局配置）
+}
+
+// FrameHopType 定位路径节点类型
+type FrameHopType string
+
+const (
+	FrameHopIframe FrameHopType = "iframe" // 进入 iframe 的文档
+	FrameHopShadow FrameHopType = "shadow" // 进入 shadow host 的 shadow root（仅支持 open 模式）
+)
+
+// FrameHop 定位路径节点
+type FrameHop struct {
+	Type     FrameHopType `json:"type"`
+	Selector string       `json:"selector"` // 当前上下文中 iframe 元素或 shadow host 的 CSS 选择器
+}
+
+// OnErrorAction 操作失败后的处理方式
+type OnErrorAction string
+
+const (
+	OnErrorContinue  OnErrorAction = "continue"   // 记录失败并继续执行后续步骤（默认）
+	OnErrorStop      OnErrorAction = "stop"       // 中止整个脚本回放
+	OnErrorGoto      OnErrorAction = "goto"       // 跳转到指定标签的步骤
+	OnErrorRunScript OnErrorAction = "run_script" // 执行回退脚本
+)
+
+const (
+	MaxRetryCount = 10    // 单个操作最多重试次数
+	MaxRetryDelay = 60000 // 单次重试前的最长等待时间（毫秒），翻倍后的间隔不超过该值
+)
+
+// ErrorPolicy 操作失败处理策略（操作级字段覆盖脚本级默认值）
+type ErrorPolicy struct {
+	RetryCount       *int          `json:"retry_count,omitempty"`        // 失败后重试次数（为空时继承脚本级，操作级设为 0 可关闭重试）
+	RetryBackoff     int           `json:"retry_backoff,omitempty"`      // 重试间隔（毫秒，每次重试翻倍）
+	Timeout          *int          `json:"timeout,omitempty"`            // 单次执行超时（毫秒，为空时继承脚本级，操作级设为 0 可取消超时）
+	OnError          OnErrorAction `json:"on_error,omitempty"`           // 失败处理方式: continue, stop, goto, run_script
+	GotoLabel        string        `json:"goto_label,omitempty"`         // on_error=goto 时跳转的步骤标签
+	FallbackScriptID string        `json:"fallback_script_id,omitempty"` // on_error=run_script 时执行的脚本 ID
+}
+
+// Retries 重试次数（未设置时为 0）
+func (p ErrorPolicy) Retries() int {
+	if p.RetryCount == nil {
+		return 0
 	}
+	return *p.RetryCount
 }
 
+// TimeoutMs 单次执行超时（毫秒，未设置时为 0，表示不限制）
+func (p ErrorPolicy) TimeoutMs() int {
+	if p.Timeout == nil {
+		return 0
+	}
+	return *p.Timeout
+}
+
+// Merge 合并操作级与脚本级策略，操作级已设置的字段优先
+func (p *ErrorPolicy) Merge(override *ErrorPolicy) ErrorPolicy {
+	var merged ErrorPolicy
+	if p != nil {
+		merged = *p
+	}
+	if override == nil {
+		return merged
+	}
+	if override.RetryCount != nil {
+		merged.RetryCount = override.RetryCount
+	}
+	if override.RetryBackoff != 0 {
+		merged.RetryBackoff = override.RetryBackoff
+	}
+	if override.Timeout != nil {
+		merged.Timeout = override.Timeout
+	}
+	if override.OnError != "" {
+		merged.OnError = override.OnError
+		merged.GotoLabel = override.GotoLabel
+		merged.FallbackScriptID = override.FallbackScriptID
+	}
+	return merged
+}
+
+// ConditionType 条件类型
+type ConditionType string
+
+const (
+	ConditionVariable       ConditionType = "variable"        // 变量比较（默认）
+	ConditionElementExists  ConditionType = "element_exists"  // 元素存在
+	ConditionElementVisible ConditionType = "element_visible" // 元素存在且可见
+	ConditionURLMatches     ConditionType = "url_matches"     // 页面 URL 匹配正则（无效正则时按子串匹配）
+	ConditionTextPresent    ConditionType = "text_present"    // 页面（或 Selector 指定区域）包含指定文本
+	ConditionJS             ConditionType = "js"              // JS 表达式结果为真
+	ConditionAnd            ConditionType = "and"             // 所有子条件成立
+	ConditionOr             ConditionType = "or"              // 任一子条件成立
+)
+
 // ActionCondition 操作执行条件
 type ActionCondition struct {
-	Variable string `json:"variable"`          // 变量名
-	Operator string `json:"operator"`          // 操作符: =, !=, >, <, >=, <=, in, not_in, exists, not_exists
-	Value    string `json:"value"`             // 比较值
-	Enabled  bool   `json:"enabled,omitempty"` // 是否启用条件（默认false）
+	Type     ConditionType `json:"type,omitempty"`    // 条件类型（默认 variable）
+	Variable string        `json:"variable"`          // 变量名
+	Operator string        `json:"operator"`          // 操作符: =, !=, >, <, >=, <=, in, not_in, exists, not_exists
+	Value    string        `json:"value"`             // 比较值
+	Enabled  bool          `json:"enabled,omitempty"` // 是否启用条件（默认false，仅顶层条件使用）
+
+	// 页面状态条件
+	Selector   string `json:"selector,omitempty"`   // element_exists / element_visible: CSS 选择器；text_present: 查找范围
+	XPath      string `json:"xpath,omitempty"`      // XPath（优先于 Selector）
+	Pattern    string `json:"pattern,omitempty"`    // url_matches: URL 正则
+	Text       string `json:"text,omitempty"`       // text_present: 文本
+	Expression string `json:"expression,omitempty"` // js: JS 表达式
+	Timeout    int    `json:"timeout,omitempty"`    // 页面状态条件最多等待条件成立的时长（毫秒，默认立即判断）
+
+	Negate     bool              `json:"negate,omitempty"`     // 对结果取反
+	Conditions []ActionCondition `json:"conditions,omitempty"` // and / or: 子条件
+
+	// variable: 比较前对变量值应用的过滤器链（与抓取操作的 Transform 相同，如 "number"、"json:data.total"）
+	Transform string `json:"transform,omitempty"`
 }
 
 type ActionIntent struct {
@@ -187,6 +423,21 @@ type Script struct {
 
 	// 预设变量（可以在脚本中使用 ${变量名} 引用，也可以在外部调用时传入覆盖）
 	Variables map[string]string `json:"variables,omitempty"` // 预设变量，key 为变量名，value 为默认值
+
+	// 参数定义（回放前校验外部传入的参数，定义后 MCPInputSchema 由参数定义生成）
+	Parameters []ScriptParameter `json:"parameters,omitempty"`
+
+	// 脚本级默认失败处理策略（操作未单独配置时使用）
+	ErrorPolicy *ErrorPolicy `json:"error_policy,omitempty"`
+
+	// 抓取数据的输出目标（每次执行完成后追加写入）
+	OutputSinks []OutputSink `json:"output_sinks,omitempty"`
+
+	// 打开起始 URL 后的等待条件（为空时使用全局配置的固定等待时长）
+	StartWait *WaitCondition `json:"start_wait,omitempty"`
+
+	// 当前版本号（每次内容变化时递增，历史版本见 ScriptRevision）
+	Revision int `json:"revision,omitempty"`
 }
 
 func (s *Script) GetActionsWithoutSemanticInfo() []ScriptAction {
@@ -220,6 +471,18 @@ func (s *Script) Copy() *Script {
 		variables[k] = v
 	}
 
+	var parameters []ScriptParameter
+	if s.Parameters != nil {
+		parameters = make([]ScriptParameter, len(s.Parameters))
+		copy(parameters, s.Parameters)
+	}
+
+	var outputSinks []OutputSink
+	if s.OutputSinks != nil {
+		outputSinks = make([]OutputSink, len(s.OutputSinks))
+		copy(outputSinks, s.OutputSinks)
+	}
+
 	return &Script{
 		ID:                    s.ID,
 		Name:                  s.Name,
@@ -239,6 +502,11 @@ func (s *Script) Copy() *Script {
 		MCPCommandDescription: s.MCPCommandDescription,
 		MCPInputSchema:        s.MCPInputSchema,
 		Variables:             variables,
+		Parameters:            parameters,
+		ErrorPolicy:           s.ErrorPolicy,
+		OutputSinks:           outputSinks,
+		StartWait:             s.StartWait,
+		Revision:              s.Revision,
 	}
 }
 
@@ -248,4 +516,10 @@ type PlayResult struct {
 	Message       string                 `json:"message"`        // 结果消息
 	ExtractedData map[string]interface{} `json:"extracted_data"` // 抓取到的数据，key 为变量名或 action 索引
 	Errors        []string               `json:"errors"`         // 错误信息列表
+	ExecutionID   string                 `json:"execution_id"`   // 执行记录 ID
+
+	// 断言结果
+	AssertionsPassed int               `json:"assertions_passed,omitempty"` // 通过的断言数
+	AssertionsFailed int               `json:"assertions_failed,omitempty"` // 失败的断言数
+	Assertions       []AssertionResult `json:"assertions,omitempty"`        // 断言明细
 }
@@ -0,0 +1,54 @@
+package models
+
+// DebugCommandType 调试命令类型
+type DebugCommandType string
+
+const (
+	DebugCommandStep   DebugCommandType = "step"   // 执行当前步骤，然后在下一步前暂停
+	DebugCommandResume DebugCommandType = "resume" // 继续执行到下一个断点
+	DebugCommandSkip   DebugCommandType = "skip"   // 跳过当前步骤，然后在下一步前暂停
+	DebugCommandRetry  DebugCommandType = "retry"  // 执行（或重新执行失败的）当前步骤，可附带修改后的操作
+	DebugCommandPause  DebugCommandType = "pause"  // 在下一步前暂停
+)
+
+// DebugStatus 调试会话状态
+type DebugStatus string
+
+const (
+	DebugStatusRunning  DebugStatus = "running"  // 执行中
+	DebugStatusPaused   DebugStatus = "paused"   // 已暂停，等待调试命令
+	DebugStatusFinished DebugStatus = "finished" // 回放已结束
+)
+
+// DebugOptions 调试回放选项
+type DebugOptions struct {
+	Breakpoints    []int `json:"breakpoints"`      // 断点（顶层操作索引，从 0 开始），在步骤执行前暂停
+	BreakOnFailure bool  `json:"break_on_failure"` // 步骤失败后暂停（可修改后重试）
+	PauseOnStart   bool  `json:"pause_on_start"`   // 在第一个步骤前暂停
+}
+
+// DebugCommand 调试命令
+type DebugCommand struct {
+	Command DebugCommandType `json:"command"`
+	Action  *ScriptAction    `json:"action,omitempty"` // retry: 替换当前步骤的操作（为空时按原操作执行）
+}
+
+// DebugState 调试会话状态快照
+type DebugState struct {
+	ExecutionID    string                 `json:"execution_id"`
+	ScriptID       string                 `json:"script_id"`
+	Status         DebugStatus            `json:"status"`
+	StepIndex      int                    `json:"step_index"`               // 暂停所在步骤的索引（从 0 开始）
+	TotalSteps     int                    `json:"total_steps"`              // 顶层步骤总数
+	Action         *ScriptAction          `json:"action,omitempty"`         // 暂停所在步骤的操作（未解析占位符）
+	LastError      string                 `json:"last_error,omitempty"`     // 步骤失败后暂停时的错误信息
+	Breakpoints    []int                  `json:"breakpoints"`              // 当前断点
+	BreakOnFailure bool                   `json:"break_on_failure"`         // 是否在失败后暂停
+	Variables      map[string]string      `json:"variables,omitempty"`      // 当前变量
+	ExtractedData  map[string]interface{} `json:"extracted_data,omitempty"` // 当前抓取结果
+	PageURL        string                 `json:"page_url,omitempty"`       // 当前页面 URL
+	PageTitle      string                 `json:"page_title,omitempty"`     // 当前页面标题
+	EditedActions  map[int]ScriptAction   `json:"edited_actions,omitempty"` // 调试过程中修改过的操作（key 为步骤索引，可用于保存回脚本）
+	Result         *PlayResult            `json:"result,omitempty"`         // 回放结果（结束后）
+	Error          string                 `json:"error,omitempty"`          // 回放错误（结束后）
+}
@@ -9,12 +9,14 @@ type ScriptExecution struct {
 	ID          string    `json:"id"`           // 执行记录 ID
 	ScriptID    string    `json:"script_id"`    // 关联的脚本 ID
 	ScriptName  string    `json:"script_name"`  // 脚本名称（冗余，方便查询）
+	ScriptRevision int    `json:"script_revision,omitempty"` // 执行时的脚本版本号
 	InstanceID  string    `json:"instance_id"`  // 浏览器实例 ID
 	InstanceName string   `json:"instance_name,omitempty"` // 浏览器实例名称（冗余，方便查询）
 	StartTime   time.Time `json:"start_time"`   // 开始时间
 	EndTime     time.Time `json:"end_time"`     // 结束时间
 	Duration    int64     `json:"duration"`     // 执行耗时（毫秒）
 	Success     bool      `json:"success"`      // 是否成功
+	Status      ExecutionStatus `json:"status,omitempty"` // 执行状态
 	Message     string    `json:"message"`      // 执行消息
 	ErrorMsg    string    `json:"error_msg"`    // 错误信息
 	
@@ -22,12 +24,94 @@ type ScriptExecution struct {
 	TotalSteps   int `json:"total_steps"`   // 总步骤数
 	SuccessSteps int `json:"success_steps"` // 成功步骤数
 	FailedSteps  int `json:"failed_steps"`  // 失败步骤数
+
+	// 断言统计（与执行错误分开统计）
+	AssertionsPassed int               `json:"assertions_passed"`    // 通过的断言数
+	AssertionsFailed int               `json:"assertions_failed"`    // 失败的断言数
+	Assertions       []AssertionResult `json:"assertions,omitempty"` // 断言明细
 	
 	// 抓取数据
 	ExtractedData map[string]interface{} `json:"extracted_data,omitempty"` // 抓取到的数据
 	
 	// 录制视频
 	VideoPath string `json:"video_path,omitempty"` // 录制视频路径
+
+	// 步骤执行记录（loop / foreach / call_script 的嵌套步骤记录在 Children 中）
+	Steps []StepRecord `json:"steps,omitempty"`
+
+	// 元素定位记录（记录每个步骤实际命中的定位策略，包括自愈结果）
+	LocatorResolutions []LocatorResolution `json:"locator_resolutions,omitempty"`
 	
 	CreatedAt time.Time `json:"created_at"` // 记录创建时间
 }
+
+// ExecutionStatus 脚本执行状态
+type ExecutionStatus string
+
+const (
+	ExecutionStatusQueued    ExecutionStatus = "queued"    // 排队等待浏览器实例名额
+	ExecutionStatusRunning   ExecutionStatus = "running"   // 执行中
+	ExecutionStatusSuccess   ExecutionStatus = "success"   // 执行成功
+	ExecutionStatusFailed    ExecutionStatus = "failed"    // 执行失败（包括断言失败）
+	ExecutionStatusCancelled ExecutionStatus = "cancelled" // 已取消
+)
+
+// RunningExecution 正在执行或排队中的回放
+type RunningExecution struct {
+	ExecutionID string          `json:"execution_id"` // 执行记录 ID
+	ScriptID    string          `json:"script_id"`    // 脚本 ID
+	ScriptName  string          `json:"script_name"`  // 脚本名称
+	InstanceID  string          `json:"instance_id"`  // 浏览器实例 ID（排队中为请求的实例，"*" 表示任意实例）
+	Source      string          `json:"source"`       // 回放来源: api, scheduler, mcp, data_run
+	Status      ExecutionStatus `json:"status"`       // queued 或 running
+	QueuedAt    time.Time       `json:"queued_at"`    // 提交时间
+	StartTime   time.Time       `json:"start_time"`   // 开始执行时间（排队中为零值）
+}
+
+// StepRecord 单个步骤的执行记录
+type StepRecord struct {
+	Index          int                `json:"index"`                     // 步骤在所属操作块中的索引（从 0 开始）
+	Type           string             `json:"type"`                      // 操作类型
+	Success        bool               `json:"success"`                   // 是否成功
+	Skipped        bool               `json:"skipped,omitempty"`         // 是否因条件不满足而跳过
+	Error          string             `json:"error,omitempty"`           // 错误信息
+	Locator        *LocatorResolution `json:"locator,omitempty"`         // 实际命中的元素定位
+	StartTime      time.Time          `json:"start_time"`                // 开始时间
+	EndTime        time.Time          `json:"end_time"`                  // 结束时间
+	Duration       int64              `json:"duration"`                  // 耗时（毫秒）
+	ExtractedValue interface{}        `json:"extracted_value,omitempty"` // 步骤抓取到的值
+	Failure        *StepFailure       `json:"failure,omitempty"`         // 失败现场（仅失败步骤）
+	ScriptID       string             `json:"script_id,omitempty"`       // 被调用的脚本 ID（call_script）
+	ScriptName     string             `json:"script_name,omitempty"`     // 被调用的脚本名称（call_script）
+	Children       []StepRecord       `json:"children,omitempty"`        // 嵌套步骤
+}
+
+// StepFailure 步骤失败时采集的页面现场
+type StepFailure struct {
+	URL         string `json:"url,omitempty"`          // 失败时的页面 URL
+	Title       string `json:"title,omitempty"`        // 失败时的页面标题
+	Screenshot  string `json:"screenshot,omitempty"`   // 截图文件名（通过执行记录的 artifacts 接口获取）
+	DOMSnapshot string `json:"dom_snapshot,omitempty"` // 精简后的 DOM 快照（去除脚本和样式，限制长度）
+	AXSnapshot  string `json:"ax_snapshot,omitempty"`  // 精简后的可访问性树快照（每行一个节点: role "name"）
+}
+
+// LocatorResolution 单个步骤的元素定位结果
+type LocatorResolution struct {
+	StepIndex int     `json:"step_index"`      // 步骤索引（从 0 开始）
+	Strategy  string  `json:"strategy"`        // 命中的策略: xpath, css, iframe, role, attributes, text
+	Selector  string  `json:"selector"`        // 实际使用的选择器
+	Score     float64 `json:"score,omitempty"` // 自愈候选评分（仅自愈策略）
+	Healed    bool    `json:"healed"`          // 是否通过自愈策略找到（原始定位器失效）
+}
+
+// AssertionResult 单个断言的结果
+type AssertionResult struct {
+	StepIndex int    `json:"step_index"`        // 步骤索引（从 0 开始）
+	Type      string `json:"type"`              // 断言类型: assert_text, assert_visible, assert_url, assert_count, assert_attribute, assert_variable
+	Target    string `json:"target,omitempty"`  // 断言对象（选择器、属性或变量名）
+	Operator  string `json:"operator"`          // 比较操作符
+	Expected  string `json:"expected"`          // 期望值
+	Actual    string `json:"actual"`            // 实际值
+	Passed    bool   `json:"passed"`            // 是否通过
+	Message   string `json:"message,omitempty"` // 失败原因
+}
@@ -0,0 +1,27 @@
+package models
+
+// ParameterType 脚本参数类型
+type ParameterType string
+
+const (
+	ParamString  ParameterType = "string"  // 字符串（默认）
+	ParamNumber  ParameterType = "number"  // 数字
+	ParamBoolean ParameterType = "boolean" // 布尔值：true/false
+	ParamEnum    ParameterType = "enum"    // 枚举：取值必须是 Enum 中的一项
+	ParamDate    ParameterType = "date"    // 日期：按 Format 解析（默认 2006-01-02）
+	ParamFile    ParameterType = "file"    // 本地文件路径（文件必须存在）
+	ParamList    ParameterType = "list"    // 列表：JSON 数组或逗号/换行分隔的字符串，回放时以 JSON 数组传入
+)
+
+// ScriptParameter 脚本参数定义
+// 回放前校验并规范化调用方传入的参数，MCP 工具的输入 schema 也由参数定义生成
+type ScriptParameter struct {
+	Name        string        `json:"name"`
+	Type        ParameterType `json:"type,omitempty"` // 为空时为 string
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required,omitempty"` // 必填（没有传入值、默认值和预设变量时报错）
+	Default     string        `json:"default,omitempty"`  // 默认值（优先于脚本预设变量）
+	Pattern     string        `json:"pattern,omitempty"`  // 正则表达式（string、file 校验整个值，list 校验每一项）
+	Enum        []string      `json:"enum,omitempty"`     // enum 类型的可选值
+	Format      string        `json:"format,omitempty"`   // date 类型的日期格式（Go 时间格式，默认 2006-01-02）
+}
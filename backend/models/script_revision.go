@@ -0,0 +1,220 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScriptRevision 脚本历史版本（只追加，不修改）
+type ScriptRevision struct {
+	ScriptID    string    `json:"script_id"`
+	Revision    int       `json:"revision"`         // 版本号（从 1 开始递增）
+	Author      string    `json:"author,omitempty"` // 修改人
+	Summary     string    `json:"summary"`          // 变更摘要
+	ActionCount int       `json:"action_count"`     // 操作数量
+	CreatedAt   time.Time `json:"created_at"`
+	Script      *Script   `json:"script,omitempty"` // 脚本快照（列表接口不返回）
+}
+
+// DiffOp 操作差异类型
+type DiffOp string
+
+const (
+	DiffAdded    DiffOp = "added"
+	DiffRemoved  DiffOp = "removed"
+	DiffModified DiffOp = "modified"
+)
+
+// ActionDiff 单个操作的差异
+type ActionDiff struct {
+	Op       DiffOp        `json:"op"`
+	OldIndex int           `json:"old_index"`        // 旧版本中的索引（新增时为 -1）
+	NewIndex int           `json:"new_index"`        // 新版本中的索引（删除时为 -1）
+	Fields   []string      `json:"fields,omitempty"` // modified: 变化的字段
+	Old      *ScriptAction `json:"old,omitempty"`
+	New      *ScriptAction `json:"new,omitempty"`
+}
+
+// ScriptDiff 两个脚本版本之间的差异
+type ScriptDiff struct {
+	FromRevision int          `json:"from_revision"`
+	ToRevision   int          `json:"to_revision"`
+	Fields       []string     `json:"fields,omitempty"` // 变化的脚本字段（不含 actions）
+	Actions      []ActionDiff `json:"actions"`          // 逐个操作的差异（未变化的操作不列出）
+	Added        int          `json:"added"`
+	Removed      int          `json:"removed"`
+	Modified     int          `json:"modified"`
+}
+
+// scriptDiffIgnoredFields 比较脚本内容时忽略的字段
+var scriptDiffIgnoredFields = map[string]bool{
+	"actions":    true,
+	"created_at": true,
+	"updated_at": true,
+	"revision":   true,
+}
+
+// SameContent 判断两个脚本内容是否相同（忽略时间戳和版本号）
+func (s *Script) SameContent(other *Script) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	a, b := *s, *other
+	a.CreatedAt, b.CreatedAt = time.Time{}, time.Time{}
+	a.UpdatedAt, b.UpdatedAt = time.Time{}, time.Time{}
+	a.Revision, b.Revision = 0, 0
+	return reflect.DeepEqual(toJSONMap(&a), toJSONMap(&b))
+}
+
+// DiffScripts 比较两个脚本版本（old 为空时视为新建）
+func DiffScripts(old, new *Script) ScriptDiff {
+	if old == nil {
+		old = &Script{}
+	}
+	if new == nil {
+		new = &Script{}
+	}
+	diff := ScriptDiff{
+		FromRevision: old.Revision,
+		ToRevision:   new.Revision,
+		Fields:       diffJSONFields(toJSONMap(old), toJSONMap(new), scriptDiffIgnoredFields),
+		Actions:      []ActionDiff{},
+	}
+
+	oldKeys := make([]map[string]interface{}, len(old.Actions))
+	for i := range old.Actions {
+		oldKeys[i] = toJSONMap(&old.Actions[i])
+	}
+	newKeys := make([]map[string]interface{}, len(new.Actions))
+	for i := range new.Actions {
+		newKeys[i] = toJSONMap(&new.Actions[i])
+	}
+
+	// 最长公共子序列对齐未变化的操作
+	n, m := len(oldKeys), len(newKeys)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if reflect.DeepEqual(oldKeys[i], newKeys[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// 两个未变化操作之间被删除和新增的操作按位置配对为修改
+	var removed, added []int
+	flush := func() {
+		paired := len(removed)
+		if len(added) < paired {
+			paired = len(added)
+		}
+		for k := 0; k < paired; k++ {
+			oi, ni := removed[k], added[k]
+			diff.Actions = append(diff.Actions, ActionDiff{
+				Op:       DiffModified,
+				OldIndex: oi,
+				NewIndex: ni,
+				Fields:   diffJSONFields(oldKeys[oi], newKeys[ni], nil),
+				Old:      &old.Actions[oi],
+				New:      &new.Actions[ni],
+			})
+			diff.Modified++
+		}
+		for _, oi := range removed[paired:] {
+			diff.Actions = append(diff.Actions, ActionDiff{Op: DiffRemoved, OldIndex: oi, NewIndex: -1, Old: &old.Actions[oi]})
+			diff.Removed++
+		}
+		for _, ni := range added[paired:] {
+			diff.Actions = append(diff.Actions, ActionDiff{Op: DiffAdded, OldIndex: -1, NewIndex: ni, New: &new.Actions[ni]})
+			diff.Added++
+		}
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && reflect.DeepEqual(oldKeys[i], newKeys[j]):
+			flush()
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, j)
+			j++
+		default:
+			removed = append(removed, i)
+			i++
+		}
+	}
+	flush()
+
+	return diff
+}
+
+// Summary 生成差异摘要，例如 "name changed; 2 actions added, 1 modified"
+func (d ScriptDiff) Summary() string {
+	var parts []string
+	if len(d.Fields) > 0 {
+		parts = append(parts, strings.Join(d.Fields, ", ")+" changed")
+	}
+	var actions []string
+	if d.Added > 0 {
+		actions = append(actions, fmt.Sprintf("%d added", d.Added))
+	}
+	if d.Removed > 0 {
+		actions = append(actions, fmt.Sprintf("%d removed", d.Removed))
+	}
+	if d.Modified > 0 {
+		actions = append(actions, fmt.Sprintf("%d modified", d.Modified))
+	}
+	if len(actions) > 0 {
+		parts = append(parts, "actions: "+strings.Join(actions, ", "))
+	}
+	if len(parts) == 0 {
+		return "No changes"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// toJSONMap 将结构体转换为 JSON 对象（用于按字段比较）
+func toJSONMap(v interface{}) map[string]interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// diffJSONFields 返回两个 JSON 对象中值不同的字段名（按字母排序）
+func diffJSONFields(a, b map[string]interface{}, ignored map[string]bool) []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, m := range []map[string]interface{}{a, b} {
+		for key := range m {
+			if seen[key] || ignored[key] {
+				continue
+			}
+			seen[key] = true
+			if !reflect.DeepEqual(a[key], b[key]) {
+				fields = append(fields, key)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffScripts(t *testing.T) {
+	click := ScriptAction{Type: "click", Selector: "#submit"}
+	input := ScriptAction{Type: "input", Selector: "#name", Value: "alice"}
+	wait := ScriptAction{Type: "sleep", Duration: 500}
+
+	old := &Script{Name: "login", URL: "https://example.com", Actions: []ScriptAction{input, click}}
+	renamed := input
+	renamed.Value = "bob"
+
+	tests := []struct {
+		name    string
+		new     *Script
+		ops     []DiffOp
+		fields  []string
+		summary string
+	}{
+		{"unchanged", &Script{Name: "login", URL: "https://example.com", Actions: []ScriptAction{input, click}}, nil, nil, "No changes"},
+		{"added", &Script{Name: "login", URL: "https://example.com", Actions: []ScriptAction{input, wait, click}}, []DiffOp{DiffAdded}, nil, "actions: 1 added"},
+		{"removed", &Script{Name: "login", URL: "https://example.com", Actions: []ScriptAction{click}}, []DiffOp{DiffRemoved}, nil, "actions: 1 removed"},
+		{"modified", &Script{Name: "login", URL: "https://example.com", Actions: []ScriptAction{renamed, click}}, []DiffOp{DiffModified}, nil, "actions: 1 modified"},
+		{"script fields", &Script{Name: "sign in", URL: "https://example.com", Actions: []ScriptAction{input, click}}, nil, []string{"name"}, "name changed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffScripts(old, tt.new)
+			var ops []DiffOp
+			for _, a := range diff.Actions {
+				ops = append(ops, a.Op)
+			}
+			if !reflect.DeepEqual(ops, tt.ops) {
+				t.Errorf("ops = %v, want %v", ops, tt.ops)
+			}
+			if !reflect.DeepEqual(diff.Fields, tt.fields) {
+				t.Errorf("fields = %v, want %v", diff.Fields, tt.fields)
+			}
+			if got := diff.Summary(); got != tt.summary {
+				t.Errorf("summary = %q, want %q", got, tt.summary)
+			}
+		})
+	}
+
+	modified := DiffScripts(old, &Script{Actions: []ScriptAction{renamed, click}}).Actions
+	if len(modified) != 1 || !reflect.DeepEqual(modified[0].Fields, []string{"value"}) || modified[0].OldIndex != 0 || modified[0].NewIndex != 0 {
+		t.Errorf("unexpected modified diff: %+v", modified)
+	}
+}
+
+func TestScriptSameContent(t *testing.T) {
+	a := &Script{ID: "s1", Name: "demo", Revision: 1, UpdatedAt: time.Now()}
+	b := &Script{ID: "s1", Name: "demo", Revision: 2, UpdatedAt: time.Now().Add(time.Hour)}
+	if !a.SameContent(b) {
+		t.Error("scripts differing only in revision and timestamps should have the same content")
+	}
+	b.Name = "other"
+	if a.SameContent(b) {
+		t.Error("scripts with different names should not have the same content")
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "testing"
+
+func TestErrorPolicyMerge(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	script := &ErrorPolicy{RetryCount: intPtr(3), RetryBackoff: 200, Timeout: intPtr(5000), OnError: OnErrorGoto, GotoLabel: "retry"}
+
+	tests := []struct {
+		name     string
+		base     *ErrorPolicy
+		override *ErrorPolicy
+		retries  int
+		backoff  int
+		timeout  int
+		onError  OnErrorAction
+		label    string
+	}{
+		{"no policies", nil, nil, 0, 0, 0, "", ""},
+		{"script only", script, nil, 3, 200, 5000, OnErrorGoto, "retry"},
+		{"action only", nil, &ErrorPolicy{RetryCount: intPtr(1)}, 1, 0, 0, "", ""},
+		{"unset fields inherit", script, &ErrorPolicy{RetryBackoff: 50}, 3, 50, 5000, OnErrorGoto, "retry"},
+		{"explicit zero disables retries and timeout", script, &ErrorPolicy{RetryCount: intPtr(0), Timeout: intPtr(0)}, 0, 200, 0, OnErrorGoto, "retry"},
+		{"on_error replaces its targets", script, &ErrorPolicy{OnError: OnErrorStop}, 3, 200, 5000, OnErrorStop, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.base.Merge(tt.override)
+			if got.Retries() != tt.retries || got.RetryBackoff != tt.backoff || got.TimeoutMs() != tt.timeout ||
+				got.OnError != tt.onError || got.GotoLabel != tt.label {
+				t.Errorf("Merge() = retries %d, backoff %d, timeout %d, on_error %q, label %q; want %d, %d, %d, %q, %q",
+					got.Retries(), got.RetryBackoff, got.TimeoutMs(), got.OnError, got.GotoLabel,
+					tt.retries, tt.backoff, tt.timeout, tt.onError, tt.label)
+			}
+		})
+	}
+
+	// 合并结果不影响脚本级策略
+	if *script.RetryCount != 3 {
+		t.Errorf("script policy modified: retry_count = %d", *script.RetryCount)
+	}
+}
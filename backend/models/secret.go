@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Secret 加密保存的密钥（脚本中通过 ${secret:name} 引用，回放时才解密）
+type Secret struct {
+	Name        string    `json:"name"`                  // 名称（唯一，字母、数字、_、-、.）
+	Description string    `json:"description,omitempty"` // 描述
+	Value       string    `json:"value,omitempty"`       // 加密后的值（接口返回时为空）
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,103 @@
+package dom
+
+import (
+	"strings"
+
+	"github.com/go-rod/rod"
+)
+
+// ExtractElementData 按提取类型（text, html, attribute, property）或字段列表提取元素数据
+func ExtractElementData(elem *rod.Element, extractType, attr string, fields []string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	switch extractType {
+	case "text":
+		text, err := elem.Text()
+		if err != nil {
+			return nil, err
+		}
+		data["text"] = text
+
+	case "html":
+		html, err := elem.HTML()
+		if err != nil {
+			return nil, err
+		}
+		data["html"] = html
+
+	case "attribute":
+		if attr != "" {
+			value, err := elem.Attribute(attr)
+			if err != nil || value == nil {
+				return nil, err
+			}
+			data[attr] = *value
+		}
+
+	case "property":
+		if attr != "" {
+			prop, err := elem.Property(attr)
+			if err != nil {
+				return nil, err
+			}
+			data[attr] = prop.String()
+		}
+
+	default:
+		// 提取指定字段
+		if len(fields) > 0 {
+			for _, field := range fields {
+				switch field {
+				case "text":
+					if text, err := elem.Text(); err == nil {
+						data["text"] = text
+					}
+				case "html":
+					if html, err := elem.HTML(); err == nil {
+						data["html"] = html
+					}
+				case "value":
+					if val, err := elem.Property("value"); err == nil {
+						data["value"] = val.String()
+					}
+				case "href":
+					if href, err := elem.Attribute("href"); err == nil && href != nil {
+						data["href"] = *href
+					}
+				case "src":
+					if src, err := elem.Attribute("src"); err == nil && src != nil {
+						data["src"] = *src
+					}
+				}
+			}
+		} else {
+			// 默认提取文本
+			text, err := elem.Text()
+			if err != nil {
+				return nil, err
+			}
+			data["text"] = text
+		}
+	}
+
+	return data, nil
+}
+
+// FieldValue 从 ExtractElementData 的结果中取出提取类型对应的值（字符串去除首尾空白，缺失时返回 nil）
+func FieldValue(data map[string]interface{}, extractType, attr string) interface{} {
+	key := extractType
+	switch extractType {
+	case "":
+		key = "text"
+	case "attribute", "property":
+		key = attr
+	}
+	value, ok := data[key]
+	if !ok {
+		return nil
+	}
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return value
+}
@@ -0,0 +1,31 @@
+package dom
+
+import "testing"
+
+func TestFieldValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        map[string]interface{}
+		extractType string
+		attr        string
+		want        interface{}
+	}{
+		{"default type is text", map[string]interface{}{"text": "  Shoes \n"}, "", "", "Shoes"},
+		{"text", map[string]interface{}{"text": "Shoes"}, "text", "", "Shoes"},
+		{"html is trimmed", map[string]interface{}{"html": " <b>x</b> "}, "html", "", "<b>x</b>"},
+		{"attribute", map[string]interface{}{"href": "/item/1"}, "attribute", "href", "/item/1"},
+		{"property", map[string]interface{}{"value": "42"}, "property", "value", "42"},
+		{"attribute does not read text", map[string]interface{}{"text": "Shoes"}, "attribute", "href", nil},
+		{"missing attribute", map[string]interface{}{}, "attribute", "src", nil},
+		{"missing text", nil, "text", "", nil},
+		{"non-string values are kept", map[string]interface{}{"text": 3}, "text", "", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FieldValue(tt.data, tt.extractType, tt.attr); got != tt.want {
+				t.Errorf("FieldValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,84 +1,84 @@
-package executor
+package dom
 
 import (
 	"fmt"
 	"strings"
 )
 
-// buildXPathFromRole 根据 ARIA role 和 name 构建 XPath 选择器
+// BuildXPathFromRole 根据 ARIA role 和 name 构建 XPath 选择器
 // 参考 agent-browser 和 Playwright 的实现
-func buildXPathFromRole(role, name string) string {
+func BuildXPathFromRole(role, name string) string {
 	role = strings.ToLower(strings.TrimSpace(role))
 	name = strings.TrimSpace(name)
-	
+
 	// 转义 XPath 中的引号
 	escapedName := escapeXPathString(name)
-	
+
 	switch role {
 	case "button":
 		return buildButtonXPath(escapedName)
-	
+
 	case "link":
 		return buildLinkXPath(escapedName)
-	
+
 	case "textbox", "searchbox":
 		return buildTextboxXPath(escapedName)
-	
+
 	case "checkbox":
 		return buildCheckboxXPath(escapedName)
-	
+
 	case "radio":
 		return buildRadioXPath(escapedName)
-	
+
 	case "combobox", "listbox":
 		return buildComboboxXPath(escapedName)
-	
+
 	case "heading":
 		return buildHeadingXPath(escapedName)
-	
+
 	case "list":
 		return buildListXPath(escapedName)
-	
+
 	case "listitem":
 		return buildListItemXPath(escapedName)
-	
+
 	case "cell", "gridcell":
 		return buildCellXPath(escapedName)
-	
+
 	case "row":
 		return buildRowXPath(escapedName)
-	
+
 	case "menuitem":
 		return buildMenuItemXPath(escapedName)
-	
+
 	case "tab":
 		return buildTabXPath(escapedName)
-	
+
 	case "article":
 		return buildArticleXPath(escapedName)
-	
+
 	case "region", "section":
 		return buildRegionXPath(escapedName)
-	
+
 	case "navigation", "nav":
 		return buildNavigationXPath(escapedName)
-	
+
 	case "main":
 		return buildMainXPath(escapedName)
-	
+
 	case "banner":
 		return buildBannerXPath(escapedName)
-	
+
 	case "contentinfo":
 		return buildContentinfoXPath(escapedName)
-	
+
 	case "complementary":
 		return buildComplementaryXPath(escapedName)
-	
+
 	default:
 		// 回退：通过 role 属性查找
 		if name != "" {
-			return fmt.Sprintf("//*[@role='%s' and (normalize-space(.)='%s' or @aria-label='%s')]", 
+			return fmt.Sprintf("//*[@role='%s' and (normalize-space(.)='%s' or @aria-label='%s')]",
 				role, escapedName, escapedName)
 		}
 		return fmt.Sprintf("//*[@role='%s']", role)
@@ -90,7 +90,7 @@ func buildButtonXPath(name string) string {
 	if name == "" {
 		return "//button | //input[@type='button'] | //input[@type='submit'] | //input[@type='reset'] | //*[@role='button']"
 	}
-	
+
 	// 对于长文本（>20字符），使用contains进行部分匹配
 	if len(name) > 20 {
 		shortName := name
@@ -107,7 +107,7 @@ func buildButtonXPath(name string) string {
 			//*[@role='button' and contains(@aria-label, '%s')]
 		)`, shortName, shortName, shortName, shortName, shortName, shortName, shortName)
 	}
-	
+
 	// 短文本使用精确匹配
 	// 多种查找方式：
 	// 1. <button>text</button>
@@ -130,7 +130,7 @@ func buildLinkXPath(name string) string {
 	if name == "" {
 		return "//a[@href] | //*[@role='link']"
 	}
-	
+
 	// 对于长文本（>30字符），使用contains进行部分匹配
 	// 对于短文本，使用精确匹配
 	if len(name) > 30 {
@@ -147,7 +147,7 @@ func buildLinkXPath(name string) string {
 			//*[@role='link' and contains(@aria-label, '%s')]
 		)`, shortName, shortName, shortName, shortName, shortName)
 	}
-	
+
 	// 短文本使用精确匹配
 	return fmt.Sprintf(`(
 		//a[@href and normalize-space(.)='%s'] |
@@ -175,7 +175,7 @@ func buildTextboxXPath(name string) string {
 			//*[@role='searchbox']
 		)`
 	}
-	
+
 	// 通过 placeholder、aria-label、或关联的 label 查找
 	return fmt.Sprintf(`(
 		//input[@type='text' and (@placeholder='%s' or @aria-label='%s')] |
@@ -191,7 +191,7 @@ func buildTextboxXPath(name string) string {
 		//*[@role='searchbox' and (@placeholder='%s' or @aria-label='%s')] |
 		//input[@id=//label[normalize-space(.)='%s']/@for]
 	)`,
-		name, name, name, name, name, name, name, name, name, name, name, name, name, name, 
+		name, name, name, name, name, name, name, name, name, name, name, name, name, name,
 		name, name, name, name, name, name, name, name, name)
 }
 
@@ -200,7 +200,7 @@ func buildCheckboxXPath(name string) string {
 	if name == "" {
 		return "//input[@type='checkbox'] | //*[@role='checkbox']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//input[@type='checkbox' and @aria-label='%s'] |
 		//input[@type='checkbox' and @id=//label[normalize-space(.)='%s']/@for] |
@@ -213,7 +213,7 @@ func buildRadioXPath(name string) string {
 	if name == "" {
 		return "//input[@type='radio'] | //*[@role='radio']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//input[@type='radio' and @aria-label='%s'] |
 		//input[@type='radio' and @id=//label[normalize-space(.)='%s']/@for] |
@@ -226,7 +226,7 @@ func buildComboboxXPath(name string) string {
 	if name == "" {
 		return "//select | //*[@role='combobox'] | //*[@role='listbox']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//select[@aria-label='%s'] |
 		//select[@id=//label[normalize-space(.)='%s']/@for] |
@@ -240,7 +240,7 @@ func buildHeadingXPath(name string) string {
 	if name == "" {
 		return "//h1 | //h2 | //h3 | //h4 | //h5 | //h6 | //*[@role='heading']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//h1[normalize-space(.)='%s'] |
 		//h2[normalize-space(.)='%s'] |
@@ -257,7 +257,7 @@ func buildListXPath(name string) string {
 	if name == "" {
 		return "//ul | //ol | //*[@role='list']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//ul[@aria-label='%s'] |
 		//ol[@aria-label='%s'] |
@@ -270,7 +270,7 @@ func buildListItemXPath(name string) string {
 	if name == "" {
 		return "//li | //*[@role='listitem']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//li[normalize-space(.)='%s'] |
 		//li[@aria-label='%s'] |
@@ -283,7 +283,7 @@ func buildCellXPath(name string) string {
 	if name == "" {
 		return "//td | //th | //*[@role='cell'] | //*[@role='gridcell']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//td[normalize-space(.)='%s'] |
 		//th[normalize-space(.)='%s'] |
@@ -297,7 +297,7 @@ func buildRowXPath(name string) string {
 	if name == "" {
 		return "//tr | //*[@role='row']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//tr[@aria-label='%s'] |
 		//*[@role='row' and @aria-label='%s']
@@ -309,7 +309,7 @@ func buildMenuItemXPath(name string) string {
 	if name == "" {
 		return "//*[@role='menuitem'] | //*[@role='menuitemcheckbox'] | //*[@role='menuitemradio']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//*[@role='menuitem' and (normalize-space(.)='%s' or @aria-label='%s')] |
 		//*[@role='menuitemcheckbox' and (normalize-space(.)='%s' or @aria-label='%s')] |
@@ -322,7 +322,7 @@ func buildTabXPath(name string) string {
 	if name == "" {
 		return "//*[@role='tab']"
 	}
-	
+
 	return fmt.Sprintf(`//*[@role='tab' and (normalize-space(.)='%s' or @aria-label='%s')]`, name, name)
 }
 
@@ -331,7 +331,7 @@ func buildArticleXPath(name string) string {
 	if name == "" {
 		return "//article | //*[@role='article']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//article[@aria-label='%s'] |
 		//*[@role='article' and @aria-label='%s']
@@ -343,7 +343,7 @@ func buildRegionXPath(name string) string {
 	if name == "" {
 		return "//section | //*[@role='region']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//section[@aria-label='%s'] |
 		//*[@role='region' and @aria-label='%s']
@@ -355,7 +355,7 @@ func buildNavigationXPath(name string) string {
 	if name == "" {
 		return "//nav | //*[@role='navigation']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//nav[@aria-label='%s'] |
 		//*[@role='navigation' and @aria-label='%s']
@@ -367,7 +367,7 @@ func buildMainXPath(name string) string {
 	if name == "" {
 		return "//main | //*[@role='main']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//main[@aria-label='%s'] |
 		//*[@role='main' and @aria-label='%s']
@@ -379,7 +379,7 @@ func buildBannerXPath(name string) string {
 	if name == "" {
 		return "//header | //*[@role='banner']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//header[@aria-label='%s'] |
 		//*[@role='banner' and @aria-label='%s']
@@ -391,7 +391,7 @@ func buildContentinfoXPath(name string) string {
 	if name == "" {
 		return "//footer | //*[@role='contentinfo']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//footer[@aria-label='%s'] |
 		//*[@role='contentinfo' and @aria-label='%s']
@@ -403,7 +403,7 @@ func buildComplementaryXPath(name string) string {
 	if name == "" {
 		return "//aside | //*[@role='complementary']"
 	}
-	
+
 	return fmt.Sprintf(`(
 		//aside[@aria-label='%s'] |
 		//*[@role='complementary' and @aria-label='%s']
@@ -417,12 +417,12 @@ func escapeXPathString(s string) string {
 	if !strings.Contains(s, "'") {
 		return s
 	}
-	
+
 	// 如果没有双引号，用双引号包裹
 	if !strings.Contains(s, "\"") {
 		return s
 	}
-	
+
 	// 如果既有单引号又有双引号，使用 concat 拼接
 	// 例如：He said "It's ok" -> concat('He said "It', "'", 's ok"')
 	parts := strings.Split(s, "'")
@@ -435,11 +435,11 @@ func escapeXPathString(s string) string {
 			result = append(result, "'"+part+"'")
 		}
 	}
-	
+
 	if len(result) == 0 {
 		return s
 	}
-	
+
 	// 简化：直接返回原字符串（XPath 会自动处理）
 	return s
 }
@@ -0,0 +1,314 @@
+package interpolate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// placeholderPattern 匹配 ${...} 占位符
+var placeholderPattern = regexp.MustCompile(`\$\{([^{}]+)\}`)
+
+// Lookup 变量查找函数，返回变量值及是否存在
+type Lookup func(name string) (interface{}, bool)
+
+// FromMap 基于字符串 map 构建变量查找函数
+func FromMap(vars map[string]string) Lookup {
+	return func(name string) (interface{}, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+// Chain 按顺序组合多个查找函数，返回第一个命中的结果
+func Chain(lookups ...Lookup) Lookup {
+	return func(name string) (interface{}, bool) {
+		for _, lookup := range lookups {
+			if lookup == nil {
+				continue
+			}
+			if value, ok := lookup(name); ok {
+				return value, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// Render 替换文本中的 ${name|filter:arg|...} 占位符
+// 支持的过滤器见 filters（参数中的 | 写作 \|）
+// 变量不存在且没有 default，或使用了未知过滤器时，占位符保持原样
+func Render(text string, lookup Lookup) string {
+	if !strings.Contains(text, "${") {
+		return text
+	}
+	return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		value, ok := Evaluate(placeholder[2:len(placeholder)-1], lookup)
+		if !ok {
+			return placeholder
+		}
+		return Stringify(value)
+	})
+}
+
+// Unresolved 返回文本中无法解析的占位符名称
+func Unresolved(text string, lookup Lookup) []string {
+	var names []string
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		if _, ok := Evaluate(match[1], lookup); !ok {
+			names = append(names, strings.TrimSpace(match[1]))
+		}
+	}
+	return names
+}
+
+// Reference 占位符引用的变量
+type Reference struct {
+	Name       string // 变量名
+	HasDefault bool   // 是否使用了 default 过滤器（变量不存在时仍可解析）
+}
+
+// filters Evaluate 支持的过滤器
+var filters = map[string]bool{
+	"default": true, "trim": true, "lower": true, "upper": true, "urlencode": true, "json": true,
+	"regex": true, "normalize": true, "number": true, "currency": true, "date": true, "split": true, "join": true,
+}
+
+// References 返回文本中占位符引用的变量（跳过使用未知过滤器的表达式，例如 JS 模板字符串中的 ${a || b}）
+func References(text string) []Reference {
+	var refs []Reference
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		parts := splitChain(match[1])
+		ref := Reference{Name: strings.TrimSpace(parts[0])}
+		valid := ref.Name != ""
+		for _, part := range parts[1:] {
+			filter, _, _ := strings.Cut(strings.TrimSpace(part), ":")
+			if !filters[filter] {
+				valid = false
+				break
+			}
+			if filter == "default" {
+				ref.HasDefault = true
+			}
+		}
+		if valid {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Evaluate 计算单个占位符表达式（不含 ${}），返回结果值及是否解析成功
+func Evaluate(expr string, lookup Lookup) (interface{}, bool) {
+	parts := splitChain(expr)
+	name := strings.TrimSpace(parts[0])
+	if name == "" {
+		return nil, false
+	}
+
+	var value interface{}
+	found := false
+	if lookup != nil {
+		value, found = lookup(name)
+	}
+
+	value, found, err := applyChain(value, found, parts[1:])
+	if err != nil {
+		// 未知过滤器（例如 JS 模板字符串中的 ||）或无效参数，整体视为未解析
+		return nil, false
+	}
+	return value, found
+}
+
+// splitChain 按 | 拆分过滤器链（\| 表示参数中的 |）
+func splitChain(expr string) []string {
+	if !strings.Contains(expr, `\|`) {
+		return strings.Split(expr, "|")
+	}
+	var parts []string
+	var current strings.Builder
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case expr[i] == '\\' && i+1 < len(expr) && expr[i+1] == '|':
+			current.WriteByte('|')
+			i++
+		case expr[i] == '|':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(expr[i])
+		}
+	}
+	return append(parts, current.String())
+}
+
+// applyChain 依次应用过滤器，返回结果值及是否存在
+func applyChain(value interface{}, found bool, parts []string) (interface{}, bool, error) {
+	for _, part := range parts {
+		filter, arg, _ := strings.Cut(strings.TrimSpace(part), ":")
+		var err error
+		value, found, err = applyFilter(filter, arg, value, found)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return value, found, nil
+}
+
+// applyFilter 应用单个过滤器（值不存在时只有 default 生效）
+func applyFilter(filter, arg string, value interface{}, found bool) (interface{}, bool, error) {
+	if filter == "default" {
+		if !found || isEmpty(value) {
+			return arg, true, nil
+		}
+		return value, true, nil
+	}
+	if !filters[filter] {
+		return nil, false, fmt.Errorf("%w: unknown filter %q", ErrInvalidTransform, filter)
+	}
+	if !found {
+		return value, false, nil
+	}
+
+	switch filter {
+	case "trim":
+		return mapValues(value, func(s string) (interface{}, bool) { return strings.TrimSpace(s), true })
+	case "normalize":
+		return mapValues(value, func(s string) (interface{}, bool) { return strings.Join(strings.Fields(s), " "), true })
+	case "lower":
+		return mapValues(value, func(s string) (interface{}, bool) { return strings.ToLower(s), true })
+	case "upper":
+		return mapValues(value, func(s string) (interface{}, bool) { return strings.ToUpper(s), true })
+	case "urlencode":
+		return url.QueryEscape(Stringify(value)), true, nil
+	case "json":
+		value, found = JSONPath(value, arg)
+		return value, found, nil
+	case "regex":
+		return regexFilter(value, arg)
+	case "number":
+		return mapValues(value, func(s string) (interface{}, bool) { return ParseNumber(s) })
+	case "currency":
+		return mapValues(value, func(s string) (interface{}, bool) { return parseCurrency(s, arg) })
+	case "date":
+		return dateFilter(value, arg)
+	case "split":
+		sep := separator(arg)
+		parts := strings.Split(Stringify(value), sep)
+		list := make([]interface{}, len(parts))
+		for i, part := range parts {
+			list[i] = part
+		}
+		return list, true, nil
+	case "join":
+		list, ok := normalizeJSON(value).([]interface{})
+		if !ok {
+			return Stringify(value), true, nil
+		}
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = Stringify(item)
+		}
+		return strings.Join(items, separator(arg)), true, nil
+	}
+	return value, found, nil
+}
+
+// JSONPath 按路径读取 JSON 数据，支持 "data.items.0.title" 和 "$.data.items[0].title" 两种写法
+// 字符串值会先尝试按 JSON 解析
+func JSONPath(value interface{}, path string) (interface{}, bool) {
+	current := normalizeJSON(value)
+
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			continue
+		}
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current = normalizeJSON(next)
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = normalizeJSON(node[index])
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Stringify 将任意值转换为字符串（非基础类型使用 JSON 编码）
+func Stringify(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int, int64, bool:
+		return fmt.Sprintf("%v", v)
+	case fmt.Stringer:
+		return v.String()
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// normalizeJSON 将值转换为通用 JSON 结构（map[string]interface{} / []interface{}）
+func normalizeJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}, []interface{}, nil, float64, bool:
+		return v
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(trimmed), &parsed); err == nil {
+				return parsed
+			}
+		}
+		return v
+	}
+
+	// 其他类型（如 gson.JSON、结构体、[]string）通过 JSON 编解码转换
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var parsed interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return value
+	}
+	return parsed
+}
+
+// isEmpty 判断值是否为空（nil、空白字符串或空列表）
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package interpolate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	vars := map[string]string{
+		"keyword": "  Go Rod  ",
+		"name":    "Alice",
+		"empty":   "",
+		"resp":    `{"data":{"items":[{"title":"first"},{"title":"second"}]}}`,
+	}
+	extracted := map[string]interface{}{
+		"xhr": map[string]interface{}{"total": float64(42)},
+	}
+	lookup := Chain(FromMap(vars), func(name string) (interface{}, bool) {
+		value, ok := extracted[name]
+		return value, ok
+	})
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Plain variable", input: "Hello ${name}", want: "Hello Alice"},
+		{name: "Trim and lower", input: "${keyword|trim|lower}", want: "go rod"},
+		{name: "Urlencode", input: "/search?q=${keyword|trim|urlencode}", want: "/search?q=Go+Rod"},
+		{name: "Default for missing", input: "${missing|default:guest}", want: "guest"},
+		{name: "Default for empty", input: "${empty|default:none}", want: "none"},
+		{name: "JSON path in string", input: "${resp|json:data.items.1.title}", want: "second"},
+		{name: "JSON path with brackets", input: "${resp|json:$.data.items[0].title|upper}", want: "FIRST"},
+		{name: "JSON path on extracted value", input: "total=${xhr|json:total}", want: "total=42"},
+		{name: "Unresolved kept", input: "${missing}", want: "${missing}"},
+		{name: "Unknown filter kept", input: "${name|reverse}", want: "${name|reverse}"},
+		{name: "JS template literal kept", input: "`${a || b}`", want: "`${a || b}`"},
+		{name: "Missing JSON path kept", input: "${resp|json:data.nope}", want: "${resp|json:data.nope}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.input, lookup); got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnresolved(t *testing.T) {
+	lookup := FromMap(map[string]string{"a": "1"})
+	got := Unresolved("${a} ${b} ${c|default:x} ${d|trim}", lookup)
+	if len(got) != 2 || got[0] != "b" || got[1] != "d|trim" {
+		t.Errorf("Unresolved() = %v, want [b d|trim]", got)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	got := References("${a} ${b|default:x|trim} `${c || d}` ${e|json:items.0}")
+	want := []Reference{{Name: "a"}, {Name: "b", HasDefault: true}, {Name: "e"}}
+	if len(got) != len(want) {
+		t.Fatalf("References() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("References()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	xhr := map[string]interface{}{"data": map[string]interface{}{"items": []interface{}{
+		map[string]interface{}{"price": "$1,299.00"},
+	}}}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		chain string
+		want  interface{}
+	}{
+		{name: "Regex capture", value: "Order #A-1234 shipped", chain: `regex:#([A-Z]-\d+)`, want: "A-1234"},
+		{name: "Regex alternation", value: "status: done", chain: `regex:(open\|done)`, want: "done"},
+		{name: "Normalize whitespace", value: "  Go \n\t Rod  ", chain: "normalize", want: "Go Rod"},
+		{name: "US number", value: "1,234.5 views", chain: "number", want: 1234.5},
+		{name: "European number", value: "1.234,50 €", chain: "number", want: 1234.5},
+		{name: "Decimal comma", value: "4,5", chain: "number", want: 4.5},
+		{name: "Thousands comma", value: "12,000", chain: "number", want: float64(12000)},
+		{name: "Spaced thousands", value: "1 234 567 items", chain: "number", want: float64(1234567)},
+		{name: "Suffix", value: "1.2K followers", chain: "number", want: float64(1200)},
+		{name: "Chinese suffix", value: "3.5万", chain: "number", want: float64(35000)},
+		{name: "Unit is not a suffix", value: "5MB", chain: "number", want: float64(5)},
+		{name: "Negative", value: "-$12.30", chain: "number", want: -12.3},
+		{name: "Currency symbol", value: "€ 1.299,00", chain: "currency", want: map[string]interface{}{"amount": 1299.0, "currency": "EUR"}},
+		{name: "Currency code", value: "1299 RMB", chain: "currency", want: map[string]interface{}{"amount": 1299.0, "currency": "CNY"}},
+		{name: "Currency default", value: "12", chain: "currency:gbp", want: map[string]interface{}{"amount": 12.0, "currency": "GBP"}},
+		{name: "Date with layout", value: " 03/04/2024 ", chain: "date:02/01/2006", want: "2024-04-03T00:00:00Z"},
+		{name: "Date output layout", value: "2024年3月5日", chain: "date:2006年1月2日=>2006-01-02", want: "2024-03-05"},
+		{name: "Date guessed", value: "Mar 5, 2024", chain: "date", want: "2024-03-05T00:00:00Z"},
+		{name: "Split trim number", value: "1, 2 ,x", chain: "split:,|trim|number", want: []interface{}{1.0, 2.0, nil}},
+		{name: "Split space and join", value: "a b c", chain: `split:\s|join:-`, want: "a-b-c"},
+		{name: "JSON path then number", value: xhr, chain: "json:$.data.items[0].price|number", want: 1299.0},
+		{name: "Default if empty", value: "   ", chain: "trim|default:N/A", want: "N/A"},
+		{name: "Default after failed parse", value: "n/a", chain: "number|default:0", want: "0"},
+		{name: "Empty chain", value: "raw", chain: "", want: "raw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Transform(tt.value, tt.chain)
+			if err != nil {
+				t.Fatalf("Transform: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Transform(%v, %q) = %#v, want %#v", tt.value, tt.chain, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Transform("abc", "number"); !errors.Is(err, ErrNoValue) {
+		t.Errorf("expected ErrNoValue, got %v", err)
+	}
+	if _, err := Transform("abc", "reverse"); !errors.Is(err, ErrInvalidTransform) {
+		t.Errorf("expected ErrInvalidTransform, got %v", err)
+	}
+	if err := ValidateChain("regex:(unclosed|number"); !errors.Is(err, ErrInvalidTransform) {
+		t.Errorf("expected ErrInvalidTransform for bad regex, got %v", err)
+	}
+	if err := ValidateChain(`trim|regex:(\d+)|number|default:0`); err != nil {
+		t.Errorf("ValidateChain: %v", err)
+	}
+
+	// 占位符中同样可以使用新过滤器
+	lookup := FromMap(map[string]string{"price": "Price: $1,299.00"})
+	if got := Render("${price|number}", lookup); got != "1299" {
+		t.Errorf("Render number = %q, want 1299", got)
+	}
+}
@@ -0,0 +1,250 @@
+package interpolate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidTransform = errors.New("invalid transform")
+	ErrNoValue          = errors.New("transform produced no value")
+)
+
+// Transform 对值依次应用过滤器链（与占位符过滤器相同，如 "regex:([\d.,]+)|number|default:0"）
+// 抓取操作、capture_xhr 和条件都使用同一套过滤器，转换结果保留类型（数字、列表、对象）
+func Transform(value interface{}, chain string) (interface{}, error) {
+	if strings.TrimSpace(chain) == "" {
+		return value, nil
+	}
+	result, found, err := applyChain(value, value != nil, splitChain(chain))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrNoValue, chain)
+	}
+	return result, nil
+}
+
+// ValidateChain 检查过滤器链（未知过滤器、无效正则）
+func ValidateChain(chain string) error {
+	if strings.TrimSpace(chain) == "" {
+		return nil
+	}
+	for _, part := range splitChain(chain) {
+		filter, arg, _ := strings.Cut(strings.TrimSpace(part), ":")
+		if !filters[filter] {
+			return fmt.Errorf("%w: unknown filter %q", ErrInvalidTransform, filter)
+		}
+		if filter == "regex" {
+			if _, err := compileRegex(arg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mapValues 对字符串值应用转换，列表逐项转换（无法转换的项为 nil）
+func mapValues(value interface{}, fn func(string) (interface{}, bool)) (interface{}, bool, error) {
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	case []string:
+		items = make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+	default:
+		result, ok := fn(Stringify(value))
+		return result, ok, nil
+	}
+
+	list := make([]interface{}, len(items))
+	for i, item := range items {
+		if result, ok := fn(Stringify(item)); ok {
+			list[i] = result
+		}
+	}
+	return list, true, nil
+}
+
+var regexCache sync.Map // 正则表达式 -> *regexp.Regexp
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid regex %q: %v", ErrInvalidTransform, pattern, err)
+	}
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+// regexFilter 返回第一个捕获组（没有捕获组时返回整个匹配），不匹配时值不存在
+func regexFilter(value interface{}, pattern string) (interface{}, bool, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return nil, false, err
+	}
+	return mapValues(value, func(s string) (interface{}, bool) {
+		match := re.FindStringSubmatch(s)
+		if match == nil {
+			return nil, false
+		}
+		if len(match) > 1 {
+			return match[1], true
+		}
+		return match[0], true
+	})
+}
+
+// numberPattern 匹配数字（允许千分位和小数分隔符，空格只作为三位一组的千分位）
+var numberPattern = regexp.MustCompile(`\d+(?:[.,'\x{00A0}\x{202F}]\d+| \d{3}\b)*`)
+
+// numberSuffixes 数字后缀的倍数（1.2k、3M、1.5万）
+var numberSuffixes = map[rune]float64{'k': 1e3, 'K': 1e3, 'm': 1e6, 'M': 1e6, 'b': 1e9, 'B': 1e9, '万': 1e4, '亿': 1e8}
+
+// ParseNumber 从文本中解析第一个数字，忽略货币符号和单位
+// 同时出现 . 和 , 时后出现的为小数点；只有 , 时，后面恰好三位数字视为千分位
+func ParseNumber(s string) (float64, bool) {
+	loc := numberPattern.FindStringIndex(s)
+	if loc == nil {
+		return 0, false
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r == '\'' || r == ' ' || r == '\u00a0' || r == '\u202f' {
+			return -1
+		}
+		return r
+	}, s[loc[0]:loc[1]])
+
+	lastDot, lastComma := strings.LastIndex(digits, "."), strings.LastIndex(digits, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			digits = strings.ReplaceAll(digits, ".", "")
+			digits = strings.Replace(digits, ",", ".", 1)
+		} else {
+			digits = strings.ReplaceAll(digits, ",", "")
+		}
+	case lastComma >= 0:
+		if strings.Count(digits, ",") == 1 && len(digits)-lastComma-1 != 3 {
+			digits = strings.Replace(digits, ",", ".", 1)
+		} else {
+			digits = strings.ReplaceAll(digits, ",", "")
+		}
+	case strings.Count(digits, ".") > 1:
+		digits = strings.ReplaceAll(digits, ".", "")
+	}
+
+	n, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return 0, false
+	}
+	if r, size := utf8.DecodeRuneInString(s[loc[1]:]); numberSuffixes[r] != 0 {
+		next, _ := utf8.DecodeRuneInString(s[loc[1]+size:])
+		if !unicode.IsLetter(r) || r > unicode.MaxASCII || !unicode.IsLetter(next) {
+			n *= numberSuffixes[r]
+		}
+	}
+	if negativePrefix(s[:loc[0]]) {
+		n = -n
+	}
+	return n, !math.IsInf(n, 0) && !math.IsNaN(n)
+}
+
+// negativePrefix 判断数字前（跳过空格和货币符号）是否为负号
+func negativePrefix(prefix string) bool {
+	for prefix != "" {
+		r, size := utf8.DecodeLastRuneInString(prefix)
+		switch {
+		case r == '-' || r == '−':
+			return true
+		case unicode.IsSpace(r) || unicode.Is(unicode.Sc, r):
+			prefix = prefix[:len(prefix)-size]
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// currencySymbols 货币符号（按顺序匹配，多字符符号在前）
+var currencySymbols = []struct{ symbol, code string }{
+	{"US$", "USD"}, {"HK$", "HKD"}, {"NT$", "TWD"}, {"A$", "AUD"}, {"C$", "CAD"}, {"S$", "SGD"}, {"R$", "BRL"},
+	{"$", "USD"}, {"€", "EUR"}, {"£", "GBP"}, {"￥", "CNY"}, {"¥", "CNY"}, {"元", "CNY"}, {"円", "JPY"},
+	{"₹", "INR"}, {"₩", "KRW"}, {"₽", "RUB"}, {"₺", "TRY"}, {"₫", "VND"}, {"฿", "THB"}, {"₱", "PHP"}, {"zł", "PLN"},
+}
+
+var currencyCodePattern = regexp.MustCompile(`\b(USD|EUR|GBP|CNY|RMB|JPY|HKD|TWD|AUD|CAD|SGD|BRL|INR|KRW|RUB|TRY|CHF|SEK|NOK|DKK|PLN|MXN|NZD|ZAR|THB|VND|PHP|IDR|MYR)\b`)
+
+// parseCurrency 解析金额和币种（ISO 代码优先于符号，都没有时使用 defaultCode）
+func parseCurrency(s, defaultCode string) (interface{}, bool) {
+	amount, ok := ParseNumber(s)
+	if !ok {
+		return nil, false
+	}
+	code := strings.ToUpper(strings.TrimSpace(defaultCode))
+	if match := currencyCodePattern.FindString(s); match != "" {
+		code = match
+		if code == "RMB" {
+			code = "CNY"
+		}
+	} else {
+		for _, c := range currencySymbols {
+			if strings.Contains(s, c.symbol) {
+				code = c.code
+				break
+			}
+		}
+	}
+	return map[string]interface{}{"amount": amount, "currency": code}, true
+}
+
+// dateLayouts 未指定格式时依次尝试的日期格式
+var dateLayouts = []string{
+	time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02", "2006/01/02", "2006.01.02",
+	"2006年1月2日", "01/02/2006", "Jan 2, 2006", "January 2, 2006", "2 Jan 2006", "2 January 2006", time.RFC1123,
+}
+
+// dateFilter 按格式解析日期（Go 时间格式，"输入格式=>输出格式"，输出默认 RFC3339），无法解析时值不存在
+func dateFilter(value interface{}, arg string) (interface{}, bool, error) {
+	layout, output, ok := strings.Cut(arg, "=>")
+	layout = strings.TrimSpace(layout)
+	if !ok || strings.TrimSpace(output) == "" {
+		output = time.RFC3339
+	}
+	return mapValues(value, func(s string) (interface{}, bool) {
+		s = strings.TrimSpace(s)
+		layouts := dateLayouts
+		if layout != "" {
+			layouts = []string{layout}
+		}
+		for _, l := range layouts {
+			if t, err := time.Parse(l, s); err == nil {
+				return t.Format(output), true
+			}
+		}
+		return nil, false
+	})
+}
+
+// separator split / join 的分隔符（默认逗号，\s 表示空格，\t、\n 表示制表符和换行）
+func separator(arg string) string {
+	if arg == "" {
+		return ","
+	}
+	return strings.NewReplacer(`\s`, " ", `\t`, "\t", `\n`, "\n").Replace(arg)
+}
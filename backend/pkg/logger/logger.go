@@ -4,6 +4,7 @@ import (
 	"context"
 	"runtime"
 	"strings"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -118,9 +119,43 @@ func InitLogger(cfg *LoggerConfig) {
 		})
 	}
 
+	log.AddHook(redactHook{})
+
 	defaultLogger = &logrusLogger{logger: log}
 }
 
+// redactor 日志脱敏函数（例如替换密钥值），未设置时不处理
+var redactor atomic.Pointer[func(string) string]
+
+// SetRedactor 设置日志脱敏函数，所有日志消息在输出前都会经过该函数
+func SetRedactor(fn func(string) string) {
+	if fn == nil {
+		redactor.Store(nil)
+		return
+	}
+	redactor.Store(&fn)
+}
+
+// Redact 使用当前的脱敏函数处理文本
+func Redact(text string) string {
+	if fn := redactor.Load(); fn != nil {
+		return (*fn)(text)
+	}
+	return text
+}
+
+// redactHook 在日志输出前脱敏消息
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = Redact(entry.Message)
+	return nil
+}
+
 func Warn(ctx context.Context, msg string, args ...any) {
 	defaultLogger.Warn(ctx, msg, args...)
 }
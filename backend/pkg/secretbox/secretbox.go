@@ -0,0 +1,147 @@
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// 加密值的前缀（带版本号，便于以后更换算法）
+const (
+	sealedPrefix       = "enc:v2:" // enc:v2:<base64(salt|nonce|密文)>
+	legacySealedPrefix = "enc:v1:" // enc:v1:<base64(nonce|密文)>，使用固定盐值派生的密钥
+)
+
+// 密钥派生参数
+const (
+	saltSize       = 16
+	legacyKDFSalt  = "browserwing-secrets" // v1 使用的固定盐值，仅用于解密旧数据
+	kdfIterations  = 100000
+	derivedKeySize = 32
+)
+
+var (
+	ErrEmptyKey      = errors.New("secret key is empty")
+	ErrDecryptFailed = errors.New("failed to decrypt value")
+)
+
+// Box 使用 AES-256-GCM 加解密字符串
+// 每个 Box 生成一个随机盐值用于加密，盐值随密文保存；解密时按密文中的盐值派生密钥（结果按盐值缓存）
+type Box struct {
+	key  string
+	salt []byte
+
+	mu    sync.Mutex
+	aeads map[string]cipher.AEAD // 盐值 -> 派生的加密器
+}
+
+// New 由配置的密钥（口令）创建加解密器
+func New(key string) (*Box, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	b := &Box{key: key, salt: salt, aeads: make(map[string]cipher.AEAD)}
+	if _, err := b.aead(salt); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// aead 返回盐值对应的加密器（首次使用时派生密钥）
+func (b *Box) aead(salt []byte) (cipher.AEAD, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if aead, ok := b.aeads[string(salt)]; ok {
+		return aead, nil
+	}
+	derived, err := pbkdf2.Key(sha256.New, b.key, salt, kdfIterations, derivedKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	b.aeads[string(salt)] = aead
+	return aead, nil
+}
+
+// Seal 加密字符串，返回 enc:v2:<base64(salt|nonce|密文)>
+func (b *Box) Seal(plaintext string) (string, error) {
+	aead, err := b.aead(b.salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	data := append([]byte{}, b.salt...)
+	data = append(data, nonce...)
+	data = aead.Seal(data, nonce, []byte(plaintext), nil)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Open 解密 Seal 的结果（密钥错误或数据被篡改时返回 ErrDecryptFailed）
+func (b *Box) Open(sealed string) (string, error) {
+	var salt []byte
+	var encoded string
+	switch {
+	case strings.HasPrefix(sealed, sealedPrefix):
+		encoded = strings.TrimPrefix(sealed, sealedPrefix)
+	case strings.HasPrefix(sealed, legacySealedPrefix):
+		encoded = strings.TrimPrefix(sealed, legacySealedPrefix)
+		salt = []byte(legacyKDFSalt)
+	default:
+		return "", fmt.Errorf("%w: value is not encrypted", ErrDecryptFailed)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed value", ErrDecryptFailed)
+	}
+	if salt == nil {
+		if len(data) < saltSize {
+			return "", fmt.Errorf("%w: malformed value", ErrDecryptFailed)
+		}
+		salt, data = data[:saltSize], data[saltSize:]
+	}
+
+	aead, err := b.aead(salt)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < aead.NonceSize() {
+		return "", fmt.Errorf("%w: malformed value", ErrDecryptFailed)
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: wrong key or corrupted data", ErrDecryptFailed)
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed 判断值是否是 Seal 的结果（包括旧版本格式）
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix) || strings.HasPrefix(value, legacySealedPrefix)
+}
+
+// IsLegacy 判断值是否是使用固定盐值的旧版本格式（应解密后重新加密）
+func IsLegacy(value string) bool {
+	return strings.HasPrefix(value, legacySealedPrefix)
+}
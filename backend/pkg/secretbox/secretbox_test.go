@@ -0,0 +1,87 @@
+package secretbox
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	box, err := New("passphrase")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	sealed, err := box.Seal("hunter2")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, "hunter2") {
+		t.Fatalf("sealed = %q", sealed)
+	}
+	if again, _ := box.Seal("hunter2"); again == sealed {
+		t.Errorf("nonce reused: %q", again)
+	}
+
+	// 同一密钥重新派生后仍可解密
+	reopened, _ := New("passphrase")
+	if plain, err := reopened.Open(sealed); err != nil || plain != "hunter2" {
+		t.Errorf("Open = %q, %v", plain, err)
+	}
+
+	other, _ := New("other")
+	if _, err := other.Open(sealed); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("wrong key: %v", err)
+	}
+	if _, err := box.Open("plain text"); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("plain value: %v", err)
+	}
+	if _, err := New(""); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("empty key: %v", err)
+	}
+}
+
+func TestRandomSalt(t *testing.T) {
+	a, _ := New("passphrase")
+	b, _ := New("passphrase")
+	sealedA, _ := a.Seal("hunter2")
+	sealedB, _ := b.Seal("hunter2")
+
+	// 不同实例使用不同的盐值，但同一口令可以互相解密
+	saltA, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealedA, sealedPrefix))
+	saltB, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealedB, sealedPrefix))
+	if bytes.Equal(saltA[:saltSize], saltB[:saltSize]) {
+		t.Errorf("salt reused across boxes")
+	}
+	if plain, err := b.Open(sealedA); err != nil || plain != "hunter2" {
+		t.Errorf("Open = %q, %v", plain, err)
+	}
+	if IsLegacy(sealedA) {
+		t.Errorf("IsLegacy(%q) = true", sealedA)
+	}
+}
+
+func TestOpenLegacy(t *testing.T) {
+	// 旧版本格式：固定盐值派生的密钥，密文为 enc:v1:<base64(nonce|密文)>
+	derived, err := pbkdf2.Key(sha256.New, "passphrase", []byte(legacyKDFSalt), kdfIterations, derivedKeySize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := aes.NewCipher(derived)
+	aead, _ := cipher.NewGCM(block)
+	nonce := make([]byte, aead.NonceSize())
+	legacy := legacySealedPrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte("hunter2"), nil))
+
+	box, _ := New("passphrase")
+	if !IsSealed(legacy) || !IsLegacy(legacy) {
+		t.Fatalf("legacy value not recognized: %q", legacy)
+	}
+	if plain, err := box.Open(legacy); err != nil || plain != "hunter2" {
+		t.Errorf("Open(legacy) = %q, %v", plain, err)
+	}
+}
@@ -9,13 +9,15 @@ import (
 
 	"github.com/browserwing/browserwing/agent"
 	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/params"
+	"github.com/browserwing/browserwing/services/runqueue"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/go-rod/rod"
 )
 
 // ScriptPlayer 脚本播放器接口
 type ScriptPlayer interface {
-	PlayScript(scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error)
+	PlayScript(ctx context.Context, scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error)
 }
 
 // AgentExecutor Agent 执行器接口
@@ -48,7 +50,7 @@ func (e *DefaultTaskExecutor) ExecuteScript(ctx context.Context, task *models.Sc
 	log.Printf("[TaskExecutor] Executing script task: %s (script: %s)", task.Name, task.ScriptID)
 
 	// 执行脚本
-	result, err := e.scriptPlayer.PlayScript(task.ScriptID, task.ScriptVariables, task.BrowserInstanceID)
+	result, err := e.scriptPlayer.PlayScript(ctx, task.ScriptID, task.ScriptVariables, task.BrowserInstanceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute script: %w", err)
 	}
@@ -114,8 +116,8 @@ func NewRealScriptPlayer(db *storage.BoltDB, browserManager interface{}) *RealSc
 	}
 }
 
-// PlayScript 播放脚本
-func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]string, instanceID string) (result *models.PlayResult, err error) {
+// PlayScript 播放脚本（ctx 取消或超时时中止回放）
+func (p *RealScriptPlayer) PlayScript(ctx context.Context, scriptID string, variables map[string]string, instanceID string) (result *models.PlayResult, err error) {
 	// 添加 recover 捕获 panic
 	defer func() {
 		if r := recover(); r != nil {
@@ -125,8 +127,6 @@ func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]stri
 		}
 	}()
 
-	ctx := context.Background()
-
 	// 获取脚本
 	script, err := p.db.GetScript(scriptID)
 	if err != nil {
@@ -135,6 +135,13 @@ func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]stri
 
 	log.Printf("[RealScriptPlayer] Playing script: %s (ID: %s)", script.Name, scriptID)
 
+	// 创建脚本副本并合并参数：先使用脚本预设变量，再用外部传入的参数覆盖（按参数定义校验）
+	// 占位符由 Player 在执行时统一解析
+	scriptToRun := script.Copy()
+	if scriptToRun.Variables, err = params.ResolveStrings(script, variables); err != nil {
+		return nil, err
+	}
+
 	// 类型断言获取 browserManager（使用接口定义避免循环依赖）
 	type browserMgr interface {
 		IsRunning() bool
@@ -158,45 +165,8 @@ func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]stri
 		}
 	}
 
-	// 创建脚本副本并替换变量
-	scriptToRun := script.Copy()
-
-	// 合并参数：先使用脚本预设变量，再用外部传入的参数覆盖
-	mergedParams := make(map[string]string)
-	if scriptToRun.Variables != nil {
-		for key, value := range scriptToRun.Variables {
-			mergedParams[key] = value
-		}
-	}
-	for key, value := range variables {
-		mergedParams[key] = value
-	}
-
-	// 替换占位符
-	if len(mergedParams) > 0 {
-		scriptToRun.URL = replacePlaceholders(scriptToRun.URL, mergedParams)
-		scriptToRun.Actions = make([]models.ScriptAction, len(script.Actions))
-		copy(scriptToRun.Actions, script.Actions)
-
-		for i := range scriptToRun.Actions {
-			scriptToRun.Actions[i].Selector = replacePlaceholders(scriptToRun.Actions[i].Selector, mergedParams)
-			scriptToRun.Actions[i].XPath = replacePlaceholders(scriptToRun.Actions[i].XPath, mergedParams)
-			scriptToRun.Actions[i].Value = replacePlaceholders(scriptToRun.Actions[i].Value, mergedParams)
-			scriptToRun.Actions[i].URL = replacePlaceholders(scriptToRun.Actions[i].URL, mergedParams)
-			scriptToRun.Actions[i].JSCode = replacePlaceholders(scriptToRun.Actions[i].JSCode, mergedParams)
-
-			if len(scriptToRun.Actions[i].FilePaths) > 0 {
-				newFilePaths := make([]string, len(scriptToRun.Actions[i].FilePaths))
-				for j, path := range scriptToRun.Actions[i].FilePaths {
-					newFilePaths[j] = replacePlaceholders(path, mergedParams)
-				}
-				scriptToRun.Actions[i].FilePaths = newFilePaths
-			}
-		}
-	}
-
-	// 执行脚本
-	result, page, err := bm.PlayScript(ctx, scriptToRun, instanceID)
+	// 执行脚本（与 API、MCP 的回放按来源轮流排队）
+	result, page, err := bm.PlayScript(runqueue.WithSource(ctx, runqueue.SourceScheduler), scriptToRun, instanceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute script: %w", err)
 	}
@@ -209,20 +179,6 @@ func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]stri
 	return result, nil
 }
 
-// replacePlaceholders 替换占位符 ${xxx}
-func replacePlaceholders(text string, params map[string]string) string {
-	if text == "" || len(params) == 0 {
-		return text
-	}
-
-	result := text
-	for key, value := range params {
-		placeholder := fmt.Sprintf("${%s}", key)
-		result = strings.ReplaceAll(result, placeholder, value)
-	}
-	return result
-}
-
 // RealAgentExecutor 真实 Agent 执行器（使用 Agent 管理器）
 type RealAgentExecutor struct {
 	agentManager interface{} // 使用 interface{} 避免循环依赖
@@ -311,7 +267,7 @@ func NewSimpleScriptPlayer(db *storage.BoltDB) *SimpleScriptPlayer {
 }
 
 // PlayScript 播放脚本
-func (p *SimpleScriptPlayer) PlayScript(scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error) {
+func (p *SimpleScriptPlayer) PlayScript(ctx context.Context, scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error) {
 	// 这是一个简化的实现，仅用于测试
 	script, err := p.db.GetScript(scriptID)
 	if err != nil {
@@ -9,10 +9,15 @@ import (
 	"time"
 
 	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/runqueue"
+	"github.com/browserwing/browserwing/services/sinks"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/robfig/cron/v3"
 )
 
+// taskTimeout 单次任务执行的超时时间
+const taskTimeout = 5 * time.Minute
+
 // TaskExecutor 任务执行器接口
 type TaskExecutor interface {
 	ExecuteScript(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error)
@@ -29,6 +34,7 @@ type Scheduler struct {
 	stopCh   chan struct{}
 	ctx      context.Context
 	cancel   context.CancelFunc
+	outputs  *sinks.Writer // 任务执行结果的输出写入器
 }
 
 // NewScheduler 创建新的调度器
@@ -49,6 +55,11 @@ func NewScheduler(db *storage.BoltDB, executor TaskExecutor) *Scheduler {
 	}
 }
 
+// SetOutputWriter 设置任务执行结果的输出写入器
+func (s *Scheduler) SetOutputWriter(outputs *sinks.Writer) {
+	s.outputs = outputs
+}
+
 // Start 启动调度器
 func (s *Scheduler) Start() error {
 	log.Println("[Scheduler] Starting scheduler...")
@@ -268,17 +279,20 @@ func (s *Scheduler) executeTask(task *models.ScheduledTask) {
 	var resultData map[string]interface{}
 	var err error
 
-	// 执行任务
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute) // 5分钟超时
+	// 执行任务（调度器停止时一并取消）
+	ctx, cancel := context.WithCancel(s.ctx)
 	defer cancel()
 
 	switch task.ExecutionType {
 	case models.ExecutionTypeScript:
 		execution.ScriptID = task.ScriptID
-		resultData, err = s.executor.ExecuteScript(ctx, task)
+		// 回放的超时从取得浏览器实例名额后开始计时，排在繁忙实例后面的任务不会因排队而超时
+		resultData, err = s.executor.ExecuteScript(runqueue.WithRunTimeout(ctx, taskTimeout), task)
 	case models.ExecutionTypeAgent:
 		execution.AgentSessionID = task.AgentSessionID
-		resultData, err = s.executor.ExecuteAgent(ctx, task)
+		agentCtx, cancelAgent := context.WithTimeout(ctx, taskTimeout)
+		resultData, err = s.executor.ExecuteAgent(agentCtx, task)
+		cancelAgent()
 	default:
 		err = fmt.Errorf("unknown execution type: %s", task.ExecutionType)
 	}
@@ -303,6 +317,26 @@ func (s *Scheduler) executeTask(task *models.ScheduledTask) {
 		log.Printf("[Scheduler] Failed to save execution record: %v", err)
 	}
 
+	// 写入任务声明的输出目标（执行出错时也写入，success 为 false 并附带错误信息）
+	if len(task.OutputSinks) > 0 {
+		if resultData == nil {
+			resultData = map[string]interface{}{}
+		}
+		record := &models.OutputRecord{
+			Timestamp:   execution.EndTime,
+			ScriptID:    task.ScriptID,
+			ScriptName:  task.ScriptName,
+			ExecutionID: execution.ID,
+			TaskID:      task.ID,
+			Success:     execution.Success,
+			Error:       execution.ErrorMsg,
+			Data:        resultData,
+		}
+		if err := s.outputs.Write(s.ctx, task.OutputSinks, record); err != nil {
+			log.Printf("[Scheduler] Failed to write task output: %v", err)
+		}
+	}
+
 	// 更新任务统计
 	s.updateTaskStats(task, execution.Success)
 
@@ -225,17 +225,18 @@ func (sc *ScriptClient) Play(ctx context.Context, scriptID string) (*ScriptExecu
 
 	// 保存执行记录到数据库
 	dbExecution := &models.ScriptExecution{
-		ID:            execution.ID,
-		ScriptID:      execution.ScriptID,
-		ScriptName:    execution.ScriptName,
-		StartTime:     time.Unix(execution.StartTime, 0),
-		EndTime:       time.Unix(execution.EndTime, 0),
-		Duration:      execution.Duration,
-		Success:       result.Success,
-		Message:       result.Message,
-		ErrorMsg:      execution.Error,
-		ExtractedData: result.ExtractedData,
-		CreatedAt:     time.Now(),
+		ID:             execution.ID,
+		ScriptID:       execution.ScriptID,
+		ScriptName:     execution.ScriptName,
+		ScriptRevision: dbScript.Revision,
+		StartTime:      time.Unix(execution.StartTime, 0),
+		EndTime:        time.Unix(execution.EndTime, 0),
+		Duration:       execution.Duration,
+		Success:        result.Success,
+		Message:        result.Message,
+		ErrorMsg:       execution.Error,
+		ExtractedData:  result.ExtractedData,
+		CreatedAt:      time.Now(),
 	}
 
 	if err := sc.client.db.SaveScriptExecution(dbExecution); err != nil {
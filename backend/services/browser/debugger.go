@@ -0,0 +1,313 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// debugSessionRetention 调试回放结束后保留会话状态的时长
+const debugSessionRetention = 10 * time.Minute
+
+var (
+	// ErrDebugSessionNotFound 调试会话不存在或已过期
+	ErrDebugSessionNotFound = errors.New("debug session not found")
+	// ErrDebugNotPaused 调试会话未处于暂停状态，无法执行命令
+	ErrDebugNotPaused = errors.New("debug session is not paused")
+)
+
+type debugSessionKey struct{}
+
+// withDebugSession 将调试会话附加到回放上下文
+func withDebugSession(ctx context.Context, session *debugSession) context.Context {
+	return context.WithValue(ctx, debugSessionKey{}, session)
+}
+
+// debugSessionFromContext 获取回放上下文中的调试会话
+func debugSessionFromContext(ctx context.Context) *debugSession {
+	session, _ := ctx.Value(debugSessionKey{}).(*debugSession)
+	return session
+}
+
+// debugSession 单次调试回放的会话：断点、暂停状态、命令通道和状态订阅
+type debugSession struct {
+	mu             sync.Mutex
+	state          models.DebugState
+	breakpoints    map[int]bool
+	breakOnFailure bool
+	stepping       bool // 在下一步前暂停
+	runOnce        bool // 下一次暂停检查直接放行（失败后重试的步骤不再暂停）
+	commands       chan models.DebugCommand
+	subscribers    map[chan models.DebugState]struct{}
+}
+
+func newDebugSession(executionID, scriptID string, totalSteps int, opts models.DebugOptions) *debugSession {
+	d := &debugSession{
+		breakpoints:    make(map[int]bool),
+		breakOnFailure: opts.BreakOnFailure,
+		stepping:       opts.PauseOnStart,
+		commands:       make(chan models.DebugCommand, 1),
+		subscribers:    make(map[chan models.DebugState]struct{}),
+	}
+	for _, index := range opts.Breakpoints {
+		d.breakpoints[index] = true
+	}
+	d.state = models.DebugState{
+		ExecutionID: executionID,
+		ScriptID:    scriptID,
+		Status:      models.DebugStatusRunning,
+		TotalSteps:  totalSteps,
+	}
+	return d
+}
+
+// shouldPause 判断是否需要在步骤执行前暂停
+func (d *debugSession) shouldPause(index int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.runOnce {
+		d.runOnce = false
+		return false
+	}
+	return d.stepping || d.breakpoints[index]
+}
+
+// skipNextPause 下一次暂停检查直接放行
+func (d *debugSession) skipNextPause() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.runOnce = true
+}
+
+// shouldBreakOnFailure 判断步骤失败后是否暂停
+func (d *debugSession) shouldBreakOnFailure() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.breakOnFailure
+}
+
+// pause 进入暂停状态并等待调试命令（上下文取消时返回错误）
+func (d *debugSession) pause(ctx context.Context, state models.DebugState) (models.DebugCommand, error) {
+	d.mu.Lock()
+	state.ExecutionID = d.state.ExecutionID
+	state.ScriptID = d.state.ScriptID
+	state.Status = models.DebugStatusPaused
+	state.EditedActions = d.state.EditedActions
+	d.state = state
+	d.publishLocked()
+	d.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return models.DebugCommand{}, ctx.Err()
+	case cmd := <-d.commands:
+		d.mu.Lock()
+		// resume 运行到下一个断点，其余命令在下一步前再次暂停
+		d.stepping = cmd.Command != models.DebugCommandResume
+		d.state.Status = models.DebugStatusRunning
+		d.state.LastError = ""
+		d.publishLocked()
+		d.mu.Unlock()
+		return cmd, nil
+	}
+}
+
+// sendCommand 发送调试命令
+func (d *debugSession) sendCommand(cmd models.DebugCommand) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.state.Status == models.DebugStatusFinished {
+		return ErrDebugNotPaused
+	}
+	if cmd.Command == models.DebugCommandPause {
+		d.stepping = true
+		return nil
+	}
+
+	switch cmd.Command {
+	case models.DebugCommandStep, models.DebugCommandResume, models.DebugCommandSkip, models.DebugCommandRetry:
+	default:
+		return fmt.Errorf("unknown debug command: %s", cmd.Command)
+	}
+	if cmd.Action != nil && cmd.Command != models.DebugCommandRetry {
+		return fmt.Errorf("action can only be edited with the retry command")
+	}
+	if d.state.Status != models.DebugStatusPaused {
+		return ErrDebugNotPaused
+	}
+
+	select {
+	case d.commands <- cmd:
+		return nil
+	default:
+		// 上一条命令尚未被处理
+		return ErrDebugNotPaused
+	}
+}
+
+// setBreakpoints 替换断点
+func (d *debugSession) setBreakpoints(breakpoints []int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.breakpoints = make(map[int]bool, len(breakpoints))
+	for _, index := range breakpoints {
+		d.breakpoints[index] = true
+	}
+	d.publishLocked()
+}
+
+// recordEdit 记录调试过程中修改过的操作
+func (d *debugSession) recordEdit(index int, action models.ScriptAction) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.state.EditedActions == nil {
+		d.state.EditedActions = make(map[int]models.ScriptAction)
+	}
+	d.state.EditedActions[index] = action
+}
+
+// finish 标记回放结束并关闭所有订阅
+func (d *debugSession) finish(result *models.PlayResult, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.state.Status = models.DebugStatusFinished
+	d.state.Action = nil
+	d.state.Result = result
+	if err != nil {
+		d.state.Error = err.Error()
+	}
+	d.publishLocked()
+	for ch := range d.subscribers {
+		close(ch)
+	}
+	d.subscribers = make(map[chan models.DebugState]struct{})
+}
+
+// snapshot 获取当前状态
+func (d *debugSession) snapshot() models.DebugState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.snapshotLocked()
+}
+
+func (d *debugSession) snapshotLocked() models.DebugState {
+	state := d.state
+	state.BreakOnFailure = d.breakOnFailure
+	state.Breakpoints = make([]int, 0, len(d.breakpoints))
+	for index := range d.breakpoints {
+		state.Breakpoints = append(state.Breakpoints, index)
+	}
+	sort.Ints(state.Breakpoints)
+	if d.state.EditedActions != nil {
+		state.EditedActions = make(map[int]models.ScriptAction, len(d.state.EditedActions))
+		for index, action := range d.state.EditedActions {
+			state.EditedActions[index] = action
+		}
+	}
+	return state
+}
+
+// subscribe 订阅状态变化，订阅后立即收到当前状态；回放结束后通道关闭
+func (d *debugSession) subscribe() (<-chan models.DebugState, func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ch := make(chan models.DebugState, 16)
+	ch <- d.snapshotLocked()
+	if d.state.Status == models.DebugStatusFinished {
+		close(ch)
+		return ch, func() {}
+	}
+
+	d.subscribers[ch] = struct{}{}
+	return ch, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if _, ok := d.subscribers[ch]; ok {
+			delete(d.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// publishLocked 向订阅者推送当前状态（订阅者处理过慢时丢弃）
+func (d *debugSession) publishLocked() {
+	state := d.snapshotLocked()
+	for ch := range d.subscribers {
+		select {
+		case ch <- state:
+		default:
+		}
+	}
+}
+
+// StartDebugSession 以调试模式异步回放脚本，返回执行 ID（通过取消接口结束调试）
+func (m *Manager) StartDebugSession(script *models.Script, instanceID string, opts models.DebugOptions) (string, error) {
+	executionID := fmt.Sprintf("%s-debug-%d", script.ID, time.Now().UnixNano())
+	session := newDebugSession(executionID, script.ID, len(script.Actions), opts)
+	m.runs.addDebug(executionID, session)
+
+	ctx := withDebugSession(WithExecutionID(context.Background(), executionID), session)
+	go func() {
+		result, page, err := m.PlayScript(ctx, script, instanceID)
+		if page != nil {
+			if closeErr := m.CloseActivePage(ctx, page); closeErr != nil {
+				logger.Warn(ctx, "Failed to close debug page: %v", closeErr)
+			}
+		}
+		session.finish(result, err)
+		logger.Info(ctx, "Debug session finished: %s", executionID)
+
+		time.AfterFunc(debugSessionRetention, func() {
+			m.runs.removeDebug(executionID)
+		})
+	}()
+
+	logger.Info(ctx, "Debug session started: %s (script: %s)", executionID, script.Name)
+	return executionID, nil
+}
+
+// GetDebugState 获取调试会话状态
+func (m *Manager) GetDebugState(executionID string) (models.DebugState, error) {
+	session := m.runs.getDebug(executionID)
+	if session == nil {
+		return models.DebugState{}, ErrDebugSessionNotFound
+	}
+	return session.snapshot(), nil
+}
+
+// SendDebugCommand 向暂停中的调试会话发送命令
+func (m *Manager) SendDebugCommand(executionID string, cmd models.DebugCommand) error {
+	session := m.runs.getDebug(executionID)
+	if session == nil {
+		return ErrDebugSessionNotFound
+	}
+	return session.sendCommand(cmd)
+}
+
+// SetDebugBreakpoints 设置调试会话的断点
+func (m *Manager) SetDebugBreakpoints(executionID string, breakpoints []int) error {
+	session := m.runs.getDebug(executionID)
+	if session == nil {
+		return ErrDebugSessionNotFound
+	}
+	session.setBreakpoints(breakpoints)
+	return nil
+}
+
+// SubscribeDebugSession 订阅调试会话状态变化（用于 SSE 推送）
+func (m *Manager) SubscribeDebugSession(executionID string) (<-chan models.DebugState, func(), error) {
+	session := m.runs.getDebug(executionID)
+	if session == nil {
+		return nil, nil, ErrDebugSessionNotFound
+	}
+	ch, unsubscribe := session.subscribe()
+	return ch, unsubscribe, nil
+}
@@ -0,0 +1,65 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestDebugSessionPauseAndCommand(t *testing.T) {
+	session := newDebugSession("exec-1", "script-1", 3, models.DebugOptions{Breakpoints: []int{1}})
+
+	if err := session.sendCommand(models.DebugCommand{Command: models.DebugCommandStep}); !errors.Is(err, ErrDebugNotPaused) {
+		t.Fatalf("sendCommand() while running error = %v, want %v", err, ErrDebugNotPaused)
+	}
+	if session.shouldPause(0) {
+		t.Errorf("shouldPause(0) = true, want false")
+	}
+	if !session.shouldPause(1) {
+		t.Fatalf("shouldPause(1) = false, want true (breakpoint)")
+	}
+
+	done := make(chan models.DebugCommand)
+	go func() {
+		cmd, err := session.pause(context.Background(), models.DebugState{StepIndex: 1})
+		if err != nil {
+			t.Errorf("pause() error = %v", err)
+		}
+		done <- cmd
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for session.snapshot().Status != models.DebugStatusPaused {
+		if time.Now().After(deadline) {
+			t.Fatalf("session did not pause")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := session.sendCommand(models.DebugCommand{Command: models.DebugCommandSkip, Action: &models.ScriptAction{}}); err == nil {
+		t.Errorf("sendCommand() with action on skip should fail")
+	}
+	if err := session.sendCommand(models.DebugCommand{Command: models.DebugCommandStep}); err != nil {
+		t.Fatalf("sendCommand() error = %v", err)
+	}
+	if cmd := <-done; cmd.Command != models.DebugCommandStep {
+		t.Errorf("pause() command = %s, want %s", cmd.Command, models.DebugCommandStep)
+	}
+
+	// step 之后在下一步前再次暂停，skipNextPause 放行一次
+	session.skipNextPause()
+	if session.shouldPause(2) {
+		t.Errorf("shouldPause(2) after skipNextPause = true, want false")
+	}
+	if !session.shouldPause(2) {
+		t.Errorf("shouldPause(2) after step = false, want true")
+	}
+
+	session.finish(nil, nil)
+	if err := session.sendCommand(models.DebugCommand{Command: models.DebugCommandResume}); !errors.Is(err, ErrDebugNotPaused) {
+		t.Errorf("sendCommand() after finish error = %v, want %v", err, ErrDebugNotPaused)
+	}
+}
@@ -20,6 +20,9 @@ import (
 	"github.com/browserwing/browserwing/llm"
 	"github.com/browserwing/browserwing/models"
 	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/runqueue"
+	"github.com/browserwing/browserwing/services/secrets"
+	"github.com/browserwing/browserwing/services/sinks"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
@@ -138,6 +141,10 @@ type Manager struct {
 	inPageRecordingStopped bool                    // 标记是否是页面内停止的录制
 	currentLanguage        string                  // 当前前端语言设置
 	downloadPath           string                  // 下载目录路径
+	runs                   *runRegistry            // 正在执行的回放（用于取消）
+	queue                  *runqueue.Queue         // 回放队列（限制每个实例同时执行的回放数）
+	secrets                *secrets.Manager        // 密钥管理器（回放时解析 ${secret:name} 并脱敏执行记录）
+	outputs                *sinks.Writer           // 抓取数据输出（写入脚本声明的输出目标）
 
 	// 向后兼容（废弃）
 	browser    *rod.Browser
@@ -165,13 +172,16 @@ func NewManager(cfg *config.Config, db *storage.BoltDB, llmManager *llm.Manager)
 		recorder.SetDB(db)
 	}
 
-	return &Manager{
+	m := &Manager{
 		config:     cfg,
 		db:         db,
 		llmManager: llmManager,
 		recorder:   recorder,
 		instances:  make(map[string]*BrowserInstanceRuntime),
+		runs:       newRunRegistry(),
 	}
+	m.queue = runqueue.New(m.runLimit, m.runnableInstances)
+	return m
 }
 
 // SetAgentManager 设置 Agent 管理器
@@ -179,6 +189,16 @@ func (m *Manager) SetAgentManager(agentManager AgentManagerInterface) {
 	m.agentManager = agentManager
 }
 
+// SetSecrets 设置密钥管理器
+func (m *Manager) SetSecrets(secrets *secrets.Manager) {
+	m.secrets = secrets
+}
+
+// SetOutputWriter 设置抓取数据输出写入器
+func (m *Manager) SetOutputWriter(outputs *sinks.Writer) {
+	m.outputs = outputs
+}
+
 // Start 启动浏览器
 func (m *Manager) Start(ctx context.Context) error {
 	m.mu.Lock()
@@ -584,6 +604,15 @@ func (m *Manager) IsInstanceRunning(instanceID string) bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if instanceID == runqueue.AnyInstance {
+		// 任意实例：有运行中的实例即可
+		for _, runtime := range m.instances {
+			if runtime != nil && runtime.browser != nil {
+				return true
+			}
+		}
+		return m.isRunning
+	}
 	if instanceID == "" && m.currentInstanceID == "" {
 		return m.isRunning // 向后兼容：如果没有实例ID，使用旧逻辑
 	}
@@ -1020,8 +1049,30 @@ func (m *Manager) ClearInPageRecordingState() {
 	m.mu.Unlock()
 }
 
+// loadScript 根据 ID 或名称加载脚本（ID 优先）
+func (m *Manager) loadScript(idOrName string) (*models.Script, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	if script, err := m.db.GetScript(idOrName); err == nil {
+		return script, nil
+	}
+
+	scripts, err := m.db.ListScripts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scripts: %w", err)
+	}
+	for _, script := range scripts {
+		if script.Name == idOrName {
+			return script, nil
+		}
+	}
+	return nil, fmt.Errorf("script not found: %s", idOrName)
+}
+
 // PlayScript 回放脚本
-// instanceID: 指定实例ID，空字符串表示使用当前实例
+// instanceID: 指定实例ID，空字符串表示使用当前实例，runqueue.AnyInstance 表示由队列分配负载最低的运行中实例
+// 每个实例同时执行的回放数受限，超出时按来源（runqueue.WithSource）轮流排队
 func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanceID string) (result *models.PlayResult, page *rod.Page, err error) {
 	// 捕获 panic 并转换为错误
 	defer func() {
@@ -1033,8 +1084,50 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 		}
 	}()
 
-	// 获取指定实例的浏览器
+	// 创建执行记录 ID（调用方可通过 WithExecutionID 预先指定）
+	executionID := executionIDFromContext(ctx)
+	if executionID == "" {
+		executionID = fmt.Sprintf("%s-%d", script.ID, time.Now().UnixNano())
+	}
+
+	// 登记回放：取消接口或调用方上下文取消时中止回放（排队中取消时直接退出排队）
+	runCtx, cancelRun := context.WithCancelCause(ctx)
+	defer cancelRun(nil)
+	source := runqueue.SourceFromContext(ctx)
+	if err := m.runs.add(&activeRun{
+		info: models.RunningExecution{
+			ExecutionID: executionID,
+			ScriptID:    script.ID,
+			ScriptName:  script.Name,
+			InstanceID:  instanceID,
+			Source:      source,
+			Status:      models.ExecutionStatusQueued,
+			QueuedAt:    time.Now(),
+		},
+		cancel: cancelRun,
+	}); err != nil {
+		return nil, nil, err
+	}
+	defer m.runs.remove(executionID)
+
+	// 等待实例名额
+	instanceID, release, err := m.acquireRun(runCtx, source, instanceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("playback cancelled while queued: %w", err)
+	}
+	defer release()
+
+	// 调用方指定的执行超时从取得名额后开始计时
+	if timeout := runqueue.RunTimeoutFromContext(ctx); timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		runCtx, cancelTimeout = context.WithTimeout(runCtx, timeout)
+		defer cancelTimeout()
+	}
+
+	// 获取指定实例的浏览器（default 实例未运行时会自动启动，需持有锁）
+	m.mu.Lock()
 	browser, _, instance, err := m.getInstanceBrowser(instanceID)
+	m.mu.Unlock()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1057,17 +1150,18 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 	}
 
 	// 创建执行记录
-	executionID := fmt.Sprintf("%s-%d", script.ID, time.Now().UnixNano())
 	execution := &models.ScriptExecution{
-		ID:           executionID,
-		ScriptID:     script.ID,
-		ScriptName:   script.Name,
-		InstanceID:   usedInstanceID,
-		InstanceName: instanceName,
-		StartTime:    time.Now(),
-		TotalSteps:   len(script.Actions),
-		CreatedAt:    time.Now(),
-	}
+		ID:             executionID,
+		ScriptID:       script.ID,
+		ScriptName:     script.Name,
+		ScriptRevision: script.Revision,
+		InstanceID:     usedInstanceID,
+		InstanceName:   instanceName,
+		StartTime:      time.Now(),
+		TotalSteps:     len(script.Actions),
+		CreatedAt:      time.Now(),
+	}
+	m.runs.start(executionID, usedInstanceID, execution.StartTime)
 
 	// 根据脚本的URL匹配配置
 	scriptURL := script.URL
@@ -1121,6 +1215,23 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 		}
 	}
 
+	// 回放被取消或调用方上下文结束时立即关闭页面，中断正在进行的页面操作
+	stopClosePage := context.AfterFunc(runCtx, func() {
+		logger.Info(ctx, "Playback interrupted (%v), closing page: %s", context.Cause(runCtx), executionID)
+		if err := page.Close(); err != nil {
+			logger.Warn(ctx, "Failed to close cancelled playback page: %v", err)
+		}
+	})
+	defer stopClosePage()
+
+	// 先保存执行中的记录，便于查询和取消
+	execution.Status = models.ExecutionStatusRunning
+	if m.db != nil {
+		if err := m.db.SaveScriptExecution(execution); err != nil {
+			logger.Warn(ctx, "Failed to save script execution record: %v", err)
+		}
+	}
+
 	// 创建播放器，传入当前语言设置
 	currentLang := m.currentLanguage
 	if currentLang == "" {
@@ -1129,6 +1240,13 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 	player := NewPlayer(currentLang)
 	player.agentManager = m.agentManager     // 设置 Agent 管理器用于 AI 控制功能
 	player.browserManager = m                // 设置 Browser 管理器用于同步活跃页面
+	player.scriptLoader = m.loadScript       // 设置脚本加载器用于回退脚本和子脚本调用
+	player.SetTraceDir(m.traceDirFor(executionID))
+	player.debugger = debugSessionFromContext(ctx)
+	player.secrets = m.secrets               // 设置密钥管理器用于解析 ${secret:name}
+	if m.config != nil {
+		player.SetPlaybackConfig(m.config.Playback)
+	}
 
 	// 设置下载路径并启动下载监听
 	if m.downloadPath != "" {
@@ -1173,7 +1291,15 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 	}
 
 	// 执行回放
-	playErr := player.PlayScript(ctx, page, script, m.currentLanguage)
+	playErr := player.PlayScript(runCtx, page, script, m.currentLanguage)
+
+	// 回放结束后不再因上下文结束关闭页面；返回 false 表示页面已被关闭
+	pageClosed := !stopClosePage()
+
+	// Player 在上下文结束时中止并返回错误；所有步骤执行完后上下文才结束的回放保留实际结果
+	interrupted := playErr != nil && runCtx.Err() != nil
+	interruptStatus, interruptMessage := playbackInterruption(runCtx)
+	playErr = m.secrets.RedactError(playErr)
 
 	// 停止下载监听
 	if m.downloadPath != "" {
@@ -1199,14 +1325,34 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 	execution.SuccessSteps = player.GetSuccessCount()
 	execution.FailedSteps = player.GetFailCount()
 	execution.ExtractedData = player.GetExtractedData()
+	execution.LocatorResolutions = player.GetLocatorResolutions()
+	execution.Steps = player.GetStepRecords()
+	execution.AssertionsPassed, execution.AssertionsFailed = player.GetAssertionCounts()
+	execution.Assertions = player.GetAssertions()
+	// 执行记录中的密钥值替换为 ******
+	if err := m.secrets.RedactJSON(execution); err != nil {
+		logger.Warn(ctx, "Failed to redact script execution record: %v", err)
+	}
 
 	// 判断是否成功
-	if playErr != nil {
+	if interrupted {
 		execution.Success = false
+		execution.Status = interruptStatus
+		execution.ErrorMsg = playErr.Error()
+		execution.Message = interruptMessage
+	} else if playErr != nil {
+		execution.Success = false
+		execution.Status = models.ExecutionStatusFailed
 		execution.ErrorMsg = playErr.Error()
 		execution.Message = "Script execution failed"
+	} else if execution.AssertionsFailed > 0 {
+		// 断言失败不属于执行错误，ErrorMsg 保持为空
+		execution.Success = false
+		execution.Status = models.ExecutionStatusFailed
+		execution.Message = fmt.Sprintf("Script assertions failed: %d of %d", execution.AssertionsFailed, execution.AssertionsPassed+execution.AssertionsFailed)
 	} else {
 		execution.Success = true
+		execution.Status = models.ExecutionStatusSuccess
 		execution.Message = "Script execution successful"
 	}
 
@@ -1219,13 +1365,9 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 		}
 	}
 
-	// 如果执行失败，返回错误
-	if playErr != nil {
-		return &models.PlayResult{
-			Success: false,
-			Message: playErr.Error(),
-			Errors:  []string{playErr.Error()},
-		}, page, playErr
+	// 回放被中断时页面已关闭，不再返回页面
+	if pageClosed {
+		page = nil
 	}
 
 	// 返回回放结果，包含抓取的数据
@@ -1239,6 +1381,11 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 		logger.Info(ctx, "[PlayScript] Extracted data keys: %v", keys)
 	}
 
+	// 返回给调用方的抓取数据同样脱敏
+	if err := m.secrets.RedactJSON(&extractedData); err != nil {
+		logger.Warn(ctx, "Failed to redact extracted data: %v", err)
+	}
+
 	// 添加下载的文件路径到提取数据
 	downloadedFiles := player.GetDownloadedFiles()
 	if len(downloadedFiles) > 0 {
@@ -1249,13 +1396,65 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 		}
 	}
 
+	// 调试会话不写入输出目标；执行失败时也写入（success 为 false 并附带错误信息），监控类任务的历史不会出现空缺
+	if player.debugger == nil {
+		m.writeScriptOutput(ctx, script, execution, extractedData)
+	}
+
+	// 如果执行失败，返回错误
+	if playErr != nil {
+		return &models.PlayResult{
+			Success:          false,
+			Message:          playErr.Error(),
+			Errors:           []string{playErr.Error()},
+			ExecutionID:      executionID,
+			AssertionsPassed: execution.AssertionsPassed,
+			AssertionsFailed: execution.AssertionsFailed,
+			Assertions:       execution.Assertions,
+		}, page, playErr
+	}
+
+	if execution.AssertionsFailed > 0 {
+		return &models.PlayResult{
+			Success:          false,
+			Message:          execution.Message,
+			ExtractedData:    extractedData,
+			ExecutionID:      executionID,
+			AssertionsPassed: execution.AssertionsPassed,
+			AssertionsFailed: execution.AssertionsFailed,
+			Assertions:       execution.Assertions,
+		}, page, nil
+	}
+
 	return &models.PlayResult{
-		Success:       true,
-		Message:       "Script replay completed",
-		ExtractedData: extractedData,
+		Success:          true,
+		Message:          "Script replay completed",
+		ExtractedData:    extractedData,
+		ExecutionID:      executionID,
+		AssertionsPassed: execution.AssertionsPassed,
+		Assertions:       execution.Assertions,
 	}, page, nil
 }
 
+// writeScriptOutput 将一次回放的结果写入脚本声明的输出目标
+func (m *Manager) writeScriptOutput(ctx context.Context, script *models.Script, execution *models.ScriptExecution, data map[string]interface{}) {
+	if len(script.OutputSinks) == 0 {
+		return
+	}
+	record := &models.OutputRecord{
+		Timestamp:   execution.EndTime,
+		ScriptID:    script.ID,
+		ScriptName:  script.Name,
+		ExecutionID: execution.ID,
+		Success:     execution.Success,
+		Error:       execution.ErrorMsg,
+		Data:        data,
+	}
+	if err := m.outputs.Write(ctx, script.OutputSinks, record); err != nil {
+		logger.Warn(ctx, "Failed to write script output: %v", err)
+	}
+}
+
 // checkInPageRecordingRequests 检查页面内的录制控制请求
 func (m *Manager) checkInPageRecordingRequests(ctx context.Context, page *rod.Page) {
 	ticker := time.NewTicker(500 * time.Millisecond)
@@ -1637,12 +1836,22 @@ func (m *Manager) setInstanceActivePage(instanceID string, page *rod.Page) error
 	return nil
 }
 
-// StartInstance 启动指定浏览器实例
+// StartInstance 启动指定浏览器实例（runqueue.AnyInstance 表示启动 default 实例）
 func (m *Manager) StartInstance(ctx context.Context, instanceID string) error {
+	if instanceID == runqueue.AnyInstance {
+		instanceID = "default"
+	}
+
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	err := m.startInstanceInternal(ctx, instanceID)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
 
-	return m.startInstanceInternal(ctx, instanceID)
+	// 排队中指定任意实例的回放可以分配到新启动的实例
+	m.queue.Dispatch()
+	return nil
 }
 
 // startInstanceInternal 内部启动函数，调用者必须已持有锁
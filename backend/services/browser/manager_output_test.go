@@ -0,0 +1,50 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/sinks"
+)
+
+func TestWriteScriptOutputOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	m := &Manager{outputs: sinks.NewWriter(dir, nil)}
+	script := &models.Script{
+		ID:          "s1",
+		Name:        "prices",
+		OutputSinks: []models.OutputSink{{Type: models.SinkJSONL, Path: "prices.jsonl"}},
+	}
+
+	executions := []*models.ScriptExecution{
+		{ID: "e1", EndTime: time.Now(), Success: true},
+		{ID: "e2", EndTime: time.Now(), Success: false, ErrorMsg: "step 2 (click) failed: element not found"},
+	}
+	m.writeScriptOutput(context.Background(), script, executions[0], map[string]interface{}{"price": 9.5})
+	m.writeScriptOutput(context.Background(), script, executions[1], map[string]interface{}{})
+
+	data, err := os.ReadFile(filepath.Join(dir, sinks.OutputDir, "prices.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("records = %d, want 2 (failed runs are written too)", len(lines))
+	}
+	var failed models.OutputRecord
+	if err := json.Unmarshal([]byte(lines[1]), &failed); err != nil {
+		t.Fatal(err)
+	}
+	if failed.Success || failed.ExecutionID != "e2" || failed.Error != executions[1].ErrorMsg {
+		t.Errorf("failed record = %+v", failed)
+	}
+
+	// 没有声明输出目标时不写入
+	m.writeScriptOutput(context.Background(), &models.Script{ID: "s2"}, executions[0], nil)
+}
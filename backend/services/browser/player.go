@@ -17,10 +17,14 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/browserwing/browserwing/config"
 	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
 	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/secrets"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
 	"github.com/go-rod/rod/lib/proto"
@@ -38,25 +42,25 @@ type BrowserManagerInterface interface {
 }
 
 type Player struct {
-	extractedData     map[string]interface{}          // 存储抓取的数据
-	successCount      int                             // 成功步骤数
-	failCount         int                             // 失败步骤数
-	recordingPage     *rod.Page                       // 录制的页面
-	recordingOutputs  chan *proto.PageScreencastFrame // 录制帧通道
-	recordingDone     chan bool                       // 录制完成信号
-	pages             map[int]*rod.Page               // 多标签页支持 (key: tab index)
-	currentPage       *rod.Page                       // 当前活动页面
-	tabCounter        int                             // 标签页计数器
-	downloadedFiles   []string                        // 下载的文件路径列表
-	downloadPath      string                          // 下载目录路径
-	downloadCtx       context.Context                 // 下载监听上下文
-	downloadCancel    context.CancelFunc              // 取消下载监听
-	currentScriptName string                          // 当前执行的脚本名称
-	currentLang       string                          // 当前语言设置
-	currentActions    []models.ScriptAction           // 当前执行的脚本动作列表
-	currentStepIndex  int                             // 当前执行到的步骤索引
-	agentManager      AgentManagerInterface           // Agent 管理器（用于 AI 控制功能）
-	browserManager    BrowserManagerInterface         // Browser 管理器（用于同步活跃页面）
+	*playState // 回放运行状态（回放过程中为本次回放的状态，结束后为最近一次回放的结果）
+
+	stateMu          sync.Mutex                      // 保护 playState 的切换
+	recordingPage    *rod.Page                       // 录制的页面
+	recordingOutputs chan *proto.PageScreencastFrame // 录制帧通道
+	recordingDone    chan bool                       // 录制完成信号
+	downloadMu       sync.Mutex                      // 保护下载监听协程写入的数据
+	downloadedFiles  []string                        // 下载的文件路径列表
+	downloadPath     string                          // 下载目录路径
+	downloadCtx      context.Context                 // 下载监听上下文
+	downloadCancel   context.CancelFunc              // 取消下载监听
+	currentLang      string                          // 当前语言设置
+	agentManager     AgentManagerInterface           // Agent 管理器（用于 AI 控制功能）
+	browserManager   BrowserManagerInterface         // Browser 管理器（用于同步活跃页面）
+	scriptLoader     ScriptLoader                    // 脚本加载器（用于回退脚本）
+	playback         *config.PlaybackConfig          // 回放等待配置（导航后的固定等待、事件等待默认超时）
+	traceDir         string                          // 失败现场截图保存目录（为空时不保存截图）
+	debugger         *debugSession                   // 调试会话（为空表示非调试模式）
+	secrets          *secrets.Manager                // 密钥管理器（解析 ${secret:name}，为空时不解析）
 }
 
 // highlightElement 高亮显示元素
@@ -111,7 +115,7 @@ func (p *Player) showAIControlIndicator(ctx context.Context, page *rod.Page, scr
 	scriptLabelText := getI18nText("ai.control.script", currentLang)
 	readyText := getI18nText("ai.control.ready", currentLang)
 
-	_, err := page.Eval(indicatorScript, scriptName, titleText, scriptLabelText, readyText)
+	_, err := page.Eval(indicatorScript, p.secrets.Redact(scriptName), titleText, scriptLabelText, readyText)
 
 	if err != nil {
 		logger.Warn(ctx, "Failed to show AI control indicator: %v", err)
@@ -353,6 +357,7 @@ func getI18nText(key, lang string) string {
 			"action.extract_text":      "提取文本",
 			"action.extract_html":      "提取HTML",
 			"action.extract_attribute": "提取属性",
+			"action.extract_list":      "提取列表",
 			"action.execute_js":        "执行JS",
 			"action.upload_file":       "上传文件",
 			"action.scroll":            "滚动页面",
@@ -363,6 +368,19 @@ func getI18nText(key, lang string) string {
 			"action.switch_active_tab": "切换到活跃标签页",
 			"action.capture_xhr":       "捕获XHR请求",
 			"action.ai_control":        "AI控制",
+			"action.loop":              "循环执行",
+			"action.foreach":           "遍历执行",
+			"action.paginate":          "分页抓取",
+			"action.wait_for":          "等待条件",
+			"action.if":                "条件分支",
+			"action.assert_text":       "断言文本",
+			"action.assert_visible":    "断言可见",
+			"action.assert_url":        "断言URL",
+			"action.assert_count":      "断言数量",
+			"action.assert_attribute":  "断言属性",
+			"action.assert_variable":   "断言变量",
+			"action.call_script":       "调用脚本",
+			"action.set_viewport":      "设置视口",
 		},
 		"zh-TW": {
 			// AI 控制指示器
@@ -383,6 +401,7 @@ func getI18nText(key, lang string) string {
 			"action.extract_text":      "提取文字",
 			"action.extract_html":      "提取HTML",
 			"action.extract_attribute": "提取屬性",
+			"action.extract_list":      "提取列表",
 			"action.execute_js":        "執行JS",
 			"action.upload_file":       "上傳檔案",
 			"action.scroll":            "滾動頁面",
@@ -393,6 +412,19 @@ func getI18nText(key, lang string) string {
 			"action.switch_active_tab": "切換到活躍標籤頁",
 			"action.capture_xhr":       "捕獲XHR請求",
 			"action.ai_control":        "AI控制",
+			"action.loop":              "循環執行",
+			"action.foreach":           "遍歷執行",
+			"action.paginate":          "分頁抓取",
+			"action.wait_for":          "等待條件",
+			"action.if":                "條件分支",
+			"action.assert_text":       "斷言文字",
+			"action.assert_visible":    "斷言可見",
+			"action.assert_url":        "斷言URL",
+			"action.assert_count":      "斷言數量",
+			"action.assert_attribute":  "斷言屬性",
+			"action.assert_variable":   "斷言變數",
+			"action.call_script":       "調用腳本",
+			"action.set_viewport":      "設定視口",
 		},
 		"en": {
 			// AI Control Indicator
@@ -413,6 +445,7 @@ func getI18nText(key, lang string) string {
 			"action.extract_text":      "Extract Text",
 			"action.extract_html":      "Extract HTML",
 			"action.extract_attribute": "Extract Attribute",
+			"action.extract_list":      "Extract List",
 			"action.execute_js":        "Execute JS",
 			"action.upload_file":       "Upload File",
 			"action.scroll":            "Scroll Page",
@@ -423,6 +456,19 @@ func getI18nText(key, lang string) string {
 			"action.switch_active_tab": "Switch to Active Tab",
 			"action.capture_xhr":       "Capture XHR Request",
 			"action.ai_control":        "AI Control",
+			"action.loop":              "Loop",
+			"action.foreach":           "For Each",
+			"action.paginate":          "Paginate",
+			"action.wait_for":          "Wait For Condition",
+			"action.if":                "If / Else",
+			"action.assert_text":       "Assert Text",
+			"action.assert_visible":    "Assert Visible",
+			"action.assert_url":        "Assert URL",
+			"action.assert_count":      "Assert Count",
+			"action.assert_attribute":  "Assert Attribute",
+			"action.assert_variable":   "Assert Variable",
+			"action.call_script":       "Call Script",
+			"action.set_viewport":      "Set Viewport",
 		},
 	}
 
@@ -461,13 +507,17 @@ type elementContext struct {
 // NewPlayer 创建回放器
 func NewPlayer(currentLang string) *Player {
 	return &Player{
-		extractedData:   make(map[string]interface{}),
-		successCount:    0,
-		failCount:       0,
-		pages:           make(map[int]*rod.Page),
-		tabCounter:      0,
+		playState:       newPlayState(),
 		downloadedFiles: make([]string, 0),
 		currentLang:     currentLang,
+		playback:        config.DefaultPlaybackConfig(),
+	}
+}
+
+// SetPlaybackConfig 设置回放等待配置
+func (p *Player) SetPlaybackConfig(cfg *config.PlaybackConfig) {
+	if cfg != nil {
+		p.playback = cfg
 	}
 }
 
@@ -494,12 +544,16 @@ func (p *Player) StartDownloadListener(ctx context.Context, browser *rod.Browser
 	// 监听下载开始事件 (BrowserDownloadWillBegin)
 	go browser.Context(p.downloadCtx).EachEvent(func(e *proto.BrowserDownloadWillBegin) {
 		// 记录 GUID 和建议的文件名
+		p.downloadMu.Lock()
 		downloadMap[e.GUID] = e.SuggestedFilename
+		p.downloadMu.Unlock()
 		logger.Info(ctx, "📥 Download will begin: %s (GUID: %s)", e.SuggestedFilename, e.GUID)
 	})()
 
 	// 监听下载进度事件 (BrowserDownloadProgress)
 	go browser.Context(p.downloadCtx).EachEvent(func(e *proto.BrowserDownloadProgress) {
+		p.downloadMu.Lock()
+		defer p.downloadMu.Unlock()
 		if e.State == proto.BrowserDownloadProgressStateCompleted {
 			// 下载完成，从映射中获取文件名
 			fileName, exists := downloadMap[e.GUID]
@@ -593,9 +647,9 @@ func (p *Player) StopDownloadListener(ctx context.Context) {
 	}
 
 	// 记录最终下载的文件
-	if len(p.downloadedFiles) > 0 {
-		logger.Info(ctx, "✓ Total downloaded files: %d", len(p.downloadedFiles))
-		for i, file := range p.downloadedFiles {
+	if files := p.GetDownloadedFiles(); len(files) > 0 {
+		logger.Info(ctx, "✓ Total downloaded files: %d", len(files))
+		for i, file := range files {
 			logger.Info(ctx, "  #%d: %s", i+1, file)
 		}
 	} else {
@@ -605,22 +659,37 @@ func (p *Player) StopDownloadListener(ctx context.Context) {
 
 // GetDownloadedFiles 获取下载的文件列表
 func (p *Player) GetDownloadedFiles() []string {
-	return p.downloadedFiles
+	p.downloadMu.Lock()
+	defer p.downloadMu.Unlock()
+	return append([]string(nil), p.downloadedFiles...)
 }
 
 // GetExtractedData 获取抓取的数据
 func (p *Player) GetExtractedData() map[string]interface{} {
-	return p.extractedData
+	return p.state().extractedData
 }
 
 // GetSuccessCount 获取成功步骤数
 func (p *Player) GetSuccessCount() int {
-	return p.successCount
+	return p.state().successCount
 }
 
 // GetFailCount 获取失败步骤数
 func (p *Player) GetFailCount() int {
-	return p.failCount
+	return p.state().failCount
+}
+
+// GetLocatorResolutions 获取各步骤的元素定位结果（按步骤索引排序）
+func (p *Player) GetLocatorResolutions() []models.LocatorResolution {
+	locatorResults := p.state().locatorResults
+	results := make([]models.LocatorResolution, 0, len(locatorResults))
+	for _, r := range locatorResults {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].StepIndex < results[j].StepIndex
+	})
+	return results
 }
 
 // ResetStats 重置统计信息
@@ -628,6 +697,10 @@ func (p *Player) ResetStats() {
 	p.successCount = 0
 	p.failCount = 0
 	p.extractedData = make(map[string]interface{})
+	p.locatorResults = make(map[int]models.LocatorResolution)
+	p.assertions = nil
+	p.stepRecords = nil
+	p.failureSeq = 0
 	// 注意：不清空录制相关字段，因为录制可能在 PlayScript 之前就已经启动
 	// 录制字段只在 StopVideoRecording 中清空
 }
@@ -921,7 +994,16 @@ func (p *Player) convertFramesToGIF(ctx context.Context, outputPath string, fram
 }
 
 // PlayScript 回放脚本
+// 每次回放使用独立的运行状态，同一 Player 上的并发回放互不影响；回放结束后 Get* 方法返回最近一次结束的回放结果
 func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.Script, currentLang string) error {
+	run := p.forRun()
+	err := run.playScript(ctx, page, script, currentLang)
+	p.setState(run.playState)
+	return err
+}
+
+// playScript 使用当前运行状态回放脚本
+func (p *Player) playScript(ctx context.Context, page *rod.Page, script *models.Script, currentLang string) error {
 	logger.Info(ctx, "Start playing script: %s", script.Name)
 	logger.Info(ctx, "Target URL: %s", script.URL)
 	logger.Info(ctx, "Total %d operation steps", len(script.Actions))
@@ -932,18 +1014,34 @@ func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.
 	}
 	logger.Info(ctx, "Using language: %s", currentLang)
 
+	// 调试模式下可能修改操作，使用副本避免影响调用方的脚本
+	if p.debugger != nil {
+		script = script.Copy()
+		logger.Info(ctx, "Debug mode enabled")
+	}
+
 	// AI 控制指示器将常驻显示，不再自动隐藏
 	// defer p.hideAIControlIndicator(ctx, page)  // 注释掉自动隐藏
 
 	// 重置统计和抓取数据
 	p.ResetStats()
 
-	// 初始化变量上下文（包含脚本预设变量）
-	variables := make(map[string]string)
+	// 脚本引用的密钥必须存在，避免把未解析的占位符输入到页面中
+	if err := p.checkSecrets(script); err != nil {
+		return err
+	}
+
+	// 初始化变量上下文（包含脚本预设变量，其中的 ${secret:name} 在此时解密）
+	// 预设变量可能是明文凭据，日志只输出变量名（密钥引用除外）
+	p.variables = make(map[string]string)
 	if script.Variables != nil {
 		for k, v := range script.Variables {
-			variables[k] = v
-			logger.Info(ctx, "Initialize variable: %s = %s", k, v)
+			p.variables[k] = p.secrets.Render(v)
+			if refs := secrets.References(v); len(refs) == 1 && strings.TrimSpace(v) == "${"+secrets.RefPrefix+refs[0]+"}" {
+				logger.Info(ctx, "Initialize variable: %s = %s", k, v)
+			} else {
+				logger.Info(ctx, "Initialize variable: %s", k)
+			}
 		}
 	}
 
@@ -952,22 +1050,21 @@ func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.
 	p.tabCounter = 0
 	p.pages[p.tabCounter] = page
 	p.currentPage = page
+	p.baseCtx = ctx
 
 	// 导航到起始URL
-	if script.URL != "" {
-		logger.Info(ctx, "Navigate to: %s", script.URL)
-		if err := page.Navigate(script.URL); err != nil {
+	if startURL := p.render(script.URL); startURL != "" {
+		logger.Info(ctx, "Navigate to: %s", startURL)
+		if err := page.Navigate(startURL); err != nil {
 			return fmt.Errorf("navigation failed: %w", err)
 		}
 		if err := page.WaitLoad(); err != nil {
 			logger.Warn(ctx, "Failed to wait for page to load: %v", err)
 		}
-		// 等待页面稳定
-		time.Sleep(2 * time.Second)
-
-		// 页面加载完成后，等待额外时间让 JavaScript 框架初始化完成
-		logger.Info(ctx, "Waiting for page JavaScript to stabilize...")
-		time.Sleep(1 * time.Second)
+		// 等待页面稳定（脚本配置了 start_wait 时按条件等待）
+		if err := p.waitAfterNavigation(ctx, page, p.resolveWaitCondition(script.StartWait)); err != nil {
+			return fmt.Errorf("failed to wait for start page: %w", err)
+		}
 	}
 
 	// 保存脚本名称和动作列表，用于后续重新注入时使用
@@ -987,50 +1084,107 @@ func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.
 		logger.Warn(ctx, "Failed to inject XHR interceptor: %v", err)
 	}
 
+	// 失败处理策略
+	p.errorPolicy = script.ErrorPolicy
+	p.callStack = []string{script.ID}
+
 	// 执行每个操作
-	for i, action := range script.Actions {
+	jumps := 0
+	for i := 0; i < len(script.Actions); i++ {
+		// 回放被取消时在步骤之间中止
+		if ctx.Err() != nil {
+			logger.Warn(ctx, "Playback cancelled before step %d", i+1)
+			return fmt.Errorf("playback cancelled at step %d: %w", i+1, context.Cause(ctx))
+		}
+
+		// 调试模式：在断点或单步执行时暂停，等待调试命令
+		skip, err := p.debugBeforeStep(ctx, page, script.Actions, i)
+		if err != nil {
+			return fmt.Errorf("playback cancelled at step %d: %w", i+1, context.Cause(ctx))
+		}
+		if skip {
+			p.recordSkippedStep(script.Actions[i], i)
+			p.markStepCompleted(ctx, page, i+1, true)
+			continue
+		}
+
+		// 执行时解析占位符（可引用前面步骤抓取的数据）
+		action := p.resolveAction(script.Actions[i])
+		p.warnUnresolved(ctx, action)
 		p.currentStepIndex = i
 		logger.Info(ctx, "[%d/%d] Execute action: %s", i+1, len(script.Actions), action.Type)
 
 		// 更新 AI 控制状态显示（标记为执行中）
 		p.updateAIControlStatus(ctx, page, i+1, len(script.Actions), action.Type)
 
-		// 检查条件执行
-		if action.Condition != nil && action.Condition.Enabled {
-			shouldExecute, err := p.evaluateCondition(ctx, action.Condition, variables)
+		// 检查条件执行（if 操作的条件用于选择分支，由操作自身处理）
+		if action.Type != "if" && action.Condition != nil && action.Condition.Enabled {
+			shouldExecute, err := p.evaluateCondition(ctx, page, action.Condition)
 			if err != nil {
 				logger.Warn(ctx, "Failed to evaluate condition: %v", err)
 			} else if !shouldExecute {
-				logger.Info(ctx, "Skipping action due to condition not met: %s %s %s",
-					action.Condition.Variable, action.Condition.Operator, action.Condition.Value)
+				logger.Info(ctx, "Skipping action due to condition not met: %s", describeCondition(action.Condition))
 				// 标记为跳过（视为成功）
+				p.recordSkippedStep(action, i)
 				p.markStepCompleted(ctx, page, i+1, true)
 				continue
 			}
-			logger.Info(ctx, "Condition met, executing action: %s %s %s",
-				action.Condition.Variable, action.Condition.Operator, action.Condition.Value)
+			logger.Info(ctx, "Condition met, executing action: %s", describeCondition(action.Condition))
 		}
 
-		if err := p.executeAction(ctx, page, action); err != nil {
-			logger.Warn(ctx, "Action execution failed (continuing with subsequent steps): %v", err)
-			p.failCount++
+		if err := p.runStep(ctx, page, action, i); err != nil {
+			// 断言失败单独统计，不计入执行失败
+			if failure, ok := asAssertionFailure(err); ok && isAssertAction(action.Type) {
+				p.recordAssertion(failure.result)
+			} else {
+				p.failCount++
+			}
 			// 标记步骤为失败
 			p.markStepCompleted(ctx, page, i+1, false)
-			// 不要中断，继续执行下一步
+
+			// 回放被取消：步骤执行中断，不再继续
+			if ctx.Err() != nil {
+				logger.Warn(ctx, "Playback cancelled during step %d: %v", i+1, err)
+				return fmt.Errorf("playback cancelled at step %d: %w", i+1, context.Cause(ctx))
+			}
+
+			// 调试模式：失败后暂停，可修改操作后重新执行
+			retry, debugErr := p.debugAfterFailure(ctx, page, script.Actions, i, err)
+			if debugErr != nil {
+				return fmt.Errorf("playback cancelled at step %d: %w", i+1, context.Cause(ctx))
+			}
+			if retry {
+				p.undoStepFailure(action, err)
+				i--
+				continue
+			}
+
+			// on_error=stop：中止回放
+			if isStopPlayback(err) {
+				logger.Error(ctx, "Action execution failed, stopping playback: %v", err)
+				return fmt.Errorf("step %d (%s) failed: %w", i+1, action.Type, err)
+			}
+
+			// on_error=goto：跳转到指定标签的步骤；否则继续执行下一步
+			logger.Warn(ctx, "Action execution failed (continuing with subsequent steps): %v", err)
+			if target, ok := p.gotoTarget(ctx, script.Actions, action, &jumps); ok {
+				i = target - 1
+			}
 		} else {
 			p.successCount++
 			// 标记步骤为成功
 			p.markStepCompleted(ctx, page, i+1, true)
 
 			// 如果 action 提取了数据，更新变量上下文
-			if action.VariableName != "" && p.extractedData[action.VariableName] != nil {
-				variables[action.VariableName] = fmt.Sprintf("%v", p.extractedData[action.VariableName])
-				logger.Info(ctx, "Updated variable from extracted data: %s = %s", action.VariableName, variables[action.VariableName])
-			}
+			p.syncExtractedVariable(ctx, action.VariableName)
 		}
 	}
 
 	logger.Info(ctx, "Script playback completed - Success: %d, Failed: %d, Total: %d", p.successCount, p.failCount, len(script.Actions))
+	if len(p.assertions) > 0 {
+		passed, failed := p.GetAssertionCounts()
+		logger.Info(ctx, "Assertions - Passed: %d, Failed: %d", passed, failed)
+	}
 	if len(p.extractedData) > 0 {
 		logger.Info(ctx, "Extracted %d data items", len(p.extractedData))
 	}
@@ -1043,28 +1197,35 @@ func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.
 	return nil
 }
 
-// evaluateCondition 评估操作执行条件
-func (p *Player) evaluateCondition(ctx context.Context, condition *models.ActionCondition, variables map[string]string) (bool, error) {
-	if condition == nil {
-		return true, nil
+// syncExtractedVariable 将抓取结果同步到变量上下文，供后续条件判断和占位符使用
+func (p *Player) syncExtractedVariable(ctx context.Context, varName string) {
+	if varName == "" || p.extractedData[varName] == nil {
+		return
 	}
+	p.variables[varName] = interpolate.Stringify(p.extractedData[varName])
+	logger.Info(ctx, "Updated variable from extracted data: %s = %s", varName, p.variables[varName])
+}
 
+// compareVariable 评估变量比较条件
+func (p *Player) compareVariable(ctx context.Context, condition *models.ActionCondition, variables map[string]string) (bool, error) {
 	varName := condition.Variable
 	operator := condition.Operator
 	expectedValue := condition.Value
 
+	// 获取变量值（配置了 Transform 时为转换后的值）
+	actualValue, exists, err := p.conditionValue(condition, variables)
+	if err != nil {
+		return false, fmt.Errorf("failed to transform variable %s: %w", varName, err)
+	}
+
 	// 处理 exists 和 not_exists 操作符
 	if operator == "exists" {
-		_, exists := variables[varName]
 		return exists, nil
 	}
 	if operator == "not_exists" {
-		_, exists := variables[varName]
 		return !exists, nil
 	}
 
-	// 获取变量值
-	actualValue, exists := variables[varName]
 	if !exists {
 		logger.Warn(ctx, "Variable not found for condition: %s", varName)
 		return false, fmt.Errorf("variable not found: %s", varName)
@@ -1184,12 +1345,16 @@ func (p *Player) executeAction(ctx context.Context, page *rod.Page, action model
 		return p.executeWait(ctx, action)
 	case "sleep":
 		return p.executeSleep(ctx, action)
+	case "wait_for":
+		return p.executeWaitFor(ctx, activePage, action)
 	case "extract_text":
 		return p.executeExtractText(ctx, activePage, action)
 	case "extract_html":
 		return p.executeExtractHTML(ctx, activePage, action)
 	case "extract_attribute":
 		return p.executeExtractAttribute(ctx, activePage, action)
+	case "extract_list":
+		return p.executeExtractList(ctx, activePage, action)
 	case "execute_js":
 		return p.executeJS(ctx, activePage, action)
 	case "upload_file":
@@ -1204,6 +1369,20 @@ func (p *Player) executeAction(ctx context.Context, page *rod.Page, action model
 		return p.executeCaptureXHR(ctx, activePage, action)
 	case "ai_control":
 		return p.executeAIControl(ctx, activePage, action)
+	case "if":
+		return p.executeIf(ctx, activePage, action)
+	case "loop":
+		return p.executeLoop(ctx, activePage, action)
+	case "foreach":
+		return p.executeForeach(ctx, activePage, action)
+	case "assert_text", "assert_visible", "assert_url", "assert_count", "assert_attribute", "assert_variable":
+		return p.executeAssert(ctx, activePage, action)
+	case "call_script":
+		return p.executeCallScript(ctx, activePage, action)
+	case "paginate":
+		return p.executePaginate(ctx, activePage, action)
+	case "set_viewport":
+		return p.executeSetViewport(ctx, activePage, action)
 	default:
 		logger.Warn(ctx, "Unknown action type: %s", action.Type)
 		return nil
@@ -1635,6 +1814,12 @@ func (p *Player) executeNavigate(ctx context.Context, page *rod.Page, action mod
 		return fmt.Errorf("failed to wait for page to load: %w", err)
 	}
 
+	if action.WaitFor != nil {
+		if err := p.waitForCondition(ctx, page, *action.WaitFor); err != nil {
+			return err
+		}
+	}
+
 	p.ensureAIControlIndicator(ctx, page)
 
 	return nil
@@ -1644,20 +1829,23 @@ func (p *Player) executeNavigate(ctx context.Context, page *rod.Page, action mod
 func (p *Player) executeWait(ctx context.Context, action models.ScriptAction) error {
 	duration := time.Duration(action.Timestamp) * time.Millisecond
 	logger.Info(ctx, "Wait for: %v", duration)
-	time.Sleep(duration)
-	return nil
+	return sleepContext(ctx, duration)
 }
 
 // executeSleep 执行延迟操作
 func (p *Player) executeSleep(ctx context.Context, action models.ScriptAction) error {
 	duration := time.Duration(action.Duration) * time.Millisecond
 	logger.Info(ctx, "Delay: %v", duration)
-	time.Sleep(duration)
-	return nil
+	return sleepContext(ctx, duration)
 }
 
-// findElementWithContext 查找元素并返回其页面上下文（支持 iframe）
+// findElementWithContext 查找元素并返回其页面上下文（支持 iframe 和 Shadow DOM）
 func (p *Player) findElementWithContext(ctx context.Context, page *rod.Page, action models.ScriptAction) (*elementContext, error) {
+	// 录制了定位路径（嵌套 iframe / Shadow DOM）
+	if len(action.FramePath) > 0 {
+		return p.findElementInFramePath(ctx, page, action)
+	}
+
 	selector := action.Selector
 	xpath := action.XPath
 
@@ -1734,6 +1922,15 @@ func (p *Player) findElementWithContext(ctx context.Context, page *rod.Page, act
 
 			if findErr == nil && element != nil {
 				logger.Info(ctx, "✓ Found element in iframe #%d", i)
+				iframeSelector := xpath
+				if iframeSelector == "" {
+					iframeSelector = selector
+				}
+				p.recordLocator(models.LocatorResolution{
+					StepIndex: p.currentStepIndex,
+					Strategy:  "iframe",
+					Selector:  iframeSelector,
+				})
 				// 返回元素及其所在的 frame 作为页面上下文
 				return &elementContext{
 					element: element,
@@ -1750,23 +1947,40 @@ func (p *Player) findElementWithContext(ctx context.Context, page *rod.Page, act
 	// 普通元素（非 iframe）
 	var element *rod.Element
 	var err error
+	strategy := ""
+	usedSelector := ""
 
 	if xpath != "" {
+		strategy, usedSelector = "xpath", xpath
 		element, err = page.Timeout(5 * time.Second).ElementX(xpath)
 		if err != nil && selector != "" && selector != "unknown" {
 			logger.Warn(ctx, "XPath lookup failed, trying CSS: %v", err)
+			strategy, usedSelector = "css", selector
 			element, err = page.Timeout(5 * time.Second).Element(selector)
 		}
 	} else if selector != "" && selector != "unknown" {
+		strategy, usedSelector = "css", selector
 		element, err = page.Timeout(5 * time.Second).Element(selector)
 	} else {
-		return nil, fmt.Errorf("missing valid selector")
+		err = fmt.Errorf("missing valid selector")
 	}
 
 	if err != nil {
-		return nil, err
+		// 原始定位器失效，尝试使用录制时的语义信息自愈
+		healed, healErr := p.healElement(ctx, page, action)
+		if healErr != nil {
+			logger.Warn(ctx, "Self-healing lookup failed: %v", healErr)
+			return nil, err
+		}
+		return healed, nil
 	}
 
+	p.recordLocator(models.LocatorResolution{
+		StepIndex: p.currentStepIndex,
+		Strategy:  strategy,
+		Selector:  usedSelector,
+	})
+
 	// 普通元素返回主页面作为上下文
 	return &elementContext{
 		element: element,
@@ -1805,7 +2019,9 @@ func (p *Player) executeExtractText(ctx context.Context, page *rod.Page, action
 	if varName == "" {
 		varName = fmt.Sprintf("text_data_%d", len(p.extractedData))
 	}
-	p.extractedData[varName] = text
+	if err := p.storeExtracted(action, varName, text); err != nil {
+		return err
+	}
 
 	logger.Info(ctx, "✓ Text extraction successful: %s = %s", varName, text)
 	return nil
@@ -1842,7 +2058,9 @@ func (p *Player) executeExtractHTML(ctx context.Context, page *rod.Page, action
 	if varName == "" {
 		varName = fmt.Sprintf("html_data_%d", len(p.extractedData))
 	}
-	p.extractedData[varName] = html
+	if err := p.storeExtracted(action, varName, html); err != nil {
+		return err
+	}
 
 	logger.Info(ctx, "✓ HTML extraction successful: %s (length: %d)", varName, len(html))
 	return nil
@@ -1887,7 +2105,9 @@ func (p *Player) executeExtractAttribute(ctx context.Context, page *rod.Page, ac
 	if varName == "" {
 		varName = fmt.Sprintf("attr_data_%d", len(p.extractedData))
 	}
-	p.extractedData[varName] = *attrValue
+	if err := p.storeExtracted(action, varName, *attrValue); err != nil {
+		return err
+	}
 
 	logger.Info(ctx, "✓ Attribute extraction successful: %s = %s", varName, *attrValue)
 	return nil
@@ -1966,7 +2186,9 @@ func (p *Player) executeJS(ctx context.Context, page *rod.Page, action models.Sc
 	if varName == "" {
 		varName = fmt.Sprintf("js_result_%d", len(p.extractedData))
 	}
-	p.extractedData[varName] = result.Value
+	if err := p.storeExtracted(action, varName, result.Value); err != nil {
+		return err
+	}
 
 	logger.Info(ctx, "✓ JavaScript execution successful: %s", varName)
 	return nil
@@ -1995,6 +2217,32 @@ func (p *Player) executeScroll(ctx context.Context, page *rod.Page, action model
 	return nil
 }
 
+// executeSetViewport 设置页面视口尺寸
+func (p *Player) executeSetViewport(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	if action.ViewportWidth <= 0 || action.ViewportHeight <= 0 {
+		return fmt.Errorf("set_viewport requires positive viewport width and height")
+	}
+	scale := action.DeviceScaleFactor
+	if scale <= 0 {
+		scale = 1
+	}
+
+	logger.Info(ctx, "Set viewport: %dx%d (scale=%v, mobile=%v)", action.ViewportWidth, action.ViewportHeight, scale, action.Mobile)
+
+	err := page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+		Width:             action.ViewportWidth,
+		Height:            action.ViewportHeight,
+		DeviceScaleFactor: scale,
+		Mobile:            action.Mobile,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set viewport: %w", err)
+	}
+
+	logger.Info(ctx, "✓ Viewport set")
+	return nil
+}
+
 // downloadFileFromURL 从 HTTP(S) URL 下载文件到临时目录
 func (p *Player) downloadFileFromURL(ctx context.Context, url string) (string, error) {
 	logger.Info(ctx, "Downloading file from URL: %s", url)
@@ -2714,12 +2962,7 @@ func (p *Player) executeScreenshot(ctx context.Context, page *rod.Page, action m
 	// 如果有自定义变量名，使用它作为文件名前缀
 	if action.VariableName != "" {
 		// 清理变量名，移除非法字符
-		cleanName := strings.Map(func(r rune) rune {
-			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
-				return r
-			}
-			return '_'
-		}, action.VariableName)
+		cleanName := safePathSegment(action.VariableName)
 		fileName = fmt.Sprintf("%s_%s_%s.png", cleanName, mode, timestamp)
 	}
 
@@ -2776,6 +3019,8 @@ func (p *Player) executeOpenTab(ctx context.Context, page *rod.Page, action mode
 	}
 
 	// 将新页面添加到 pages map
+	// 新标签页会在后续步骤中继续使用，不能绑定到当前步骤的超时上下文
+	newPage = p.tabPage(newPage)
 	p.tabCounter++
 	tabIndex := p.tabCounter
 	p.pages[tabIndex] = newPage
@@ -2791,6 +3036,14 @@ func (p *Player) executeOpenTab(ctx context.Context, page *rod.Page, action mode
 	return nil
 }
 
+// tabPage 返回绑定到回放根上下文的页面（保存到 pages/currentPage 的页面不能继承单步超时上下文）
+func (p *Player) tabPage(page *rod.Page) *rod.Page {
+	if p.baseCtx == nil {
+		return page
+	}
+	return page.Context(p.baseCtx)
+}
+
 func (p *Player) executeSwitchActiveTab(ctx context.Context) error {
 	logger.Info(ctx, "Switching to browser's active tab")
 
@@ -2859,12 +3112,13 @@ func (p *Player) executeSwitchActiveTab(ctx context.Context) error {
 	}
 
 	// 将找到的活跃页面设置为当前页面
+	activePage = p.tabPage(activePage)
 	p.currentPage = activePage
 
 	// 同步到 pages map 中（如果该页面不在 map 中，则添加）
 	pageFound := false
 	for idx, pg := range p.pages {
-		if pg == activePage {
+		if pg.TargetID == activePage.TargetID {
 			pageFound = true
 			logger.Info(ctx, "Active page found in pages map at index: %d", idx)
 			break
@@ -2929,11 +3183,11 @@ func (p *Player) executeSwitchTab(ctx context.Context, action models.ScriptActio
 func (p *Player) injectXHRInterceptorForScript(ctx context.Context, page *rod.Page, actions []models.ScriptAction) error {
 	// 收集所有需要监听的 capture_xhr action
 	var captureTargets []map[string]string
-	for _, action := range actions {
+	for _, action := range flattenActions(actions) {
 		if action.Type == "capture_xhr" && action.URL != "" && action.Method != "" {
 			captureTargets = append(captureTargets, map[string]string{
 				"method": action.Method,
-				"url":    action.URL,
+				"url":    p.render(action.URL),
 			})
 		}
 	}
@@ -3174,7 +3428,9 @@ func (p *Player) executeCaptureXHR(ctx context.Context, page *rod.Page, action m
 			if varName == "" {
 				varName = fmt.Sprintf("xhr_data_%d", len(p.extractedData))
 			}
-			p.extractedData[varName] = xhrData["response"]
+			if err := p.storeExtracted(action, varName, xhrData["response"]); err != nil {
+				return err
+			}
 
 			logger.Info(ctx, "✓ XHR request captured successfully: %s = %v", varName, xhrData["status"])
 			logger.Info(ctx, "Response status: %v %v", xhrData["status"], xhrData["statusText"])
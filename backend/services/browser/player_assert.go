@@ -0,0 +1,270 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// assertionFailure 断言未通过（与执行错误区分统计）
+type assertionFailure struct {
+	result models.AssertionResult
+}
+
+func (e *assertionFailure) Error() string {
+	return fmt.Sprintf("assertion failed: %s", e.result.Message)
+}
+
+// asAssertionFailure 判断错误是否为断言失败
+func asAssertionFailure(err error) (*assertionFailure, bool) {
+	var failure *assertionFailure
+	if errors.As(err, &failure) {
+		return failure, true
+	}
+	return nil, false
+}
+
+// isAssertAction 判断是否为断言类型的操作
+func isAssertAction(actionType string) bool {
+	return strings.HasPrefix(actionType, "assert_")
+}
+
+// executeAssert 执行断言操作，断言通过返回 nil，未通过返回 *assertionFailure
+func (p *Player) executeAssert(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	result := models.AssertionResult{
+		StepIndex: p.currentStepIndex,
+		Type:      action.Type,
+		Operator:  action.AssertOperator,
+		Expected:  action.Value,
+	}
+
+	var actual string
+	var err error
+	switch action.Type {
+	case "assert_text":
+		result.Target = assertTarget(action)
+		if result.Operator == "" {
+			result.Operator = "contains"
+		}
+		actual, err = p.assertElementValue(ctx, page, action, func(element *rod.Element) (string, error) {
+			return element.Text()
+		})
+
+	case "assert_attribute":
+		result.Target = assertTarget(action) + "@" + action.AttributeName
+		if action.AttributeName == "" {
+			err = fmt.Errorf("attribute name is empty")
+			break
+		}
+		actual, err = p.assertElementValue(ctx, page, action, func(element *rod.Element) (string, error) {
+			value, attrErr := element.Attribute(action.AttributeName)
+			if attrErr != nil {
+				return "", attrErr
+			}
+			if value == nil {
+				return "", fmt.Errorf("attribute %s not found", action.AttributeName)
+			}
+			return *value, nil
+		})
+
+	case "assert_visible":
+		// Value 为 "false" 时断言元素不可见（或不存在）
+		result.Target = assertTarget(action)
+		result.Operator = "equals"
+		if result.Expected == "" {
+			result.Expected = "true"
+		}
+		var visible bool
+		visible, err = elementVisibleNow(ctx, page, action)
+		actual = strconv.FormatBool(visible)
+
+	case "assert_url":
+		result.Target = "url"
+		if result.Operator == "" {
+			result.Operator = "contains"
+		}
+		info, infoErr := page.Info()
+		if infoErr != nil {
+			err = fmt.Errorf("failed to get page info: %w", infoErr)
+			break
+		}
+		actual = info.URL
+
+	case "assert_count":
+		result.Target = assertTarget(action)
+		var elements rod.Elements
+		if action.XPath != "" {
+			elements, err = page.ElementsX(action.XPath)
+		} else if action.Selector != "" {
+			elements, err = page.Elements(action.Selector)
+		} else {
+			err = fmt.Errorf("missing valid selector")
+		}
+		actual = strconv.Itoa(len(elements))
+
+	case "assert_variable":
+		result.Target = action.VariableName
+		if value, ok := p.variables[action.VariableName]; ok {
+			actual = value
+		} else if value, ok := p.extractedData[action.VariableName]; ok {
+			actual = fmt.Sprintf("%v", value)
+		} else {
+			err = fmt.Errorf("variable not found: %s", action.VariableName)
+		}
+
+	default:
+		return fmt.Errorf("unknown assertion type: %s", action.Type)
+	}
+
+	if result.Operator == "" {
+		result.Operator = "equals"
+	}
+	result.Actual = actual
+
+	if err != nil {
+		result.Message = err.Error()
+	} else {
+		passed, cmpErr := compareAssertion(actual, result.Expected, result.Operator)
+		if cmpErr != nil {
+			result.Message = cmpErr.Error()
+		} else if passed {
+			result.Passed = true
+		} else {
+			result.Message = fmt.Sprintf("expected %s %s %q, got %q", result.Target, result.Operator, result.Expected, actual)
+		}
+	}
+
+	if !result.Passed {
+		logger.Warn(ctx, "✗ Assertion failed [%s]: %s", action.Type, result.Message)
+		return &assertionFailure{result: result}
+	}
+
+	logger.Info(ctx, "✓ Assertion passed [%s]: %s %s %q", action.Type, result.Target, result.Operator, result.Expected)
+	p.recordAssertion(result)
+	return nil
+}
+
+// assertElementValue 查找断言目标元素并读取其值
+func (p *Player) assertElementValue(ctx context.Context, page *rod.Page, action models.ScriptAction, read func(*rod.Element) (string, error)) (string, error) {
+	elemCtx, err := p.findElementWithContext(ctx, page, action)
+	if err != nil {
+		return "", fmt.Errorf("element not found: %w", err)
+	}
+	value, err := read(elemCtx.element)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// elementVisibleNow 立即判断元素是否存在且可见（不等待元素出现，也不自愈）
+// 等待和自愈会让"不可见"断言每次都耗尽查找超时，还可能匹配到另一个可见元素
+func elementVisibleNow(ctx context.Context, page *rod.Page, action models.ScriptAction) (bool, error) {
+	selector := action.Selector
+	if selector == "unknown" {
+		selector = ""
+	}
+	if selector == "" && action.XPath == "" {
+		return false, fmt.Errorf("missing valid selector")
+	}
+
+	scope := frameScope{page: page}
+	if len(action.FramePath) > 0 {
+		var err error
+		scope, err = resolveFramePath(ctx, page, action.FramePath)
+		if err != nil {
+			// 定位路径不存在时元素也不可见
+			logger.Info(ctx, "Frame path not resolved, element treated as not visible: %v", err)
+			return false, nil
+		}
+	}
+
+	if scope.root != nil {
+		// shadow root 内无法使用 document 查询，直接在 shadow root 上查找（不等待）
+		var elements rod.Elements
+		var err error
+		if selector != "" {
+			elements, err = scope.root.Context(ctx).Elements(selector)
+		} else {
+			elements, err = scope.root.Context(ctx).ElementsX(action.XPath)
+		}
+		if err != nil {
+			return false, err
+		}
+		if len(elements) == 0 {
+			return false, nil
+		}
+		return elements.First().Visible()
+	}
+
+	result, err := scope.page.Context(ctx).Eval(waitConditionJS, string(models.WaitSelectorVisible), selector, action.XPath, "")
+	if err != nil {
+		return false, err
+	}
+	return result.Value.Bool(), nil
+}
+
+// recordAssertion 记录断言结果
+func (p *Player) recordAssertion(result models.AssertionResult) {
+	p.assertions = append(p.assertions, result)
+}
+
+// GetAssertions 获取断言结果
+func (p *Player) GetAssertions() []models.AssertionResult {
+	return p.state().assertions
+}
+
+// GetAssertionCounts 获取断言通过数和失败数
+func (p *Player) GetAssertionCounts() (passed, failed int) {
+	for _, result := range p.state().assertions {
+		if result.Passed {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	return passed, failed
+}
+
+// assertTarget 返回断言目标的描述（优先 XPath）
+func assertTarget(action models.ScriptAction) string {
+	if action.XPath != "" {
+		return action.XPath
+	}
+	return action.Selector
+}
+
+// compareAssertion 按操作符比较断言的实际值和期望值
+func compareAssertion(actual, expected, operator string) (bool, error) {
+	switch operator {
+	case "equals", "=", "==":
+		return actual == expected, nil
+	case "not_equals", "!=":
+		return actual != expected, nil
+	case "contains":
+		return strings.Contains(actual, expected), nil
+	case "not_contains":
+		return !strings.Contains(actual, expected), nil
+	case "starts_with":
+		return strings.HasPrefix(actual, expected), nil
+	case "ends_with":
+		return strings.HasSuffix(actual, expected), nil
+	case "matches":
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern %q: %w", expected, err)
+		}
+		return re.MatchString(actual), nil
+	case ">", "<", ">=", "<=":
+		return compareNumeric(actual, expected, operator)
+	default:
+		return false, fmt.Errorf("unsupported assertion operator: %s", operator)
+	}
+}
@@ -0,0 +1,41 @@
+package browser
+
+import (
+	"testing"
+)
+
+func TestCompareAssertion(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   string
+		expected string
+		operator string
+		want     bool
+		wantErr  bool
+	}{
+		{name: "Equals", actual: "Login", expected: "Login", operator: "equals", want: true},
+		{name: "Equals symbol mismatch", actual: "Login", expected: "login", operator: "==", want: false},
+		{name: "Not equals", actual: "a", expected: "b", operator: "not_equals", want: true},
+		{name: "Contains", actual: "Welcome back, Alice", expected: "Alice", operator: "contains", want: true},
+		{name: "Not contains", actual: "Welcome", expected: "Error", operator: "not_contains", want: true},
+		{name: "Starts with", actual: "https://example.com/home", expected: "https://", operator: "starts_with", want: true},
+		{name: "Ends with", actual: "https://example.com/home", expected: "/login", operator: "ends_with", want: false},
+		{name: "Matches", actual: "Order #12345", expected: `#\d+$`, operator: "matches", want: true},
+		{name: "Invalid pattern", actual: "x", expected: "(", operator: "matches", wantErr: true},
+		{name: "Numeric greater", actual: "10", expected: "9", operator: ">", want: true},
+		{name: "Numeric less or equal", actual: "3", expected: "3", operator: "<=", want: true},
+		{name: "Unsupported operator", actual: "a", expected: "a", operator: "like", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compareAssertion(tt.actual, tt.expected, tt.operator)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compareAssertion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("compareAssertion(%q, %q, %q) = %v, want %v", tt.actual, tt.expected, tt.operator, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// maxCallDepth 子脚本嵌套调用的最大深度
+const maxCallDepth = 10
+
+// executeCallScript 执行 call_script 操作：在当前页面中运行另一个脚本，并映射输入变量和抓取结果
+func (p *Player) executeCallScript(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	ref := action.ScriptID
+	if ref == "" {
+		ref = action.ScriptName
+	}
+	if ref == "" {
+		return fmt.Errorf("call_script requires script_id or script_name")
+	}
+	if p.scriptLoader == nil {
+		return fmt.Errorf("script loader not configured")
+	}
+
+	script, err := p.scriptLoader(ref)
+	if err != nil {
+		return fmt.Errorf("failed to load script %s: %w", ref, err)
+	}
+	p.lastCalledScript = script
+
+	// 递归检测
+	for _, id := range p.callStack {
+		if id == script.ID {
+			return fmt.Errorf("recursive script call detected: %s -> %s", strings.Join(p.callStack, " -> "), script.ID)
+		}
+	}
+	if len(p.callStack) >= maxCallDepth {
+		return fmt.Errorf("script call depth exceeds %d", maxCallDepth)
+	}
+
+	logger.Info(ctx, "Call script: %s (depth %d)", script.Name, len(p.callStack))
+
+	// 子脚本引用的密钥必须存在
+	if err := p.checkSecrets(script); err != nil {
+		return err
+	}
+
+	// 保存父脚本的执行状态，子脚本使用独立的变量和抓取数据
+	parentVariables := p.variables
+	parentExtracted := p.extractedData
+	parentPolicy := p.errorPolicy
+	p.variables = p.callVariables(action, script)
+	p.extractedData = make(map[string]interface{})
+	p.errorPolicy = script.ErrorPolicy
+	p.callStack = append(p.callStack, script.ID)
+
+	childExtracted, runErr := p.runCalledScript(ctx, page, action, script)
+
+	p.callStack = p.callStack[:len(p.callStack)-1]
+	p.variables = parentVariables
+	p.extractedData = parentExtracted
+	p.errorPolicy = parentPolicy
+
+	p.mapCallOutput(action, childExtracted)
+	p.lastCalledScript = script
+
+	if runErr != nil {
+		return fmt.Errorf("script %s failed: %v", script.Name, runErr)
+	}
+
+	logger.Info(ctx, "✓ Called script completed: %s", script.Name)
+	return nil
+}
+
+// runCalledScript 运行被调用脚本的操作，返回子脚本的抓取数据
+func (p *Player) runCalledScript(ctx context.Context, page *rod.Page, action models.ScriptAction, script *models.Script) (map[string]interface{}, error) {
+	if url := p.calledScriptURL(action, script); url != "" {
+		logger.Info(ctx, "Navigate to: %s", url)
+		if err := page.Navigate(url); err != nil {
+			return p.extractedData, fmt.Errorf("navigation failed: %w", err)
+		}
+		if err := page.WaitLoad(); err != nil {
+			logger.Warn(ctx, "Failed to wait for page to load: %v", err)
+		}
+	}
+
+	if err := p.injectXHRInterceptorForScript(ctx, page, script.Actions); err != nil {
+		logger.Warn(ctx, "Failed to inject XHR interceptor: %v", err)
+	}
+
+	// 子脚本中的 stop 只中止子脚本本身，由 call_script 的失败处理策略决定父脚本行为
+	failed, total, err := p.executeNestedActions(ctx, page, script.Actions)
+	if err != nil {
+		return p.extractedData, err
+	}
+	if failed > 0 {
+		return p.extractedData, fmt.Errorf("%d of %d actions failed", failed, total)
+	}
+	return p.extractedData, nil
+}
+
+// callVariables 构建子脚本变量：预设变量（解密其中的密钥引用）< 父脚本同名变量 < 输入映射
+func (p *Player) callVariables(action models.ScriptAction, script *models.Script) map[string]string {
+	childVariables := make(map[string]string)
+	for k, v := range script.Variables {
+		childVariables[k] = p.secrets.Render(v)
+		if parentValue, ok := p.variables[k]; ok {
+			childVariables[k] = parentValue
+		}
+	}
+	for k, v := range action.InputMapping {
+		childVariables[k] = v
+	}
+	return childVariables
+}
+
+// mapCallOutput 将子脚本抓取结果映射回当前脚本（未配置输出映射时全部按原名合并）
+func (p *Player) mapCallOutput(action models.ScriptAction, childExtracted map[string]interface{}) {
+	if len(action.OutputMapping) > 0 {
+		for childKey, parentKey := range action.OutputMapping {
+			if value, ok := childExtracted[childKey]; ok {
+				p.extractedData[parentKey] = value
+				p.variables[parentKey] = interpolate.Stringify(value)
+			}
+		}
+		return
+	}
+	for k, value := range childExtracted {
+		p.extractedData[k] = value
+		p.variables[k] = interpolate.Stringify(value)
+	}
+}
+
+// calledScriptURL 子脚本运行前要打开的地址：优先使用操作配置的 URL，否则使用子脚本自身的起始 URL（按子脚本变量解析）
+func (p *Player) calledScriptURL(action models.ScriptAction, script *models.Script) string {
+	if action.URL != "" {
+		return action.URL
+	}
+	return p.render(script.URL)
+}
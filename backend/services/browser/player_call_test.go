@@ -0,0 +1,128 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+func TestExecuteCallScriptGuards(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	scripts := map[string]*models.Script{
+		"child":  {ID: "child", Name: "Child"},
+		"parent": {ID: "parent", Name: "Parent"},
+	}
+	loader := func(ref string) (*models.Script, error) {
+		if script, ok := scripts[ref]; ok {
+			return script, nil
+		}
+		return nil, fmt.Errorf("script not found")
+	}
+
+	deepStack := make([]string, maxCallDepth)
+	for i := range deepStack {
+		deepStack[i] = fmt.Sprintf("s%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		action  models.ScriptAction
+		stack   []string
+		wantErr string
+	}{
+		{"missing reference", models.ScriptAction{Type: "call_script"}, nil, "requires script_id or script_name"},
+		{"unknown script", models.ScriptAction{ScriptID: "nope"}, nil, "failed to load script nope"},
+		{"direct recursion", models.ScriptAction{ScriptID: "parent"}, []string{"parent"}, "recursive script call detected: parent -> parent"},
+		{"indirect recursion", models.ScriptAction{ScriptName: "parent"}, []string{"parent", "child2"}, "parent -> child2 -> parent"},
+		{"depth limit", models.ScriptAction{ScriptID: "child"}, deepStack, fmt.Sprintf("depth exceeds %d", maxCallDepth)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer("en")
+			p.scriptLoader = loader
+			p.callStack = append([]string{}, tt.stack...)
+
+			err := p.executeCallScript(context.Background(), nil, tt.action)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("executeCallScript() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(p.callStack, append([]string{}, tt.stack...)) {
+				t.Errorf("callStack = %v, want %v", p.callStack, tt.stack)
+			}
+		})
+	}
+}
+
+func TestCallVariables(t *testing.T) {
+	p := NewPlayer("en")
+	p.variables = map[string]string{"region": "eu", "unrelated": "x"}
+	script := &models.Script{Variables: map[string]string{"region": "us", "limit": "10", "query": ""}}
+	action := models.ScriptAction{InputMapping: map[string]string{"query": "shoes", "limit": "20"}}
+
+	// 子脚本预设变量 < 父脚本同名变量 < 输入映射；父脚本的其他变量不传入
+	want := map[string]string{"region": "eu", "limit": "20", "query": "shoes"}
+	if got := p.callVariables(action, script); !reflect.DeepEqual(got, want) {
+		t.Errorf("callVariables() = %v, want %v", got, want)
+	}
+}
+
+func TestMapCallOutput(t *testing.T) {
+	childExtracted := map[string]interface{}{"price": 9.5, "title": "Shoes"}
+	tests := []struct {
+		name     string
+		mapping  map[string]string
+		wantData map[string]interface{}
+		wantVars map[string]string
+	}{
+		{
+			name:     "without mapping all results are merged",
+			wantData: map[string]interface{}{"existing": "keep", "price": 9.5, "title": "Shoes"},
+			wantVars: map[string]string{"price": "9.5", "title": "Shoes"},
+		},
+		{
+			name:     "mapping renames and filters",
+			mapping:  map[string]string{"price": "child_price", "missing": "unused"},
+			wantData: map[string]interface{}{"existing": "keep", "child_price": 9.5},
+			wantVars: map[string]string{"child_price": "9.5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer("en")
+			p.extractedData = map[string]interface{}{"existing": "keep"}
+			p.variables = map[string]string{}
+
+			p.mapCallOutput(models.ScriptAction{OutputMapping: tt.mapping}, childExtracted)
+			if !reflect.DeepEqual(p.extractedData, tt.wantData) {
+				t.Errorf("extractedData = %v, want %v", p.extractedData, tt.wantData)
+			}
+			if !reflect.DeepEqual(p.variables, tt.wantVars) {
+				t.Errorf("variables = %v, want %v", p.variables, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestCalledScriptURL(t *testing.T) {
+	p := NewPlayer("en")
+	p.variables = map[string]string{"query": "shoes"}
+	script := &models.Script{URL: "https://example.com/search?q=${query}"}
+
+	if got := p.calledScriptURL(models.ScriptAction{URL: "https://other.example"}, script); got != "https://other.example" {
+		t.Errorf("action URL = %q", got)
+	}
+	// 操作未配置 URL 时使用子脚本的起始 URL（按子脚本变量解析）
+	if got := p.calledScriptURL(models.ScriptAction{}, script); got != "https://example.com/search?q=shoes" {
+		t.Errorf("script URL = %q", got)
+	}
+	if got := p.calledScriptURL(models.ScriptAction{}, &models.Script{}); got != "" {
+		t.Errorf("empty URL = %q", got)
+	}
+}
@@ -0,0 +1,205 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// executeIf 执行 if 操作：条件成立时执行 Actions，否则执行 ElseActions
+func (p *Player) executeIf(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	if action.Condition == nil {
+		return fmt.Errorf("if requires condition")
+	}
+
+	ok, err := p.evaluateCondition(ctx, page, action.Condition)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate if condition: %w", err)
+	}
+
+	branch, name := action.Actions, "then"
+	if !ok {
+		branch, name = action.ElseActions, "else"
+	}
+	logger.Info(ctx, "If condition %s is %v, executing %s branch (%d actions)", describeCondition(action.Condition), ok, name, len(branch))
+	if len(branch) == 0 {
+		return nil
+	}
+
+	failed, total, err := p.executeNestedActions(ctx, page, branch)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d nested actions failed", failed, total)
+	}
+	return nil
+}
+
+// evaluateCondition 评估条件：变量比较、页面状态或 and/or 组合
+func (p *Player) evaluateCondition(ctx context.Context, page *rod.Page, condition *models.ActionCondition) (bool, error) {
+	if condition == nil {
+		return true, nil
+	}
+
+	var result bool
+	var err error
+	switch condition.Type {
+	case "", models.ConditionVariable:
+		result, err = p.compareVariable(ctx, condition, p.variables)
+	case models.ConditionAnd, models.ConditionOr:
+		result, err = p.evaluateConditionGroup(ctx, page, condition)
+	case models.ConditionElementExists, models.ConditionElementVisible, models.ConditionURLMatches, models.ConditionTextPresent, models.ConditionJS:
+		// 跨标签页操作后页面状态以当前活动页面为准
+		if p.currentPage != nil {
+			page = p.currentPage
+		}
+		result, err = p.evaluatePageCondition(ctx, page, condition)
+	default:
+		return false, fmt.Errorf("unknown condition type: %s", condition.Type)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if condition.Negate {
+		return !result, nil
+	}
+	return result, nil
+}
+
+// evaluateConditionGroup 评估 and/or 条件组（短路求值）
+func (p *Player) evaluateConditionGroup(ctx context.Context, page *rod.Page, condition *models.ActionCondition) (bool, error) {
+	if len(condition.Conditions) == 0 {
+		return false, fmt.Errorf("%s condition requires sub-conditions", condition.Type)
+	}
+
+	isAnd := condition.Type == models.ConditionAnd
+	for i := range condition.Conditions {
+		ok, err := p.evaluateCondition(ctx, page, &condition.Conditions[i])
+		if err != nil {
+			return false, err
+		}
+		if ok != isAnd {
+			return ok, nil
+		}
+	}
+	return isAnd, nil
+}
+
+// evaluatePageCondition 评估页面状态条件，配置了 Timeout 时在超时前等待条件成立
+func (p *Player) evaluatePageCondition(ctx context.Context, page *rod.Page, condition *models.ActionCondition) (bool, error) {
+	if page == nil {
+		return false, fmt.Errorf("%s condition requires a page", condition.Type)
+	}
+
+	var check func(ctx context.Context) (bool, error)
+	switch condition.Type {
+	case models.ConditionURLMatches:
+		if condition.Pattern == "" {
+			return false, fmt.Errorf("url_matches requires pattern")
+		}
+		match := urlMatcher(condition.Pattern)
+		check = func(ctx context.Context) (bool, error) {
+			info, err := page.Context(ctx).Info()
+			if err != nil {
+				return false, err
+			}
+			return match(info.URL), nil
+		}
+	case models.ConditionJS:
+		if condition.Expression == "" {
+			return false, fmt.Errorf("js condition requires expression")
+		}
+		js := fmt.Sprintf("() => !!(%s)", condition.Expression)
+		check = func(ctx context.Context) (bool, error) {
+			result, err := page.Context(ctx).Eval(js)
+			if err != nil {
+				return false, err
+			}
+			return result.Value.Bool(), nil
+		}
+	default:
+		jsType := "selector_exists"
+		switch condition.Type {
+		case models.ConditionElementVisible:
+			jsType = string(models.WaitSelectorVisible)
+		case models.ConditionTextPresent:
+			jsType = string(models.WaitTextPresent)
+			if condition.Text == "" {
+				return false, fmt.Errorf("text_present requires text")
+			}
+		}
+		if condition.Type != models.ConditionTextPresent && condition.Selector == "" && condition.XPath == "" {
+			return false, fmt.Errorf("%s requires selector or xpath", condition.Type)
+		}
+		check = func(ctx context.Context) (bool, error) {
+			result, err := page.Context(ctx).Eval(waitConditionJS, jsType, condition.Selector, condition.XPath, condition.Text)
+			if err != nil {
+				return false, err
+			}
+			return result.Value.Bool(), nil
+		}
+	}
+
+	if condition.Timeout <= 0 {
+		return check(ctx)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, time.Duration(condition.Timeout)*time.Millisecond)
+	defer cancel()
+	err := pollCondition(tctx, func() (bool, error) { return check(tctx) })
+	if err == nil {
+		return true, nil
+	}
+	// 超时视为条件不成立
+	if ctx.Err() == nil && errors.Is(tctx.Err(), context.DeadlineExceeded) {
+		return false, nil
+	}
+	return false, err
+}
+
+// describeCondition 生成条件的描述（用于日志）
+func describeCondition(condition *models.ActionCondition) string {
+	if condition == nil {
+		return ""
+	}
+
+	var desc string
+	target := condition.XPath
+	if target == "" {
+		target = condition.Selector
+	}
+	switch condition.Type {
+	case "", models.ConditionVariable:
+		desc = strings.TrimSpace(fmt.Sprintf("%s %s %s", condition.Variable, condition.Operator, condition.Value))
+	case models.ConditionAnd, models.ConditionOr:
+		parts := make([]string, len(condition.Conditions))
+		for i := range condition.Conditions {
+			parts[i] = describeCondition(&condition.Conditions[i])
+		}
+		desc = "(" + strings.Join(parts, " "+strings.ToUpper(string(condition.Type))+" ") + ")"
+	case models.ConditionURLMatches:
+		desc = fmt.Sprintf("url matches %q", condition.Pattern)
+	case models.ConditionJS:
+		desc = fmt.Sprintf("js %q", condition.Expression)
+	case models.ConditionTextPresent:
+		desc = fmt.Sprintf("text %q present", condition.Text)
+		if target != "" {
+			desc = fmt.Sprintf("text %q present in %s", condition.Text, target)
+		}
+	default:
+		desc = fmt.Sprintf("%s %s", condition.Type, target)
+	}
+
+	if condition.Negate {
+		return "NOT " + desc
+	}
+	return desc
+}
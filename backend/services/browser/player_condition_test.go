@@ -0,0 +1,69 @@
+package browser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+func TestEvaluateConditionGroups(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	p := NewPlayer("en")
+	p.variables = map[string]string{"status": "ok", "count": "3", "price": "$1,299.00"}
+
+	statusOK := models.ActionCondition{Variable: "status", Operator: "=", Value: "ok"}
+	countBig := models.ActionCondition{Variable: "count", Operator: ">", Value: "5"}
+	missing := models.ActionCondition{Variable: "missing", Operator: "=", Value: "x"}
+
+	tests := []struct {
+		name    string
+		cond    models.ActionCondition
+		want    bool
+		wantErr bool
+	}{
+		{"variable", statusOK, true, false},
+		{"negate", models.ActionCondition{Variable: "status", Operator: "=", Value: "ok", Negate: true}, false, false},
+		{"and", models.ActionCondition{Type: models.ConditionAnd, Conditions: []models.ActionCondition{statusOK, countBig}}, false, false},
+		{"or", models.ActionCondition{Type: models.ConditionOr, Conditions: []models.ActionCondition{countBig, statusOK}}, true, false},
+		{"or short-circuits", models.ActionCondition{Type: models.ConditionOr, Conditions: []models.ActionCondition{statusOK, missing}}, true, false},
+		{"and short-circuits", models.ActionCondition{Type: models.ConditionAnd, Conditions: []models.ActionCondition{countBig, missing}}, false, false},
+		{"nested", models.ActionCondition{Type: models.ConditionAnd, Conditions: []models.ActionCondition{
+			statusOK,
+			{Type: models.ConditionOr, Conditions: []models.ActionCondition{countBig, {Variable: "count", Operator: "exists"}}},
+		}}, true, false},
+		{"empty group", models.ActionCondition{Type: models.ConditionAnd}, false, true},
+		{"missing variable", missing, false, true},
+		{"page condition without page", models.ActionCondition{Type: models.ConditionElementExists, Selector: "#x"}, false, true},
+		{"unknown type", models.ActionCondition{Type: "unknown"}, false, true},
+		{"transformed", models.ActionCondition{Variable: "price", Operator: ">", Value: "1000", Transform: "number"}, true, false},
+		{"transform without match", models.ActionCondition{Variable: "price", Operator: "not_exists", Transform: `regex:(\d+)%`}, true, false},
+		{"invalid transform", models.ActionCondition{Variable: "price", Operator: "exists", Transform: "bogus"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.evaluateCondition(context.Background(), nil, &tt.cond)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluateCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evaluateCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeCondition(t *testing.T) {
+	cond := &models.ActionCondition{
+		Type: models.ConditionOr,
+		Conditions: []models.ActionCondition{
+			{Type: models.ConditionElementVisible, Selector: "#cookie-banner"},
+			{Variable: "logged_in", Operator: "=", Value: "true", Negate: true},
+		},
+	}
+	want := `(element_visible #cookie-banner OR NOT logged_in = true)`
+	if got := describeCondition(cond); got != want {
+		t.Errorf("describeCondition() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package browser
+
+import (
+	"context"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// debugBeforeStep 调试模式下在断点或单步执行时于步骤执行前暂停，返回是否跳过该步骤
+func (p *Player) debugBeforeStep(ctx context.Context, page *rod.Page, actions []models.ScriptAction, index int) (bool, error) {
+	if p.debugger == nil || !p.debugger.shouldPause(index) {
+		return false, nil
+	}
+
+	cmd, err := p.debugPause(ctx, page, actions, index, "")
+	if err != nil {
+		return false, err
+	}
+	switch cmd.Command {
+	case models.DebugCommandSkip:
+		logger.Info(ctx, "Debugger skipped step %d", index+1)
+		return true, nil
+	case models.DebugCommandRetry:
+		p.debugEditAction(actions, index, cmd.Action)
+	}
+	return false, nil
+}
+
+// debugAfterFailure 调试模式下在步骤失败后暂停，返回是否重新执行该步骤
+func (p *Player) debugAfterFailure(ctx context.Context, page *rod.Page, actions []models.ScriptAction, index int, stepErr error) (bool, error) {
+	if p.debugger == nil || !p.debugger.shouldBreakOnFailure() {
+		return false, nil
+	}
+
+	cmd, err := p.debugPause(ctx, page, actions, index, stepErr.Error())
+	if err != nil {
+		return false, err
+	}
+	if cmd.Command != models.DebugCommandRetry {
+		return false, nil
+	}
+	p.debugEditAction(actions, index, cmd.Action)
+	p.debugger.skipNextPause()
+	logger.Info(ctx, "Debugger retrying step %d", index+1)
+	return true, nil
+}
+
+// debugPause 在指定步骤暂停，更新页面指示器并等待调试命令
+func (p *Player) debugPause(ctx context.Context, page *rod.Page, actions []models.ScriptAction, index int, lastErr string) (models.DebugCommand, error) {
+	action := actions[index]
+	p.updateAIControlStatus(ctx, page, index+1, len(actions), action.Type)
+	p.markStepPaused(ctx, page, index+1)
+	logger.Info(ctx, "Debugger paused at step %d (%s)", index+1, action.Type)
+
+	state := models.DebugState{
+		StepIndex:     index,
+		TotalSteps:    len(actions),
+		Action:        &action,
+		LastError:     lastErr,
+		Variables:     make(map[string]string, len(p.variables)),
+		ExtractedData: make(map[string]interface{}, len(p.extractedData)),
+	}
+	for k, v := range p.variables {
+		state.Variables[k] = v
+	}
+	for k, v := range p.extractedData {
+		state.ExtractedData[k] = v
+	}
+	if err := p.secrets.RedactJSON(&state); err != nil {
+		logger.Warn(ctx, "Failed to redact debug state: %v", err)
+	}
+
+	activePage := p.currentPage
+	if activePage == nil {
+		activePage = page
+	}
+	if info, err := activePage.Timeout(3 * time.Second).Info(); err == nil {
+		state.PageURL = info.URL
+		state.PageTitle = info.Title
+	}
+
+	cmd, err := p.debugger.pause(ctx, state)
+	if err != nil {
+		return cmd, err
+	}
+	logger.Info(ctx, "Debugger command: %s", cmd.Command)
+	return cmd, nil
+}
+
+// debugEditAction 用调试命令附带的操作替换当前步骤
+func (p *Player) debugEditAction(actions []models.ScriptAction, index int, edited *models.ScriptAction) {
+	if edited == nil {
+		return
+	}
+	actions[index] = *edited
+	p.debugger.recordEdit(index, *edited)
+}
+
+// undoStepFailure 撤销失败步骤的统计（调试重试时使用，步骤记录保留以便查看每次尝试）
+func (p *Player) undoStepFailure(action models.ScriptAction, err error) {
+	if _, ok := asAssertionFailure(err); ok && isAssertAction(action.Type) {
+		if n := len(p.assertions); n > 0 {
+			p.assertions = p.assertions[:n-1]
+		}
+		return
+	}
+	p.failCount--
+}
+
+// markStepPaused 在页面指示器中将步骤标记为已暂停
+func (p *Player) markStepPaused(ctx context.Context, page *rod.Page, stepIndex int) {
+	if page == nil {
+		return
+	}
+
+	_, err := page.Eval(`(stepIndex) => {
+		const stepItem = document.getElementById('browserwing-ai-step-' + stepIndex);
+		if (!stepItem) return false;
+
+		const statusIcon = stepItem.querySelector('.browserwing-step-status');
+		if (!statusIcon) return false;
+
+		stepItem.style.setProperty('background', 'linear-gradient(135deg, #fef3c7 0%, #fde68a 100%)', 'important');
+		stepItem.style.setProperty('border-left', '4px solid #f59e0b', 'important');
+
+		// 暂停的 SVG 图标
+		statusIcon.innerHTML = '<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" fill="currentColor" style="width: 14px !important; height: 14px !important;"><path d="M6 5h4v14H6zM14 5h4v14h-4z"/></svg>';
+		statusIcon.style.setProperty('background', '#f59e0b', 'important');
+		statusIcon.style.setProperty('animation', 'none', 'important');
+		return true;
+	}`, stepIndex)
+	if err != nil {
+		logger.Warn(ctx, "Failed to mark step as paused: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/dom"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// executeExtractList 执行列表抓取操作：对每个列表项容器按字段定义提取数据，生成对象数组
+func (p *Player) executeExtractList(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	if len(action.Fields) == 0 {
+		return fmt.Errorf("extract_list requires at least one field")
+	}
+
+	logger.Info(ctx, "Extract list data: %s", assertTarget(action))
+
+	containers, err := p.findAllElements(ctx, page, action)
+	if err != nil {
+		return err
+	}
+
+	// 字段按名称排序，保证日志和错误输出稳定
+	names := make([]string, 0, len(action.Fields))
+	for name := range action.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]map[string]interface{}, 0, len(containers))
+	for i, container := range containers {
+		row := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			value, err := extractListField(container, action.Fields[name])
+			if err != nil {
+				logger.Warn(ctx, "Failed to extract field %s of item #%d: %v", name, i, err)
+			}
+			row[name] = value
+		}
+		rows = append(rows, row)
+	}
+
+	varName := action.VariableName
+	if varName == "" {
+		varName = fmt.Sprintf("list_data_%d", len(p.extractedData))
+	}
+	if err := p.storeExtracted(action, varName, rows); err != nil {
+		return err
+	}
+
+	logger.Info(ctx, "✓ List extraction successful: %s = %d items", varName, len(rows))
+	return nil
+}
+
+// extractListField 在列表项容器内按字段定义提取值
+func extractListField(container *rod.Element, field models.ExtractField) (interface{}, error) {
+	targets := rod.Elements{container}
+	if field.Selector != "" {
+		// Elements 不会等待元素出现，缺失字段直接返回空值
+		elements, err := container.Elements(field.Selector)
+		if err != nil {
+			return nil, err
+		}
+		targets = elements
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	if !field.Multiple {
+		targets = targets[:1]
+	}
+
+	records := make([]map[string]interface{}, 0, len(targets))
+	for _, target := range targets {
+		data, err := dom.ExtractElementData(target, field.Type, field.Attribute, nil)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, data)
+	}
+	return listFieldValue(records, field)
+}
+
+// listFieldValue 按字段定义从提取结果中取值并应用值转换（Multiple 时返回数组）
+func listFieldValue(records []map[string]interface{}, field models.ExtractField) (interface{}, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	values := make([]interface{}, 0, len(records))
+	for _, data := range records {
+		value, err := interpolate.Transform(dom.FieldValue(data, field.Type, field.Attribute), field.Transform)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	if field.Multiple {
+		return values, nil
+	}
+	return values[0], nil
+}
@@ -0,0 +1,79 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestListFieldValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []map[string]interface{}
+		field   models.ExtractField
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:    "text field",
+			records: []map[string]interface{}{{"text": " Shoes "}},
+			field:   models.ExtractField{Selector: ".title"},
+			want:    "Shoes",
+		},
+		{
+			name:    "attribute field",
+			records: []map[string]interface{}{{"href": "/item/1"}},
+			field:   models.ExtractField{Selector: "a", Type: "attribute", Attribute: "href"},
+			want:    "/item/1",
+		},
+		{
+			name:    "missing element",
+			records: nil,
+			field:   models.ExtractField{Selector: ".price", Transform: "number"},
+			want:    nil,
+		},
+		{
+			name:    "missing attribute",
+			records: []map[string]interface{}{nil},
+			field:   models.ExtractField{Type: "attribute", Attribute: "src"},
+			want:    nil,
+		},
+		{
+			name:    "transform",
+			records: []map[string]interface{}{{"text": "$1,299.00"}},
+			field:   models.ExtractField{Transform: "number"},
+			want:    1299.0,
+		},
+		{
+			name:    "default for missing attribute",
+			records: []map[string]interface{}{nil},
+			field:   models.ExtractField{Type: "attribute", Attribute: "alt", Transform: "default:none"},
+			want:    "none",
+		},
+		{
+			name:    "multiple values are transformed individually",
+			records: []map[string]interface{}{{"text": "Red"}, {"text": " Blue"}},
+			field:   models.ExtractField{Multiple: true, Transform: "lower"},
+			want:    []interface{}{"red", "blue"},
+		},
+		{
+			name:    "transform error",
+			records: []map[string]interface{}{{"text": "n/a"}},
+			field:   models.ExtractField{Transform: "number"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := listFieldValue(tt.records, tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("listFieldValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("listFieldValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
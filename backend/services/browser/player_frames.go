@@ -0,0 +1,162 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// frameHopTimeout 定位路径中单个 iframe / shadow host 的查找超时
+const frameHopTimeout = 5 * time.Second
+
+// frameScope 定位路径解析过程中的查找范围：页面（或 iframe 的 frame）及可选的 shadow root
+type frameScope struct {
+	page *rod.Page
+	root *rod.Element // 非空时在该 shadow root 内查找
+}
+
+// element 在查找范围内按 CSS 选择器查找元素（返回的元素不受查找超时限制）
+func (s frameScope) element(selector string, timeout time.Duration) (*rod.Element, error) {
+	var element *rod.Element
+	var err error
+	if s.root != nil {
+		element, err = s.root.Timeout(timeout).Element(selector)
+	} else {
+		element, err = s.page.Timeout(timeout).Element(selector)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return element.Context(s.page.GetContext()), nil
+}
+
+// elementX 在查找范围内按 XPath 查找元素（在 shadow root 内时 XPath 以 shadow root 为上下文节点）
+func (s frameScope) elementX(xpath string, timeout time.Duration) (*rod.Element, error) {
+	var element *rod.Element
+	var err error
+	if s.root != nil {
+		element, err = s.root.Timeout(timeout).ElementX(xpath)
+	} else {
+		element, err = s.page.Timeout(timeout).ElementX(xpath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return element.Context(s.page.GetContext()), nil
+}
+
+// resolveFramePath 依次进入定位路径中的 iframe 和 shadow root，返回最内层的查找范围
+func resolveFramePath(ctx context.Context, page *rod.Page, path []models.FrameHop) (frameScope, error) {
+	scope := frameScope{page: page}
+	for i, hop := range path {
+		if hop.Selector == "" {
+			return scope, fmt.Errorf("frame path hop %d has empty selector", i+1)
+		}
+
+		element, err := scope.element(hop.Selector, frameHopTimeout)
+		if err != nil {
+			return scope, fmt.Errorf("frame path hop %d (%s %s) not found: %w", i+1, hop.Type, hop.Selector, err)
+		}
+
+		switch hop.Type {
+		case models.FrameHopIframe:
+			frame, err := element.Frame()
+			if err != nil {
+				return scope, fmt.Errorf("failed to enter iframe %s: %w", hop.Selector, err)
+			}
+			if err := frame.Timeout(frameHopTimeout).WaitLoad(); err != nil {
+				logger.Warn(ctx, "Failed to wait for iframe %s to load: %v", hop.Selector, err)
+			}
+			scope = frameScope{page: frame}
+		case models.FrameHopShadow:
+			root, err := element.ShadowRoot()
+			if err != nil {
+				return scope, fmt.Errorf("shadow host %s has no open shadow root: %w", hop.Selector, err)
+			}
+			scope.root = root
+		default:
+			return scope, fmt.Errorf("unknown frame path hop type: %s", hop.Type)
+		}
+	}
+	return scope, nil
+}
+
+// findElementInFramePath 按定位路径查找位于嵌套 iframe 或 Shadow DOM 中的元素
+func (p *Player) findElementInFramePath(ctx context.Context, page *rod.Page, action models.ScriptAction) (*elementContext, error) {
+	pathDesc := describeFramePath(action.FramePath)
+	logger.Info(ctx, "Resolving frame path: %s", pathDesc)
+
+	scope, err := resolveFramePath(ctx, page, action.FramePath)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := action.Selector
+	if selector == "unknown" {
+		selector = ""
+	}
+
+	// shadow root 内 CSS 选择器更可靠，优先使用；普通文档中与顶层查找一致，优先使用 XPath
+	type lookup struct {
+		strategy string
+		value    string
+	}
+	var lookups []lookup
+	if scope.root != nil {
+		lookups = []lookup{{"css", selector}, {"xpath", action.XPath}}
+	} else {
+		lookups = []lookup{{"xpath", action.XPath}, {"css", selector}}
+	}
+
+	err = fmt.Errorf("missing valid selector")
+	for _, l := range lookups {
+		if l.value == "" {
+			continue
+		}
+		var element *rod.Element
+		if l.strategy == "xpath" {
+			element, err = scope.elementX(l.value, 5*time.Second)
+		} else {
+			element, err = scope.element(l.value, 5*time.Second)
+		}
+		if err != nil {
+			logger.Warn(ctx, "%s lookup in frame path failed: %v", strings.ToUpper(l.strategy), err)
+			continue
+		}
+
+		logger.Info(ctx, "✓ Found element in frame path %s", pathDesc)
+		p.recordLocator(models.LocatorResolution{
+			StepIndex: p.currentStepIndex,
+			Strategy:  l.strategy,
+			Selector:  pathDesc + " >> " + l.value,
+		})
+		return &elementContext{
+			element: element,
+			page:    scope.page,
+		}, nil
+	}
+
+	// 最内层是普通文档时，尝试使用录制时的语义信息自愈
+	if scope.root == nil {
+		healed, healErr := p.healElement(ctx, scope.page, action)
+		if healErr == nil {
+			return healed, nil
+		}
+		logger.Warn(ctx, "Self-healing lookup failed: %v", healErr)
+	}
+	return nil, fmt.Errorf("element not found in frame path %s: %w", pathDesc, err)
+}
+
+// describeFramePath 生成定位路径的可读描述，例如 iframe(#main) >> shadow(my-app)
+func describeFramePath(path []models.FrameHop) string {
+	parts := make([]string, len(path))
+	for i, hop := range path {
+		parts[i] = fmt.Sprintf("%s(%s)", hop.Type, hop.Selector)
+	}
+	return strings.Join(parts, " >> ")
+}
@@ -0,0 +1,34 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestDescribeFramePath(t *testing.T) {
+	tests := []struct {
+		name string
+		path []models.FrameHop
+		want string
+	}{
+		{"empty", nil, ""},
+		{"single iframe", []models.FrameHop{{Type: models.FrameHopIframe, Selector: "#main"}}, "iframe(#main)"},
+		{
+			"nested",
+			[]models.FrameHop{
+				{Type: models.FrameHopShadow, Selector: "my-app"},
+				{Type: models.FrameHopIframe, Selector: "iframe:nth-of-type(2)"},
+				{Type: models.FrameHopShadow, Selector: "#form > x-input"},
+			},
+			"shadow(my-app) >> iframe(iframe:nth-of-type(2)) >> shadow(#form > x-input)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeFramePath(tt.path); got != tt.want {
+				t.Errorf("describeFramePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
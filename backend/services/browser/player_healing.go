@@ -0,0 +1,293 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/dom"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+const (
+	// healMaxCandidates 每个自愈策略最多评估的候选元素数量
+	healMaxCandidates = 20
+	// healMinScore 自愈候选被接受的最低分数
+	healMinScore = 0.5
+)
+
+// healStrategy 自愈查找策略
+type healStrategy struct {
+	name  string  // 策略名称: role, attributes, text
+	xpath string  // 候选元素 XPath
+	base  float64 // 策略基础分
+}
+
+// healCandidate 自愈候选元素
+type healCandidate struct {
+	element  *rod.Element
+	strategy healStrategy
+	score    float64
+}
+
+// healElement 原始定位器失效时，根据录制的语义信息（Intent/Accessibility/Context/Evidence）查找并评分候选元素
+func (p *Player) healElement(ctx context.Context, page *rod.Page, action models.ScriptAction) (*elementContext, error) {
+	strategies := buildHealStrategies(action)
+	if len(strategies) == 0 {
+		return nil, fmt.Errorf("no semantic information available for self-healing")
+	}
+
+	logger.Info(ctx, "Original locator failed, trying %d self-healing strategies", len(strategies))
+
+	var candidates []healCandidate
+	for _, strategy := range strategies {
+		elements, err := page.Timeout(2 * time.Second).ElementsX(strategy.xpath)
+		if err != nil || len(elements) == 0 {
+			continue
+		}
+		matches := len(elements)
+		if len(elements) > healMaxCandidates {
+			elements = elements[:healMaxCandidates]
+		}
+
+		for _, element := range elements {
+			contextScore, err := scoreHealCandidate(element, action)
+			if err != nil {
+				continue
+			}
+			candidates = append(candidates, healCandidate{
+				element:  element,
+				strategy: strategy,
+				score:    healScore(strategy, contextScore, matches),
+			})
+		}
+	}
+
+	best := bestHealCandidate(candidates)
+	if best == nil {
+		return nil, fmt.Errorf("no candidate element matched the recorded semantics")
+	}
+
+	logger.Info(ctx, "✓ Self-healing matched element via %s (score: %.2f): %s", best.strategy.name, best.score, best.strategy.xpath)
+	p.recordLocator(models.LocatorResolution{
+		StepIndex: p.currentStepIndex,
+		Strategy:  best.strategy.name,
+		Selector:  best.strategy.xpath,
+		Score:     best.score,
+		Healed:    true,
+	})
+
+	return &elementContext{
+		element: best.element,
+		page:    page,
+	}, nil
+}
+
+// healScore 候选元素的总分：策略基础分 + 上下文评分，策略唯一匹配时额外加分
+func healScore(strategy healStrategy, contextScore float64, matches int) float64 {
+	score := strategy.base + contextScore
+	if matches == 1 {
+		score += 0.1
+	}
+	return score
+}
+
+// bestHealCandidate 返回得分最高的候选（同分时取先出现的，即优先级更高的策略），最高分低于 healMinScore 时返回 nil
+func bestHealCandidate(candidates []healCandidate) *healCandidate {
+	var best *healCandidate
+	for i := range candidates {
+		if best == nil || candidates[i].score > best.score {
+			best = &candidates[i]
+		}
+	}
+	if best == nil || best.score < healMinScore {
+		return nil
+	}
+	return best
+}
+
+// recordLocator 记录步骤实际命中的定位策略（同一步骤重试时覆盖）
+func (p *Player) recordLocator(resolution models.LocatorResolution) {
+	if p.locatorResults == nil {
+		p.locatorResults = make(map[int]models.LocatorResolution)
+	}
+	p.locatorResults[resolution.StepIndex] = resolution
+	p.stepLocator = &resolution
+}
+
+// buildHealStrategies 根据录制的语义信息构建自愈策略列表
+func buildHealStrategies(action models.ScriptAction) []healStrategy {
+	var strategies []healStrategy
+
+	// 1. ARIA role + name
+	if action.Accessibility != nil && action.Accessibility.Role != "" {
+		if xpath := dom.BuildXPathFromRole(action.Accessibility.Role, action.Accessibility.Name); xpath != "" {
+			base := 0.4
+			if action.Accessibility.Name != "" {
+				base = 0.5
+			}
+			strategies = append(strategies, healStrategy{name: "role", xpath: xpath, base: base})
+		}
+	}
+
+	// 2. 稳定属性
+	tag := strings.ToLower(action.TagName)
+	if tag == "" {
+		tag = "*"
+	}
+	for _, attr := range []string{"id", "data-testid", "name", "aria-label", "placeholder"} {
+		value := strings.TrimSpace(action.Attrs[attr])
+		if value == "" {
+			continue
+		}
+		strategies = append(strategies, healStrategy{
+			name:  "attributes",
+			xpath: fmt.Sprintf("//%s[@%s=%s]", tag, attr, xpathLiteral(value)),
+			base:  0.45,
+		})
+	}
+
+	// 3. 可见文本
+	text := strings.TrimSpace(action.Text)
+	if text == "" && action.Accessibility != nil {
+		text = strings.TrimSpace(action.Accessibility.Name)
+	}
+	if text != "" {
+		var xpath string
+		if len([]rune(text)) > 40 {
+			xpath = fmt.Sprintf("//%s[contains(normalize-space(.), %s)]", tag, xpathLiteral(string([]rune(text)[:40])))
+		} else {
+			xpath = fmt.Sprintf("//%s[normalize-space(.)=%s]", tag, xpathLiteral(text))
+		}
+		strategies = append(strategies, healStrategy{name: "text", xpath: xpath, base: 0.35})
+	}
+
+	return strategies
+}
+
+// scoreHealCandidate 根据上下文信息为候选元素评分（0 ~ 0.6）
+func scoreHealCandidate(element *rod.Element, action models.ScriptAction) (float64, error) {
+	role, name := "", ""
+	if action.Accessibility != nil {
+		role, name = action.Accessibility.Role, action.Accessibility.Name
+	}
+	var nearbyText, ancestorTags []string
+	formHint := ""
+	if action.Context != nil {
+		nearbyText = action.Context.NearbyText
+		ancestorTags = action.Context.AncestorTags
+		formHint = action.Context.FormHint
+	}
+	if nearbyText == nil {
+		nearbyText = []string{}
+	}
+	if ancestorTags == nil {
+		ancestorTags = []string{}
+	}
+	verb := ""
+	if action.Intent != nil {
+		verb = action.Intent.Verb
+	}
+
+	result, err := element.Eval(`(tag, role, name, nearbyText, ancestorTags, formHint, verb) => {
+		const el = this;
+		const norm = (s) => (s || '').replace(/\s+/g, ' ').trim().toLowerCase();
+		let score = 0;
+
+		const rect = el.getBoundingClientRect();
+		const style = window.getComputedStyle(el);
+		if (rect.width > 0 && rect.height > 0 && style.visibility !== 'hidden' && style.display !== 'none') {
+			score += 0.1;
+		}
+
+		if (tag && el.tagName.toLowerCase() === tag.toLowerCase()) {
+			score += 0.1;
+		}
+
+		if (role && (el.getAttribute('role') || '').toLowerCase() === role.toLowerCase()) {
+			score += 0.05;
+		}
+
+		if (name) {
+			const label = norm(el.getAttribute('aria-label') || el.getAttribute('placeholder') || el.getAttribute('title') || el.value || el.innerText);
+			if (label === norm(name)) {
+				score += 0.1;
+			} else if (label && label.includes(norm(name))) {
+				score += 0.05;
+			}
+		}
+
+		if (ancestorTags.length > 0) {
+			const matched = ancestorTags.filter(t => t && el.parentElement && el.parentElement.closest(t.toLowerCase())).length;
+			score += 0.1 * matched / ancestorTags.length;
+		}
+
+		if (nearbyText.length > 0) {
+			let container = el.parentElement;
+			let containerText = '';
+			for (let i = 0; i < 3 && container; i++) {
+				containerText = norm(container.innerText);
+				container = container.parentElement;
+			}
+			const matched = nearbyText.filter(t => t && containerText.includes(norm(t))).length;
+			score += 0.1 * matched / nearbyText.length;
+		}
+
+		if (formHint) {
+			const form = el.closest('form');
+			if (form) {
+				const hint = norm(formHint);
+				const formText = norm([form.id, form.getAttribute('name'), form.getAttribute('action'), form.getAttribute('aria-label')].join(' '));
+				if (formText.includes(hint)) {
+					score += 0.05;
+				}
+			}
+		}
+
+		if (verb === 'type' || verb === 'input' || verb === 'fill') {
+			if (el.matches('input, textarea, [contenteditable="true"]')) {
+				score += 0.05;
+			}
+		} else if (verb === 'click') {
+			if (el.matches('a, button, input[type="button"], input[type="submit"], [role="button"], [onclick]')) {
+				score += 0.05;
+			}
+		}
+
+		return score;
+	}`, strings.ToLower(action.TagName), role, name, nearbyText, ancestorTags, formHint, verb)
+	if err != nil {
+		return 0, err
+	}
+
+	score := result.Value.Num()
+	// 录制时的置信度作为权重微调
+	if action.Evidence != nil && action.Evidence.Confidence > 0 && action.Evidence.Confidence < 1 {
+		score *= 0.5 + action.Evidence.Confidence/2
+	}
+	return score, nil
+}
+
+// xpathLiteral 将字符串转换为 XPath 字符串字面量（处理引号）
+func xpathLiteral(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+	parts := strings.Split(s, "'")
+	quoted := make([]string, 0, len(parts)*2)
+	for i, part := range parts {
+		if i > 0 {
+			quoted = append(quoted, `"'"`)
+		}
+		if part != "" {
+			quoted = append(quoted, "'"+part+"'")
+		}
+	}
+	return "concat(" + strings.Join(quoted, ", ") + ")"
+}
@@ -0,0 +1,119 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/dom"
+)
+
+func TestBuildHealStrategies(t *testing.T) {
+	tests := []struct {
+		name   string
+		action models.ScriptAction
+		want   []healStrategy
+	}{
+		{
+			name:   "no semantic information",
+			action: models.ScriptAction{Type: "click", Selector: "#gone"},
+			want:   nil,
+		},
+		{
+			name: "role, attributes and text in priority order",
+			action: models.ScriptAction{
+				TagName:       "BUTTON",
+				Text:          "Sign in",
+				Attrs:         map[string]string{"name": "login", "id": "submit", "class": "btn"},
+				Accessibility: &models.AccessibilityInfo{Role: "button", Name: "Sign in"},
+			},
+			want: []healStrategy{
+				{name: "role", xpath: dom.BuildXPathFromRole("button", "Sign in"), base: 0.5},
+				{name: "attributes", xpath: "//button[@id='submit']", base: 0.45},
+				{name: "attributes", xpath: "//button[@name='login']", base: 0.45},
+				{name: "text", xpath: "//button[normalize-space(.)='Sign in']", base: 0.35},
+			},
+		},
+		{
+			name: "role without name has a lower base score",
+			action: models.ScriptAction{
+				Accessibility: &models.AccessibilityInfo{Role: "textbox"},
+			},
+			want: []healStrategy{
+				{name: "role", xpath: dom.BuildXPathFromRole("textbox", ""), base: 0.4},
+			},
+		},
+		{
+			name: "text falls back to accessible name and quotes are escaped",
+			action: models.ScriptAction{
+				Accessibility: &models.AccessibilityInfo{Name: "Don't click"},
+			},
+			want: []healStrategy{
+				{name: "text", xpath: `//*[normalize-space(.)="Don't click"]`, base: 0.35},
+			},
+		},
+		{
+			name: "long text uses a contains match",
+			action: models.ScriptAction{
+				TagName: "p",
+				Text:    "This paragraph is long enough to be truncated for matching",
+			},
+			want: []healStrategy{
+				{name: "text", xpath: "//p[contains(normalize-space(.), 'This paragraph is long enough to be trun')]", base: 0.35},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildHealStrategies(tt.action)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildHealStrategies() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestHealCandidate(t *testing.T) {
+	role := healStrategy{name: "role", base: 0.5}
+	attrs := healStrategy{name: "attributes", base: 0.45}
+	text := healStrategy{name: "text", base: 0.35}
+
+	tests := []struct {
+		name       string
+		candidates []healCandidate
+		want       string // 选中的策略名称，为空表示没有候选被接受
+	}{
+		{"no candidates", nil, ""},
+		{"below threshold", []healCandidate{
+			{strategy: text, score: healScore(text, 0.1, 3)},
+		}, ""},
+		{"unique match reaches threshold", []healCandidate{
+			{strategy: text, score: healScore(text, 0.1, 1)},
+		}, "text"},
+		{"exactly at threshold", []healCandidate{
+			{strategy: role, score: healScore(role, 0, 2)},
+		}, "role"},
+		{"highest score wins over strategy order", []healCandidate{
+			{strategy: role, score: healScore(role, 0.05, 2)},
+			{strategy: attrs, score: healScore(attrs, 0.1, 1)},
+		}, "attributes"},
+		{"tie keeps the earlier strategy", []healCandidate{
+			{strategy: role, score: 0.6},
+			{strategy: attrs, score: 0.6},
+		}, "role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best := bestHealCandidate(tt.candidates)
+			got := ""
+			if best != nil {
+				got = best.strategy.name
+			}
+			if got != tt.want {
+				t.Errorf("bestHealCandidate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,473 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+const (
+	// defaultMaxLoopIterations 条件循环的默认最大迭代次数
+	defaultMaxLoopIterations = 100
+	// loopItemAttribute foreach 遍历元素时写入的标记属性
+	loopItemAttribute = "data-browserwing-item"
+)
+
+// executeLoop 执行 loop 操作（固定次数或条件成立时循环）
+func (p *Player) executeLoop(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	if len(action.Actions) == 0 {
+		return fmt.Errorf("loop has no nested actions")
+	}
+
+	whileMode := action.LoopCondition != nil
+	if !whileMode && action.LoopCount <= 0 {
+		return fmt.Errorf("loop requires loop_count or loop_condition")
+	}
+
+	limit := action.LoopCount
+	if whileMode {
+		limit = action.MaxIterations
+		if limit <= 0 {
+			limit = defaultMaxLoopIterations
+		}
+		if action.LoopCount > 0 && action.LoopCount < limit {
+			limit = action.LoopCount
+		}
+	} else if action.MaxIterations > 0 && action.MaxIterations < limit {
+		limit = action.MaxIterations
+	}
+
+	logger.Info(ctx, "Start loop: max %d iterations, %d nested actions", limit, len(action.Actions))
+
+	indexVar := loopVariableName(action.IndexVariable, "index")
+	restore := p.saveVariables(indexVar)
+	defer restore()
+
+	collector := newLoopCollector(action.Actions)
+	failed, total := 0, 0
+	iterations := 0
+	for i := 0; i < limit; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if whileMode {
+			ok, err := p.evaluateCondition(ctx, page, action.LoopCondition)
+			if err != nil {
+				logger.Warn(ctx, "Loop condition evaluation failed, stopping loop: %v", err)
+				break
+			}
+			if !ok {
+				logger.Info(ctx, "Loop condition no longer met after %d iterations", i)
+				break
+			}
+		}
+
+		p.variables[indexVar] = strconv.Itoa(i)
+		logger.Info(ctx, "Loop iteration %d/%d", i+1, limit)
+
+		collector.begin(p.extractedData)
+		f, t, err := p.executeNestedActions(ctx, page, action.Actions)
+		collector.end(p.extractedData)
+		failed += f
+		total += t
+		if err != nil {
+			collector.flush(p.extractedData)
+			return err
+		}
+		iterations++
+	}
+
+	collector.flush(p.extractedData)
+	logger.Info(ctx, "✓ Loop completed: %d iterations", iterations)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d nested actions failed", failed, total)
+	}
+	return nil
+}
+
+// executeForeach 执行 foreach 操作（遍历匹配的元素或列表变量）
+func (p *Player) executeForeach(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	if len(action.Actions) == 0 {
+		return fmt.Errorf("foreach has no nested actions")
+	}
+
+	var items []interface{}
+	var itemSelectors []string
+	var err error
+	if action.ListVariable != "" {
+		items, err = p.resolveListVariable(action.ListVariable)
+	} else {
+		items, itemSelectors, err = p.markForeachElements(ctx, page, action)
+	}
+	if err != nil {
+		return err
+	}
+
+	limit := len(items)
+	if action.MaxIterations > 0 && action.MaxIterations < limit {
+		limit = action.MaxIterations
+	}
+
+	logger.Info(ctx, "Start foreach: %d items, %d nested actions", limit, len(action.Actions))
+
+	indexVar := loopVariableName(action.IndexVariable, "index")
+	itemVar := loopVariableName(action.ItemVariable, "item")
+	selectorVar := itemVar + "_selector"
+	restore := p.saveVariables(indexVar, itemVar, selectorVar)
+	defer restore()
+
+	collector := newLoopCollector(action.Actions)
+	failed, total := 0, 0
+	for i := 0; i < limit; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		p.variables[indexVar] = strconv.Itoa(i)
+		p.variables[itemVar] = interpolate.Stringify(items[i])
+		if itemSelectors != nil {
+			p.variables[selectorVar] = itemSelectors[i]
+		}
+		logger.Info(ctx, "Foreach iteration %d/%d", i+1, limit)
+
+		collector.begin(p.extractedData)
+		f, t, err := p.executeNestedActions(ctx, page, action.Actions)
+		collector.end(p.extractedData)
+		failed += f
+		total += t
+		if err != nil {
+			collector.flush(p.extractedData)
+			return err
+		}
+	}
+
+	collector.flush(p.extractedData)
+	logger.Info(ctx, "✓ Foreach completed: %d iterations", limit)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d nested actions failed", failed, total)
+	}
+	return nil
+}
+
+// executeNestedActions 执行操作块内的嵌套操作，返回失败数和执行总数；on_error=stop 或回放被取消时返回错误
+func (p *Player) executeNestedActions(ctx context.Context, page *rod.Page, actions []models.ScriptAction) (int, int, error) {
+	failed, total := 0, 0
+	jumps := 0
+	for i := 0; i < len(actions); i++ {
+		if err := ctx.Err(); err != nil {
+			return failed, total, err
+		}
+
+		action := p.resolveAction(actions[i])
+		p.warnUnresolved(ctx, action)
+
+		if action.Type != "if" && action.Condition != nil && action.Condition.Enabled {
+			shouldExecute, err := p.evaluateCondition(ctx, page, action.Condition)
+			if err != nil {
+				logger.Warn(ctx, "Failed to evaluate condition: %v", err)
+			} else if !shouldExecute {
+				logger.Info(ctx, "Skipping nested action due to condition not met: %s", action.Type)
+				p.recordSkippedStep(action, i)
+				continue
+			}
+		}
+
+		total++
+		logger.Info(ctx, "  [nested %d/%d] Execute action: %s", i+1, len(actions), action.Type)
+		if err := p.runStep(ctx, page, action, i); err != nil {
+			// 断言失败单独统计，不计入执行失败
+			if failure, ok := asAssertionFailure(err); ok && isAssertAction(action.Type) {
+				p.recordAssertion(failure.result)
+			} else {
+				failed++
+			}
+			if isStopPlayback(err) || ctx.Err() != nil {
+				return failed, total, err
+			}
+			logger.Warn(ctx, "Nested action execution failed (continuing): %v", err)
+			if target, ok := p.gotoTarget(ctx, actions, action, &jumps); ok {
+				i = target - 1
+			}
+			continue
+		}
+		p.syncExtractedVariable(ctx, action.VariableName)
+	}
+	return failed, total, nil
+}
+
+// markForeachElements 查找 foreach 要遍历的元素，并为每个元素写入标记属性以便嵌套操作定位
+func (p *Player) markForeachElements(ctx context.Context, page *rod.Page, action models.ScriptAction) ([]interface{}, []string, error) {
+	elements, err := p.findAllElements(ctx, page, action)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seq := p.loopSeq
+	p.loopSeq++
+
+	items := make([]interface{}, 0, len(elements))
+	selectors := make([]string, 0, len(elements))
+	for i, element := range elements {
+		marker := fmt.Sprintf("%d-%d", seq, i)
+		if _, err := element.Eval(`(attr, marker) => this.setAttribute(attr, marker)`, loopItemAttribute, marker); err != nil {
+			logger.Warn(ctx, "Failed to mark foreach element #%d: %v", i, err)
+			continue
+		}
+		text, _ := element.Text()
+		items = append(items, strings.TrimSpace(text))
+		selectors = append(selectors, fmt.Sprintf(`[%s="%s"]`, loopItemAttribute, marker))
+	}
+
+	return items, selectors, nil
+}
+
+// findAllElements 查找 Selector/XPath 匹配的所有元素（先等待至少一个元素出现）
+func (p *Player) findAllElements(ctx context.Context, page *rod.Page, action models.ScriptAction) (rod.Elements, error) {
+	var elements rod.Elements
+	var err error
+	if action.XPath != "" {
+		if _, waitErr := page.Timeout(5 * time.Second).ElementX(action.XPath); waitErr != nil {
+			logger.Warn(ctx, "No elements matched XPath: %s", action.XPath)
+		}
+		elements, err = page.ElementsX(action.XPath)
+	} else if action.Selector != "" && action.Selector != "unknown" {
+		if _, waitErr := page.Timeout(5 * time.Second).Element(action.Selector); waitErr != nil {
+			logger.Warn(ctx, "No elements matched selector: %s", action.Selector)
+		}
+		elements, err = page.Elements(action.Selector)
+	} else {
+		return nil, fmt.Errorf("missing valid selector")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find elements: %w", err)
+	}
+	return elements, nil
+}
+
+// resolveListVariable 解析 foreach 遍历的列表变量（优先使用抓取结果，其次是 JSON 数组或逗号/换行分隔的字符串变量）
+func (p *Player) resolveListVariable(name string) ([]interface{}, error) {
+	if value, ok := p.extractedData[name]; ok {
+		if list, ok := toInterfaceSlice(value); ok {
+			return list, nil
+		}
+		if str, ok := value.(string); ok {
+			return splitListString(str), nil
+		}
+	}
+
+	if value, ok := p.variables[name]; ok {
+		return splitListString(value), nil
+	}
+
+	return nil, fmt.Errorf("list variable not found: %s", name)
+}
+
+// saveVariables 保存循环变量的原值，返回恢复函数（支持嵌套循环）
+func (p *Player) saveVariables(names ...string) func() {
+	saved := make(map[string]string)
+	for _, name := range names {
+		if value, ok := p.variables[name]; ok {
+			saved[name] = value
+		}
+	}
+	return func() {
+		for _, name := range names {
+			if value, ok := saved[name]; ok {
+				p.variables[name] = value
+			} else {
+				delete(p.variables, name)
+			}
+		}
+	}
+}
+
+// loopCollector 将循环体每次迭代抓取的数据收集为数组
+type loopCollector struct {
+	bodyKeys []string                 // 循环体内显式声明的变量名
+	original map[string]interface{}   // 循环开始前的抓取数据快照（循环未产生值时恢复）
+	before   map[string]interface{}   // 迭代开始前的抓取数据快照
+	values   map[string][]interface{} // 按变量名收集的迭代结果
+}
+
+// newLoopCollector 创建循环数据收集器
+func newLoopCollector(actions []models.ScriptAction) *loopCollector {
+	return &loopCollector{
+		bodyKeys: collectVariableNames(actions),
+		values:   make(map[string][]interface{}),
+	}
+}
+
+// begin 迭代开始：清除上一次迭代写入的数据并记录快照
+func (c *loopCollector) begin(data map[string]interface{}) {
+	if c.original == nil {
+		c.original = make(map[string]interface{}, len(data))
+		for k, v := range data {
+			c.original[k] = v
+		}
+	}
+	for _, key := range c.bodyKeys {
+		delete(data, key)
+	}
+	for key := range c.values {
+		delete(data, key)
+	}
+	c.before = make(map[string]interface{}, len(data))
+	for k, v := range data {
+		c.before[k] = v
+	}
+}
+
+// end 迭代结束：收集本次迭代新写入或变化的数据
+func (c *loopCollector) end(data map[string]interface{}) {
+	for k, v := range data {
+		if old, ok := c.before[k]; ok && reflect.DeepEqual(old, v) {
+			continue
+		}
+		c.values[k] = append(c.values[k], v)
+	}
+	// 显式声明的变量本次未抓取到时补 nil，保持各字段数组按迭代对齐
+	for _, key := range c.bodyKeys {
+		if _, ok := data[key]; !ok {
+			c.values[key] = append(c.values[key], nil)
+		}
+	}
+}
+
+// flush 将收集到的数组写回抓取结果
+func (c *loopCollector) flush(data map[string]interface{}) {
+	for k, v := range c.values {
+		if c.restore(data, k, v) {
+			continue
+		}
+		data[k] = v
+	}
+}
+
+// restore 变量在所有迭代中都没有抓取到值时恢复循环开始前的值，返回是否已恢复
+func (c *loopCollector) restore(data map[string]interface{}, key string, values []interface{}) bool {
+	original, ok := c.original[key]
+	if !ok {
+		return false
+	}
+	for _, v := range values {
+		if v != nil {
+			return false
+		}
+	}
+	data[key] = original
+	return true
+}
+
+// flushConcat 将收集到的结果写回抓取结果，每次迭代的数组结果拼接为一个数组（用于分页抓取）
+func (c *loopCollector) flushConcat(data map[string]interface{}) {
+	for k, values := range c.values {
+		if c.restore(data, k, values) {
+			continue
+		}
+		concatenated := make([]interface{}, 0, len(values))
+		allLists := true
+		for _, v := range values {
+			if v == nil {
+				continue
+			}
+			list, ok := toInterfaceSlice(v)
+			if !ok {
+				allLists = false
+				break
+			}
+			concatenated = append(concatenated, list...)
+		}
+		if allLists {
+			data[k] = concatenated
+		} else {
+			data[k] = values
+		}
+	}
+}
+
+// collectVariableNames 递归收集操作列表中声明的变量名
+func collectVariableNames(actions []models.ScriptAction) []string {
+	var names []string
+	for _, action := range actions {
+		if action.VariableName != "" && !strings.Contains(action.VariableName, "${") {
+			names = append(names, action.VariableName)
+		}
+		names = append(names, collectVariableNames(action.Actions)...)
+		names = append(names, collectVariableNames(action.ElseActions)...)
+	}
+	return names
+}
+
+// flattenActions 展开嵌套操作列表（深度优先）
+func flattenActions(actions []models.ScriptAction) []models.ScriptAction {
+	var flat []models.ScriptAction
+	for _, action := range actions {
+		flat = append(flat, action)
+		flat = append(flat, flattenActions(action.Actions)...)
+		flat = append(flat, flattenActions(action.ElseActions)...)
+	}
+	return flat
+}
+
+// loopVariableName 返回循环变量名（为空时使用默认名）
+func loopVariableName(name, defaultName string) string {
+	if name == "" {
+		return defaultName
+	}
+	return name
+}
+
+// toInterfaceSlice 将任意切片类型转换为 []interface{}
+func toInterfaceSlice(value interface{}) ([]interface{}, bool) {
+	// execute_js 的结果为 gson.JSON，先取出原始值
+	if j, ok := value.(interface{ Val() interface{} }); ok {
+		value = j.Val()
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	list := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
+
+// splitListString 解析字符串形式的列表：JSON 数组，或按换行/逗号分隔
+func splitListString(value string) []interface{} {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return []interface{}{}
+	}
+
+	var list []interface{}
+	if strings.HasPrefix(value, "[") && json.Unmarshal([]byte(value), &list) == nil {
+		return list
+	}
+
+	sep := ","
+	if strings.Contains(value, "\n") {
+		sep = "\n"
+	}
+	parts := strings.Split(value, sep)
+	list = make([]interface{}, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
@@ -0,0 +1,146 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestLoopCollector(t *testing.T) {
+	body := []models.ScriptAction{
+		{Type: "extract_text", VariableName: "title"},
+		{Type: "extract_attribute", VariableName: "link"},
+	}
+	data := map[string]interface{}{"title": "before", "other": "keep"}
+
+	// 每次迭代写入的数据（nil 表示该次迭代未抓取到）
+	iterations := []map[string]interface{}{
+		{"title": "a", "link": "/a"},
+		{"title": "a", "link": nil},
+		{"title": "c", "link": "/c"},
+	}
+
+	collector := newLoopCollector(body)
+	for _, iteration := range iterations {
+		collector.begin(data)
+		for k, v := range iteration {
+			if v != nil {
+				data[k] = v
+			}
+		}
+		collector.end(data)
+	}
+	collector.flush(data)
+
+	want := map[string]interface{}{
+		"title": []interface{}{"a", "a", "c"},
+		"link":  []interface{}{"/a", nil, "/c"},
+		"other": "keep",
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("collected data = %v, want %v", data, want)
+	}
+}
+
+func TestSplitListString(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []interface{}
+	}{
+		{name: "JSON array", input: `["a", 1, true]`, want: []interface{}{"a", float64(1), true}},
+		{name: "Comma separated", input: "a, b ,c", want: []interface{}{"a", "b", "c"}},
+		{name: "Newline separated", input: "a,1\nb,2\n", want: []interface{}{"a,1", "b,2"}},
+		{name: "Empty", input: "  ", want: []interface{}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitListString(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitListString(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoopCollectorFlushConcat(t *testing.T) {
+	body := []models.ScriptAction{
+		{Type: "extract_list", VariableName: "products"},
+		{Type: "extract_text", VariableName: "heading"},
+	}
+	data := map[string]interface{}{}
+
+	// 每页的列表结果拼接为一个数组，非列表结果保持按页收集
+	pages := []map[string]interface{}{
+		{"products": []map[string]interface{}{{"name": "a"}, {"name": "b"}}, "heading": "Page 1"},
+		{"products": []map[string]interface{}{{"name": "c"}}, "heading": "Page 2"},
+	}
+
+	collector := newLoopCollector(body)
+	for _, page := range pages {
+		collector.begin(data)
+		for k, v := range page {
+			data[k] = v
+		}
+		collector.end(data)
+	}
+	collector.flushConcat(data)
+
+	want := map[string]interface{}{
+		"products": []interface{}{
+			map[string]interface{}{"name": "a"},
+			map[string]interface{}{"name": "b"},
+			map[string]interface{}{"name": "c"},
+		},
+		"heading": []interface{}{"Page 1", "Page 2"},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("collected data = %v, want %v", data, want)
+	}
+}
+
+func TestLoopCollectorRestoresPreLoopValues(t *testing.T) {
+	body := []models.ScriptAction{
+		{Type: "extract_text", VariableName: "title"},
+		{Type: "extract_text", VariableName: "price"},
+		{Type: "extract_text", VariableName: "note"},
+	}
+
+	// title 从未抓取到，恢复循环前的值；price 至少抓取到一次，按迭代收集；note 循环前不存在，保持原有的收集结果
+	iterations := []map[string]interface{}{
+		{},
+		{"price": "5.00"},
+	}
+
+	for _, flushConcat := range []bool{false, true} {
+		data := map[string]interface{}{"title": "before", "price": "9.99"}
+		collector := newLoopCollector(body)
+		for _, iteration := range iterations {
+			collector.begin(data)
+			for k, v := range iteration {
+				data[k] = v
+			}
+			collector.end(data)
+		}
+		if flushConcat {
+			collector.flushConcat(data)
+		} else {
+			collector.flush(data)
+		}
+
+		want := map[string]interface{}{
+			"title": "before",
+			"price": []interface{}{nil, "5.00"},
+			"note":  []interface{}{nil, nil},
+		}
+		if flushConcat {
+			// 分页拼接时未抓取到的列表结果为空数组
+			want["note"] = []interface{}{}
+		}
+		if !reflect.DeepEqual(data, want) {
+			t.Errorf("flushConcat=%v: collected data = %v, want %v", flushConcat, data, want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	// defaultMaxPages 分页抓取的默认最大页数
+	defaultMaxPages = 50
+	// defaultPageWait 翻页后的默认等待时长（毫秒）
+	defaultPageWait = 1000
+)
+
+// executePaginate 执行分页抓取：每页执行操作块，然后点击下一页或按 URL 模板跳转，直到达到最大页数、没有下一页或内容不再变化
+func (p *Player) executePaginate(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	cfg := action.Pagination
+	if cfg == nil {
+		return fmt.Errorf("paginate requires pagination config")
+	}
+	if len(action.Actions) == 0 {
+		return fmt.Errorf("paginate has no nested actions")
+	}
+
+	clickMode := cfg.NextSelector != "" || cfg.NextXPath != ""
+	if !clickMode && cfg.URLTemplate == "" {
+		return fmt.Errorf("paginate requires next_selector, next_xpath or url_template")
+	}
+
+	maxPages := cfg.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	waitAfter := cfg.WaitAfter
+	if waitAfter <= 0 {
+		waitAfter = defaultPageWait
+	}
+	startPage := cfg.StartPage
+	if startPage <= 0 {
+		startPage = 1
+	}
+
+	pageVar := loopVariableName(action.IndexVariable, "page")
+	restore := p.saveVariables(pageVar)
+	defer restore()
+
+	logger.Info(ctx, "Start pagination: max %d pages", maxPages)
+
+	collector := newLoopCollector(action.Actions)
+	defer collector.flushConcat(p.extractedData)
+
+	failed, total := 0, 0
+	pages := 0
+	previous := ""
+	for i := 0; i < maxPages; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		pageNumber := startPage + i
+		p.variables[pageVar] = strconv.Itoa(pageNumber)
+
+		// URL 模板模式：按页码跳转
+		if !clickMode {
+			pageURL := p.render(cfg.URLTemplate)
+			logger.Info(ctx, "Navigate to page %d: %s", pageNumber, pageURL)
+			if err := page.Navigate(pageURL); err != nil {
+				return fmt.Errorf("navigation failed: %w", err)
+			}
+			if err := page.WaitLoad(); err != nil {
+				logger.Warn(ctx, "Failed to wait for page to load: %v", err)
+			}
+		}
+
+		// 内容未变化说明已到最后一页（或翻页无效）
+		fingerprint := pageFingerprint(page, cfg.ChangeSelector)
+		if i > 0 && fingerprint != "" && fingerprint == previous {
+			logger.Info(ctx, "Page content unchanged, stopping pagination at page %d", pageNumber)
+			break
+		}
+		previous = fingerprint
+
+		logger.Info(ctx, "Pagination page %d", pageNumber)
+		collector.begin(p.extractedData)
+		f, t, err := p.executeNestedActions(ctx, page, action.Actions)
+		collector.end(p.extractedData)
+		failed += f
+		total += t
+		pages++
+		if err != nil {
+			return err
+		}
+
+		if i == maxPages-1 {
+			logger.Info(ctx, "Reached max pages: %d", maxPages)
+			break
+		}
+
+		// 点击翻页模式：查找下一页按钮
+		if clickMode {
+			next, reason := p.findNextPageElement(page, cfg)
+			if next == nil {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				logger.Info(ctx, "Stopping pagination at page %d: %s", pageNumber, reason)
+				break
+			}
+			if err := next.Click(proto.InputMouseButtonLeft, 1); err != nil {
+				// 回放被取消导致的失败直接中止，不当作翻页结束
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+				logger.Info(ctx, "Stopping pagination at page %d: failed to click next page: %v", pageNumber, err)
+				break
+			}
+			if err := sleepContext(ctx, time.Duration(waitAfter)*time.Millisecond); err != nil {
+				return err
+			}
+			if err := page.WaitLoad(); err != nil {
+				logger.Warn(ctx, "Failed to wait for page to load: %v", err)
+			}
+		} else if err := sleepContext(ctx, time.Duration(waitAfter)*time.Millisecond); err != nil {
+			return err
+		}
+	}
+
+	logger.Info(ctx, "✓ Pagination completed: %d pages", pages)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d nested actions failed", failed, total)
+	}
+	return nil
+}
+
+// findNextPageElement 查找可点击的下一页按钮，找不到或已禁用时返回原因
+func (p *Player) findNextPageElement(page *rod.Page, cfg *models.PaginationConfig) (*rod.Element, string) {
+	var next *rod.Element
+	var err error
+	if cfg.NextXPath != "" {
+		next, err = page.Timeout(3 * time.Second).ElementX(p.render(cfg.NextXPath))
+	} else {
+		next, err = page.Timeout(3 * time.Second).Element(p.render(cfg.NextSelector))
+	}
+	if err != nil || next == nil {
+		return nil, "next page button not found"
+	}
+	// 去掉查找时设置的超时，避免影响后续点击
+	next = next.CancelTimeout()
+
+	disabled, err := next.Eval(`() => {
+		const el = this;
+		if (el.disabled || el.getAttribute('aria-disabled') === 'true') return true;
+		const cls = (el.className && el.className.toString ? el.className.toString() : '').toLowerCase();
+		if (/(^|\s|-)disabled(\s|$)/.test(cls)) return true;
+		const parent = el.closest('li');
+		if (parent && /(^|\s|-)disabled(\s|$)/.test((parent.className || '').toString().toLowerCase())) return true;
+		const rect = el.getBoundingClientRect();
+		return rect.width === 0 || rect.height === 0;
+	}`)
+	if err == nil && disabled.Value.Bool() {
+		return nil, "next page button is disabled"
+	}
+	return next, ""
+}
+
+// pageFingerprint 计算页面内容指纹，用于检测翻页后内容是否变化
+func pageFingerprint(page *rod.Page, selector string) string {
+	if selector == "" {
+		selector = "body"
+	}
+	result, err := page.Eval(`(selector) => {
+		const el = document.querySelector(selector);
+		const text = el ? el.innerText : '';
+		let hash = 0;
+		for (let i = 0; i < text.length; i++) {
+			hash = ((hash << 5) - hash + text.charCodeAt(i)) | 0;
+		}
+		return text.length + ':' + hash;
+	}`, selector)
+	if err != nil {
+		return ""
+	}
+	return result.Value.String()
+}
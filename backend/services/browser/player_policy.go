@@ -0,0 +1,219 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+const (
+	// defaultRetryBackoff 未配置重试间隔时的默认值（毫秒）
+	defaultRetryBackoff = 500
+	// maxGotoJumps 单个操作块内 goto 跳转的最大次数（防止死循环）
+	maxGotoJumps = 100
+)
+
+// ScriptLoader 根据 ID 或名称加载脚本（用于回退脚本和 call_script）
+type ScriptLoader func(idOrName string) (*models.Script, error)
+
+// stopPlaybackError 需要中止整个回放的失败（on_error=stop）
+type stopPlaybackError struct {
+	err error
+}
+
+func (e *stopPlaybackError) Error() string { return e.err.Error() }
+
+func (e *stopPlaybackError) Unwrap() error { return e.err }
+
+// isStopPlayback 判断错误是否要求中止回放
+func isStopPlayback(err error) bool {
+	var stopErr *stopPlaybackError
+	return errors.As(err, &stopErr)
+}
+
+// errorPolicyFor 获取操作的有效失败处理策略（操作级覆盖脚本级）
+func (p *Player) errorPolicyFor(action models.ScriptAction) models.ErrorPolicy {
+	return p.errorPolicy.Merge(action.ErrorPolicy)
+}
+
+// executeActionWithPolicy 按失败处理策略执行操作（超时、重试、回退脚本），返回最终错误
+// on_error=stop 时返回的错误可通过 isStopPlayback 识别；goto 由调用方根据操作块处理
+func (p *Player) executeActionWithPolicy(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	policy := p.errorPolicyFor(action)
+	retries := policy.Retries()
+	if retries > models.MaxRetryCount {
+		logger.Warn(ctx, "Retry count %d exceeds the limit, using %d", retries, models.MaxRetryCount)
+		retries = models.MaxRetryCount
+	}
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			delay := retryDelay(policy.RetryBackoff, attempt)
+			logger.Info(ctx, "Retrying action %s (attempt %d/%d) after %v", action.Type, attempt, retries, delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return err
+			}
+		}
+
+		err = p.executeActionWithTimeout(ctx, page, action, policy.TimeoutMs())
+		if err == nil {
+			return nil
+		}
+		// 嵌套操作要求中止回放或上下文已取消时不再重试
+		if isStopPlayback(err) || ctx.Err() != nil {
+			return err
+		}
+		if attempt < retries {
+			logger.Warn(ctx, "Action %s failed: %v", action.Type, err)
+		}
+	}
+
+	switch policy.OnError {
+	case models.OnErrorRunScript:
+		if policy.FallbackScriptID == "" {
+			logger.Warn(ctx, "on_error=run_script configured without fallback_script_id")
+			return err
+		}
+		if fallbackErr := p.runFallbackScript(ctx, page, policy.FallbackScriptID); fallbackErr != nil {
+			return fmt.Errorf("%w (fallback script failed: %v)", err, fallbackErr)
+		}
+		logger.Info(ctx, "✓ Action %s recovered by fallback script", action.Type)
+		return nil
+	case models.OnErrorStop:
+		return &stopPlaybackError{err: err}
+	}
+	return err
+}
+
+// retryDelay 第 attempt 次重试（从 1 开始）前的等待时间：间隔每次翻倍，不超过 MaxRetryDelay
+func retryDelay(backoff, attempt int) time.Duration {
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	delay := min(backoff, models.MaxRetryDelay)
+	for i := 1; i < attempt && delay < models.MaxRetryDelay; i++ {
+		delay = min(delay*2, models.MaxRetryDelay)
+	}
+	return time.Duration(delay) * time.Millisecond
+}
+
+// executeActionWithTimeout 在超时限制内执行操作（timeoutMs <= 0 表示不限制）
+func (p *Player) executeActionWithTimeout(ctx context.Context, page *rod.Page, action models.ScriptAction, timeoutMs int) error {
+	if timeoutMs <= 0 {
+		return p.executeAction(ctx, page, action)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+	defer cancel()
+
+	// executeAction 使用 currentPage 执行跨标签页操作，临时替换为带超时的页面
+	original := p.currentPage
+	var timed *rod.Page
+	if original != nil {
+		timed = original.Context(tctx)
+		p.currentPage = timed
+	}
+
+	err := p.executeAction(tctx, page.Context(tctx), action)
+
+	// 操作未切换标签页时恢复原页面（避免后续步骤继承已过期的上下文）
+	if timed != nil && p.currentPage == timed {
+		p.currentPage = original
+	}
+
+	if err != nil && errors.Is(tctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("action timed out after %dms: %w", timeoutMs, err)
+	}
+	return err
+}
+
+// gotoTarget 根据失败处理策略计算 goto 跳转的目标索引
+func (p *Player) gotoTarget(ctx context.Context, actions []models.ScriptAction, action models.ScriptAction, jumps *int) (int, bool) {
+	policy := p.errorPolicyFor(action)
+	if policy.OnError != models.OnErrorGoto {
+		return 0, false
+	}
+	if policy.GotoLabel == "" {
+		logger.Warn(ctx, "on_error=goto configured without goto_label")
+		return 0, false
+	}
+	if *jumps >= maxGotoJumps {
+		logger.Warn(ctx, "Too many goto jumps (%d), continuing with next step", *jumps)
+		return 0, false
+	}
+
+	for i, candidate := range actions {
+		if candidate.Label == policy.GotoLabel {
+			*jumps++
+			logger.Info(ctx, "Jumping to step labeled %q (step %d)", policy.GotoLabel, i+1)
+			return i, true
+		}
+	}
+
+	logger.Warn(ctx, "Goto label not found in current block: %s", policy.GotoLabel)
+	return 0, false
+}
+
+// runFallbackScript 在当前页面执行回退脚本的操作
+func (p *Player) runFallbackScript(ctx context.Context, page *rod.Page, scriptID string) error {
+	if p.scriptLoader == nil {
+		return fmt.Errorf("script loader not configured")
+	}
+	script, err := p.scriptLoader(scriptID)
+	if err != nil {
+		return fmt.Errorf("failed to load fallback script: %w", err)
+	}
+
+	for _, id := range p.callStack {
+		if id == script.ID {
+			return fmt.Errorf("recursive fallback script: %s", script.ID)
+		}
+	}
+
+	p.callStack = append(p.callStack, script.ID)
+	defer func() {
+		p.callStack = p.callStack[:len(p.callStack)-1]
+	}()
+
+	logger.Info(ctx, "Run fallback script: %s", script.Name)
+
+	// 回退脚本引用的密钥必须存在
+	if err := p.checkSecrets(script); err != nil {
+		return err
+	}
+
+	// 回退脚本的预设变量（解密其中的密钥引用）不覆盖当前变量
+	for k, v := range script.Variables {
+		if _, exists := p.variables[k]; !exists {
+			p.variables[k] = p.secrets.Render(v)
+		}
+	}
+
+	activePage := p.currentPage
+	if activePage == nil {
+		activePage = page
+	}
+	if fallbackURL := p.render(script.URL); fallbackURL != "" {
+		if err := activePage.Navigate(fallbackURL); err != nil {
+			return fmt.Errorf("navigation failed: %w", err)
+		}
+		if err := activePage.WaitLoad(); err != nil {
+			logger.Warn(ctx, "Failed to wait for page to load: %v", err)
+		}
+	}
+
+	failed, total, err := p.executeNestedActions(ctx, activePage, script.Actions)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d actions failed", failed, total)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestRetryDelay(t *testing.T) {
+	maxDelay := time.Duration(models.MaxRetryDelay) * time.Millisecond
+	tests := []struct {
+		name    string
+		backoff int
+		attempt int
+		want    time.Duration
+	}{
+		{"default backoff", 0, 1, defaultRetryBackoff * time.Millisecond},
+		{"first retry", 1000, 1, time.Second},
+		{"doubles", 1000, 3, 4 * time.Second},
+		{"capped", 1000, 20, maxDelay},
+		{"large attempt does not overflow", 1000, 1000, maxDelay},
+		{"backoff above cap", models.MaxRetryDelay * 2, 1, maxDelay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(tt.backoff, tt.attempt); got != tt.want {
+				t.Errorf("retryDelay(%d, %d) = %v, want %v", tt.backoff, tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
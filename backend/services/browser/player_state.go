@@ -0,0 +1,71 @@
+package browser
+
+import (
+	"context"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod"
+)
+
+// playState 单次回放的运行状态
+// 每次 PlayScript 使用独立的状态，同一 Player 上并发回放时不会互相覆盖标签页、变量和抓取结果
+type playState struct {
+	extractedData     map[string]interface{}           // 存储抓取的数据
+	successCount      int                              // 成功步骤数
+	failCount         int                              // 失败步骤数
+	pages             map[int]*rod.Page                // 多标签页支持 (key: tab index)
+	currentPage       *rod.Page                        // 当前活动页面
+	baseCtx           context.Context                  // 回放的根上下文（标签页绑定到该上下文，而不是单步超时上下文）
+	tabCounter        int                              // 标签页计数器
+	currentScriptName string                           // 当前执行的脚本名称
+	currentActions    []models.ScriptAction            // 当前执行的脚本动作列表
+	currentStepIndex  int                              // 当前执行到的步骤索引
+	locatorResults    map[int]models.LocatorResolution // 每个步骤实际命中的定位策略 (key: step index)
+	variables         map[string]string                // 回放时的变量上下文（预设变量 + 抓取结果 + 循环变量）
+	errorPolicy       *models.ErrorPolicy              // 脚本级默认失败处理策略
+	callStack         []string                         // 当前执行中的脚本 ID 栈（用于检测递归调用）
+	loopSeq           int                              // foreach 元素标记序号（区分嵌套循环）
+	assertions        []models.AssertionResult         // 断言结果
+	stepRecords       []models.StepRecord              // 步骤执行记录（当前操作块）
+	lastCalledScript  *models.Script                   // 最近一次 call_script 调用的脚本（用于步骤记录）
+	stepLocator       *models.LocatorResolution        // 当前步骤实际命中的元素定位（用于步骤记录）
+	failureSeq        int                              // 失败截图序号
+}
+
+func newPlayState() *playState {
+	return &playState{
+		extractedData:  make(map[string]interface{}),
+		pages:          make(map[int]*rod.Page),
+		locatorResults: make(map[int]models.LocatorResolution),
+	}
+}
+
+// forRun 创建用于单次回放的 Player：共享配置，使用新的运行状态
+func (p *Player) forRun() *Player {
+	return &Player{
+		playState:      newPlayState(),
+		downloadPath:   p.downloadPath,
+		currentLang:    p.currentLang,
+		agentManager:   p.agentManager,
+		browserManager: p.browserManager,
+		scriptLoader:   p.scriptLoader,
+		playback:       p.playback,
+		traceDir:       p.traceDir,
+		debugger:       p.debugger,
+		secrets:        p.secrets,
+	}
+}
+
+// state 返回最近一次结束的回放状态
+func (p *Player) state() *playState {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.playState
+}
+
+// setState 回放结束后保存本次回放的状态，供 Get* 方法读取
+func (p *Player) setState(s *playState) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	p.playState = s
+}
@@ -0,0 +1,38 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+)
+
+func TestPlayerForRun(t *testing.T) {
+	p := NewPlayer("en")
+	p.SetPlaybackConfig(&config.PlaybackConfig{WaitTimeout: 1000})
+	p.SetTraceDir("/tmp/trace")
+	p.extractedData["title"] = "previous"
+
+	a, b := p.forRun(), p.forRun()
+	if a.playback != p.playback || a.traceDir != "/tmp/trace" || a.currentLang != "en" {
+		t.Errorf("run does not share player configuration")
+	}
+	if len(a.extractedData) != 0 {
+		t.Errorf("run state not reset: %v", a.extractedData)
+	}
+
+	// 并发回放各自维护标签页和变量
+	a.variables = map[string]string{"keyword": "a"}
+	b.variables = map[string]string{"keyword": "b"}
+	a.tabCounter++
+	if b.variables["keyword"] != "b" || b.tabCounter != 0 {
+		t.Errorf("runs share state: %v, tab %d", b.variables, b.tabCounter)
+	}
+
+	// 回放结束后结果对 Player 可见
+	a.extractedData["title"] = "run a"
+	a.successCount = 2
+	p.setState(a.playState)
+	if p.GetExtractedData()["title"] != "run a" || p.GetSuccessCount() != 2 {
+		t.Errorf("finished run not published: %v", p.GetExtractedData())
+	}
+}
@@ -0,0 +1,80 @@
+package browser
+
+import (
+	"context"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod"
+)
+
+// runStep 按失败处理策略执行单个步骤并记录执行结果（执行期间产生的嵌套步骤记录为子步骤）
+func (p *Player) runStep(ctx context.Context, page *rod.Page, action models.ScriptAction, index int) error {
+	parent := p.stepRecords
+	p.stepRecords = nil
+	p.lastCalledScript = nil
+	p.stepLocator = nil
+
+	start := time.Now()
+	err := p.executeActionWithPolicy(ctx, page, action)
+	end := time.Now()
+
+	record := models.StepRecord{
+		Index:     index,
+		Type:      action.Type,
+		Success:   err == nil,
+		StartTime: start,
+		EndTime:   end,
+		Duration:  end.Sub(start).Milliseconds(),
+		Children:  p.stepRecords,
+	}
+	// 操作块的定位和抓取结果已记录在子步骤中
+	if len(record.Children) == 0 {
+		record.Locator = p.stepLocator
+		if err == nil && action.VariableName != "" {
+			record.ExtractedValue = p.extractedData[action.VariableName]
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+		// 回放被取消时页面已关闭；子步骤失败时现场已在子步骤中采集
+		if ctx.Err() == nil && !hasFailedStep(record.Children) {
+			record.Failure = p.captureFailure(ctx, page, action.Type)
+		}
+	}
+	if action.Type == "call_script" && p.lastCalledScript != nil {
+		record.ScriptID = p.lastCalledScript.ID
+		record.ScriptName = p.lastCalledScript.Name
+	}
+
+	p.stepRecords = append(parent, record)
+	return err
+}
+
+// hasFailedStep 判断步骤列表中是否有失败的步骤
+func hasFailedStep(records []models.StepRecord) bool {
+	for _, record := range records {
+		if !record.Success {
+			return true
+		}
+	}
+	return false
+}
+
+// recordSkippedStep 记录因条件不满足而跳过的步骤
+func (p *Player) recordSkippedStep(action models.ScriptAction, index int) {
+	now := time.Now()
+	p.stepRecords = append(p.stepRecords, models.StepRecord{
+		Index:     index,
+		Type:      action.Type,
+		Success:   true,
+		Skipped:   true,
+		StartTime: now,
+		EndTime:   now,
+	})
+}
+
+// GetStepRecords 获取步骤执行记录
+func (p *Player) GetStepRecords() []models.StepRecord {
+	return p.state().stepRecords
+}
@@ -0,0 +1,202 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	// failureCaptureTimeout 采集失败现场的单项超时
+	failureCaptureTimeout = 5 * time.Second
+	// maxDOMSnapshotLength DOM 快照的最大长度（字符）
+	maxDOMSnapshotLength = 20000
+	// maxAXSnapshotNodes 可访问性树快照的最大节点数
+	maxAXSnapshotNodes = 300
+	// maxAXNameLength 可访问性节点名称的最大长度（字符）
+	maxAXNameLength = 100
+)
+
+// domSnapshotJS 生成精简的 DOM 快照：去除脚本、样式等无关节点，只保留用于定位的属性
+const domSnapshotJS = `() => {
+	const root = document.documentElement.cloneNode(true);
+	root.querySelectorAll('script, style, noscript, template, link, meta, svg').forEach(el => el.remove());
+	const keep = /^(id|class|name|type|href|src|role|value|placeholder|title|alt|for|aria-[\w-]+|data-testid)$/;
+	root.querySelectorAll('*').forEach(el => {
+		for (const attr of Array.from(el.attributes)) {
+			if (!keep.test(attr.name)) el.removeAttribute(attr.name);
+		}
+	});
+	return root.outerHTML.replace(/\s+/g, ' ');
+}`
+
+// SetTraceDir 设置失败现场截图的保存目录
+func (p *Player) SetTraceDir(dir string) {
+	p.traceDir = dir
+}
+
+// captureFailure 采集步骤失败时的页面现场（URL、截图、DOM 和可访问性树快照），采集失败时只记录日志
+func (p *Player) captureFailure(ctx context.Context, page *rod.Page, actionType string) *models.StepFailure {
+	// 跨标签页操作失败时，现场在当前活动页面
+	if p.currentPage != nil {
+		page = p.currentPage
+	}
+	if page == nil {
+		return nil
+	}
+
+	failure := &models.StepFailure{}
+	if info, err := page.Timeout(failureCaptureTimeout).Info(); err == nil {
+		failure.URL = info.URL
+		failure.Title = info.Title
+	} else {
+		logger.Warn(ctx, "Failed to get page info for failure trace: %v", err)
+	}
+
+	if p.traceDir != "" {
+		if name, err := p.saveFailureScreenshot(page, actionType); err == nil {
+			failure.Screenshot = name
+		} else {
+			logger.Warn(ctx, "Failed to capture failure screenshot: %v", err)
+		}
+	}
+
+	if result, err := page.Timeout(failureCaptureTimeout).Eval(domSnapshotJS); err == nil {
+		failure.DOMSnapshot = truncateRunes(result.Value.Str(), maxDOMSnapshotLength)
+	} else {
+		logger.Warn(ctx, "Failed to capture DOM snapshot: %v", err)
+	}
+
+	if snapshot, err := axTreeSnapshot(page.Timeout(failureCaptureTimeout)); err == nil {
+		failure.AXSnapshot = snapshot
+	} else {
+		logger.Warn(ctx, "Failed to capture accessibility snapshot: %v", err)
+	}
+
+	return failure
+}
+
+// saveFailureScreenshot 保存失败截图，返回文件名
+func (p *Player) saveFailureScreenshot(page *rod.Page, actionType string) (string, error) {
+	data, err := page.Timeout(failureCaptureTimeout).Screenshot(false, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(p.traceDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create trace directory: %w", err)
+	}
+
+	p.failureSeq++
+	name := fmt.Sprintf("step_%03d_%s.png", p.failureSeq, safePathSegment(actionType))
+	if err := os.WriteFile(filepath.Join(p.traceDir, name), data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to save screenshot: %w", err)
+	}
+	return name, nil
+}
+
+// axTreeSnapshot 生成精简的可访问性树快照：跳过忽略节点、纯文本节点和无名称的容器节点
+func axTreeSnapshot(page *rod.Page) (string, error) {
+	if err := (proto.AccessibilityEnable{}).Call(page); err != nil {
+		return "", err
+	}
+	defer func() { _ = proto.AccessibilityDisable{}.Call(page) }()
+
+	tree, err := proto.AccessibilityGetFullAXTree{}.Call(page)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	count := 0
+	for _, node := range tree.Nodes {
+		if node.Ignored {
+			continue
+		}
+		role := axValueString(node.Role)
+		name := strings.Join(strings.Fields(axValueString(node.Name)), " ")
+		switch role {
+		case "StaticText", "InlineTextBox", "LineBreak":
+			continue
+		case "", "generic", "none", "presentation":
+			if name == "" {
+				continue
+			}
+		}
+
+		if count >= maxAXSnapshotNodes {
+			fmt.Fprintf(&b, "... (%d nodes total)\n", len(tree.Nodes))
+			break
+		}
+		if name != "" {
+			fmt.Fprintf(&b, "%s %q\n", role, truncateRunes(name, maxAXNameLength))
+		} else {
+			fmt.Fprintf(&b, "%s\n", role)
+		}
+		count++
+	}
+	return b.String(), nil
+}
+
+// axValueString 获取可访问性属性值的字符串形式
+func axValueString(value *proto.AccessibilityAXValue) string {
+	if value == nil {
+		return ""
+	}
+	return value.Value.Str()
+}
+
+// truncateRunes 按字符数截断字符串
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
+
+// safePathSegment 将字符串转换为安全的文件名片段（只保留字母、数字、下划线和连字符）
+func safePathSegment(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// traceDirFor 获取执行记录的失败现场目录
+func (m *Manager) traceDirFor(executionID string) string {
+	assetsDir := "./data"
+	if m.config != nil && m.config.AssetsDir != "" {
+		assetsDir = m.config.AssetsDir
+	}
+	return filepath.Join(assetsDir, "traces", safePathSegment(executionID))
+}
+
+// GetTraceArtifactPath 获取执行记录失败现场文件的路径
+func (m *Manager) GetTraceArtifactPath(executionID, name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid artifact name: %s", name)
+	}
+	path := filepath.Join(m.traceDirFor(executionID), name)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("artifact not found: %s", name)
+	}
+	return path, nil
+}
+
+// DeleteTraceArtifacts 删除执行记录的失败现场文件
+func (m *Manager) DeleteTraceArtifacts(executionID string) error {
+	if executionID == "" {
+		return nil
+	}
+	return os.RemoveAll(m.traceDirFor(executionID))
+}
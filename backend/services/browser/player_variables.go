@@ -0,0 +1,165 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/secrets"
+)
+
+// variableLookup 回放时的变量查找：先查变量上下文，再查抓取结果（保留原始类型，便于 json 过滤器读取 XHR 数据），
+// 最后解析 secret:name 形式的密钥引用
+func (p *Player) variableLookup() interpolate.Lookup {
+	return interpolate.Chain(
+		interpolate.FromMap(p.variables),
+		func(name string) (interface{}, bool) {
+			value, ok := p.extractedData[name]
+			return value, ok
+		},
+		p.secrets.Lookup(),
+	)
+}
+
+// storeExtracted 对抓取结果应用操作配置的值转换后保存（转换结果保留类型）
+func (p *Player) storeExtracted(action models.ScriptAction, varName string, value interface{}) error {
+	value, err := interpolate.Transform(value, action.Transform)
+	if err != nil {
+		return fmt.Errorf("failed to transform %s: %w", varName, err)
+	}
+	p.extractedData[varName] = value
+	return nil
+}
+
+// conditionValue 计算变量条件的实际值：配置了 Transform 时先转换
+// 抓取结果与变量值一致时使用抓取结果的原始值（保留 XHR 数据等结构）
+func (p *Player) conditionValue(condition *models.ActionCondition, variables map[string]string) (string, bool, error) {
+	value, exists := variables[condition.Variable]
+	if !exists || condition.Transform == "" {
+		return value, exists, nil
+	}
+	var raw interface{} = value
+	if extracted, ok := p.extractedData[condition.Variable]; ok && interpolate.Stringify(extracted) == value {
+		raw = extracted
+	}
+	transformed, err := interpolate.Transform(raw, condition.Transform)
+	if errors.Is(err, interpolate.ErrNoValue) {
+		// 转换没有结果（如正则不匹配）视为变量不存在
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return interpolate.Stringify(transformed), true, nil
+}
+
+// checkSecrets 检查脚本（起始 URL、预设变量和所有操作）引用的密钥是否都能解密
+func (p *Player) checkSecrets(script *models.Script) error {
+	data, err := json.Marshal(script)
+	if err != nil {
+		return err
+	}
+	for _, name := range secrets.References(string(data)) {
+		if _, err := p.secrets.Resolve(name); err != nil {
+			return fmt.Errorf("failed to resolve ${secret:%s}: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// render 在执行时解析文本中的 ${...} 占位符
+func (p *Player) render(text string) string {
+	return interpolate.Render(text, p.variableLookup())
+}
+
+// resolveAction 在执行前解析操作所有字符串字段中的占位符（嵌套操作在执行时再解析）
+func (p *Player) resolveAction(action models.ScriptAction) models.ScriptAction {
+	action.Selector = p.render(action.Selector)
+	action.XPath = p.render(action.XPath)
+	action.Value = p.render(action.Value)
+	action.URL = p.render(action.URL)
+	action.Text = p.render(action.Text)
+	action.Key = p.render(action.Key)
+	action.JSCode = p.render(action.JSCode)
+	action.AttributeName = p.render(action.AttributeName)
+	action.VariableName = p.render(action.VariableName)
+	action.ListVariable = p.render(action.ListVariable)
+	action.ScriptID = p.render(action.ScriptID)
+	action.ScriptName = p.render(action.ScriptName)
+	action.AIControlPrompt = p.render(action.AIControlPrompt)
+	action.AIControlXPath = p.render(action.AIControlXPath)
+
+	if len(action.FilePaths) > 0 {
+		filePaths := make([]string, len(action.FilePaths))
+		for i, path := range action.FilePaths {
+			filePaths[i] = p.render(path)
+		}
+		action.FilePaths = filePaths
+	}
+	if len(action.InputMapping) > 0 {
+		inputMapping := make(map[string]string, len(action.InputMapping))
+		for k, v := range action.InputMapping {
+			inputMapping[k] = p.render(v)
+		}
+		action.InputMapping = inputMapping
+	}
+	action.WaitFor = p.resolveWaitCondition(action.WaitFor)
+	if len(action.FramePath) > 0 {
+		framePath := make([]models.FrameHop, len(action.FramePath))
+		for i, hop := range action.FramePath {
+			framePath[i] = models.FrameHop{Type: hop.Type, Selector: p.render(hop.Selector)}
+		}
+		action.FramePath = framePath
+	}
+	action.Condition = p.resolveCondition(action.Condition)
+	return action
+}
+
+// resolveCondition 解析条件（包括子条件）中的占位符
+func (p *Player) resolveCondition(cond *models.ActionCondition) *models.ActionCondition {
+	if cond == nil {
+		return nil
+	}
+	resolved := *cond
+	resolved.Value = p.render(resolved.Value)
+	resolved.Selector = p.render(resolved.Selector)
+	resolved.XPath = p.render(resolved.XPath)
+	resolved.Pattern = p.render(resolved.Pattern)
+	resolved.Text = p.render(resolved.Text)
+	resolved.Expression = p.render(resolved.Expression)
+	if len(cond.Conditions) > 0 {
+		resolved.Conditions = make([]models.ActionCondition, len(cond.Conditions))
+		for i := range cond.Conditions {
+			resolved.Conditions[i] = *p.resolveCondition(&cond.Conditions[i])
+		}
+	}
+	return &resolved
+}
+
+// resolveWaitCondition 解析等待条件中的占位符
+func (p *Player) resolveWaitCondition(cond *models.WaitCondition) *models.WaitCondition {
+	if cond == nil {
+		return nil
+	}
+	resolved := *cond
+	resolved.Selector = p.render(resolved.Selector)
+	resolved.XPath = p.render(resolved.XPath)
+	resolved.Pattern = p.render(resolved.Pattern)
+	resolved.Expression = p.render(resolved.Expression)
+	resolved.Text = p.render(resolved.Text)
+	return &resolved
+}
+
+// warnUnresolved 记录无法解析的占位符，便于排查变量名错误
+func (p *Player) warnUnresolved(ctx context.Context, action models.ScriptAction) {
+	lookup := p.variableLookup()
+	for _, text := range []string{action.Selector, action.XPath, action.Value, action.URL} {
+		if names := interpolate.Unresolved(text, lookup); len(names) > 0 {
+			logger.Warn(ctx, "Unresolved placeholders in %s action: %v", action.Type, names)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// waitPollInterval 条件等待的轮询间隔
+const waitPollInterval = 100 * time.Millisecond
+
+// waitConditionJS 在页面中判断元素/文本条件是否成立
+const waitConditionJS = `(type, selector, xpath, text) => {
+	let el = null;
+	if (xpath) {
+		el = document.evaluate(xpath, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue;
+	} else if (selector) {
+		el = document.querySelector(selector);
+	}
+	const visible = (node) => {
+		if (!node || !node.isConnected) return false;
+		const style = window.getComputedStyle(node);
+		if (style.display === 'none' || style.visibility === 'hidden' || style.opacity === '0') return false;
+		const rect = node.getBoundingClientRect();
+		return rect.width > 0 && rect.height > 0;
+	};
+	switch (type) {
+		case 'selector_exists':
+			return !!el;
+		case 'selector_visible':
+			return visible(el);
+		case 'selector_hidden':
+			return !visible(el);
+		case 'text_present': {
+			const scope = (xpath || selector) ? el : document.body;
+			return !!scope && (scope.innerText || scope.textContent || '').includes(text);
+		}
+	}
+	return false;
+}`
+
+// executeWaitFor 执行事件等待操作
+func (p *Player) executeWaitFor(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	if action.WaitFor == nil {
+		return fmt.Errorf("wait_for requires wait condition")
+	}
+	return p.waitForCondition(ctx, page, *action.WaitFor)
+}
+
+// waitTimeout 条件等待的超时：条件配置 > 回放配置 > 默认值（不大于 0 视为未配置）
+func (p *Player) waitTimeout(cond models.WaitCondition) int {
+	if cond.Timeout > 0 {
+		return cond.Timeout
+	}
+	if p.playback != nil && p.playback.WaitTimeout > 0 {
+		return p.playback.WaitTimeout
+	}
+	return config.DefaultWaitTimeout
+}
+
+// waitForCondition 等待条件成立，超时返回错误
+func (p *Player) waitForCondition(ctx context.Context, page *rod.Page, cond models.WaitCondition) error {
+	timeout := p.waitTimeout(cond)
+	description := describeWaitCondition(cond)
+	logger.Info(ctx, "Wait for %s (timeout %dms)", description, timeout)
+
+	tctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+	defer cancel()
+
+	var err error
+	switch cond.Type {
+	case models.WaitNetworkIdle:
+		idle := cond.IdleTime
+		if idle <= 0 {
+			idle = p.playback.NetworkIdleTime
+		}
+		err = waitNetworkIdle(tctx, page, time.Duration(idle)*time.Millisecond)
+	case models.WaitURLMatches:
+		if cond.Pattern == "" {
+			return fmt.Errorf("url_matches requires pattern")
+		}
+		match := urlMatcher(cond.Pattern)
+		err = pollCondition(tctx, func() (bool, error) {
+			info, err := page.Context(tctx).Info()
+			if err != nil {
+				return false, err
+			}
+			return match(info.URL), nil
+		})
+	case models.WaitJSPredicate:
+		if cond.Expression == "" {
+			return fmt.Errorf("js_predicate requires expression")
+		}
+		js := fmt.Sprintf("() => !!(%s)", cond.Expression)
+		err = pollCondition(tctx, func() (bool, error) {
+			result, err := page.Context(tctx).Eval(js)
+			if err != nil {
+				return false, err
+			}
+			return result.Value.Bool(), nil
+		})
+	case models.WaitSelectorVisible, models.WaitSelectorHidden, models.WaitTextPresent:
+		if cond.Type == models.WaitTextPresent && cond.Text == "" {
+			return fmt.Errorf("text_present requires text")
+		}
+		if cond.Type != models.WaitTextPresent && cond.Selector == "" && cond.XPath == "" {
+			return fmt.Errorf("%s requires selector or xpath", cond.Type)
+		}
+		err = pollCondition(tctx, func() (bool, error) {
+			result, err := page.Context(tctx).Eval(waitConditionJS, string(cond.Type), cond.Selector, cond.XPath, cond.Text)
+			if err != nil {
+				return false, err
+			}
+			return result.Value.Bool(), nil
+		})
+	default:
+		return fmt.Errorf("unknown wait condition type: %s", cond.Type)
+	}
+
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(tctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %dms waiting for %s: %w", timeout, description, err)
+		}
+		return err
+	}
+
+	logger.Info(ctx, "✓ Wait condition met: %s", description)
+	return nil
+}
+
+// pollCondition 轮询检查条件直到成立或上下文结束
+// 检查出错（如页面正在跳转）时继续轮询，超时后返回最后一次错误
+func pollCondition(ctx context.Context, check func() (bool, error)) error {
+	var lastErr error
+	for {
+		ok, err := check()
+		if err == nil && ok {
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
+			}
+			return ctx.Err()
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// waitNetworkIdle 通过 CDP Network 事件等待持续 idle 时长内没有进行中的请求
+// 只统计开始等待后发出的请求，忽略 WebSocket、EventSource、媒体、图片和字体
+func waitNetworkIdle(ctx context.Context, page *rod.Page, idle time.Duration) error {
+	wait := page.Context(ctx).WaitRequestIdle(idle, nil, nil, nil)
+	wait()
+	return ctx.Err()
+}
+
+// urlMatcher 构造 URL 匹配函数：优先按正则匹配，正则无效时按子串匹配
+func urlMatcher(pattern string) func(string) bool {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return func(url string) bool {
+			return strings.Contains(url, pattern)
+		}
+	}
+	return re.MatchString
+}
+
+// describeWaitCondition 生成等待条件的描述（用于日志和错误信息）
+func describeWaitCondition(cond models.WaitCondition) string {
+	target := cond.XPath
+	if target == "" {
+		target = cond.Selector
+	}
+	switch cond.Type {
+	case models.WaitURLMatches:
+		return fmt.Sprintf("url matching %q", cond.Pattern)
+	case models.WaitJSPredicate:
+		return fmt.Sprintf("js predicate %q", cond.Expression)
+	case models.WaitTextPresent:
+		if target != "" {
+			return fmt.Sprintf("text %q in %s", cond.Text, target)
+		}
+		return fmt.Sprintf("text %q", cond.Text)
+	case models.WaitNetworkIdle:
+		return "network idle"
+	}
+	return fmt.Sprintf("%s %s", cond.Type, target)
+}
+
+// waitAfterNavigation 导航完成后等待：配置了等待条件时按条件等待，否则使用全局配置的固定等待时长
+func (p *Player) waitAfterNavigation(ctx context.Context, page *rod.Page, cond *models.WaitCondition) error {
+	if cond != nil {
+		return p.waitForCondition(ctx, page, *cond)
+	}
+
+	if err := sleepContext(ctx, time.Duration(p.playback.NavigationDelay)*time.Millisecond); err != nil {
+		return err
+	}
+	if p.playback.StabilizeDelay > 0 {
+		// 页面加载完成后，等待额外时间让 JavaScript 框架初始化完成
+		logger.Info(ctx, "Waiting for page JavaScript to stabilize...")
+	}
+	return sleepContext(ctx, time.Duration(p.playback.StabilizeDelay)*time.Millisecond)
+}
+
+// sleepContext 等待指定时长，上下文取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestURLMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		url     string
+		want    bool
+	}{
+		{name: "Regexp match", pattern: `/orders/\d+$`, url: "https://example.com/orders/42", want: true},
+		{name: "Regexp mismatch", pattern: `/orders/\d+$`, url: "https://example.com/orders/new", want: false},
+		{name: "Invalid regexp falls back to substring", pattern: "?page=(2", url: "https://example.com/list?page=(2", want: true},
+		{name: "Invalid regexp substring mismatch", pattern: "?page=(2", url: "https://example.com/list", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := urlMatcher(tt.pattern)(tt.url); got != tt.want {
+				t.Errorf("urlMatcher(%q)(%q) = %v, want %v", tt.pattern, tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		cond     int
+		playback int
+		want     int
+	}{
+		{name: "Condition timeout", cond: 5000, playback: 10000, want: 5000},
+		{name: "Playback default", cond: 0, playback: 10000, want: 10000},
+		{name: "Zero playback timeout uses default", cond: 0, playback: 0, want: config.DefaultWaitTimeout},
+		{name: "Negative playback timeout uses default", cond: -1, playback: -1, want: config.DefaultWaitTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPlayer("en")
+			p.SetPlaybackConfig(&config.PlaybackConfig{WaitTimeout: tt.playback})
+			if got := p.waitTimeout(models.WaitCondition{Timeout: tt.cond}); got != tt.want {
+				t.Errorf("waitTimeout() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
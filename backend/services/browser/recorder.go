@@ -790,19 +790,42 @@ func (r *Recorder) injectIframeRecorders(ctx context.Context, page *rod.Page) {
 			continue
 		}
 
-		// 等待 iframe 加载
-		if err := frame.WaitLoad(); err != nil {
-			logger.Warn(ctx, "Failed to wait for iframe #%d to load: %v", i, err)
-		}
+		r.injectIframeRecorder(ctx, frame, fmt.Sprintf("#%d", i), 1)
+	}
+}
+
+// maxIframeDepth 注入录制脚本的最大 iframe 嵌套层数
+const maxIframeDepth = 5
+
+// injectIframeRecorder 在 iframe 中注入录制脚本，并递归注入其中嵌套的 iframe（嵌套 iframe 的操作经父 iframe 转发）
+func (r *Recorder) injectIframeRecorder(ctx context.Context, frame *rod.Page, label string, depth int) {
+	// 等待 iframe 加载
+	if err := frame.WaitLoad(); err != nil {
+		logger.Warn(ctx, "Failed to wait for iframe %s to load: %v", label, err)
+	}
 
-		// 在 iframe 的页面上下文中注入录制脚本（使用本地化版本）
-		localizedIframeScript := ReplaceI18nPlaceholders(iframeRecorderScript, r.language, RecorderI18n)
-		_, err = frame.Eval(`() => { ` + localizedIframeScript + ` return true; }`)
+	// 在 iframe 的页面上下文中注入录制脚本（使用本地化版本）
+	localizedIframeScript := ReplaceI18nPlaceholders(iframeRecorderScript, r.language, RecorderI18n)
+	if _, err := frame.Eval(`() => { ` + localizedIframeScript + ` return true; }`); err != nil {
+		logger.Warn(ctx, "Failed to inject script into iframe %s: %v", label, err)
+		return
+	}
+	logger.Info(ctx, "✓ Recording script injected into iframe %s successfully", label)
+
+	if depth >= maxIframeDepth {
+		return
+	}
+	nested, err := frame.Elements("iframe")
+	if err != nil {
+		return
+	}
+	for i, iframeElement := range nested {
+		child, err := iframeElement.Frame()
 		if err != nil {
-			logger.Warn(ctx, "Failed to inject script into iframe #%d: %v", i, err)
-		} else {
-			logger.Info(ctx, "✓ Recording script injected into iframe #%d successfully", i)
+			logger.Warn(ctx, "Failed to get Frame for nested iframe %s.%d: %v", label, i, err)
+			continue
 		}
+		r.injectIframeRecorder(ctx, child, fmt.Sprintf("%s.%d", label, i), depth+1)
 	}
 }
 
@@ -844,19 +867,7 @@ func (r *Recorder) watchForNewIframes(ctx context.Context, page *rod.Page) {
 						continue
 					}
 
-					// 等待 iframe 加载
-					if err := frame.WaitLoad(); err != nil {
-						logger.Warn(ctx, "Failed to wait for new iframe #%d to load: %v", i, err)
-					}
-
-					// 在 iframe 的页面上下文中注入录制脚本（使用本地化版本）
-					localizedIframeScript := ReplaceI18nPlaceholders(iframeRecorderScript, r.language, RecorderI18n)
-					_, err = frame.Eval(`() => { ` + localizedIframeScript + ` return true; }`)
-					if err != nil {
-						logger.Warn(ctx, "Failed to inject script into new iframe #%d: %v", i, err)
-					} else {
-						logger.Info(ctx, "✓ Recording script injected into new iframe #%d successfully", i)
-					}
+					r.injectIframeRecorder(ctx, frame, fmt.Sprintf("#%d", i), 1)
 				}
 
 				processedIframeCount = len(iframes)
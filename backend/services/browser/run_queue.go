@@ -0,0 +1,69 @@
+package browser
+
+import (
+	"context"
+	"sort"
+
+	"github.com/browserwing/browserwing/services/runqueue"
+)
+
+// acquireRun 排队等待实例名额，返回回放使用的实例 ID（指定 runqueue.AnyInstance 时为队列分配的实例）和归还名额的函数
+func (m *Manager) acquireRun(ctx context.Context, source, instanceID string) (string, func(), error) {
+	key := instanceID
+	if key != runqueue.AnyInstance {
+		m.mu.Lock()
+		key = m.queueKeyLocked(instanceID)
+		m.mu.Unlock()
+	}
+
+	assigned, err := m.queue.Acquire(ctx, source, key)
+	if err != nil {
+		return "", nil, err
+	}
+	release := func() { m.queue.Release(assigned) }
+	if instanceID == runqueue.AnyInstance {
+		return assigned, release, nil
+	}
+	return instanceID, release, nil
+}
+
+// queueKeyLocked 实例在队列中的 ID（空字符串表示当前实例，没有当前实例时使用 default）
+func (m *Manager) queueKeyLocked(instanceID string) string {
+	if instanceID == "" {
+		instanceID = m.currentInstanceID
+	}
+	if instanceID == "" {
+		instanceID = "default"
+	}
+	return instanceID
+}
+
+// runLimit 实例同时执行的回放数上限（实例未配置时使用全局配置，均为 0 时由队列使用默认值）
+func (m *Manager) runLimit(instanceID string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if runtime, exists := m.instances[instanceID]; exists && runtime != nil && runtime.instance != nil && runtime.instance.MaxConcurrentRuns > 0 {
+		return runtime.instance.MaxConcurrentRuns
+	}
+	if m.config != nil && m.config.Playback != nil {
+		return m.config.Playback.MaxRunsPerInstance
+	}
+	return 0
+}
+
+// runnableInstances 可分配回放的实例（没有运行中的实例时使用当前实例，回放时会自动启动 default 实例）
+func (m *Manager) runnableInstances() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]string, 0, len(m.instances))
+	for id, runtime := range m.instances {
+		if runtime != nil && runtime.browser != nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return []string{m.queueKeyLocked("")}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,152 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+var (
+	// ErrExecutionCancelled 回放被取消（通过取消接口）
+	ErrExecutionCancelled = errors.New("script execution cancelled")
+	// ErrExecutionNotRunning 执行记录不存在或已结束
+	ErrExecutionNotRunning = errors.New("script execution not running")
+)
+
+type executionIDKey struct{}
+
+// WithExecutionID 为回放预先指定执行 ID，调用方可在回放开始前得知 ID 以便取消
+func WithExecutionID(ctx context.Context, executionID string) context.Context {
+	return context.WithValue(ctx, executionIDKey{}, executionID)
+}
+
+// executionIDFromContext 获取预先指定的执行 ID
+func executionIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(executionIDKey{}).(string)
+	return id
+}
+
+// playbackInterruption 回放被上下文结束中断时的执行状态和说明
+// 只有取消接口触发的取消记为 cancelled；调用方超时（如定时任务）或断开连接（HTTP 客户端、MCP 请求）记为 failed
+func playbackInterruption(runCtx context.Context) (models.ExecutionStatus, string) {
+	cause := context.Cause(runCtx)
+	switch {
+	case errors.Is(cause, ErrExecutionCancelled):
+		return models.ExecutionStatusCancelled, "Script execution cancelled"
+	case errors.Is(cause, context.DeadlineExceeded):
+		return models.ExecutionStatusFailed, "Script execution timed out"
+	default:
+		return models.ExecutionStatusFailed, "Script execution aborted: caller disconnected"
+	}
+}
+
+// activeRun 正在执行的回放
+type activeRun struct {
+	info   models.RunningExecution
+	cancel context.CancelCauseFunc
+}
+
+// runRegistry 正在执行的回放登记表（用于取消回放和调试）
+type runRegistry struct {
+	mu    sync.Mutex
+	runs  map[string]*activeRun
+	debug map[string]*debugSession
+}
+
+func newRunRegistry() *runRegistry {
+	return &runRegistry{
+		runs:  make(map[string]*activeRun),
+		debug: make(map[string]*debugSession),
+	}
+}
+
+// add 登记回放，执行 ID 重复时返回错误
+func (r *runRegistry) add(run *activeRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.runs[run.info.ExecutionID]; exists {
+		return fmt.Errorf("execution already running: %s", run.info.ExecutionID)
+	}
+	r.runs[run.info.ExecutionID] = run
+	return nil
+}
+
+// start 排队结束，标记回放开始执行
+func (r *runRegistry) start(executionID, instanceID string, startTime time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if run, exists := r.runs[executionID]; exists {
+		run.info.InstanceID = instanceID
+		run.info.Status = models.ExecutionStatusRunning
+		run.info.StartTime = startTime
+	}
+}
+
+// remove 移除已结束的回放
+func (r *runRegistry) remove(executionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.runs, executionID)
+}
+
+// cancel 取消正在执行的回放
+func (r *runRegistry) cancel(executionID string) error {
+	r.mu.Lock()
+	run, exists := r.runs[executionID]
+	r.mu.Unlock()
+	if !exists {
+		return ErrExecutionNotRunning
+	}
+	run.cancel(ErrExecutionCancelled)
+	return nil
+}
+
+// addDebug 登记调试会话
+func (r *runRegistry) addDebug(executionID string, session *debugSession) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.debug[executionID] = session
+}
+
+// getDebug 获取调试会话
+func (r *runRegistry) getDebug(executionID string) *debugSession {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.debug[executionID]
+}
+
+// removeDebug 移除调试会话
+func (r *runRegistry) removeDebug(executionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.debug, executionID)
+}
+
+// list 列出正在执行和排队中的回放（按提交时间排序）
+func (r *runRegistry) list() []models.RunningExecution {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	runs := make([]models.RunningExecution, 0, len(r.runs))
+	for _, run := range r.runs {
+		runs = append(runs, run.info)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].QueuedAt.Before(runs[j].QueuedAt)
+	})
+	return runs
+}
+
+// CancelExecution 取消正在执行的回放（回放页面会被关闭，执行记录状态为 cancelled；排队中的回放直接退出排队）
+func (m *Manager) CancelExecution(executionID string) error {
+	return m.runs.cancel(executionID)
+}
+
+// ListRunningExecutions 列出正在执行和排队中的回放
+func (m *Manager) ListRunningExecutions() []models.RunningExecution {
+	return m.runs.list()
+}
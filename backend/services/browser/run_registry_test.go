@@ -0,0 +1,74 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestRunRegistryCancel(t *testing.T) {
+	registry := newRunRegistry()
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	run := &activeRun{
+		info:   models.RunningExecution{ExecutionID: "exec-1", StartTime: time.Now()},
+		cancel: cancel,
+	}
+	if err := registry.add(run); err != nil {
+		t.Fatalf("add() error = %v", err)
+	}
+	if err := registry.add(run); err == nil {
+		t.Errorf("add() with duplicate execution ID should fail")
+	}
+	if got := registry.list(); len(got) != 1 || got[0].ExecutionID != "exec-1" {
+		t.Errorf("list() = %v, want exec-1", got)
+	}
+
+	if err := registry.cancel("exec-1"); err != nil {
+		t.Fatalf("cancel() error = %v", err)
+	}
+	if !errors.Is(context.Cause(ctx), ErrExecutionCancelled) {
+		t.Errorf("context cause = %v, want %v", context.Cause(ctx), ErrExecutionCancelled)
+	}
+
+	registry.remove("exec-1")
+	if err := registry.cancel("exec-1"); !errors.Is(err, ErrExecutionNotRunning) {
+		t.Errorf("cancel() after remove error = %v, want %v", err, ErrExecutionNotRunning)
+	}
+}
+
+func TestPlaybackInterruption(t *testing.T) {
+	cancelled, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrExecutionCancelled)
+
+	expired, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+	timedOut, cancelTimedOut := context.WithCancelCause(expired)
+	defer cancelTimedOut(nil)
+
+	parent, disconnect := context.WithCancel(context.Background())
+	disconnected, cancelDisconnected := context.WithCancelCause(parent)
+	defer cancelDisconnected(nil)
+	disconnect()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want models.ExecutionStatus
+	}{
+		{"cancel endpoint", cancelled, models.ExecutionStatusCancelled},
+		{"caller deadline", timedOut, models.ExecutionStatusFailed},
+		{"caller disconnected", disconnected, models.ExecutionStatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := playbackInterruption(tt.ctx); got != tt.want {
+				t.Errorf("playbackInterruption() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,11 +5,81 @@
 	}
 	window.__browserwingIframeListener__ = true;
 	
+	// 生成相对于元素所在根节点（document 或 shadow root）的 CSS 选择器
+	var getScopedCss = function(element) {
+		var root = element.getRootNode ? element.getRootNode() : document;
+		var parts = [];
+		for (var el = element; el && el.nodeType === 1; el = el.parentNode) {
+			var tag = el.tagName.toLowerCase();
+			if (el.id && !/^[0-9]/.test(el.id) && window.CSS && CSS.escape) {
+				var idSelector = '#' + CSS.escape(el.id);
+				try {
+					if (root.querySelectorAll(idSelector).length === 1) {
+						parts.unshift(idSelector);
+						break;
+					}
+				} catch (e) {}
+			}
+			
+			var index = 1;
+			var sameTagCount = 0;
+			if (el.parentNode && el.parentNode.children) {
+				var siblings = el.parentNode.children;
+				for (var i = 0; i < siblings.length; i++) {
+					if (siblings[i].tagName === el.tagName) {
+						sameTagCount++;
+						if (siblings[i] === el) index = sameTagCount;
+					}
+				}
+			}
+			parts.unshift(sameTagCount > 1 ? tag + ':nth-of-type(' + index + ')' : tag);
+		}
+		return parts.join(' > ');
+	};
+	
+	// 查找 contentWindow 为指定窗口的 iframe 元素（包括 open shadow root 中的 iframe）
+	var findFrameElement = function(source, root) {
+		var frames = root.querySelectorAll('iframe, frame');
+		for (var i = 0; i < frames.length; i++) {
+			if (frames[i].contentWindow === source) return frames[i];
+		}
+		var all = root.querySelectorAll('*');
+		for (var j = 0; j < all.length; j++) {
+			if (all[j].shadowRoot) {
+				var found = findFrameElement(source, all[j].shadowRoot);
+				if (found) return found;
+			}
+		}
+		return null;
+	};
+	
+	// 获取 iframe 元素在顶层文档中的定位路径（所在的 shadow host 链 + iframe 自身）
+	var getFrameHops = function(frameElement) {
+		var hops = [{ type: 'iframe', selector: getScopedCss(frameElement) }];
+		var root = frameElement.getRootNode ? frameElement.getRootNode() : null;
+		while (root && root.nodeType === 11 && root.host) {
+			hops.unshift({ type: 'shadow', selector: getScopedCss(root.host) });
+			root = root.host.getRootNode();
+		}
+		return hops;
+	};
+	
 	window.addEventListener('message', function(event) {
 		try {
 			if (event.data && event.data.type === '__browserwing_iframe_action__') {
 				var action = event.data.action;
 				if (action && window.__recordedActions__) {
+					// 补全定位路径：frame_path 以顶层文档为起点
+					var frameElement = findFrameElement(event.source, document);
+					if (frameElement) {
+						action.frame_path = getFrameHops(frameElement).concat(action.frame_path || []);
+					} else if (!action.frame_path || action.frame_path.length === 0) {
+						// 无法定位来源 iframe 时回退到旧格式（回放时依次尝试顶层 iframe）
+						action.selector = 'iframe ' + action.selector;
+						action.xpath = '//iframe' + action.xpath;
+						delete action.frame_path;
+					}
+					
 					// 去重逻辑：检查最近的操作是否与当前操作重复
 					var shouldRecord = true;
 					
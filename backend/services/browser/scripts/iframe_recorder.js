@@ -8,8 +8,92 @@
 	
 	console.log('[BrowserWing] iframe Recorder initialized');
 	
-	// 向父窗口发送录制的操作
-	var sendToParent = function(action) {
+	// 获取事件的真实目标元素（穿透 open shadow root）
+	var getEventTarget = function(e) {
+		if (e.composedPath) {
+			var path = e.composedPath();
+			if (path && path.length > 0 && path[0] && path[0].nodeType === 1) {
+				return path[0];
+			}
+		}
+		return e.target || e.srcElement;
+	};
+	
+	// 生成相对于元素所在根节点（document 或 shadow root）的选择器
+	var getScopedSelectors = function(element) {
+		var root = element.getRootNode ? element.getRootNode() : document;
+		var inShadow = root !== document && root.nodeType === 11;
+		var cssParts = [];
+		var xpathParts = [];
+		var anchor = '';
+		
+		for (var el = element; el && el.nodeType === 1; el = el.parentNode) {
+			var tag = el.tagName.toLowerCase();
+			if (el.id && !/^[0-9]/.test(el.id) && window.CSS && CSS.escape) {
+				var idSelector = '#' + CSS.escape(el.id);
+				try {
+					if (root.querySelectorAll(idSelector).length === 1) {
+						cssParts.unshift(idSelector);
+						anchor = '//*[@id="' + el.id + '"]';
+						break;
+					}
+				} catch (e) {}
+			}
+			
+			var index = 1;
+			var sameTagCount = 0;
+			if (el.parentNode && el.parentNode.children) {
+				var siblings = el.parentNode.children;
+				for (var i = 0; i < siblings.length; i++) {
+					if (siblings[i].tagName === el.tagName) {
+						sameTagCount++;
+						if (siblings[i] === el) index = sameTagCount;
+					}
+				}
+			}
+			cssParts.unshift(sameTagCount > 1 ? tag + ':nth-of-type(' + index + ')' : tag);
+			xpathParts.unshift(sameTagCount > 1 ? tag + '[' + index + ']' : tag);
+		}
+		
+		var xpath;
+		if (anchor) {
+			xpath = (inShadow ? '.' : '') + anchor + (xpathParts.length > 0 ? '/' + xpathParts.join('/') : '');
+		} else {
+			xpath = (inShadow ? './' : '/') + xpathParts.join('/');
+		}
+		return { css: cssParts.join(' > '), xpath: xpath };
+	};
+	
+	// 获取元素所在的 shadow host 链（从外到内）
+	var getShadowHops = function(element) {
+		var hops = [];
+		var root = element && element.getRootNode ? element.getRootNode() : null;
+		while (root && root.nodeType === 11 && root.host) {
+			var host = root.host;
+			hops.unshift({ type: 'shadow', selector: getScopedSelectors(host).css });
+			root = host.getRootNode();
+		}
+		return hops;
+	};
+	
+	// 查找 contentWindow 为指定窗口的 iframe 元素（包括 open shadow root 中的 iframe）
+	var findFrameElement = function(source, root) {
+		var frames = root.querySelectorAll('iframe, frame');
+		for (var i = 0; i < frames.length; i++) {
+			if (frames[i].contentWindow === source) return frames[i];
+		}
+		var all = root.querySelectorAll('*');
+		for (var j = 0; j < all.length; j++) {
+			if (all[j].shadowRoot) {
+				var found = findFrameElement(source, all[j].shadowRoot);
+				if (found) return found;
+			}
+		}
+		return null;
+	};
+	
+	// 向父窗口发送录制的操作（父窗口负责在 frame_path 前补充当前 iframe 的路径）
+	var postToParent = function(action) {
 		try {
 			// 标记为来自 iframe
 			action.fromIframe = true;
@@ -22,55 +106,68 @@
 		}
 	};
 	
-	// 获取选择器（简化版）
+	// 发送当前文档中元素的操作：frame_path 记录元素所在的 shadow host 链
+	var sendToParent = function(action, element) {
+		action.frame_path = element ? getShadowHops(element) : [];
+		postToParent(action);
+	};
+	
+	// 转发嵌套 iframe 中录制的操作：在 frame_path 前补充子 iframe 在当前文档中的路径
+	window.addEventListener('message', function(event) {
+		try {
+			if (!event.data || event.data.type !== '__browserwing_iframe_action__' || !event.data.action) return;
+			
+			var action = event.data.action;
+			var frameElement = findFrameElement(event.source, document);
+			if (frameElement) {
+				var hops = getShadowHops(frameElement);
+				hops.push({ type: 'iframe', selector: getScopedSelectors(frameElement).css });
+				action.frame_path = hops.concat(action.frame_path || []);
+			} else {
+				console.warn('[BrowserWing] Could not locate nested iframe for forwarded action');
+			}
+			postToParent(action);
+		} catch (e) {
+			console.error('[BrowserWing] iframe forward error:', e);
+		}
+	});
+	
+	// 获取选择器（简化版，相对于元素所在的文档或 shadow root）
 	var getSelector = function(element) {
 		if (!element || !element.tagName) {
 			return { css: 'unknown', xpath: '//*' };
 		}
 		
-		var css = element.tagName.toLowerCase();
-		var xpath = '//' + css;
+		var tag = element.tagName.toLowerCase();
 		
 		// ID
 		if (element.id) {
-			css = '#' + element.id;
-			xpath = '//*[@id="' + element.id + '"]';
-			return { css: css, xpath: xpath };
+			return { css: '#' + element.id, xpath: '//*[@id="' + element.id + '"]' };
 		}
 		
 		// name
 		if (element.name) {
-			css += '[name="' + element.name + '"]';
-			xpath += '[@name="' + element.name + '"]';
-			return { css: css, xpath: xpath };
-		}
-		
-		// class
-		if (element.className && typeof element.className === 'string') {
-			var classes = element.className.trim().split(/\s+/);
-			if (classes.length > 0 && classes[0]) {
-				css += '.' + classes[0];
-			}
+			return { css: tag + '[name="' + element.name + '"]', xpath: '//' + tag + '[@name="' + element.name + '"]' };
 		}
 		
-		return { css: css, xpath: xpath };
+		return getScopedSelectors(element);
 	};
 	
 	// 监听点击
 	document.addEventListener('click', function(e) {
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target || !target.tagName) return;
 			
 			var selectors = getSelector(target);
 			sendToParent({
 				type: 'click',
 				timestamp: Date.now(),
-				selector: 'iframe ' + selectors.css,
-				xpath: '//iframe' + selectors.xpath,
+				selector: selectors.css,
+				xpath: selectors.xpath,
 				text: (target.innerText || target.textContent || '').substring(0, 50),
 				tagName: target.tagName.toLowerCase()
-			});
+			}, target);
 		} catch (err) {
 			console.error('[BrowserWing] iframe click error:', err);
 		}
@@ -82,7 +179,7 @@
 	// 监听 input 事件（标准输入）
 	document.addEventListener('input', function(e) {
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target) return;
 			
 			var tagName = target.tagName ? target.tagName.toUpperCase() : '';
@@ -103,11 +200,11 @@
 				sendToParent({
 					type: 'input',
 					timestamp: Date.now(),
-					selector: 'iframe ' + selectors.css,
-					xpath: '//iframe' + selectors.xpath,
+					selector: selectors.css,
+					xpath: selectors.xpath,
 					value: content,
 					tagName: isContentEditable ? 'contenteditable' : tagName.toLowerCase()
-				});
+				}, target);
 			}, 500);
 		} catch (err) {
 			console.error('[BrowserWing] iframe input error:', err);
@@ -117,7 +214,7 @@
 	// 监听 blur 事件（失去焦点时立即记录最终值）
 	document.addEventListener('blur', function(e) {
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target) return;
 			
 			var tagName = target.tagName ? target.tagName.toUpperCase() : '';
@@ -142,11 +239,11 @@
 				sendToParent({
 					type: 'input',
 					timestamp: Date.now(),
-					selector: 'iframe ' + selectors.css,
-					xpath: '//iframe' + selectors.xpath,
+					selector: selectors.css,
+					xpath: selectors.xpath,
 					value: content,
 					tagName: isContentEditable ? 'contenteditable' : tagName.toLowerCase()
-				});
+				}, target);
 			}
 		} catch (err) {
 			console.error('[BrowserWing] iframe blur error:', err);
@@ -181,11 +278,11 @@
 				sendToParent({
 					type: 'input',
 					timestamp: Date.now(),
-					selector: 'iframe ' + selectors.css,
-					xpath: '//iframe' + selectors.xpath,
+					selector: selectors.css,
+					xpath: selectors.xpath,
 					value: content,
 					tagName: 'contenteditable'
-				});
+				}, editableParent);
 			}, 500);
 		} catch (err) {
 			console.error('[BrowserWing] iframe DOMCharacterDataModified error:', err);
@@ -195,7 +292,7 @@
 	// 监听选择
 	document.addEventListener('change', function(e) {
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target) return;
 			
 			var tagName = target.tagName ? target.tagName.toUpperCase() : '';
@@ -207,12 +304,12 @@
 				sendToParent({
 					type: 'select',
 					timestamp: Date.now(),
-					selector: 'iframe ' + selectors.css,
-					xpath: '//iframe' + selectors.xpath,
+					selector: selectors.css,
+					xpath: selectors.xpath,
 					value: target.value || '',
 					text: selectedText,
 					tagName: 'select'
-				});
+				}, target);
 			}
 		} catch (err) {
 			console.error('[BrowserWing] iframe change error:', err);
@@ -3258,6 +3258,84 @@ if (window.__browserwingRecorder__) {
 		console.log('[BrowserWing] Recorded extraction:', extractType, variableName);
 	};
 	
+	// ============= Shadow DOM / iframe 定位路径 =============
+	
+	// 获取事件的真实目标元素（穿透 open shadow root，document 上的监听器只能拿到 shadow host）
+	var getEventTarget = function(e) {
+		if (e.composedPath) {
+			var path = e.composedPath();
+			if (path && path.length > 0 && path[0] && path[0].nodeType === 1) {
+				return path[0];
+			}
+		}
+		return e.target || e.srcElement;
+	};
+	
+	// 判断元素是否位于 shadow root 中
+	var isInShadowRoot = function(element) {
+		if (!element || !element.getRootNode) return false;
+		var root = element.getRootNode();
+		return !!root && root.nodeType === 11 && !!root.host;
+	};
+	
+	// 生成相对于元素所在根节点（document 或 shadow root）的选择器
+	// CSS 为 "#id > div:nth-of-type(2) > input" 形式；shadow root 内 XPath 以 "./" 开头（以 shadow root 为上下文节点）
+	var getScopedSelectors = function(element) {
+		var root = element.getRootNode ? element.getRootNode() : document;
+		var inShadow = root !== document && root.nodeType === 11;
+		var cssParts = [];
+		var xpathParts = [];
+		var anchor = '';
+		
+		for (var el = element; el && el.nodeType === 1; el = el.parentNode) {
+			var tag = el.tagName.toLowerCase();
+			if (el.id && !/^[0-9]/.test(el.id) && window.CSS && CSS.escape) {
+				var idSelector = '#' + CSS.escape(el.id);
+				try {
+					if (root.querySelectorAll(idSelector).length === 1) {
+						cssParts.unshift(idSelector);
+						anchor = '//*[@id="' + el.id + '"]';
+						break;
+					}
+				} catch (e) {}
+			}
+			
+			var index = 1;
+			var sameTagCount = 0;
+			if (el.parentNode && el.parentNode.children) {
+				var siblings = el.parentNode.children;
+				for (var i = 0; i < siblings.length; i++) {
+					if (siblings[i].tagName === el.tagName) {
+						sameTagCount++;
+						if (siblings[i] === el) index = sameTagCount;
+					}
+				}
+			}
+			cssParts.unshift(sameTagCount > 1 ? tag + ':nth-of-type(' + index + ')' : tag);
+			xpathParts.unshift(sameTagCount > 1 ? tag + '[' + index + ']' : tag);
+		}
+		
+		var xpath;
+		if (anchor) {
+			xpath = (inShadow ? '.' : '') + anchor + (xpathParts.length > 0 ? '/' + xpathParts.join('/') : '');
+		} else {
+			xpath = (inShadow ? './' : '/') + xpathParts.join('/');
+		}
+		return { css: cssParts.join(' > '), xpath: xpath };
+	};
+	
+	// 获取元素所在的 shadow host 链（从外到内），用于 frame_path
+	var getShadowHops = function(element) {
+		var hops = [];
+		var root = element && element.getRootNode ? element.getRootNode() : null;
+		while (root && root.nodeType === 11 && root.host) {
+			var host = root.host;
+			hops.unshift({ type: 'shadow', selector: getScopedSelectors(host).css });
+			root = host.getRootNode();
+		}
+		return hops;
+	};
+	
 	// 生成更精确和可靠的选择器（支持 CSS 和 XPath）
 	var getSelector = function(element) {
 		if (!element || !element.tagName) {
@@ -3265,6 +3343,11 @@ if (window.__browserwingRecorder__) {
 		}
 		
 		try {
+			// shadow root 内的元素使用相对于 shadow root 的选择器（配合 frame_path 回放）
+			if (isInShadowRoot(element)) {
+				return getScopedSelectors(element);
+			}
+			
 			var css = '';
 			var xpath = '';
 			
@@ -3823,6 +3906,14 @@ if (window.__browserwingRecorder__) {
 	
 	// 记录操作的辅助函数（带去重）
 	var recordAction = function(action, element, eventType) {
+		// 元素位于 Shadow DOM 中时记录定位路径
+		if (element && !action.frame_path) {
+			var shadowHops = getShadowHops(element);
+			if (shadowHops.length > 0) {
+				action.frame_path = shadowHops;
+			}
+		}
+		
 		// 去重逻辑：检查最近的操作是否与当前操作重复
 		if (window.__recordedActions__.length > 0) {
 			var lastAction = window.__recordedActions__[window.__recordedActions__.length - 1];
@@ -4007,7 +4098,7 @@ if (window.__browserwingRecorder__) {
 		if (!window.__isRecordingActive__) return;
 		
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target || !target.tagName) return;
 			
 		// 忽略录制器 UI 自身
@@ -4049,7 +4140,7 @@ if (window.__browserwingRecorder__) {
 		if (!window.__isRecordingActive__) return;
 		
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target || !target.tagName) return;
 			
 		// 忽略录制器 UI 自身的点击
@@ -4297,7 +4388,7 @@ if (window.__browserwingRecorder__) {
 		}
 		
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target) return;
 			
 			// 忽略AI控制面板的输入框
@@ -4366,7 +4457,7 @@ if (window.__browserwingRecorder__) {
 		if (!window.__isRecordingActive__) return;
 		
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target) return;
 			
 			// 忽略AI控制面板的输入框
@@ -4481,7 +4572,7 @@ if (window.__browserwingRecorder__) {
 		}
 		
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target) return;
 			
 			var tagName = target.tagName ? target.tagName.toUpperCase() : '';
@@ -4560,7 +4651,7 @@ if (window.__browserwingRecorder__) {
 	document.addEventListener('contextmenu', function(e) {
 		if (!window.__extractMode__) return;
 		
-		var target = e.target || e.srcElement;
+		var target = getEventTarget(e);
 		if (!target || !target.tagName) return;
 		
 		// 忽略录制器 UI 自身
@@ -4592,7 +4683,7 @@ if (window.__browserwingRecorder__) {
 		if (!window.__isRecordingActive__) return;
 		
 		try {
-			var target = e.target || e.srcElement;
+			var target = getEventTarget(e);
 			if (!target) return;
 			
 		// 忽略录制器 UI 自身的键盘事件
@@ -0,0 +1,306 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+)
+
+// FormatVersion 当前导出包格式版本
+const FormatVersion = 1
+
+const (
+	manifestName = "bundle.json" // zip 包中的清单文件
+	filesDir     = "files/"      // zip 包中上传文件所在目录
+	zipMagic     = "PK\x03\x04"
+)
+
+var (
+	ErrNoScripts          = errors.New("no scripts to export")
+	ErrInvalidBundle      = errors.New("invalid bundle")
+	ErrUnsupportedVersion = errors.New("unsupported bundle version")
+)
+
+// Bundle 脚本导出包
+type Bundle struct {
+	Version        int                    `json:"version"`
+	ExportedAt     time.Time              `json:"exported_at"`
+	Scripts        []*models.Script       `json:"scripts"`
+	ToolConfigs    []*models.ToolConfig   `json:"tool_configs,omitempty"`    // 脚本对应的 MCP 工具配置
+	ScheduledTasks []models.ScheduledTask `json:"scheduled_tasks,omitempty"` // 关联脚本的定时任务（可选）
+	BrowserConfigs []models.BrowserConfig `json:"browser_configs,omitempty"` // URL 匹配脚本的浏览器配置（可选）
+	Files          []File                 `json:"files,omitempty"`           // upload_file 操作引用的本地文件
+	MissingFiles   []string               `json:"missing_files,omitempty"`   // 导出时无法读取的文件
+}
+
+// File 上传操作引用的文件
+type File struct {
+	Name   string `json:"name"`           // 包内文件名（zip 中位于 files/ 目录）
+	Path   string `json:"path"`           // 导出端的原始路径（FilePaths 中的值）
+	Size   int64  `json:"size"`           // 文件大小
+	SHA256 string `json:"sha256"`         // 文件内容校验值
+	Data   []byte `json:"data,omitempty"` // 文件内容（仅 JSON 格式内嵌，base64）
+}
+
+// ExportOptions 导出选项
+type ExportOptions struct {
+	ScriptIDs             []string // 为空时导出全部脚本
+	IncludeTasks          bool     // 是否包含关联的定时任务
+	IncludeBrowserConfigs bool     // 是否包含匹配的浏览器配置
+}
+
+// Export 从数据库收集脚本及其依赖生成导出包
+func Export(db *storage.BoltDB, opts ExportOptions) (*Bundle, error) {
+	var scripts []*models.Script
+	if len(opts.ScriptIDs) == 0 {
+		all, err := db.ListScripts()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list scripts: %w", err)
+		}
+		scripts = all
+	} else {
+		for _, id := range opts.ScriptIDs {
+			script, err := db.GetScript(id)
+			if err != nil {
+				continue // 跳过不存在的脚本
+			}
+			scripts = append(scripts, script)
+		}
+	}
+	if len(scripts) == 0 {
+		return nil, ErrNoScripts
+	}
+
+	b := &Bundle{
+		Version:    FormatVersion,
+		ExportedAt: time.Now(),
+		Scripts:    scripts,
+	}
+	scriptIDs := make(map[string]bool, len(scripts))
+	for _, script := range scripts {
+		scriptIDs[script.ID] = true
+	}
+
+	toolConfigs, err := db.ListToolConfigs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool configs: %w", err)
+	}
+	for _, cfg := range toolConfigs {
+		if cfg.Type == models.ToolTypeScript && scriptIDs[cfg.ScriptID] {
+			b.ToolConfigs = append(b.ToolConfigs, cfg)
+		}
+	}
+
+	if opts.IncludeTasks {
+		tasks, err := db.ListScheduledTasks()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list scheduled tasks: %w", err)
+		}
+		for _, task := range tasks {
+			if task.ExecutionType == models.ExecutionTypeScript && scriptIDs[task.ScriptID] {
+				b.ScheduledTasks = append(b.ScheduledTasks, task)
+			}
+		}
+	}
+
+	if opts.IncludeBrowserConfigs {
+		configs, err := db.ListBrowserConfigs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list browser configs: %w", err)
+		}
+		for _, cfg := range configs {
+			if matchesAnyScript(cfg.URLPattern, scripts) {
+				b.BrowserConfigs = append(b.BrowserConfigs, cfg)
+			}
+		}
+	}
+
+	b.collectFiles()
+	return b, nil
+}
+
+// matchesAnyScript 判断浏览器配置的 URL 规则是否匹配任一脚本（与回放时的配置匹配规则一致）
+func matchesAnyScript(pattern string, scripts []*models.Script) bool {
+	if pattern == "" {
+		return false
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	for _, script := range scripts {
+		url := script.URL
+		if url == "" && len(script.Actions) > 0 {
+			url = script.Actions[0].URL
+		}
+		if url != "" && re.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectFiles 读取脚本上传操作引用的本地文件（跳过 URL 和包含变量的路径）
+func (b *Bundle) collectFiles() {
+	seen := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, script := range b.Scripts {
+		walkActions(script.Actions, func(action *models.ScriptAction) {
+			for _, path := range action.FilePaths {
+				if seen[path] || !isLocalFilePath(path) {
+					continue
+				}
+				seen[path] = true
+
+				data, err := os.ReadFile(path)
+				if err != nil {
+					b.MissingFiles = append(b.MissingFiles, path)
+					continue
+				}
+				sum := sha256.Sum256(data)
+				b.Files = append(b.Files, File{
+					Name:   uniqueFileName(filepath.Base(path), names),
+					Path:   path,
+					Size:   int64(len(data)),
+					SHA256: hex.EncodeToString(sum[:]),
+					Data:   data,
+				})
+			}
+		})
+	}
+}
+
+// isLocalFilePath 判断 FilePaths 中的值是否为可打包的本地文件路径
+func isLocalFilePath(path string) bool {
+	lower := strings.ToLower(path)
+	return path != "" &&
+		!strings.HasPrefix(lower, "http://") &&
+		!strings.HasPrefix(lower, "https://") &&
+		!strings.Contains(path, "${")
+}
+
+// uniqueFileName 生成包内唯一的文件名
+func uniqueFileName(name string, used map[string]bool) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// walkActions 递归遍历操作（包括循环体、条件分支中的嵌套操作）
+func walkActions(actions []models.ScriptAction, fn func(*models.ScriptAction)) {
+	for i := range actions {
+		fn(&actions[i])
+		walkActions(actions[i].Actions, fn)
+		walkActions(actions[i].ElseActions, fn)
+	}
+}
+
+// WriteJSON 以 JSON 格式写出导出包（文件内容内嵌为 base64）
+func (b *Bundle) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(b)
+}
+
+// WriteZip 以 zip 格式写出导出包（清单为 bundle.json，文件位于 files/ 目录）
+func (b *Bundle) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	manifest := *b
+	manifest.Files = make([]File, len(b.Files))
+	for i, f := range b.Files {
+		f.Data = nil
+		manifest.Files[i] = f
+	}
+	mw, err := zw.Create(manifestName)
+	if err != nil {
+		return err
+	}
+	if err := manifest.WriteJSON(mw); err != nil {
+		return err
+	}
+
+	for _, f := range b.Files {
+		fw, err := zw.Create(filesDir + f.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.Data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Read 解析导出包（自动识别 zip 和 JSON 格式）
+func Read(data []byte) (*Bundle, error) {
+	var b Bundle
+	if bytes.HasPrefix(data, []byte(zipMagic)) {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+		entries := make(map[string]*zip.File, len(zr.File))
+		for _, f := range zr.File {
+			entries[f.Name] = f
+		}
+		manifest, ok := entries[manifestName]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidBundle, manifestName)
+		}
+		raw, err := readZipEntry(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+		for i := range b.Files {
+			entry, ok := entries[filesDir+b.Files[i].Name]
+			if !ok {
+				continue
+			}
+			if b.Files[i].Data, err = readZipEntry(entry); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+			}
+		}
+	} else if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+
+	if b.Version <= 0 {
+		return nil, fmt.Errorf("%w: missing version", ErrInvalidBundle)
+	}
+	if b.Version > FormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, b.Version)
+	}
+	return &b, nil
+}
+
+// readZipEntry 读取 zip 中的单个文件
+func readZipEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,143 @@
+package bundle
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func newTestDB(t *testing.T) *storage.BoltDB {
+	t.Helper()
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	return db
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	upload := filepath.Join(dir, "avatar.png")
+	if err := os.WriteFile(upload, []byte("png-data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	src := newTestDB(t)
+	child := &models.Script{ID: "child", Name: "child", URL: "https://example.com/child"}
+	parent := &models.Script{ID: "parent", Name: "parent", URL: "https://example.com", Actions: []models.ScriptAction{
+		{Type: "upload_file", Selector: "#file", FilePaths: []string{upload, "https://example.com/remote.png"}},
+		{Type: "call_script", ScriptID: "child"},
+	}}
+	for _, s := range []*models.Script{child, parent} {
+		if err := src.SaveScript(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	task := &models.ScheduledTask{ID: "task", Name: "nightly", ExecutionType: models.ExecutionTypeScript, ScriptID: "parent", ExecutionCount: 7}
+	if err := src.CreateScheduledTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := Export(src, ExportOptions{IncludeTasks: true})
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(exported.Scripts) != 2 || len(exported.ScheduledTasks) != 1 || len(exported.Files) != 1 {
+		t.Fatalf("unexpected bundle contents: %d scripts, %d tasks, %d files",
+			len(exported.Scripts), len(exported.ScheduledTasks), len(exported.Files))
+	}
+
+	var buf bytes.Buffer
+	if err := exported.WriteZip(&buf); err != nil {
+		t.Fatalf("write zip failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		conflict ConflictMode
+		scripts  int
+		renamed  int
+		skipped  int
+	}{
+		{"first import", ConflictSkip, 2, 0, 0},
+		{"skip", ConflictSkip, 2, 0, 2},
+		{"rename", ConflictRename, 4, 2, 0},
+		{"overwrite", ConflictOverwrite, 4, 0, 0},
+	}
+
+	dst := newTestDB(t)
+	filesDir := filepath.Join(t.TempDir(), "uploads")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Read(buf.Bytes())
+			if err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+			report, err := Import(dst, b, ImportOptions{Conflict: tt.conflict, FilesDir: filesDir})
+			if err != nil {
+				t.Fatalf("import failed: %v", err)
+			}
+			scripts, _ := dst.ListScripts()
+			if len(scripts) != tt.scripts {
+				t.Errorf("scripts = %d, want %d", len(scripts), tt.scripts)
+			}
+			var renamed, skipped int
+			for _, item := range report.Items {
+				if item.Kind != KindScript {
+					continue
+				}
+				switch item.Action {
+				case ImportRenamed:
+					renamed++
+				case ImportSkipped:
+					skipped++
+				}
+			}
+			if renamed != tt.renamed || skipped != tt.skipped {
+				t.Errorf("renamed = %d, skipped = %d, want %d, %d", renamed, skipped, tt.renamed, tt.skipped)
+			}
+
+			// 重命名导入的脚本引用应指向同批导入的子脚本
+			for _, id := range report.Imported(KindScript) {
+				s, _ := dst.GetScript(id)
+				if len(s.Actions) != 2 {
+					continue
+				}
+				childID := s.Actions[1].ScriptID
+				if _, err := dst.GetScript(childID); err != nil {
+					t.Errorf("call_script references missing script %s", childID)
+				}
+				paths := s.Actions[0].FilePaths
+				if data, err := os.ReadFile(paths[0]); err != nil || string(data) != "png-data" {
+					t.Errorf("upload file not restored: %v", err)
+				}
+				if paths[1] != "https://example.com/remote.png" {
+					t.Errorf("remote file path rewritten: %s", paths[1])
+				}
+			}
+		})
+	}
+
+	tasks, _ := dst.ListScheduledTasks()
+	for _, task := range tasks {
+		if task.ExecutionCount != 0 {
+			t.Errorf("imported task %s kept execution stats", task.Name)
+		}
+		if _, err := dst.GetScript(task.ScriptID); err != nil {
+			t.Errorf("imported task %s references missing script %s", task.Name, task.ScriptID)
+		}
+	}
+}
+
+func TestReadRejectsUnsupportedVersion(t *testing.T) {
+	if _, err := Read([]byte(`{"version": 99, "scripts": []}`)); err == nil {
+		t.Fatal("expected error for unsupported version")
+	}
+	if _, err := Read([]byte(`{"scripts": []}`)); err == nil {
+		t.Fatal("expected error for missing version")
+	}
+}
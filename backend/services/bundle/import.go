@@ -0,0 +1,480 @@
+package bundle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+	"github.com/google/uuid"
+)
+
+// ConflictMode 导入时与已有数据冲突（ID 或名称相同）的处理方式
+type ConflictMode string
+
+const (
+	ConflictSkip      ConflictMode = "skip"      // 保留已有数据
+	ConflictOverwrite ConflictMode = "overwrite" // 覆盖已有数据（保留已有 ID）
+	ConflictRename    ConflictMode = "rename"    // 以新 ID 和新名称导入
+)
+
+// ImportAction 单个对象的导入结果
+type ImportAction string
+
+const (
+	ImportCreated     ImportAction = "created"
+	ImportOverwritten ImportAction = "overwritten"
+	ImportRenamed     ImportAction = "renamed"
+	ImportSkipped     ImportAction = "skipped"
+)
+
+// 导入对象类型
+const (
+	KindScript        = "script"
+	KindScheduledTask = "scheduled_task"
+	KindBrowserConfig = "browser_config"
+	KindFile          = "file"
+)
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	Conflict ConflictMode // 冲突处理方式（默认 skip）
+	FilesDir string       // 上传文件的保存目录
+	Author   string       // 脚本版本记录的修改人
+}
+
+// ImportItem 单个对象的导入记录
+type ImportItem struct {
+	Kind     string       `json:"kind"`
+	SourceID string       `json:"source_id"` // 导出包中的 ID（文件为原始路径）
+	ID       string       `json:"id"`        // 导入后的 ID（文件为保存路径）
+	Name     string       `json:"name"`      // 导入后的名称
+	Action   ImportAction `json:"action"`    // created, overwritten, renamed, skipped
+}
+
+// ImportReport 导入结果
+type ImportReport struct {
+	Items       []ImportItem `json:"items"`
+	Created     int          `json:"created"`
+	Overwritten int          `json:"overwritten"`
+	Renamed     int          `json:"renamed"`
+	Skipped     int          `json:"skipped"`
+	Warnings    []string     `json:"warnings,omitempty"`
+}
+
+// add 记录导入结果并更新计数
+func (r *ImportReport) add(item ImportItem) {
+	r.Items = append(r.Items, item)
+	switch item.Action {
+	case ImportCreated:
+		r.Created++
+	case ImportOverwritten:
+		r.Overwritten++
+	case ImportRenamed:
+		r.Renamed++
+	case ImportSkipped:
+		r.Skipped++
+	}
+}
+
+// Imported 返回指定类型中实际写入数据库的对象 ID（不含跳过的）
+func (r *ImportReport) Imported(kind string) []string {
+	var ids []string
+	for _, item := range r.Items {
+		if item.Kind == kind && item.Action != ImportSkipped {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// ParseConflictMode 解析冲突处理方式（为空时使用 skip）
+func ParseConflictMode(s string) (ConflictMode, error) {
+	switch mode := ConflictMode(s); mode {
+	case "":
+		return ConflictSkip, nil
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown conflict mode: %s", s)
+	}
+}
+
+// importer 单次导入的上下文
+type importer struct {
+	db     *storage.BoltDB
+	opts   ImportOptions
+	report *ImportReport
+
+	scriptIDs   map[string]string // 导出包中的脚本 ID -> 导入后的脚本 ID
+	scriptNames map[string]string // 重命名的脚本：原名称 -> 新名称
+	filePaths   map[string]string // 导出端文件路径 -> 导入后的文件路径
+}
+
+// Import 将导出包导入数据库
+// 依次导入文件、脚本（含 MCP 工具配置）、定时任务和浏览器配置，脚本间引用和任务关联按导入后的 ID 重写
+func Import(db *storage.BoltDB, b *Bundle, opts ImportOptions) (*ImportReport, error) {
+	if opts.Conflict == "" {
+		opts.Conflict = ConflictSkip
+	}
+	im := &importer{
+		db:          db,
+		opts:        opts,
+		report:      &ImportReport{Items: []ImportItem{}},
+		scriptIDs:   make(map[string]string),
+		scriptNames: make(map[string]string),
+		filePaths:   make(map[string]string),
+	}
+	for _, path := range b.MissingFiles {
+		im.report.Warnings = append(im.report.Warnings, fmt.Sprintf("file was not included in the bundle: %s", path))
+	}
+
+	if err := im.importFiles(b.Files); err != nil {
+		return nil, err
+	}
+	if err := im.importScripts(b.Scripts, b.ToolConfigs); err != nil {
+		return nil, err
+	}
+	if err := im.importTasks(b.ScheduledTasks); err != nil {
+		return nil, err
+	}
+	if err := im.importBrowserConfigs(b.BrowserConfigs); err != nil {
+		return nil, err
+	}
+	return im.report, nil
+}
+
+// importFiles 保存上传文件（按内容去重），记录路径映射
+func (im *importer) importFiles(files []File) error {
+	if len(files) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(im.opts.FilesDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create files directory: %w", err)
+	}
+	for _, f := range files {
+		if f.Data == nil {
+			im.report.Warnings = append(im.report.Warnings, fmt.Sprintf("file content missing in bundle: %s", f.Path))
+			continue
+		}
+		sum := sha256.Sum256(f.Data)
+		checksum := hex.EncodeToString(sum[:])
+		if f.SHA256 != "" && f.SHA256 != checksum {
+			im.report.Warnings = append(im.report.Warnings, fmt.Sprintf("checksum mismatch, file skipped: %s", f.Path))
+			continue
+		}
+
+		target := filepath.Join(im.opts.FilesDir, checksum[:12]+"_"+filepath.Base(f.Name))
+		action := ImportCreated
+		if existing, err := os.ReadFile(target); err == nil && bytes.Equal(existing, f.Data) {
+			action = ImportSkipped
+		} else if err := os.WriteFile(target, f.Data, 0o644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", target, err)
+		}
+		if abs, err := filepath.Abs(target); err == nil {
+			target = abs
+		}
+		im.filePaths[f.Path] = target
+		im.report.add(ImportItem{Kind: KindFile, SourceID: f.Path, ID: target, Name: f.Name, Action: action})
+	}
+	return nil
+}
+
+// importScripts 导入脚本，先确定所有脚本的目标 ID，再重写脚本间引用后保存
+func (im *importer) importScripts(scripts []*models.Script, toolConfigs []*models.ToolConfig) error {
+	existing, err := im.db.ListScripts()
+	if err != nil {
+		return fmt.Errorf("failed to list scripts: %w", err)
+	}
+	byID := make(map[string]*models.Script, len(existing))
+	byName := make(map[string]*models.Script, len(existing))
+	names := make(map[string]bool, len(existing))
+	mcpNames := make(map[string]string) // MCP 命令名称 -> 脚本 ID
+	for _, s := range existing {
+		byID[s.ID] = s
+		byName[s.Name] = s
+		names[s.Name] = true
+		if s.IsMCPCommand && s.MCPCommandName != "" {
+			mcpNames[s.MCPCommandName] = s.ID
+		}
+	}
+
+	type plan struct {
+		sourceID string
+		script   *models.Script
+		current  *models.Script
+		action   ImportAction
+	}
+	plans := make([]plan, 0, len(scripts))
+	for _, s := range scripts {
+		if s == nil {
+			continue
+		}
+		sourceID := s.ID
+		current := byID[s.ID]
+		if current == nil {
+			current = byName[s.Name]
+		}
+
+		action := ImportCreated
+		switch {
+		case current == nil:
+			if s.ID == "" {
+				s.ID = uuid.New().String()
+			}
+		case im.opts.Conflict == ConflictSkip:
+			action = ImportSkipped
+			s.ID = current.ID
+		case im.opts.Conflict == ConflictOverwrite:
+			action = ImportOverwritten
+			s.ID = current.ID
+		default:
+			action = ImportRenamed
+			s.ID = uuid.New().String()
+			newName := uniqueName(s.Name, names)
+			im.scriptNames[s.Name] = newName
+			s.Name = newName
+		}
+		names[s.Name] = true
+		im.scriptIDs[sourceID] = s.ID
+		plans = append(plans, plan{sourceID: sourceID, script: s, current: current, action: action})
+	}
+
+	for _, p := range plans {
+		s := p.script
+		if p.action == ImportSkipped {
+			im.report.add(ImportItem{Kind: KindScript, SourceID: p.sourceID, ID: s.ID, Name: p.current.Name, Action: p.action})
+			continue
+		}
+
+		im.rewriteScript(s)
+		if s.IsMCPCommand && s.MCPCommandName != "" {
+			if owner, ok := mcpNames[s.MCPCommandName]; ok && owner != s.ID {
+				original := s.MCPCommandName
+				for i := 2; ; i++ {
+					candidate := fmt.Sprintf("%s_%d", original, i)
+					if _, taken := mcpNames[candidate]; !taken {
+						s.MCPCommandName = candidate
+						break
+					}
+				}
+				im.report.Warnings = append(im.report.Warnings,
+					fmt.Sprintf("MCP command name %q already in use, script %q imported as %q", original, s.Name, s.MCPCommandName))
+			}
+			mcpNames[s.MCPCommandName] = s.ID
+		}
+
+		if p.current != nil && p.action == ImportOverwritten {
+			s.CreatedAt = p.current.CreatedAt
+		} else if s.CreatedAt.IsZero() || p.action == ImportRenamed {
+			s.CreatedAt = time.Now()
+		}
+		if err := im.db.UpdateScriptWithRevision(s, im.opts.Author, "Imported from bundle"); err != nil {
+			return fmt.Errorf("failed to save script %s: %w", s.Name, err)
+		}
+		im.report.add(ImportItem{Kind: KindScript, SourceID: p.sourceID, ID: s.ID, Name: s.Name, Action: p.action})
+	}
+
+	// MCP 工具配置跟随脚本导入（ID 与脚本 ID 关联）
+	imported := make(map[string]*models.Script)
+	for _, p := range plans {
+		if p.action != ImportSkipped {
+			imported[p.script.ID] = p.script
+		}
+	}
+	for _, cfg := range toolConfigs {
+		if cfg == nil {
+			continue
+		}
+		script, ok := imported[im.scriptIDs[cfg.ScriptID]]
+		if !ok {
+			continue
+		}
+		cfg.ID = "script_" + script.ID
+		cfg.ScriptID = script.ID
+		cfg.Name = script.MCPCommandName
+		cfg.UpdatedAt = time.Now()
+		if err := im.db.SaveToolConfig(cfg); err != nil {
+			return fmt.Errorf("failed to save tool config for script %s: %w", script.Name, err)
+		}
+	}
+	return nil
+}
+
+// rewriteScript 按导入后的 ID 和路径重写脚本中的引用
+func (im *importer) rewriteScript(s *models.Script) {
+	if s.ErrorPolicy != nil {
+		s.ErrorPolicy.FallbackScriptID = im.mapScriptID(s.ErrorPolicy.FallbackScriptID)
+	}
+	walkActions(s.Actions, func(action *models.ScriptAction) {
+		if action.ScriptID != "" {
+			action.ScriptID = im.mapScriptID(action.ScriptID)
+		} else if newName, ok := im.scriptNames[action.ScriptName]; ok {
+			action.ScriptName = newName
+		}
+		if action.ErrorPolicy != nil {
+			action.ErrorPolicy.FallbackScriptID = im.mapScriptID(action.ErrorPolicy.FallbackScriptID)
+		}
+		for i, path := range action.FilePaths {
+			if target, ok := im.filePaths[path]; ok {
+				action.FilePaths[i] = target
+			}
+		}
+	})
+}
+
+// mapScriptID 将导出包中的脚本 ID 映射为导入后的 ID（不在包中的 ID 保持不变）
+func (im *importer) mapScriptID(id string) string {
+	if mapped, ok := im.scriptIDs[id]; ok {
+		return mapped
+	}
+	return id
+}
+
+// importTasks 导入定时任务，关联脚本按导入后的 ID 重写，执行统计清零
+func (im *importer) importTasks(tasks []models.ScheduledTask) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	existing, err := im.db.ListScheduledTasks()
+	if err != nil {
+		return fmt.Errorf("failed to list scheduled tasks: %w", err)
+	}
+	byID := make(map[string]*models.ScheduledTask, len(existing))
+	byName := make(map[string]*models.ScheduledTask, len(existing))
+	names := make(map[string]bool, len(existing))
+	for i := range existing {
+		byID[existing[i].ID] = &existing[i]
+		byName[existing[i].Name] = &existing[i]
+		names[existing[i].Name] = true
+	}
+
+	for i := range tasks {
+		task := tasks[i]
+		sourceID := task.ID
+		current := byID[task.ID]
+		if current == nil {
+			current = byName[task.Name]
+		}
+
+		action := ImportCreated
+		switch {
+		case current == nil:
+			if task.ID == "" {
+				task.ID = uuid.New().String()
+			}
+		case im.opts.Conflict == ConflictSkip:
+			im.report.add(ImportItem{Kind: KindScheduledTask, SourceID: sourceID, ID: current.ID, Name: current.Name, Action: ImportSkipped})
+			continue
+		case im.opts.Conflict == ConflictOverwrite:
+			action = ImportOverwritten
+			task.ID = current.ID
+		default:
+			action = ImportRenamed
+			task.ID = uuid.New().String()
+			task.Name = uniqueName(task.Name, names)
+		}
+		names[task.Name] = true
+
+		task.ScriptID = im.mapScriptID(task.ScriptID)
+		if script, err := im.db.GetScript(task.ScriptID); err == nil {
+			task.ScriptName = script.Name
+		}
+		if task.BrowserInstanceID != "" {
+			if _, err := im.db.GetBrowserInstance(task.BrowserInstanceID); err != nil {
+				im.report.Warnings = append(im.report.Warnings,
+					fmt.Sprintf("browser instance %s of task %q not found, using default instance", task.BrowserInstanceID, task.Name))
+				task.BrowserInstanceID = ""
+			}
+		}
+		task.LastExecutionTime = nil
+		task.NextExecutionTime = nil
+		task.LastExecutionStatus = ""
+		task.ExecutionCount, task.SuccessCount, task.FailedCount = 0, 0, 0
+		task.UpdatedAt = time.Now()
+
+		if action == ImportOverwritten {
+			task.CreatedAt = current.CreatedAt
+			err = im.db.UpdateScheduledTask(&task)
+		} else {
+			task.CreatedAt = time.Now()
+			err = im.db.CreateScheduledTask(&task)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save scheduled task %s: %w", task.Name, err)
+		}
+		im.report.add(ImportItem{Kind: KindScheduledTask, SourceID: sourceID, ID: task.ID, Name: task.Name, Action: action})
+	}
+	return nil
+}
+
+// importBrowserConfigs 导入浏览器配置（导入的配置不会成为默认配置）
+func (im *importer) importBrowserConfigs(configs []models.BrowserConfig) error {
+	if len(configs) == 0 {
+		return nil
+	}
+	existing, err := im.db.ListBrowserConfigs()
+	if err != nil {
+		return fmt.Errorf("failed to list browser configs: %w", err)
+	}
+	byID := make(map[string]*models.BrowserConfig, len(existing))
+	byName := make(map[string]*models.BrowserConfig, len(existing))
+	names := make(map[string]bool, len(existing))
+	for i := range existing {
+		byID[existing[i].ID] = &existing[i]
+		byName[existing[i].Name] = &existing[i]
+		names[existing[i].Name] = true
+	}
+
+	for i := range configs {
+		cfg := configs[i]
+		sourceID := cfg.ID
+		current := byID[cfg.ID]
+		if current == nil {
+			current = byName[cfg.Name]
+		}
+
+		action := ImportCreated
+		switch {
+		case current == nil:
+			if cfg.ID == "" {
+				cfg.ID = fmt.Sprintf("config_%d", time.Now().UnixNano())
+			}
+			cfg.CreatedAt = time.Time{}
+		case im.opts.Conflict == ConflictSkip:
+			im.report.add(ImportItem{Kind: KindBrowserConfig, SourceID: sourceID, ID: current.ID, Name: current.Name, Action: ImportSkipped})
+			continue
+		case im.opts.Conflict == ConflictOverwrite:
+			action = ImportOverwritten
+			cfg.ID = current.ID
+			cfg.CreatedAt = current.CreatedAt
+		default:
+			action = ImportRenamed
+			cfg.ID = fmt.Sprintf("config_%d", time.Now().UnixNano())
+			cfg.Name = uniqueName(cfg.Name, names)
+			cfg.CreatedAt = time.Time{}
+		}
+		names[cfg.Name] = true
+		cfg.IsDefault = current != nil && action == ImportOverwritten && current.IsDefault
+
+		if err := im.db.SaveBrowserConfig(&cfg); err != nil {
+			return fmt.Errorf("failed to save browser config %s: %w", cfg.Name, err)
+		}
+		im.report.add(ImportItem{Kind: KindBrowserConfig, SourceID: sourceID, ID: cfg.ID, Name: cfg.Name, Action: action})
+	}
+	return nil
+}
+
+// uniqueName 生成不与已有名称重复的名称，例如 "登录 (2)"
+func uniqueName(name string, used map[string]bool) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s (%d)", name, i)
+	}
+	return candidate
+}
@@ -0,0 +1,1110 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// Target 代码生成目标
+type Target string
+
+const (
+	TargetRod              Target = "go-rod"            // Go 程序（go-rod）
+	TargetPlaywrightTS     Target = "playwright-ts"     // @playwright/test 测试用例（TypeScript）
+	TargetPlaywrightPython Target = "playwright-python" // pytest-playwright 测试用例（Python）
+	TargetPuppeteer        Target = "puppeteer"         // Node.js 脚本（Puppeteer）
+)
+
+// Targets 支持的代码生成目标
+var Targets = []Target{TargetRod, TargetPlaywrightTS, TargetPlaywrightPython, TargetPuppeteer}
+
+// ErrUnsupportedTarget 不支持的代码生成目标
+var ErrUnsupportedTarget = errors.New("unsupported target")
+
+const (
+	defaultMaxLoopIterations = 100   // 与回放一致：条件循环默认最大迭代次数
+	defaultMaxPages          = 50    // 与回放一致：分页默认最大页数
+	defaultPageWait          = 1000  // 与回放一致：翻页后默认等待时长（毫秒）
+	defaultIdleTime          = 500   // 网络空闲默认时长（毫秒）
+	defaultWaitTimeout       = 30000 // 事件等待和 XHR 捕获默认超时（毫秒）
+	loopItemAttribute        = "data-browserwing-item"
+	todoMarker               = "TODO(browserwing)"
+)
+
+// TODO 无法自动转换、需要人工处理的步骤
+type TODO struct {
+	Step   string `json:"step"` // 步骤编号（嵌套步骤如 "3.2"，else 分支如 "3.else.1"）
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// Result 代码生成结果
+type Result struct {
+	Target   Target `json:"target"`
+	FileName string `json:"file_name"`
+	Code     string `json:"code"`
+	TODOs    []TODO `json:"todos"`
+}
+
+// ParseTarget 解析代码生成目标（支持常用别名）
+func ParseTarget(s string) (Target, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "go-rod", "rod", "go":
+		return TargetRod, nil
+	case "playwright-ts", "playwright", "ts", "typescript":
+		return TargetPlaywrightTS, nil
+	case "playwright-python", "python", "py":
+		return TargetPlaywrightPython, nil
+	case "puppeteer", "js", "javascript":
+		return TargetPuppeteer, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedTarget, s)
+}
+
+// Generate 将脚本的操作列表转换为目标框架可直接运行的源码
+func Generate(script *models.Script, target Target) (*Result, error) {
+	var d dialect
+	switch target {
+	case TargetRod:
+		d = rodDialect{}
+	case TargetPlaywrightTS:
+		d = playwrightTSDialect{}
+	case TargetPlaywrightPython:
+		d = playwrightPythonDialect{}
+	case TargetPuppeteer:
+		d = puppeteerDialect{}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTarget, target)
+	}
+
+	g := &generator{d: d, script: script, uses: make(map[string]bool), shared: make(map[string]bool), todos: []TODO{}}
+	g.scan(script.Actions)
+	if g.uses["filters"] {
+		g.todos = append(g.todos, TODO{Step: "*", Type: "variables",
+			Reason: "variable filters (${name|filter}) are not applied, the raw value is substituted"})
+	}
+
+	// 先生成主体，前言部分根据用到的特性决定导入和初始化代码
+	g.depth = d.bodyDepth()
+	g.emitActions(script.Actions, "")
+	body := g.buf.String()
+
+	code := d.prologue(g) + body + d.epilogue(g)
+	if target == TargetRod {
+		if formatted, err := format.Source([]byte(code)); err == nil {
+			code = string(formatted)
+		}
+	}
+	return &Result{
+		Target:   target,
+		FileName: d.fileName(fileBaseName(script.Name)),
+		Code:     code,
+		TODOs:    g.todos,
+	}, nil
+}
+
+// locator 元素定位（表达式已按目标语言生成）
+type locator struct {
+	Hops  []hop
+	Expr  string // 选择器表达式
+	XPath bool   // Expr 是否为 XPath
+}
+
+// hop 定位路径节点
+type hop struct {
+	Type models.FrameHopType
+	Expr string // iframe 元素或 shadow host 的 CSS 选择器表达式
+}
+
+// dialect 目标语言/框架的代码片段
+// 表达式方法返回单个表达式；语句方法返回一行或多行代码，多行时使用 indentUnit 表示相对缩进
+type dialect interface {
+	fileName(base string) string
+	indentUnit() string
+	bodyDepth() int
+	prologue(g *generator) string
+	epilogue(g *generator) string
+	comment(text string) string
+
+	// 表达式
+	literal(s string) string
+	render(s string) string // 含 ${var} 的字符串模板
+	strList(exprs []string) string
+	concat(a, b string) string
+	xpathPrefix() string
+	element(loc locator) string
+	function(js string) string                // 内联的页面 JS 函数
+	snippetName(name string) string           // 共用页面 JS 常量名
+	snippetDef(name, js string) string        // 共用页面 JS 常量定义
+	eval(fn string, args []string) string     // 任意 JSON 值
+	evalStr(fn string, args []string) string  // 字符串
+	evalBool(fn string, args []string) string // 布尔值
+	evalList(fn string, args []string) string // 字符串列表
+	text(el string) string
+	html(el string) string
+	attr(el, name string) string
+	varGet(name string) string
+	hasVar(name string) string
+	listVar(name string) string
+	check(actual, op, expected string) string
+	and(exprs []string) string
+	or(exprs []string) string
+	not(expr string) string
+	boolLiteral(b bool) string
+	itoa(expr string) string // 整数表达式转字符串
+
+	// 语句
+	navigate(url string) string
+	click(el string) string
+	fill(el, value string) string
+	selectOption(el, label string) string
+	focus(el string) string
+	press(k keyCombo) string
+	upload(el, files string) string
+	sleep(ms int) string
+	waitForFunction(fn string, args []string, timeoutMs int) string
+	waitNetworkIdle(idleMs int) string
+	setViewport(width, height int, scale float64, mobile bool) string
+	evalStmt(fn string, args []string) string
+	setVar(name, value string) string   // 字符串结果（同时写入变量和抓取结果）
+	setData(name, value string) string  // JSON 结果（写入抓取结果）
+	setLocal(name, value string) string // 循环变量（只写入变量）
+	screenshot(path, mode string, clip [4]int) string
+	openTab(url string) string
+	switchTab(index int) string
+	switchActiveTab() string
+	assert(label, actual, op, expected string) string
+
+	// 控制流
+	ifOpen(cond string) string
+	elseLine() string
+	blockClose() string
+	emptyBlock() string
+	forRange(index string, limit int) string
+	forEach(index, item, list string) string
+	breakStmt() string
+}
+
+// generator 代码生成上下文
+type generator struct {
+	d      dialect
+	script *models.Script
+	buf    strings.Builder
+	depth  int
+	todos  []TODO
+	uses   map[string]bool // 生成代码用到的特性（xhr、tabs、screenshot、region、keyboard、filters），决定导入和初始化代码
+	shared map[string]bool // 用到的共用页面 JS 片段
+	seq    int             // 局部变量序号
+	stmts  int             // 已写入的语句数（不含注释）
+	blocks []int           // 代码块开始时的语句数（用于补全空代码块）
+}
+
+// scan 预先扫描脚本用到的特性（XHR 拦截器需在导航前注入）
+func (g *generator) scan(actions []models.ScriptAction) {
+	for _, a := range actions {
+		switch a.Type {
+		case "capture_xhr":
+			g.uses["xhr"] = true
+		case "open_tab", "switch_tab", "switch_active_tab":
+			g.uses["tabs"] = true
+		}
+		g.scan(a.Actions)
+		g.scan(a.ElseActions)
+	}
+	data, _ := json.Marshal(actions)
+	if filterPattern.Match(data) {
+		g.uses["filters"] = true
+	}
+}
+
+// js 返回页面 JS 函数表达式，共用片段引用常量名
+func (g *generator) js(js string) string {
+	for _, s := range snippets {
+		if s.js == js {
+			g.shared[s.name] = true
+			return g.d.snippetName(s.name)
+		}
+	}
+	return g.d.function(js)
+}
+
+// snippetDefs 返回用到的共用页面 JS 片段定义
+func (g *generator) snippetDefs() string {
+	var b strings.Builder
+	for _, s := range snippets {
+		if g.shared[s.name] {
+			b.WriteString(g.d.snippetDef(s.name, s.js) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// line 按当前缩进写入代码
+func (g *generator) line(code string) {
+	if code == "" {
+		return
+	}
+	if !strings.HasPrefix(code, g.d.comment("")) {
+		g.stmts++
+	}
+	indent := strings.Repeat(g.d.indentUnit(), g.depth)
+	for _, l := range strings.Split(code, "\n") {
+		if strings.TrimSpace(l) == "" {
+			g.buf.WriteString("\n")
+			continue
+		}
+		g.buf.WriteString(indent + l + "\n")
+	}
+}
+
+// open 写入代码块开头并增加缩进
+func (g *generator) open(code string) {
+	g.line(code)
+	g.depth++
+	g.blocks = append(g.blocks, g.stmts)
+}
+
+// close 结束代码块（代码块为空时补全占位语句）
+func (g *generator) close(code string) {
+	start := g.blocks[len(g.blocks)-1]
+	g.blocks = g.blocks[:len(g.blocks)-1]
+	if g.stmts == start {
+		g.line(g.d.emptyBlock())
+	}
+	g.depth--
+	g.line(code)
+}
+
+// todo 记录无法转换的步骤并写入 TODO 注释
+func (g *generator) todo(step string, a models.ScriptAction, reason string) {
+	g.todos = append(g.todos, TODO{Step: step, Type: a.Type, Reason: reason})
+	g.line(g.d.comment(fmt.Sprintf("%s: step %s (%s) %s", todoMarker, step, a.Type, reason)))
+}
+
+// emitActions 生成一组操作的代码
+func (g *generator) emitActions(actions []models.ScriptAction, prefix string) {
+	for i, a := range actions {
+		step := strconv.Itoa(i + 1)
+		if prefix != "" {
+			step = prefix + "." + step
+		}
+		g.emitAction(a, step)
+	}
+}
+
+// emitAction 生成单个操作的代码（启用的操作级条件转换为 if 块）
+func (g *generator) emitAction(a models.ScriptAction, step string) {
+	g.line(g.d.comment(fmt.Sprintf("Step %s: %s", step, describeAction(a))))
+	if p := a.ErrorPolicy; p != nil && (p.Retries() > 0 || p.OnError != "") {
+		g.todo(step, a, "error policy (retry / on_error) is not translated")
+	}
+
+	if a.Type != "if" && a.Condition != nil && a.Condition.Enabled {
+		g.open(g.d.ifOpen(g.condition(*a.Condition, step, a)))
+		g.emitStep(a, step)
+		g.close(g.d.blockClose())
+		return
+	}
+	g.emitStep(a, step)
+}
+
+// emitStep 生成操作本身的代码
+func (g *generator) emitStep(a models.ScriptAction, step string) {
+	d := g.d
+	if a.Transform != "" {
+		g.todo(step, a, "value transform is not applied")
+	}
+	switch a.Type {
+	case "navigate":
+		g.line(d.navigate(g.str(a.URL)))
+		if a.WaitFor != nil {
+			g.waitFor(*a.WaitFor, step, a)
+		}
+	case "click":
+		g.line(d.click(g.element(a, step)))
+	case "input":
+		g.line(d.fill(g.element(a, step), g.str(a.Value)))
+	case "select":
+		g.line(d.selectOption(g.element(a, step), g.str(a.Value)))
+	case "sleep":
+		g.line(d.sleep(a.Duration))
+	case "wait":
+		g.line(d.sleep(int(a.Timestamp)))
+	case "wait_for":
+		if a.WaitFor == nil {
+			g.todo(step, a, "has no wait condition")
+			return
+		}
+		g.waitFor(*a.WaitFor, step, a)
+	case "extract_text":
+		g.line(d.setVar(variableName(a, "text"), d.text(g.element(a, step))))
+	case "extract_html":
+		g.line(d.setVar(variableName(a, "html"), d.html(g.element(a, step))))
+	case "extract_attribute":
+		g.line(d.setVar(variableName(a, "attribute"), d.attr(g.element(a, step), a.AttributeName)))
+	case "extract_list":
+		if a.Selector == "" && a.XPath == "" {
+			g.todo(step, a, "has no list container selector")
+			return
+		}
+		if len(a.FramePath) > 0 {
+			g.todo(step, a, "frame path is ignored, the list is read from the top document")
+		}
+		if hasFieldTransforms(a) {
+			g.todo(step, a, "field transforms are not applied")
+		}
+		args := []string{g.str(a.Selector), g.str(a.XPath), d.literal(fieldsJSON(a))}
+		g.line(d.setData(variableName(a, "list"), d.eval(g.js(extractListJS), args)))
+	case "execute_js":
+		if strings.Contains(a.JSCode, "${") {
+			g.todo(step, a, "variables inside the JS code are not substituted")
+		}
+		js := functionJS(a.JSCode)
+		if a.VariableName != "" {
+			g.line(d.setData(a.VariableName, d.eval(g.js(js), nil)))
+		} else {
+			g.line(d.evalStmt(g.js(js), nil))
+		}
+	case "upload_file":
+		exprs := make([]string, len(a.FilePaths))
+		for i, path := range a.FilePaths {
+			exprs[i] = g.str(path)
+		}
+		g.line(d.upload(g.element(a, step), d.strList(exprs)))
+	case "scroll":
+		g.line(d.evalStmt(g.js("([x, y]) => window.scrollTo(x, y)"), []string{strconv.Itoa(a.ScrollX), strconv.Itoa(a.ScrollY)}))
+	case "keyboard":
+		k, ok := parseKey(a.Key)
+		if !ok {
+			g.todo(step, a, fmt.Sprintf("key %q is not supported", a.Key))
+			return
+		}
+		if a.Selector != "" || a.XPath != "" {
+			g.line(d.focus(g.element(a, step)))
+		}
+		g.uses["keyboard"] = true
+		g.line(d.press(k))
+	case "screenshot":
+		mode := a.ScreenshotMode
+		if mode == "" {
+			mode = "viewport"
+		}
+		g.uses["screenshot"] = true
+		if mode == "region" {
+			g.uses["region"] = true
+		}
+		path := variableName(a, "screenshot_"+strings.ReplaceAll(step, ".", "_")) + ".png"
+		g.line(d.screenshot(path, mode, [4]int{a.X, a.Y, a.ScreenshotWidth, a.ScreenshotHeight}))
+	case "capture_xhr":
+		if a.URL == "" || a.Method == "" {
+			g.todo(step, a, "requires url and method")
+			return
+		}
+		key := []string{d.literal(strings.ToUpper(a.Method) + "|" + a.URL)}
+		g.line(d.waitForFunction(g.js(xhrReadyJS), key, defaultWaitTimeout))
+		g.line(d.setData(variableName(a, "xhr_data"), d.eval(g.js(xhrResponseJS), key)))
+	case "open_tab":
+		g.line(d.openTab(g.str(a.URL)))
+	case "switch_tab":
+		index, err := strconv.Atoi(strings.TrimSpace(a.Value))
+		if err != nil {
+			g.todo(step, a, fmt.Sprintf("tab index %q is not a number", a.Value))
+			return
+		}
+		g.line(d.switchTab(index))
+	case "switch_active_tab":
+		g.line(d.switchActiveTab())
+	case "if":
+		g.emitIf(a, step)
+	case "loop":
+		g.emitLoop(a, step)
+	case "foreach":
+		g.emitForeach(a, step)
+	case "paginate":
+		g.emitPaginate(a, step)
+	case "assert_text", "assert_visible", "assert_url", "assert_count", "assert_attribute", "assert_variable":
+		g.emitAssert(a, step)
+	case "set_viewport":
+		if a.ViewportWidth <= 0 || a.ViewportHeight <= 0 {
+			g.todo(step, a, "viewport width and height are not set")
+			return
+		}
+		scale := a.DeviceScaleFactor
+		if scale <= 0 {
+			scale = 1
+		}
+		g.line(d.setViewport(a.ViewportWidth, a.ViewportHeight, scale, a.Mobile))
+	case "call_script":
+		name := a.ScriptName
+		if name == "" {
+			name = a.ScriptID
+		}
+		g.todo(step, a, fmt.Sprintf("inline or call the generated code of script %q", name))
+	case "ai_control":
+		g.todo(step, a, fmt.Sprintf("AI-driven step cannot be translated, prompt: %q", a.AIControlPrompt))
+	default:
+		g.todo(step, a, "unknown action type")
+	}
+}
+
+// str 生成字符串表达式（含 ${var} 时在运行时替换）
+func (g *generator) str(s string) string {
+	if !strings.Contains(s, "${") {
+		return g.d.literal(s)
+	}
+	return g.d.render(s)
+}
+
+// prefixed 生成带固定前缀的字符串表达式
+func (g *generator) prefixed(prefix, s string) string {
+	if prefix == "" {
+		return g.str(s)
+	}
+	if !strings.Contains(s, "${") {
+		return g.d.literal(prefix + s)
+	}
+	return g.d.concat(g.d.literal(prefix), g.d.render(s))
+}
+
+// element 生成元素定位表达式（优先 XPath，与回放一致）
+func (g *generator) element(a models.ScriptAction, step string) string {
+	if a.Selector == "" && a.XPath == "" {
+		g.todo(step, a, "has no selector")
+	}
+	return g.d.element(g.locator(a.FramePath, a.Selector, a.XPath))
+}
+
+// locator 构建元素定位
+func (g *generator) locator(path []models.FrameHop, selector, xpath string) locator {
+	loc := locator{Expr: g.str(selector)}
+	if xpath != "" {
+		loc = locator{Expr: g.prefixed(g.d.xpathPrefix(), xpath), XPath: true}
+	}
+	for _, h := range path {
+		loc.Hops = append(loc.Hops, hop{Type: h.Type, Expr: g.str(h.Selector)})
+	}
+	return loc
+}
+
+// pageArgs 生成页面状态判断的参数 [type, selector, xpath, text]
+func (g *generator) pageArgs(kind, selector, xpath, text string) []string {
+	return []string{g.d.literal(kind), g.str(selector), g.str(xpath), g.str(text)}
+}
+
+// emitIf 生成 if/else 块
+func (g *generator) emitIf(a models.ScriptAction, step string) {
+	if a.Condition == nil {
+		g.todo(step, a, "has no condition")
+		return
+	}
+	g.open(g.d.ifOpen(g.condition(*a.Condition, step, a)))
+	g.emitActions(a.Actions, step)
+	if len(a.ElseActions) > 0 {
+		g.close(g.d.elseLine())
+		g.depth++
+		g.blocks = append(g.blocks, g.stmts)
+		g.emitActions(a.ElseActions, step+".else")
+	}
+	g.close(g.d.blockClose())
+}
+
+// emitLoop 生成 loop 块（固定次数或条件循环，条件循环受最大迭代次数限制）
+func (g *generator) emitLoop(a models.ScriptAction, step string) {
+	limit := a.LoopCount
+	if a.LoopCondition != nil {
+		limit = a.MaxIterations
+		if limit <= 0 {
+			limit = defaultMaxLoopIterations
+		}
+		if a.LoopCount > 0 && a.LoopCount < limit {
+			limit = a.LoopCount
+		}
+	} else if a.MaxIterations > 0 && a.MaxIterations < limit {
+		limit = a.MaxIterations
+	}
+	if limit <= 0 {
+		g.todo(step, a, "requires loop_count or loop_condition")
+		return
+	}
+
+	index := g.localName("i")
+	g.open(g.d.forRange(index, limit))
+	g.line(g.d.setLocal(loopVariableName(a.IndexVariable, "index"), g.d.itoa(index)))
+	if a.LoopCondition != nil {
+		g.open(g.d.ifOpen(g.d.not(g.condition(*a.LoopCondition, step, a))))
+		g.line(g.d.breakStmt())
+		g.close(g.d.blockClose())
+	}
+	g.emitActions(a.Actions, step)
+	g.close(g.d.blockClose())
+}
+
+// emitForeach 生成 foreach 块（遍历列表变量或匹配的元素）
+// 遍历元素时与回放一致：先为每个元素打上标记属性，${<item>_selector} 引用当前元素
+func (g *generator) emitForeach(a models.ScriptAction, step string) {
+	d := g.d
+	itemVar := loopVariableName(a.ItemVariable, "item")
+	index := g.localName("i")
+	item := g.localName("item")
+
+	var list string
+	marked := a.ListVariable == ""
+	if !marked {
+		list = d.listVar(a.ListVariable)
+	} else if a.Selector != "" || a.XPath != "" {
+		if len(a.FramePath) > 0 {
+			g.todo(step, a, "frame path is ignored, elements are matched in the top document")
+		}
+		list = d.evalList(g.js(markElementsJS), []string{g.str(a.Selector), g.str(a.XPath), d.literal(strconv.Itoa(g.seq))})
+	} else {
+		g.todo(step, a, "requires list_variable or selector")
+		return
+	}
+
+	g.open(d.forEach(index, item, list))
+	if a.MaxIterations > 0 {
+		g.open(d.ifOpen(fmt.Sprintf("%s >= %d", index, a.MaxIterations)))
+		g.line(d.breakStmt())
+		g.close(d.blockClose())
+	}
+	g.line(d.setLocal(loopVariableName(a.IndexVariable, "index"), d.itoa(index)))
+	g.line(d.setLocal(itemVar, item))
+	if marked {
+		prefix := d.literal(fmt.Sprintf(`[%s="%d-`, loopItemAttribute, g.seq))
+		g.line(d.setLocal(itemVar+"_selector", d.concat(d.concat(prefix, d.itoa(index)), d.literal(`"]`))))
+	}
+	g.emitActions(a.Actions, step)
+	g.close(d.blockClose())
+}
+
+// emitPaginate 生成分页块：每页执行嵌套操作后点击下一页，或按 URL 模板跳转后执行嵌套操作
+func (g *generator) emitPaginate(a models.ScriptAction, step string) {
+	d := g.d
+	cfg := a.Pagination
+	if cfg == nil || (cfg.NextSelector == "" && cfg.NextXPath == "" && cfg.URLTemplate == "") {
+		g.todo(step, a, "requires next_selector, next_xpath or url_template")
+		return
+	}
+	maxPages := cfg.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	wait := cfg.WaitAfter
+	if wait <= 0 {
+		wait = defaultPageWait
+	}
+	startPage := cfg.StartPage
+	if startPage <= 0 {
+		startPage = 1
+	}
+
+	index := g.localName("p")
+	g.open(d.forRange(index, maxPages))
+	if cfg.URLTemplate != "" {
+		g.line(d.setLocal("page", d.itoa(fmt.Sprintf("%s+%d", index, startPage))))
+		g.line(d.navigate(d.render(cfg.URLTemplate)))
+		g.line(d.sleep(wait))
+		g.emitActions(a.Actions, step)
+	} else {
+		g.line(d.setLocal("page", d.itoa(index+"+1")))
+		g.emitActions(a.Actions, step)
+		visible := d.evalBool(g.js(pageConditionJS), g.pageArgs("selector_visible", cfg.NextSelector, cfg.NextXPath, ""))
+		g.open(d.ifOpen(d.not(visible)))
+		g.line(d.breakStmt())
+		g.close(d.blockClose())
+		g.line(d.click(d.element(g.locator(nil, cfg.NextSelector, cfg.NextXPath))))
+		g.line(d.sleep(wait))
+	}
+	g.close(d.blockClose())
+}
+
+// emitAssert 生成断言（操作符默认值与回放一致）
+func (g *generator) emitAssert(a models.ScriptAction, step string) {
+	d := g.d
+	op := a.AssertOperator
+	expected := g.str(a.Value)
+	var actual, label string
+	switch a.Type {
+	case "assert_text":
+		label = targetDescription(a)
+		actual = d.text(g.element(a, step))
+		if op == "" {
+			op = "contains"
+		}
+	case "assert_attribute":
+		label = targetDescription(a) + "@" + a.AttributeName
+		actual = d.attr(g.element(a, step), a.AttributeName)
+	case "assert_visible":
+		label = targetDescription(a)
+		actual = d.evalStr(g.js(visibleJS), []string{g.str(a.Selector), g.str(a.XPath)})
+		op = "equals"
+		if a.Value == "" {
+			expected = d.literal("true")
+		}
+	case "assert_url":
+		label = "url"
+		actual = d.evalStr(g.js("() => location.href"), nil)
+		if op == "" {
+			op = "contains"
+		}
+	case "assert_count":
+		label = targetDescription(a)
+		actual = d.evalStr(g.js(countJS), []string{g.str(a.Selector), g.str(a.XPath)})
+	case "assert_variable":
+		label = a.VariableName
+		actual = d.varGet(a.VariableName)
+	}
+	if op == "" {
+		op = "equals"
+	}
+	g.line(d.assert(label, actual, op, expected))
+}
+
+// waitFor 生成事件等待
+func (g *generator) waitFor(cond models.WaitCondition, step string, a models.ScriptAction) {
+	d := g.d
+	timeout := cond.Timeout
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+	switch cond.Type {
+	case models.WaitNetworkIdle:
+		idle := cond.IdleTime
+		if idle <= 0 {
+			idle = defaultIdleTime
+		}
+		g.line(d.waitNetworkIdle(idle))
+	case models.WaitURLMatches:
+		g.line(d.waitForFunction(g.js(urlMatchesJS), []string{g.str(cond.Pattern)}, timeout))
+	case models.WaitJSPredicate:
+		g.line(d.waitForFunction(g.js(expressionJS(cond.Expression)), nil, timeout))
+	case models.WaitSelectorVisible, models.WaitSelectorHidden, models.WaitTextPresent:
+		g.line(d.waitForFunction(g.js(pageConditionJS), g.pageArgs(string(cond.Type), cond.Selector, cond.XPath, cond.Text), timeout))
+	default:
+		g.todo(step, a, fmt.Sprintf("wait condition %q is not supported", cond.Type))
+	}
+}
+
+// condition 生成条件表达式
+func (g *generator) condition(cond models.ActionCondition, step string, a models.ScriptAction) string {
+	d := g.d
+	var expr string
+	switch cond.Type {
+	case "", models.ConditionVariable:
+		if cond.Transform != "" {
+			g.todo(step, a, "condition transform is not applied, the raw variable is compared")
+		}
+		switch cond.Operator {
+		case "exists":
+			expr = d.hasVar(cond.Variable)
+		case "not_exists":
+			expr = d.not(d.hasVar(cond.Variable))
+		default:
+			expr = d.check(d.varGet(cond.Variable), cond.Operator, g.str(cond.Value))
+		}
+	case models.ConditionElementExists:
+		expr = d.evalBool(g.js(pageConditionJS), g.pageArgs("selector_exists", cond.Selector, cond.XPath, ""))
+	case models.ConditionElementVisible:
+		expr = d.evalBool(g.js(pageConditionJS), g.pageArgs("selector_visible", cond.Selector, cond.XPath, ""))
+	case models.ConditionTextPresent:
+		expr = d.evalBool(g.js(pageConditionJS), g.pageArgs("text_present", cond.Selector, cond.XPath, cond.Text))
+	case models.ConditionURLMatches:
+		expr = d.evalBool(g.js(urlMatchesJS), []string{g.str(cond.Pattern)})
+	case models.ConditionJS:
+		expr = d.evalBool(g.js(expressionJS(cond.Expression)), nil)
+	case models.ConditionAnd, models.ConditionOr:
+		if len(cond.Conditions) == 0 {
+			g.todo(step, a, fmt.Sprintf("%s condition has no sub-conditions", cond.Type))
+			return d.boolLiteral(false)
+		}
+		parts := make([]string, len(cond.Conditions))
+		for i, sub := range cond.Conditions {
+			parts[i] = g.condition(sub, step, a)
+		}
+		if cond.Type == models.ConditionAnd {
+			expr = d.and(parts)
+		} else {
+			expr = d.or(parts)
+		}
+	default:
+		g.todo(step, a, fmt.Sprintf("condition type %q is not supported", cond.Type))
+		return d.boolLiteral(false)
+	}
+	if cond.Timeout > 0 && isPageCondition(cond.Type) {
+		g.todo(step, a, "condition timeout is not translated, the page state is checked once")
+	}
+	if cond.Negate {
+		expr = d.not(expr)
+	}
+	return expr
+}
+
+// localName 生成唯一的局部变量名
+func (g *generator) localName(base string) string {
+	g.seq++
+	return base + strconv.Itoa(g.seq)
+}
+
+// variableNames 返回脚本预设变量名（按名称排序，保证输出稳定）
+func (g *generator) variableNames() []string {
+	names := make([]string, 0, len(g.script.Variables))
+	for name := range g.script.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// header 生成文件头注释
+func (g *generator) header() string {
+	lines := []string{
+		fmt.Sprintf("Generated by BrowserWing from script %q.", g.script.Name),
+		"Review the code before committing it to your repository.",
+	}
+	if len(g.todos) > 0 {
+		lines = append(lines, fmt.Sprintf("%d step(s) need manual work, search for %s.", len(g.todos), todoMarker))
+	}
+	for i, l := range lines {
+		lines[i] = g.d.comment(l)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// isPageCondition 判断是否为页面状态条件
+func isPageCondition(t models.ConditionType) bool {
+	switch t {
+	case models.ConditionElementExists, models.ConditionElementVisible, models.ConditionURLMatches,
+		models.ConditionTextPresent, models.ConditionJS:
+		return true
+	}
+	return false
+}
+
+// describeAction 生成步骤注释
+func describeAction(a models.ScriptAction) string {
+	desc := a.Type
+	if target := targetDescription(a); target != "" {
+		desc += " " + target
+	}
+	if a.URL != "" && (a.Type == "navigate" || a.Type == "open_tab" || a.Type == "capture_xhr") {
+		desc += " " + a.URL
+	}
+	if a.Remark != "" {
+		desc += " - " + a.Remark
+	} else if a.Description != "" {
+		desc += " - " + a.Description
+	}
+	return strings.Join(strings.Fields(desc), " ")
+}
+
+// targetDescription 元素定位描述（优先 XPath）
+func targetDescription(a models.ScriptAction) string {
+	if a.XPath != "" {
+		return a.XPath
+	}
+	return a.Selector
+}
+
+// variableName 返回结果变量名（为空时使用默认名称）
+func variableName(a models.ScriptAction, fallback string) string {
+	if a.VariableName != "" {
+		return a.VariableName
+	}
+	return fallback
+}
+
+// loopVariableName 返回循环变量名（为空时使用默认名称）
+func loopVariableName(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// hasFieldTransforms 判断列表抓取字段是否配置了值转换
+func hasFieldTransforms(a models.ScriptAction) bool {
+	for _, f := range a.Fields {
+		if f.Transform != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldsJSON 序列化列表抓取的字段定义（作为参数传入页面内 JS）
+func fieldsJSON(a models.ScriptAction) string {
+	data, _ := json.Marshal(a.Fields)
+	return string(data)
+}
+
+// functionJS 将 execute_js 代码转换为函数表达式（与回放时的包装规则一致）
+func functionJS(code string) string {
+	code = strings.TrimSpace(code)
+	switch {
+	case strings.HasPrefix(code, "() =>"), strings.HasPrefix(code, "function"):
+		return code
+	case strings.HasPrefix(code, "(() =>") && strings.HasSuffix(code, ")();"):
+		return code[1 : len(code)-4]
+	case strings.HasPrefix(code, "(() =>") && strings.HasSuffix(code, ")()"):
+		return code[1 : len(code)-3]
+	default:
+		return "() => {\n" + code + "\n}"
+	}
+}
+
+// expressionJS 将 JS 表达式包装为返回布尔值的函数
+func expressionJS(expr string) string {
+	return "() => !!(" + strings.TrimSpace(expr) + ")"
+}
+
+// extractListJS 列表抓取（参数：[selector, xpath, fieldsJSON]）
+const extractListJS = `([selector, xpath, fieldsJSON]) => {
+	const fields = JSON.parse(fieldsJSON || '{}');
+	let items = [];
+	if (xpath) {
+		const r = document.evaluate(xpath, document, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+		for (let i = 0; i < r.snapshotLength; i++) items.push(r.snapshotItem(i));
+	} else {
+		items = Array.from(document.querySelectorAll(selector));
+	}
+	const read = (el, f) => {
+		if (!el) return null;
+		switch (f.type) {
+			case 'html': return el.innerHTML;
+			case 'attribute': return el.getAttribute(f.attribute);
+			case 'property': return el[f.attribute];
+			default: return (el.innerText || el.textContent || '').trim();
+		}
+	};
+	return items.map((item) => {
+		const row = {};
+		for (const [name, f] of Object.entries(fields)) {
+			if (f.multiple) {
+				row[name] = (f.selector ? Array.from(item.querySelectorAll(f.selector)) : [item]).map((el) => read(el, f));
+			} else {
+				row[name] = read(f.selector ? item.querySelector(f.selector) : item, f);
+			}
+		}
+		return row;
+	});
+}`
+
+// pageConditionJS 页面状态判断（参数：[type, selector, xpath, text]）
+const pageConditionJS = `([type, selector, xpath, text]) => {
+	let el = null;
+	if (xpath) {
+		el = document.evaluate(xpath, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue;
+	} else if (selector) {
+		el = document.querySelector(selector);
+	}
+	const visible = (node) => {
+		if (!node || !node.isConnected) return false;
+		const style = window.getComputedStyle(node);
+		if (style.display === 'none' || style.visibility === 'hidden' || style.opacity === '0') return false;
+		const rect = node.getBoundingClientRect();
+		return rect.width > 0 && rect.height > 0;
+	};
+	switch (type) {
+		case 'selector_exists': return !!el;
+		case 'selector_visible': return visible(el);
+		case 'selector_hidden': return !visible(el);
+		case 'text_present': {
+			const scope = xpath || selector ? el : document.body;
+			return !!scope && (scope.innerText || scope.textContent || '').includes(text);
+		}
+	}
+	return false;
+}`
+
+// urlMatchesJS 页面 URL 匹配（正则无效时按子串匹配，参数：[pattern]）
+const urlMatchesJS = `([pattern]) => {
+	try { return new RegExp(pattern).test(location.href); } catch (e) { return location.href.includes(pattern); }
+}`
+
+// visibleJS 元素是否可见，返回 "true"/"false"（参数：[selector, xpath]）
+const visibleJS = `([selector, xpath]) => {
+	const el = xpath
+		? document.evaluate(xpath, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue
+		: document.querySelector(selector);
+	if (!el) return 'false';
+	const style = window.getComputedStyle(el);
+	const rect = el.getBoundingClientRect();
+	return String(rect.width > 0 && rect.height > 0 && style.visibility !== 'hidden' && style.display !== 'none');
+}`
+
+// countJS 匹配元素数量，返回字符串（参数：[selector, xpath]）
+const countJS = `([selector, xpath]) => String(xpath
+	? document.evaluate(xpath, document, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null).snapshotLength
+	: document.querySelectorAll(selector).length)`
+
+// markElementsJS 为匹配的元素打上循环标记，返回元素文本列表（参数：[selector, xpath, seq]）
+const markElementsJS = `([selector, xpath, seq]) => {
+	let items = [];
+	if (xpath) {
+		const r = document.evaluate(xpath, document, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+		for (let i = 0; i < r.snapshotLength; i++) items.push(r.snapshotItem(i));
+	} else {
+		items = Array.from(document.querySelectorAll(selector));
+	}
+	return items.map((el, i) => {
+		el.setAttribute('` + loopItemAttribute + `', seq + '-' + i);
+		return (el.innerText || el.textContent || '').trim();
+	});
+}`
+
+// xhrInterceptorJS 页面加载前注入的 XHR/fetch 拦截器，按 "METHOD|origin+pathname" 保存响应（与回放一致）
+const xhrInterceptorJS = `() => {
+	if (window.__capturedXHRData__) return;
+	window.__capturedXHRData__ = {};
+	const keyOf = (method, url) => {
+		try {
+			const u = new URL(url, location.href);
+			return String(method).toUpperCase() + '|' + u.origin + u.pathname;
+		} catch (e) {
+			return String(method).toUpperCase() + '|' + url;
+		}
+	};
+	const store = (key, status, text) => {
+		let response = text;
+		try { response = JSON.parse(text); } catch (e) {}
+		window.__capturedXHRData__[key] = { status, response };
+	};
+	const open = XMLHttpRequest.prototype.open;
+	XMLHttpRequest.prototype.open = function (method, url) {
+		this.__browserwingKey = keyOf(method, url);
+		return open.apply(this, arguments);
+	};
+	const send = XMLHttpRequest.prototype.send;
+	XMLHttpRequest.prototype.send = function () {
+		this.addEventListener('load', () => {
+			const text = this.responseType === '' || this.responseType === 'text' ? this.responseText : JSON.stringify(this.response);
+			store(this.__browserwingKey, this.status, text);
+		});
+		return send.apply(this, arguments);
+	};
+	const fetch = window.fetch;
+	window.fetch = function (input, init) {
+		const method = (init && init.method) || (input && input.method) || 'GET';
+		const url = typeof input === 'string' ? input : (input && input.url) || String(input);
+		return fetch.apply(this, arguments).then((res) => {
+			res.clone().text().then((text) => store(keyOf(method, url), res.status, text)).catch(() => {});
+			return res;
+		});
+	};
+}`
+
+// xhrReadyJS 判断指定请求是否已捕获（参数：[key]）
+const xhrReadyJS = `([key]) => !!(window.__capturedXHRData__ && window.__capturedXHRData__[key])`
+
+// xhrResponseJS 读取捕获的响应数据（参数：[key]）
+const xhrResponseJS = `([key]) => window.__capturedXHRData__[key].response`
+
+// activeTabJS 判断标签页是否处于可见状态
+// snippets 生成代码中多次引用的页面 JS，定义为常量
+var snippets = []struct{ name, js string }{
+	{"extractList", extractListJS},
+	{"pageCondition", pageConditionJS},
+	{"urlMatches", urlMatchesJS},
+	{"isVisible", visibleJS},
+	{"countElements", countJS},
+	{"markElements", markElementsJS},
+	{"xhrReady", xhrReadyJS},
+	{"xhrResponse", xhrResponseJS},
+}
+
+const activeTabJS = `() => document.visibilityState === 'visible'`
+
+// keyCombo 按键组合（键名与 Playwright/Puppeteer 一致）
+type keyCombo struct {
+	Modifiers []string // Control, Shift, Alt, Meta
+	Key       string   // Enter, Tab, a, 1 ...
+}
+
+// String 返回 Playwright 格式的按键，例如 "Control+a"
+func (k keyCombo) String() string {
+	return strings.Join(append(append([]string{}, k.Modifiers...), k.Key), "+")
+}
+
+var namedKeys = map[string]string{
+	"enter": "Enter", "return": "Enter", "tab": "Tab", "backspace": "Backspace",
+	"escape": "Escape", "esc": "Escape", "delete": "Delete", "del": "Delete",
+	"space": "Space", "home": "Home", "end": "End", "pageup": "PageUp", "pagedown": "PageDown",
+	"arrowup": "ArrowUp", "up": "ArrowUp", "arrowdown": "ArrowDown", "down": "ArrowDown",
+	"arrowleft": "ArrowLeft", "left": "ArrowLeft", "arrowright": "ArrowRight", "right": "ArrowRight",
+}
+
+var modifierKeys = map[string]string{
+	"ctrl": "Control", "control": "Control", "shift": "Shift",
+	"alt": "Alt", "option": "Alt", "meta": "Meta", "cmd": "Meta", "command": "Meta",
+}
+
+// parseKey 解析录制的按键（如 "ctrl+c"、"enter"）
+func parseKey(key string) (keyCombo, bool) {
+	var k keyCombo
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(key)), "+")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if i < len(parts)-1 {
+			mod, ok := modifierKeys[part]
+			if !ok {
+				return k, false
+			}
+			k.Modifiers = append(k.Modifiers, mod)
+			continue
+		}
+		if named, ok := namedKeys[part]; ok {
+			k.Key = named
+		} else if len(part) == 1 && (part[0] >= 'a' && part[0] <= 'z' || part[0] >= '0' && part[0] <= '9') {
+			k.Key = part
+		}
+	}
+	return k, k.Key != ""
+}
+
+// filterPattern 匹配使用了过滤器的占位符（如 ${name|trim}）
+var filterPattern = regexp.MustCompile(`\$\{[^{}|]+\|[^{}]*\}`)
+
+// fileBaseName 根据脚本名称生成文件名（非 ASCII 名称使用默认名）
+func fileBaseName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ', r == '-', r == '_', r == '.':
+			b.WriteRune('_')
+		}
+	}
+	base := b.String()
+	for strings.Contains(base, "__") {
+		base = strings.ReplaceAll(base, "__", "_")
+	}
+	base = strings.Trim(base, "_")
+	if base == "" || base[0] >= '0' && base[0] <= '9' {
+		base = "script_" + base
+	}
+	return strings.TrimSuffix(base, "_")
+}
+
+// jsonString 生成 JSON 字符串字面量（不转义 HTML 字符，可直接作为 JS/Python 字符串字面量）
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSpace(buf.String())
+}
+
+// argList 拼接参数表达式
+func argList(args []string) string {
+	return strings.Join(args, ", ")
+}
@@ -0,0 +1,101 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func sampleScript() *models.Script {
+	return &models.Script{
+		Name:      "Search products",
+		URL:       "https://example.com",
+		Variables: map[string]string{"keyword": "laptop", "pages": "a,b"},
+		Actions: []models.ScriptAction{
+			{Type: "input", Selector: "#q", Value: "${keyword}"},
+			{Type: "keyboard", Key: "enter"},
+			{Type: "click", XPath: "//button[@id='go']", FramePath: []models.FrameHop{{Type: models.FrameHopIframe, Selector: "iframe#main"}}},
+			{Type: "capture_xhr", Method: "get", URL: "https://api.example.com/search", VariableName: "results"},
+			{Type: "extract_text", Selector: ".total", VariableName: "total"},
+			{Type: "extract_list", Selector: ".item", VariableName: "items", Fields: map[string]models.ExtractField{
+				"title": {Selector: ".title"},
+			}},
+			{Type: "if", Condition: &models.ActionCondition{Type: models.ConditionAnd, Conditions: []models.ActionCondition{
+				{Variable: "total", Operator: ">", Value: "0"},
+				{Type: models.ConditionElementVisible, Selector: ".next"},
+			}}, Actions: []models.ScriptAction{
+				{Type: "assert_text", Selector: ".total", Value: "results"},
+			}, ElseActions: []models.ScriptAction{
+				{Type: "ai_control", AIControlPrompt: "find another product"},
+			}},
+			{Type: "foreach", Selector: ".item", Actions: []models.ScriptAction{
+				{Type: "click", Selector: "${item_selector}"},
+			}},
+			{Type: "loop", LoopCount: 2, Actions: []models.ScriptAction{
+				{Type: "sleep", Duration: 100, Condition: &models.ActionCondition{Variable: "index", Operator: "=", Value: "1", Enabled: true}},
+			}},
+			{Type: "open_tab", URL: "https://example.com/help"},
+			{Type: "switch_tab", Value: "0"},
+			{Type: "screenshot", ScreenshotMode: "region", ScreenshotWidth: 100, ScreenshotHeight: 50},
+			{Type: "call_script", ScriptName: "login"},
+		},
+	}
+}
+
+func TestGenerateTODOs(t *testing.T) {
+	for _, target := range Targets {
+		t.Run(string(target), func(t *testing.T) {
+			result, err := Generate(sampleScript(), target)
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+			if len(result.TODOs) != 2 {
+				t.Fatalf("todos = %+v, want ai_control and call_script", result.TODOs)
+			}
+			if result.TODOs[0].Step != "7.else.1" || result.TODOs[0].Type != "ai_control" {
+				t.Errorf("unexpected todo: %+v", result.TODOs[0])
+			}
+			if n := strings.Count(result.Code, todoMarker+": step"); n != 2 {
+				t.Errorf("code has %d TODO markers, want 2", n)
+			}
+			for _, want := range []string{"GET|https://api.example.com/search", "__capturedXHRData__", "laptop", "iframe#main"} {
+				if !strings.Contains(result.Code, want) {
+					t.Errorf("code does not contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateRodParses(t *testing.T) {
+	result, err := Generate(sampleScript(), TargetRod)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if result.FileName != "search_products.go" {
+		t.Errorf("file name = %s", result.FileName)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), result.FileName, result.Code, 0); err != nil {
+		t.Fatalf("generated Go code does not parse: %v\n%s", err, result.Code)
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := map[string]Target{
+		"rod":               TargetRod,
+		"Playwright":        TargetPlaywrightTS,
+		"playwright-python": TargetPlaywrightPython,
+		"puppeteer":         TargetPuppeteer,
+	}
+	for input, want := range tests {
+		if got, err := ParseTarget(input); err != nil || got != want {
+			t.Errorf("ParseTarget(%q) = %s, %v, want %s", input, got, err, want)
+		}
+	}
+	if _, err := ParseTarget("selenium"); err == nil {
+		t.Error("expected error for unsupported target")
+	}
+}
@@ -0,0 +1,558 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// viewportNote Playwright 只能在创建 context 时设置设备像素比和移动设备模拟
+const viewportNote = "device scale factor and mobile emulation are set when creating the browser context"
+
+// playwrightTSDialect 生成 @playwright/test 测试用例（TypeScript）
+type playwrightTSDialect struct{ jsDialect }
+
+func (playwrightTSDialect) fileName(base string) string { return base + ".spec.ts" }
+func (playwrightTSDialect) bodyDepth() int              { return 1 }
+
+func (d playwrightTSDialect) prologue(g *generator) string {
+	var b strings.Builder
+	b.WriteString(g.header())
+	if g.uses["tabs"] {
+		b.WriteString("import { test, type BrowserContext, type Page } from '@playwright/test';\n\n")
+	} else {
+		b.WriteString("import { test } from '@playwright/test';\n\n")
+	}
+	b.WriteString("// Script variables, edit the defaults before running.\nconst variables: Record<string, string> = {\n")
+	for _, name := range g.variableNames() {
+		fmt.Fprintf(&b, "  %s: %s,\n", d.literal(name), d.literal(g.script.Variables[name]))
+	}
+	b.WriteString("};\n\n// Data extracted by the script.\nconst extracted: Record<string, unknown> = {};\n\n")
+	b.WriteString(g.snippetDefs())
+
+	if g.uses["tabs"] {
+		fmt.Fprintf(&b, "test(%s, async ({ page: firstPage, context }) => {\n  let page = firstPage;\n", d.literal(g.script.Name))
+	} else {
+		fmt.Fprintf(&b, "test(%s, async ({ page, context }) => {\n", d.literal(g.script.Name))
+	}
+	b.WriteString("  test.setTimeout(5 * 60 * 1000);\n")
+	if g.uses["xhr"] {
+		b.WriteString("  await context.addInitScript(" + indentLines(jsSource(xhrInterceptorJS), "  ") + ");\n")
+	}
+	if g.script.URL != "" {
+		b.WriteString("  " + d.navigate(g.str(g.script.URL)) + "\n")
+	}
+	if g.uses["tabs"] {
+		b.WriteString("  const tabs: Page[] = [page];\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (playwrightTSDialect) epilogue(g *generator) string {
+	code := "\n  console.log(JSON.stringify(extracted, null, 2));\n});\n" + tsHelpers
+	if g.uses["tabs"] {
+		code += `
+async function openTab(context: BrowserContext, url: string): Promise<Page> {
+  const page = await context.newPage();
+  await page.goto(url);
+  return page;
+}
+
+// Returns another visible tab, or current when there is none.
+async function activePage(context: BrowserContext, current: Page): Promise<Page> {
+  for (const p of context.pages()) {
+    if (p !== current && (await p.evaluate(` + activeTabJS + `))) return p;
+  }
+  return current;
+}
+`
+	}
+	return code
+}
+
+func (playwrightTSDialect) xpathPrefix() string { return "xpath=" }
+
+func (playwrightTSDialect) element(loc locator) string {
+	expr := "page"
+	for _, h := range loc.Hops {
+		if h.Type == models.FrameHopShadow {
+			expr += ".locator(" + h.Expr + ")" // Playwright 的 CSS 选择器可穿透 open shadow root
+		} else {
+			expr += ".frameLocator(" + h.Expr + ")"
+		}
+	}
+	return expr + ".locator(" + loc.Expr + ").first()"
+}
+
+func (playwrightTSDialect) text(el string) string { return "await " + el + ".innerText()" }
+func (playwrightTSDialect) html(el string) string {
+	return "await " + el + ".evaluate((e) => e.outerHTML)"
+}
+func (d playwrightTSDialect) attr(el, name string) string {
+	return "(await " + el + ".getAttribute(" + d.literal(name) + ")) ?? ''"
+}
+
+func (playwrightTSDialect) navigate(url string) string { return "await page.goto(" + url + ");" }
+func (playwrightTSDialect) fill(el, value string) string {
+	return "await " + el + ".fill(" + value + ");"
+}
+func (playwrightTSDialect) selectOption(el, label string) string {
+	return "await " + el + ".selectOption({ label: " + label + " });"
+}
+func (d playwrightTSDialect) press(k keyCombo) string {
+	return "await page.keyboard.press(" + d.literal(k.String()) + ");"
+}
+func (playwrightTSDialect) upload(el, files string) string {
+	return "await " + el + ".setInputFiles(" + files + ");"
+}
+func (playwrightTSDialect) sleep(ms int) string {
+	return fmt.Sprintf("await page.waitForTimeout(%d);", ms)
+}
+func (playwrightTSDialect) waitForFunction(fn string, args []string, timeoutMs int) string {
+	arg := "undefined"
+	if len(args) > 0 {
+		arg = "[" + argList(args) + "]"
+	}
+	return fmt.Sprintf("await page.waitForFunction(%s, %s, { timeout: %d });", fn, arg, timeoutMs)
+}
+func (playwrightTSDialect) waitNetworkIdle(idleMs int) string {
+	return "await page.waitForLoadState('networkidle');"
+}
+func (d playwrightTSDialect) setViewport(width, height int, scale float64, mobile bool) string {
+	code := fmt.Sprintf("await page.setViewportSize({ width: %d, height: %d });", width, height)
+	if scale != 1 || mobile {
+		code = d.comment(viewportNote) + "\n" + code
+	}
+	return code
+}
+func (d playwrightTSDialect) screenshot(path, mode string, clip [4]int) string {
+	switch mode {
+	case "fullpage":
+		return "await page.screenshot({ path: " + d.literal(path) + ", fullPage: true });"
+	case "region":
+		return fmt.Sprintf("await page.screenshot({ path: %s, clip: { x: %d, y: %d, width: %d, height: %d } });",
+			d.literal(path), clip[0], clip[1], clip[2], clip[3])
+	}
+	return "await page.screenshot({ path: " + d.literal(path) + " });"
+}
+func (playwrightTSDialect) openTab(url string) string {
+	return "page = await openTab(context, " + url + ");\ntabs.push(page);"
+}
+func (playwrightTSDialect) switchActiveTab() string { return "page = await activePage(context, page);" }
+
+// tsHelpers 生成代码使用的辅助函数（TypeScript）
+const tsHelpers = `
+function render(template: string): string {
+  return template.replace(/\$\{([^{}]+)\}/g, (placeholder: string, expr: string) => {
+    const name = expr.split('|')[0].trim();
+    return hasVar(name) ? getVar(name) : placeholder;
+  });
+}
+
+function getVar(name: string): string {
+  if (name in variables) return variables[name];
+  if (name in extracted) return stringify(extracted[name]);
+  return '';
+}
+
+function hasVar(name: string): boolean {
+  return name in variables || name in extracted;
+}
+
+function setVar(name: string, value: string): void {
+  variables[name] = value;
+  extracted[name] = value;
+}
+
+function setData(name: string, value: unknown): void {
+  extracted[name] = value;
+}
+
+function stringify(value: unknown): string {
+  return typeof value === 'string' ? value : JSON.stringify(value);
+}
+
+// Resolves a list variable: extracted arrays, JSON arrays, or newline/comma separated text.
+function listVar(name: string): string[] {
+  const value = extracted[name];
+  if (Array.isArray(value)) return value.map(stringify);
+  const text = getVar(name).trim();
+  if (text.startsWith('[')) {
+    try {
+      const list = JSON.parse(text);
+      if (Array.isArray(list)) return list.map(stringify);
+    } catch {
+      // not a JSON array
+    }
+  }
+  return text
+    .split(text.includes('\n') ? '\n' : ',')
+    .map((v) => v.trim())
+    .filter((v) => v !== '');
+}
+
+// Compares actual with expected using a BrowserWing operator.
+function check(actual: string, op: string, expected: string): boolean {
+  switch (op) {
+    case '':
+    case '=':
+    case '==':
+    case 'equals':
+      return actual === expected;
+    case '!=':
+    case 'not_equals':
+      return actual !== expected;
+    case 'contains':
+      return actual.includes(expected);
+    case 'not_contains':
+      return !actual.includes(expected);
+    case 'starts_with':
+      return actual.startsWith(expected);
+    case 'ends_with':
+      return actual.endsWith(expected);
+    case 'matches':
+      return new RegExp(expected).test(actual);
+    case 'in':
+    case 'not_in':
+      return expected.split(',').map((v) => v.trim()).includes(actual) === (op === 'in');
+    case '>':
+    case '<':
+    case '>=':
+    case '<=': {
+      const a = parseFloat(actual);
+      const b = parseFloat(expected);
+      if (isNaN(a) || isNaN(b)) return false;
+      return op === '>' ? a > b : op === '<' ? a < b : op === '>=' ? a >= b : a <= b;
+    }
+  }
+  throw new Error(` + "`unsupported operator: ${op}`" + `);
+}
+
+function assertThat(label: string, actual: string, op: string, expected: string): void {
+  if (!check(actual, op, expected)) {
+    throw new Error(` + "`assertion failed: expected ${label} ${op} ${JSON.stringify(expected)}, got ${JSON.stringify(actual)}`" + `);
+  }
+}
+`
+
+// playwrightPythonDialect 生成 pytest-playwright 测试用例（Python，同步 API）
+type playwrightPythonDialect struct{}
+
+func (playwrightPythonDialect) fileName(base string) string { return "test_" + base + ".py" }
+func (playwrightPythonDialect) indentUnit() string          { return "    " }
+func (playwrightPythonDialect) bodyDepth() int              { return 1 }
+func (playwrightPythonDialect) comment(text string) string  { return "# " + text }
+
+func (d playwrightPythonDialect) prologue(g *generator) string {
+	var b strings.Builder
+	b.WriteString(g.header())
+	b.WriteString("import json\nimport re\n\n")
+	if g.uses["tabs"] {
+		b.WriteString("from playwright.sync_api import BrowserContext, Page\n\n")
+	} else {
+		b.WriteString("from playwright.sync_api import Page\n\n")
+	}
+	b.WriteString("# Script variables, edit the defaults before running.\nvariables: dict = {\n")
+	for _, name := range g.variableNames() {
+		fmt.Fprintf(&b, "    %s: %s,\n", d.literal(name), d.literal(g.script.Variables[name]))
+	}
+	b.WriteString("}\n\n# Data extracted by the script.\nextracted: dict = {}\n\n")
+	b.WriteString(g.snippetDefs())
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "def test_%s(page: Page) -> None:\n", fileBaseName(g.script.Name))
+	if g.uses["xhr"] || g.uses["tabs"] {
+		b.WriteString("    context = page.context\n")
+	}
+	if g.uses["xhr"] {
+		b.WriteString("    context.add_init_script(script=" + d.function("("+xhrInterceptorJS+")()") + ")\n")
+	}
+	if g.script.URL != "" {
+		b.WriteString("    " + d.navigate(g.str(g.script.URL)) + "\n")
+	}
+	if g.uses["tabs"] {
+		b.WriteString("    tabs = [page]\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (playwrightPythonDialect) epilogue(g *generator) string {
+	code := "\n    print(json.dumps(extracted, indent=2, ensure_ascii=False))\n" + pythonHelpers
+	if g.uses["tabs"] {
+		code += `
+
+def open_tab(context: BrowserContext, url: str) -> Page:
+    page = context.new_page()
+    page.goto(url)
+    return page
+
+
+def active_page(context: BrowserContext, current: Page) -> Page:
+    """Return another visible tab, or current when there is none."""
+    for p in context.pages:
+        if p != current and p.evaluate("` + activeTabJS + `"):
+            return p
+    return current
+`
+	}
+	return code
+}
+
+func (playwrightPythonDialect) literal(s string) string { return jsonString(s) }
+
+// function 页面内 JS 优先使用原始三引号字符串
+func (playwrightPythonDialect) function(js string) string {
+	js = jsSource(js)
+	if strings.Contains(js, `"""`) || strings.HasSuffix(js, `"`) || strings.HasSuffix(js, `\`) {
+		return jsonString(js)
+	}
+	if !strings.Contains(js, "\n") && !strings.Contains(js, `\`) {
+		return jsonString(js)
+	}
+	return `r"""` + js + `"""`
+}
+
+func (d playwrightPythonDialect) render(s string) string      { return "render(" + d.literal(s) + ")" }
+func (playwrightPythonDialect) strList(exprs []string) string { return "[" + argList(exprs) + "]" }
+func (playwrightPythonDialect) concat(a, b string) string     { return a + " + " + b }
+func (playwrightPythonDialect) xpathPrefix() string           { return "xpath=" }
+
+func (playwrightPythonDialect) element(loc locator) string {
+	expr := "page"
+	for _, h := range loc.Hops {
+		if h.Type == models.FrameHopShadow {
+			expr += ".locator(" + h.Expr + ")"
+		} else {
+			expr += ".frame_locator(" + h.Expr + ")"
+		}
+	}
+	return expr + ".locator(" + loc.Expr + ").first"
+}
+
+// snippetName 转换为大写下划线常量名，如 extractList -> EXTRACT_LIST_JS
+func (playwrightPythonDialect) snippetName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String()) + "_JS"
+}
+
+func (d playwrightPythonDialect) snippetDef(name, js string) string {
+	return d.snippetName(name) + " = " + d.function(js) + "\n"
+}
+
+func (playwrightPythonDialect) eval(fn string, args []string) string {
+	if len(args) == 0 {
+		return "page.evaluate(" + fn + ")"
+	}
+	return "page.evaluate(" + fn + ", [" + argList(args) + "])"
+}
+
+func (d playwrightPythonDialect) evalStr(fn string, args []string) string  { return d.eval(fn, args) }
+func (d playwrightPythonDialect) evalBool(fn string, args []string) string { return d.eval(fn, args) }
+func (d playwrightPythonDialect) evalList(fn string, args []string) string { return d.eval(fn, args) }
+
+func (playwrightPythonDialect) text(el string) string { return el + ".inner_text()" }
+func (playwrightPythonDialect) html(el string) string {
+	return el + `.evaluate("(e) => e.outerHTML")`
+}
+func (d playwrightPythonDialect) attr(el, name string) string {
+	return "(" + el + ".get_attribute(" + d.literal(name) + `) or "")`
+}
+func (d playwrightPythonDialect) varGet(name string) string {
+	return "get_var(" + d.literal(name) + ")"
+}
+func (d playwrightPythonDialect) hasVar(name string) string {
+	return "has_var(" + d.literal(name) + ")"
+}
+func (d playwrightPythonDialect) listVar(name string) string {
+	return "list_var(" + d.literal(name) + ")"
+}
+func (d playwrightPythonDialect) check(actual, op, expected string) string {
+	return "check(" + actual + ", " + d.literal(op) + ", " + expected + ")"
+}
+func (playwrightPythonDialect) and(exprs []string) string {
+	return "(" + strings.Join(exprs, " and ") + ")"
+}
+func (playwrightPythonDialect) or(exprs []string) string {
+	return "(" + strings.Join(exprs, " or ") + ")"
+}
+func (playwrightPythonDialect) not(expr string) string { return "not " + expr }
+func (playwrightPythonDialect) boolLiteral(b bool) string {
+	if b {
+		return "True"
+	}
+	return "False"
+}
+func (playwrightPythonDialect) itoa(expr string) string { return "str(" + expr + ")" }
+
+func (playwrightPythonDialect) navigate(url string) string   { return "page.goto(" + url + ")" }
+func (playwrightPythonDialect) click(el string) string       { return el + ".click()" }
+func (playwrightPythonDialect) fill(el, value string) string { return el + ".fill(" + value + ")" }
+func (playwrightPythonDialect) selectOption(el, label string) string {
+	return el + ".select_option(label=" + label + ")"
+}
+func (playwrightPythonDialect) focus(el string) string { return el + ".focus()" }
+func (d playwrightPythonDialect) press(k keyCombo) string {
+	return "page.keyboard.press(" + d.literal(k.String()) + ")"
+}
+func (playwrightPythonDialect) upload(el, files string) string {
+	return el + ".set_input_files(" + files + ")"
+}
+func (playwrightPythonDialect) sleep(ms int) string {
+	return fmt.Sprintf("page.wait_for_timeout(%d)", ms)
+}
+func (playwrightPythonDialect) waitForFunction(fn string, args []string, timeoutMs int) string {
+	if len(args) == 0 {
+		return fmt.Sprintf("page.wait_for_function(%s, timeout=%d)", fn, timeoutMs)
+	}
+	return fmt.Sprintf("page.wait_for_function(%s, arg=[%s], timeout=%d)", fn, argList(args), timeoutMs)
+}
+func (playwrightPythonDialect) waitNetworkIdle(idleMs int) string {
+	return `page.wait_for_load_state("networkidle")`
+}
+func (d playwrightPythonDialect) setViewport(width, height int, scale float64, mobile bool) string {
+	code := fmt.Sprintf(`page.set_viewport_size({"width": %d, "height": %d})`, width, height)
+	if scale != 1 || mobile {
+		code = d.comment(viewportNote) + "\n" + code
+	}
+	return code
+}
+func (d playwrightPythonDialect) evalStmt(fn string, args []string) string { return d.eval(fn, args) }
+func (d playwrightPythonDialect) setVar(name, value string) string {
+	return "set_var(" + d.literal(name) + ", " + value + ")"
+}
+func (d playwrightPythonDialect) setData(name, value string) string {
+	return "set_data(" + d.literal(name) + ", " + value + ")"
+}
+func (d playwrightPythonDialect) setLocal(name, value string) string {
+	return "variables[" + d.literal(name) + "] = " + value
+}
+func (d playwrightPythonDialect) screenshot(path, mode string, clip [4]int) string {
+	switch mode {
+	case "fullpage":
+		return "page.screenshot(path=" + d.literal(path) + ", full_page=True)"
+	case "region":
+		return fmt.Sprintf(`page.screenshot(path=%s, clip={"x": %d, "y": %d, "width": %d, "height": %d})`,
+			d.literal(path), clip[0], clip[1], clip[2], clip[3])
+	}
+	return "page.screenshot(path=" + d.literal(path) + ")"
+}
+func (playwrightPythonDialect) openTab(url string) string {
+	return "page = open_tab(context, " + url + ")\ntabs.append(page)"
+}
+func (playwrightPythonDialect) switchTab(index int) string {
+	return fmt.Sprintf("page = tabs[%d]\npage.bring_to_front()", index)
+}
+func (playwrightPythonDialect) switchActiveTab() string { return "page = active_page(context, page)" }
+func (d playwrightPythonDialect) assert(label, actual, op, expected string) string {
+	return "assert_that(" + d.literal(label) + ", " + actual + ", " + d.literal(op) + ", " + expected + ")"
+}
+
+func (playwrightPythonDialect) ifOpen(cond string) string { return "if " + cond + ":" }
+func (playwrightPythonDialect) elseLine() string          { return "else:" }
+func (playwrightPythonDialect) blockClose() string        { return "" }
+func (playwrightPythonDialect) emptyBlock() string        { return "pass" }
+func (playwrightPythonDialect) forRange(index string, limit int) string {
+	return fmt.Sprintf("for %s in range(%d):", index, limit)
+}
+func (playwrightPythonDialect) forEach(index, item, list string) string {
+	return fmt.Sprintf("for %s, %s in enumerate(%s):", index, item, list)
+}
+func (playwrightPythonDialect) breakStmt() string { return "break" }
+
+// pythonHelpers 生成代码使用的辅助函数（Python）
+const pythonHelpers = `
+
+def render(template: str) -> str:
+    def replace(match: re.Match) -> str:
+        name = match.group(1).split("|")[0].strip()
+        return get_var(name) if has_var(name) else match.group(0)
+
+    return re.sub(r"\$\{([^{}]+)\}", replace, template)
+
+
+def get_var(name: str) -> str:
+    if name in variables:
+        return variables[name]
+    if name in extracted:
+        return stringify(extracted[name])
+    return ""
+
+
+def has_var(name: str) -> bool:
+    return name in variables or name in extracted
+
+
+def set_var(name: str, value: str) -> None:
+    variables[name] = value
+    extracted[name] = value
+
+
+def set_data(name: str, value) -> None:
+    extracted[name] = value
+
+
+def stringify(value) -> str:
+    return value if isinstance(value, str) else json.dumps(value, ensure_ascii=False)
+
+
+def list_var(name: str) -> list:
+    """Resolve a list variable: extracted lists, JSON arrays, or newline/comma separated text."""
+    value = extracted.get(name)
+    if isinstance(value, list):
+        return [stringify(v) for v in value]
+    text = get_var(name).strip()
+    if text.startswith("["):
+        try:
+            items = json.loads(text)
+            if isinstance(items, list):
+                return [stringify(v) for v in items]
+        except ValueError:
+            pass
+    sep = "\n" if "\n" in text else ","
+    return [v.strip() for v in text.split(sep) if v.strip()]
+
+
+def check(actual: str, op: str, expected: str) -> bool:
+    """Compare actual with expected using a BrowserWing operator."""
+    if op in ("", "=", "==", "equals"):
+        return actual == expected
+    if op in ("!=", "not_equals"):
+        return actual != expected
+    if op == "contains":
+        return expected in actual
+    if op == "not_contains":
+        return expected not in actual
+    if op == "starts_with":
+        return actual.startswith(expected)
+    if op == "ends_with":
+        return actual.endswith(expected)
+    if op == "matches":
+        return re.search(expected, actual) is not None
+    if op in ("in", "not_in"):
+        return (actual in [v.strip() for v in expected.split(",")]) == (op == "in")
+    if op in (">", "<", ">=", "<="):
+        try:
+            a, b = float(actual), float(expected)
+        except ValueError:
+            return False
+        return {">": a > b, "<": a < b, ">=": a >= b, "<=": a <= b}[op]
+    raise ValueError(f"unsupported operator: {op}")
+
+
+def assert_that(label: str, actual: str, op: str, expected: str) -> None:
+    assert check(actual, op, expected), f"expected {label} {op} {expected!r}, got {actual!r}"
+`
+
+// indentLines 为多行代码的后续行增加缩进
+func indentLines(code, indent string) string {
+	return strings.ReplaceAll(code, "\n", "\n"+indent)
+}
@@ -0,0 +1,357 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// jsDialect Playwright（TypeScript）和 Puppeteer 共用的 JavaScript 代码片段
+type jsDialect struct{}
+
+func (jsDialect) indentUnit() string         { return "  " }
+func (jsDialect) comment(text string) string { return "// " + text }
+func (jsDialect) literal(s string) string    { return jsonString(s) }
+
+func (d jsDialect) render(s string) string      { return "render(" + d.literal(s) + ")" }
+func (jsDialect) strList(exprs []string) string { return "[" + argList(exprs) + "]" }
+func (jsDialect) concat(a, b string) string     { return a + " + " + b }
+
+func (jsDialect) function(js string) string      { return jsSource(js) }
+func (jsDialect) snippetName(name string) string { return name }
+func (jsDialect) snippetDef(name, js string) string {
+	return "const " + name + " = " + jsSource(js) + ";\n"
+}
+
+func (jsDialect) eval(fn string, args []string) string {
+	if len(args) == 0 {
+		return "await page.evaluate(" + fn + ")"
+	}
+	return "await page.evaluate(" + fn + ", [" + argList(args) + "])"
+}
+
+func (d jsDialect) evalStr(fn string, args []string) string  { return d.eval(fn, args) }
+func (d jsDialect) evalBool(fn string, args []string) string { return d.eval(fn, args) }
+func (d jsDialect) evalList(fn string, args []string) string { return d.eval(fn, args) }
+
+func (d jsDialect) varGet(name string) string  { return "getVar(" + d.literal(name) + ")" }
+func (d jsDialect) hasVar(name string) string  { return "hasVar(" + d.literal(name) + ")" }
+func (d jsDialect) listVar(name string) string { return "listVar(" + d.literal(name) + ")" }
+func (d jsDialect) check(actual, op, expected string) string {
+	return "check(" + actual + ", " + d.literal(op) + ", " + expected + ")"
+}
+func (jsDialect) and(exprs []string) string { return "(" + strings.Join(exprs, " && ") + ")" }
+func (jsDialect) or(exprs []string) string  { return "(" + strings.Join(exprs, " || ") + ")" }
+func (jsDialect) not(expr string) string    { return "!" + expr }
+func (jsDialect) boolLiteral(b bool) string { return strconv.FormatBool(b) }
+func (jsDialect) itoa(expr string) string   { return "String(" + expr + ")" }
+
+func (jsDialect) click(el string) string                     { return "await " + el + ".click();" }
+func (jsDialect) focus(el string) string                     { return "await " + el + ".focus();" }
+func (d jsDialect) evalStmt(fn string, args []string) string { return d.eval(fn, args) + ";" }
+func (d jsDialect) setVar(name, value string) string {
+	return "setVar(" + d.literal(name) + ", " + value + ");"
+}
+func (d jsDialect) setData(name, value string) string {
+	return "setData(" + d.literal(name) + ", " + value + ");"
+}
+func (d jsDialect) setLocal(name, value string) string {
+	return "variables[" + d.literal(name) + "] = " + value + ";"
+}
+func (jsDialect) switchTab(index int) string {
+	return fmt.Sprintf("page = tabs[%d];\nawait page.bringToFront();", index)
+}
+func (d jsDialect) assert(label, actual, op, expected string) string {
+	return "assertThat(" + d.literal(label) + ", " + actual + ", " + d.literal(op) + ", " + expected + ");"
+}
+
+func (jsDialect) ifOpen(cond string) string { return "if (" + cond + ") {" }
+func (jsDialect) elseLine() string          { return "} else {" }
+func (jsDialect) blockClose() string        { return "}" }
+func (jsDialect) emptyBlock() string        { return "" }
+func (jsDialect) forRange(index string, limit int) string {
+	return fmt.Sprintf("for (let %s = 0; %s < %d; %s++) {", index, index, limit, index)
+}
+func (jsDialect) forEach(index, item, list string) string {
+	return fmt.Sprintf("for (const [%s, %s] of (%s).entries()) {", index, item, list)
+}
+func (jsDialect) breakStmt() string { return "break;" }
+
+// jsSource 将页面内 JS 的制表符缩进转换为空格
+func jsSource(js string) string {
+	return strings.ReplaceAll(js, "\t", "  ")
+}
+
+// puppeteerDialect 生成 Node.js 脚本（Puppeteer）
+type puppeteerDialect struct{ jsDialect }
+
+func (puppeteerDialect) fileName(base string) string { return base + ".js" }
+func (puppeteerDialect) bodyDepth() int              { return 2 }
+
+func (d puppeteerDialect) prologue(g *generator) string {
+	var b strings.Builder
+	b.WriteString(g.header())
+	b.WriteString("const puppeteer = require('puppeteer');\n\n")
+	b.WriteString("// Script variables, edit the defaults before running.\nconst variables = {\n")
+	for _, name := range g.variableNames() {
+		fmt.Fprintf(&b, "  %s: %s,\n", d.literal(name), d.literal(g.script.Variables[name]))
+	}
+	b.WriteString("};\n\n// Data extracted by the script.\nconst extracted = {};\n\n")
+	b.WriteString(g.snippetDefs())
+
+	b.WriteString("(async () => {\n  const browser = await puppeteer.launch({ headless: false });\n")
+	if g.uses["tabs"] {
+		b.WriteString("  let page = await browser.newPage();\n")
+	} else {
+		b.WriteString("  const page = await browser.newPage();\n")
+	}
+	if g.uses["xhr"] {
+		b.WriteString("  await page.evaluateOnNewDocument(" + indentLines(jsSource(xhrInterceptorJS), "  ") + ");\n")
+	}
+	b.WriteString("  try {\n")
+	if g.script.URL != "" {
+		b.WriteString("    " + d.navigate(g.str(g.script.URL)) + "\n")
+	}
+	if g.uses["tabs"] {
+		b.WriteString("    const tabs = [page];\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (puppeteerDialect) epilogue(g *generator) string {
+	code := `
+    console.log(JSON.stringify(extracted, null, 2));
+  } finally {
+    await browser.close();
+  }
+})().catch((err) => {
+  console.error(err);
+  process.exit(1);
+});
+` + jsHelpers
+	if g.uses["tabs"] {
+		code += `
+async function openTab(browser, url) {
+  const page = await browser.newPage();
+`
+		if g.uses["xhr"] {
+			code += "  await page.evaluateOnNewDocument(" + indentLines(jsSource(xhrInterceptorJS), "  ") + ");\n"
+		}
+		code += `  await page.goto(url, { waitUntil: 'load' });
+  return page;
+}
+
+// Returns another visible tab, or current when there is none.
+async function activePage(browser, current) {
+  for (const p of await browser.pages()) {
+    if (p !== current && (await p.evaluate(` + activeTabJS + `))) return p;
+  }
+  return current;
+}
+`
+	}
+	return code
+}
+
+func (puppeteerDialect) xpathPrefix() string { return "xpath/" }
+
+// element 依次进入 iframe（contentFrame）和 shadow root（>>> 深度选择器）后查找元素
+func (d puppeteerDialect) element(loc locator) string {
+	scope := "page"
+	var shadow []string
+	selector := func(expr string) string {
+		for i := len(shadow) - 1; i >= 0; i-- {
+			expr = d.concat(d.concat(shadow[i], d.literal(" >>> ")), expr)
+		}
+		return expr
+	}
+	for _, h := range loc.Hops {
+		if h.Type == models.FrameHopShadow {
+			shadow = append(shadow, h.Expr)
+			continue
+		}
+		scope = "(await (await " + scope + ".waitForSelector(" + selector(h.Expr) + ")).contentFrame())"
+		shadow = nil
+	}
+	return "(await " + scope + ".waitForSelector(" + selector(loc.Expr) + "))"
+}
+
+func (puppeteerDialect) text(el string) string {
+	return "await " + el + ".evaluate((e) => e.innerText)"
+}
+func (puppeteerDialect) html(el string) string {
+	return "await " + el + ".evaluate((e) => e.outerHTML)"
+}
+func (d puppeteerDialect) attr(el, name string) string {
+	return "await " + el + ".evaluate((e, name) => e.getAttribute(name) ?? '', " + d.literal(name) + ")"
+}
+
+func (puppeteerDialect) navigate(url string) string {
+	return "await page.goto(" + url + ", { waitUntil: 'load' });"
+}
+func (puppeteerDialect) fill(el, value string) string {
+	return "await fill(" + el + ", " + value + ");"
+}
+func (puppeteerDialect) selectOption(el, label string) string {
+	return "await selectByLabel(" + el + ", " + label + ");"
+}
+func (d puppeteerDialect) press(k keyCombo) string {
+	var lines []string
+	for _, m := range k.Modifiers {
+		lines = append(lines, "await page.keyboard.down("+d.literal(m)+");")
+	}
+	lines = append(lines, "await page.keyboard.press("+d.literal(k.Key)+");")
+	for i := len(k.Modifiers) - 1; i >= 0; i-- {
+		lines = append(lines, "await page.keyboard.up("+d.literal(k.Modifiers[i])+");")
+	}
+	return strings.Join(lines, "\n")
+}
+func (puppeteerDialect) upload(el, files string) string {
+	return "await " + el + ".uploadFile(..." + files + ");"
+}
+func (puppeteerDialect) sleep(ms int) string { return fmt.Sprintf("await sleep(%d);", ms) }
+func (puppeteerDialect) waitForFunction(fn string, args []string, timeoutMs int) string {
+	if len(args) == 0 {
+		return fmt.Sprintf("await page.waitForFunction(%s, { timeout: %d });", fn, timeoutMs)
+	}
+	return fmt.Sprintf("await page.waitForFunction(%s, { timeout: %d }, [%s]);", fn, timeoutMs, argList(args))
+}
+func (puppeteerDialect) waitNetworkIdle(idleMs int) string {
+	return fmt.Sprintf("await page.waitForNetworkIdle({ idleTime: %d });", idleMs)
+}
+func (puppeteerDialect) setViewport(width, height int, scale float64, mobile bool) string {
+	return fmt.Sprintf("await page.setViewport({ width: %d, height: %d, deviceScaleFactor: %s, isMobile: %t });",
+		width, height, strconv.FormatFloat(scale, 'f', -1, 64), mobile)
+}
+func (d puppeteerDialect) screenshot(path, mode string, clip [4]int) string {
+	switch mode {
+	case "fullpage":
+		return "await page.screenshot({ path: " + d.literal(path) + ", fullPage: true });"
+	case "region":
+		return fmt.Sprintf("await page.screenshot({ path: %s, clip: { x: %d, y: %d, width: %d, height: %d } });",
+			d.literal(path), clip[0], clip[1], clip[2], clip[3])
+	}
+	return "await page.screenshot({ path: " + d.literal(path) + " });"
+}
+func (puppeteerDialect) openTab(url string) string {
+	return "page = await openTab(browser, " + url + ");\ntabs.push(page);"
+}
+func (puppeteerDialect) switchActiveTab() string { return "page = await activePage(browser, page);" }
+
+// jsHelpers 生成代码使用的辅助函数（JavaScript）
+const jsHelpers = `
+function sleep(ms) {
+  return new Promise((resolve) => setTimeout(resolve, ms));
+}
+
+async function fill(el, value) {
+  await el.evaluate((e) => {
+    e.value = '';
+  });
+  await el.type(value);
+}
+
+async function selectByLabel(el, label) {
+  await el.evaluate((e, text) => {
+    const option = Array.from(e.options).find((o) => o.text.trim() === text);
+    if (!option) throw new Error(` + "`option not found: ${text}`" + `);
+    e.value = option.value;
+    e.dispatchEvent(new Event('change', { bubbles: true }));
+  }, label);
+}
+
+function render(template) {
+  return template.replace(/\$\{([^{}]+)\}/g, (placeholder, expr) => {
+    const name = expr.split('|')[0].trim();
+    return hasVar(name) ? getVar(name) : placeholder;
+  });
+}
+
+function getVar(name) {
+  if (name in variables) return variables[name];
+  if (name in extracted) return stringify(extracted[name]);
+  return '';
+}
+
+function hasVar(name) {
+  return name in variables || name in extracted;
+}
+
+function setVar(name, value) {
+  variables[name] = value;
+  extracted[name] = value;
+}
+
+function setData(name, value) {
+  extracted[name] = value;
+}
+
+function stringify(value) {
+  return typeof value === 'string' ? value : JSON.stringify(value);
+}
+
+// Resolves a list variable: extracted arrays, JSON arrays, or newline/comma separated text.
+function listVar(name) {
+  const value = extracted[name];
+  if (Array.isArray(value)) return value.map(stringify);
+  const text = getVar(name).trim();
+  if (text.startsWith('[')) {
+    try {
+      const list = JSON.parse(text);
+      if (Array.isArray(list)) return list.map(stringify);
+    } catch {
+      // not a JSON array
+    }
+  }
+  return text
+    .split(text.includes('\n') ? '\n' : ',')
+    .map((v) => v.trim())
+    .filter((v) => v !== '');
+}
+
+// Compares actual with expected using a BrowserWing operator.
+function check(actual, op, expected) {
+  switch (op) {
+    case '':
+    case '=':
+    case '==':
+    case 'equals':
+      return actual === expected;
+    case '!=':
+    case 'not_equals':
+      return actual !== expected;
+    case 'contains':
+      return actual.includes(expected);
+    case 'not_contains':
+      return !actual.includes(expected);
+    case 'starts_with':
+      return actual.startsWith(expected);
+    case 'ends_with':
+      return actual.endsWith(expected);
+    case 'matches':
+      return new RegExp(expected).test(actual);
+    case 'in':
+    case 'not_in':
+      return expected.split(',').map((v) => v.trim()).includes(actual) === (op === 'in');
+    case '>':
+    case '<':
+    case '>=':
+    case '<=': {
+      const a = parseFloat(actual);
+      const b = parseFloat(expected);
+      if (isNaN(a) || isNaN(b)) return false;
+      return op === '>' ? a > b : op === '<' ? a < b : op === '>=' ? a >= b : a <= b;
+    }
+  }
+  throw new Error(` + "`unsupported operator: ${op}`" + `);
+}
+
+function assertThat(label, actual, op, expected) {
+  if (!check(actual, op, expected)) {
+    throw new Error(` + "`assertion failed: expected ${label} ${op} ${JSON.stringify(expected)}, got ${JSON.stringify(actual)}`" + `);
+  }
+}
+`
@@ -0,0 +1,427 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// rodDialect 生成 Go 程序（go-rod）
+type rodDialect struct{}
+
+func (rodDialect) fileName(base string) string { return base + ".go" }
+func (rodDialect) indentUnit() string          { return "\t" }
+func (rodDialect) bodyDepth() int              { return 1 }
+func (rodDialect) comment(text string) string  { return "// " + text }
+
+func (d rodDialect) prologue(g *generator) string {
+	var b strings.Builder
+	b.WriteString(g.header())
+	b.WriteString("package main\n\nimport (\n")
+	imports := []string{"encoding/json", "fmt", "log"}
+	if g.uses["region"] {
+		imports = append(imports, "os")
+	}
+	imports = append(imports, "regexp", "strconv", "strings", "time")
+	for _, imp := range imports {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	b.WriteString("\n\t\"github.com/go-rod/rod\"\n")
+	if g.uses["keyboard"] {
+		b.WriteString("\t\"github.com/go-rod/rod/lib/input\"\n")
+	}
+	if g.uses["region"] {
+		b.WriteString("\t\"github.com/go-rod/rod/lib/proto\"\n")
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// variables holds the script variables, edit the defaults before running.\nvar variables = map[string]string{\n")
+	for _, name := range g.variableNames() {
+		fmt.Fprintf(&b, "\t%s: %s,\n", d.literal(name), d.literal(g.script.Variables[name]))
+	}
+	b.WriteString("}\n\n// extracted holds the data extracted by the script.\nvar extracted = map[string]interface{}{}\n\n")
+	b.WriteString(g.snippetDefs())
+
+	b.WriteString("func main() {\n\tbrowser := rod.New().MustConnect()\n\tdefer browser.MustClose()\n\n\tpage := browser.MustPage()\n")
+	if g.uses["xhr"] {
+		b.WriteString("\tpage.MustEvalOnNewDocument(" + d.function("("+xhrInterceptorJS+")()") + ")\n")
+	}
+	if g.script.URL != "" {
+		b.WriteString("\t" + d.navigate(g.str(g.script.URL)) + "\n")
+	}
+	if g.uses["tabs"] {
+		b.WriteString("\ttabs := []*rod.Page{page}\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (d rodDialect) epilogue(g *generator) string {
+	var b strings.Builder
+	b.WriteString("\n\tout, _ := json.MarshalIndent(extracted, \"\", \"  \")\n\tfmt.Println(string(out))\n}\n")
+	b.WriteString(rodHelpers)
+	if g.uses["tabs"] {
+		b.WriteString(`
+// openTab opens url in a new tab.
+func openTab(browser *rod.Browser, url string) *rod.Page {
+	page := browser.MustPage()
+`)
+		if g.uses["xhr"] {
+			b.WriteString("\tpage.MustEvalOnNewDocument(" + d.function("("+xhrInterceptorJS+")()") + ")\n")
+		}
+		b.WriteString(`	return page.MustNavigate(url).MustWaitLoad()
+}
+
+// activePage returns another visible tab, or current when there is none.
+func activePage(browser *rod.Browser, current *rod.Page) *rod.Page {
+	for _, p := range browser.MustPages() {
+		if p.TargetID != current.TargetID && p.MustEval(` + d.function(activeTabJS) + `).Bool() {
+			return p
+		}
+	}
+	return current
+}
+`)
+	}
+	if g.uses["region"] {
+		b.WriteString(`
+// screenshotRegion saves a screenshot of the given viewport region.
+func screenshotRegion(page *rod.Page, path string, x, y, width, height float64) {
+	img, err := page.Screenshot(false, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+		Clip:   &proto.PageViewport{X: x, Y: y, Width: width, Height: height, Scale: 1},
+	})
+	if err != nil {
+		log.Fatalf("screenshot failed: %v", err)
+	}
+	if err := os.WriteFile(path, img, 0o644); err != nil {
+		log.Fatalf("save screenshot failed: %v", err)
+	}
+}
+`)
+	}
+	return b.String()
+}
+
+func (rodDialect) literal(s string) string { return strconv.Quote(s) }
+
+// function 页面内 JS 优先使用原始字符串
+func (rodDialect) function(js string) string {
+	if strings.Contains(js, "`") {
+		return strconv.Quote(js)
+	}
+	return "`" + js + "`"
+}
+
+func (d rodDialect) render(s string) string      { return "render(" + d.literal(s) + ")" }
+func (rodDialect) strList(exprs []string) string { return "[]string{" + argList(exprs) + "}" }
+func (rodDialect) concat(a, b string) string     { return a + " + " + b }
+func (rodDialect) xpathPrefix() string           { return "" }
+
+func (rodDialect) element(loc locator) string {
+	expr := "page"
+	for _, h := range loc.Hops {
+		if h.Type == models.FrameHopShadow {
+			expr += ".MustElement(" + h.Expr + ").MustShadowRoot()"
+		} else {
+			expr += ".MustElement(" + h.Expr + ").MustFrame()"
+		}
+	}
+	if loc.XPath {
+		return expr + ".MustElementX(" + loc.Expr + ")"
+	}
+	return expr + ".MustElement(" + loc.Expr + ")"
+}
+
+func (rodDialect) snippetName(name string) string { return name + "JS" }
+func (d rodDialect) snippetDef(name, js string) string {
+	return "const " + d.snippetName(name) + " = " + d.function(js) + "\n"
+}
+
+func (rodDialect) eval(fn string, args []string) string {
+	if len(args) == 0 {
+		return "page.MustEval(" + fn + ")"
+	}
+	return "page.MustEval(" + fn + ", []interface{}{" + argList(args) + "})"
+}
+
+func (d rodDialect) evalStr(fn string, args []string) string  { return d.eval(fn, args) + ".Str()" }
+func (d rodDialect) evalBool(fn string, args []string) string { return d.eval(fn, args) + ".Bool()" }
+func (d rodDialect) evalList(fn string, args []string) string {
+	return "listOf(" + d.eval(fn, args) + ")"
+}
+
+func (rodDialect) text(el string) string         { return el + ".MustText()" }
+func (rodDialect) html(el string) string         { return el + ".MustHTML()" }
+func (d rodDialect) attr(el, name string) string { return "attr(" + el + ", " + d.literal(name) + ")" }
+func (d rodDialect) varGet(name string) string   { return "getVar(" + d.literal(name) + ")" }
+func (d rodDialect) hasVar(name string) string   { return "hasVar(" + d.literal(name) + ")" }
+func (d rodDialect) listVar(name string) string  { return "listVar(" + d.literal(name) + ")" }
+func (d rodDialect) check(actual, op, expected string) string {
+	return "check(" + actual + ", " + d.literal(op) + ", " + expected + ")"
+}
+func (rodDialect) and(exprs []string) string { return "(" + strings.Join(exprs, " && ") + ")" }
+func (rodDialect) or(exprs []string) string  { return "(" + strings.Join(exprs, " || ") + ")" }
+func (rodDialect) not(expr string) string    { return "!" + expr }
+func (rodDialect) boolLiteral(b bool) string { return strconv.FormatBool(b) }
+func (rodDialect) itoa(expr string) string   { return "strconv.Itoa(" + expr + ")" }
+
+func (rodDialect) navigate(url string) string { return "page.MustNavigate(" + url + ").MustWaitLoad()" }
+func (rodDialect) click(el string) string     { return el + ".MustClick()" }
+func (rodDialect) fill(el, value string) string {
+	return el + ".MustSelectAllText().MustInput(" + value + ")"
+}
+func (rodDialect) selectOption(el, label string) string { return el + ".MustSelect(" + label + ")" }
+func (rodDialect) focus(el string) string               { return el + ".MustFocus()" }
+
+func (rodDialect) press(k keyCombo) string {
+	key := rodKey(k.Key)
+	if len(k.Modifiers) == 0 {
+		return "page.Keyboard.MustType(" + key + ")"
+	}
+	mods := make([]string, len(k.Modifiers))
+	for i, m := range k.Modifiers {
+		mods[i] = rodKey(m)
+	}
+	return "page.KeyActions().Press(" + argList(mods) + ").Type(" + key + ").MustDo()"
+}
+
+// rodKey 返回按键对应的 go-rod input 常量
+func rodKey(name string) string {
+	switch {
+	case name == "Control" || name == "Shift" || name == "Alt" || name == "Meta":
+		return "input." + name + "Left"
+	case len(name) == 1 && name[0] >= '0' && name[0] <= '9':
+		return "input.Digit" + name
+	case len(name) == 1:
+		return "input.Key" + strings.ToUpper(name)
+	}
+	return "input." + name
+}
+
+func (rodDialect) upload(el, files string) string { return el + ".MustSetFiles(" + files + "...)" }
+func (rodDialect) sleep(ms int) string {
+	return fmt.Sprintf("time.Sleep(%d * time.Millisecond)", ms)
+}
+
+func (rodDialect) waitForFunction(fn string, args []string, timeoutMs int) string {
+	call := fmt.Sprintf("waitFor(page, %d, %s", timeoutMs, fn)
+	if len(args) > 0 {
+		call += ", []interface{}{" + argList(args) + "}"
+	}
+	return call + ")"
+}
+
+func (rodDialect) waitNetworkIdle(idleMs int) string {
+	return fmt.Sprintf("page.WaitRequestIdle(%d*time.Millisecond, nil, nil, nil)()", idleMs)
+}
+
+func (rodDialect) setViewport(width, height int, scale float64, mobile bool) string {
+	return fmt.Sprintf("page.MustSetViewport(%d, %d, %s, %t)", width, height, strconv.FormatFloat(scale, 'f', -1, 64), mobile)
+}
+
+func (d rodDialect) evalStmt(fn string, args []string) string { return d.eval(fn, args) }
+func (d rodDialect) setVar(name, value string) string {
+	return "setVar(" + d.literal(name) + ", " + value + ")"
+}
+func (d rodDialect) setData(name, value string) string {
+	return "setData(" + d.literal(name) + ", " + value + ")"
+}
+func (d rodDialect) setLocal(name, value string) string {
+	return "variables[" + d.literal(name) + "] = " + value
+}
+
+func (d rodDialect) screenshot(path, mode string, clip [4]int) string {
+	switch mode {
+	case "fullpage":
+		return "page.MustScreenshotFullPage(" + d.literal(path) + ")"
+	case "region":
+		return fmt.Sprintf("screenshotRegion(page, %s, %d, %d, %d, %d)", d.literal(path), clip[0], clip[1], clip[2], clip[3])
+	}
+	return "page.MustScreenshot(" + d.literal(path) + ")"
+}
+
+func (rodDialect) openTab(url string) string {
+	return "page = openTab(browser, " + url + ")\ntabs = append(tabs, page)"
+}
+func (rodDialect) switchTab(index int) string {
+	return fmt.Sprintf("page = tabs[%d]\npage.MustActivate()", index)
+}
+func (rodDialect) switchActiveTab() string { return "page = activePage(browser, page)" }
+func (d rodDialect) assert(label, actual, op, expected string) string {
+	return "assertThat(" + d.literal(label) + ", " + actual + ", " + d.literal(op) + ", " + expected + ")"
+}
+
+func (rodDialect) ifOpen(cond string) string { return "if " + cond + " {" }
+func (rodDialect) elseLine() string          { return "} else {" }
+func (rodDialect) blockClose() string        { return "}" }
+func (rodDialect) emptyBlock() string        { return "" }
+func (rodDialect) forRange(index string, limit int) string {
+	return fmt.Sprintf("for %s := 0; %s < %d; %s++ {", index, index, limit, index)
+}
+func (rodDialect) forEach(index, item, list string) string {
+	return fmt.Sprintf("for %s, %s := range %s {", index, item, list)
+}
+func (rodDialect) breakStmt() string { return "break" }
+
+// rodHelpers 生成代码使用的辅助函数
+const rodHelpers = `
+var placeholder = regexp.MustCompile(` + "`" + `\$\{([^{}]+)\}` + "`" + `)
+
+// render substitutes ${name} placeholders with variable values.
+func render(s string) string {
+	return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		name := strings.TrimSpace(strings.SplitN(m[2:len(m)-1], "|", 2)[0])
+		if !hasVar(name) {
+			return m
+		}
+		return getVar(name)
+	})
+}
+
+// getVar returns a variable, falling back to extracted data.
+func getVar(name string) string {
+	if v, ok := variables[name]; ok {
+		return v
+	}
+	if v, ok := extracted[name]; ok {
+		return stringify(v)
+	}
+	return ""
+}
+
+// hasVar reports whether a variable or extracted value exists.
+func hasVar(name string) bool {
+	if _, ok := variables[name]; ok {
+		return true
+	}
+	_, ok := extracted[name]
+	return ok
+}
+
+// setVar stores a string result as both variable and extracted data.
+func setVar(name, value string) {
+	variables[name] = value
+	extracted[name] = value
+}
+
+// setData stores a JSON result as extracted data.
+func setData(name string, value interface{}) {
+	data, _ := json.Marshal(value)
+	var v interface{}
+	_ = json.Unmarshal(data, &v)
+	extracted[name] = v
+}
+
+// stringify converts a value to the string used in placeholders.
+func stringify(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// listOf converts a JSON array to strings.
+func listOf(v interface{}) []string {
+	data, _ := json.Marshal(v)
+	var list []interface{}
+	_ = json.Unmarshal(data, &list)
+	items := make([]string, len(list))
+	for i, item := range list {
+		items[i] = stringify(item)
+	}
+	return items
+}
+
+// listVar resolves a list variable: extracted arrays, JSON arrays, or newline/comma separated text.
+func listVar(name string) []string {
+	if v, ok := extracted[name].([]interface{}); ok {
+		return listOf(v)
+	}
+	s := strings.TrimSpace(getVar(name))
+	var list []interface{}
+	if strings.HasPrefix(s, "[") && json.Unmarshal([]byte(s), &list) == nil {
+		return listOf(list)
+	}
+	sep := ","
+	if strings.Contains(s, "\n") {
+		sep = "\n"
+	}
+	var items []string
+	for _, part := range strings.Split(s, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// check compares actual with expected using a BrowserWing operator.
+func check(actual, op, expected string) bool {
+	switch op {
+	case "", "=", "==", "equals":
+		return actual == expected
+	case "!=", "not_equals":
+		return actual != expected
+	case "contains":
+		return strings.Contains(actual, expected)
+	case "not_contains":
+		return !strings.Contains(actual, expected)
+	case "starts_with":
+		return strings.HasPrefix(actual, expected)
+	case "ends_with":
+		return strings.HasSuffix(actual, expected)
+	case "matches":
+		return regexp.MustCompile(expected).MatchString(actual)
+	case "in", "not_in":
+		found := false
+		for _, v := range strings.Split(expected, ",") {
+			if strings.TrimSpace(v) == actual {
+				found = true
+			}
+		}
+		return found == (op == "in")
+	case ">", "<", ">=", "<=":
+		a, errA := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+		b, errB := strconv.ParseFloat(strings.TrimSpace(expected), 64)
+		if errA != nil || errB != nil {
+			return false
+		}
+		switch op {
+		case ">":
+			return a > b
+		case "<":
+			return a < b
+		case ">=":
+			return a >= b
+		}
+		return a <= b
+	}
+	log.Fatalf("unsupported operator: %s", op)
+	return false
+}
+
+// assertThat stops the program when the assertion fails.
+func assertThat(label, actual, op, expected string) {
+	if !check(actual, op, expected) {
+		log.Fatalf("assertion failed: expected %s %s %q, got %q", label, op, expected, actual)
+	}
+	log.Printf("assertion passed: %s %s %q", label, op, expected)
+}
+
+// waitFor waits until the page function returns a truthy value.
+func waitFor(page *rod.Page, timeoutMs int, js string, args ...interface{}) {
+	page.Timeout(time.Duration(timeoutMs) * time.Millisecond).MustWait(js, args...)
+}
+
+// attr returns an element attribute, or an empty string when it is missing.
+func attr(el *rod.Element, name string) string {
+	if v := el.MustAttribute(name); v != nil {
+		return *v
+	}
+	return ""
+}
+`
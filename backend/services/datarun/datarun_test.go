@@ -0,0 +1,196 @@
+package datarun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		file   string
+		data   string
+		want   []string
+	}{
+		{name: "CSV by extension", file: "keywords.csv", data: "\xef\xbb\xbfkeyword, page\nlaptop,1\n\"tv, 4k\",2\n", want: []string{"keyword", "page"}},
+		{name: "JSONL by content", data: "{\"keyword\":\"laptop\",\"page\":1}\n\n{\"keyword\":\"tv\",\"tags\":[\"a\"]}\n", want: []string{"keyword", "page", "tags"}},
+		{name: "JSON array", format: "json", data: `[{"b":"2","a":"1"}]`, want: []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := ParseFormat(tt.format, tt.file, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseFormat: %v", err)
+			}
+			ds, err := Parse([]byte(tt.data), format)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if strings.Join(ds.Columns, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("columns = %v, want %v", ds.Columns, tt.want)
+			}
+		})
+	}
+
+	ds, _ := Parse([]byte("keyword,page\n\"tv, 4k\",2\n"), FormatCSV)
+	if ds.Rows[0]["keyword"] != "tv, 4k" || ds.Rows[0]["page"] != "2" {
+		t.Errorf("csv row = %v", ds.Rows[0])
+	}
+	ds, _ = Parse([]byte(`{"keyword":"tv","tags":["a"],"n":3}`), FormatJSONL)
+	if ds.Rows[0]["tags"] != `["a"]` || ds.Rows[0]["n"] != "3" {
+		t.Errorf("jsonl row = %v", ds.Rows[0])
+	}
+
+	if _, err := Parse([]byte("keyword\n"), FormatCSV); !errors.Is(err, ErrEmptyDataset) {
+		t.Errorf("expected ErrEmptyDataset, got %v", err)
+	}
+	if _, err := Parse([]byte("a,a\n1,2\n"), FormatCSV); !errors.Is(err, ErrInvalidDataset) {
+		t.Errorf("expected ErrInvalidDataset, got %v", err)
+	}
+}
+
+type memoryStore struct {
+	mu   sync.Mutex
+	last models.DataRun
+}
+
+func (s *memoryStore) SaveDataRun(run *models.DataRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last = *run
+	s.last.Rows = append([]models.DataRunRow(nil), run.Rows...)
+	return nil
+}
+
+func (s *memoryStore) get() models.DataRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+func waitDone(t *testing.T, r *Runner, id string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for r.IsActive(id) {
+		if time.Now().After(deadline) {
+			t.Fatal("data run did not finish")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRunner(t *testing.T) {
+	script := &models.Script{ID: "s1", Name: "Search", URL: "https://example.com", Variables: map[string]string{"region": "us"}}
+	ds := &Dataset{
+		Format:  FormatCSV,
+		Columns: []string{"keyword"},
+		Rows:    []map[string]string{{"keyword": "a"}, {"keyword": "fail"}, {"keyword": "c"}, {"keyword": "d"}},
+	}
+
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	play := func(ctx context.Context, s *models.Script, instanceID, executionID string) (*models.PlayResult, error) {
+		keyword := s.Variables["keyword"]
+		mu.Lock()
+		attempts[keyword]++
+		mu.Unlock()
+		if s.Variables["region"] != "us" {
+			t.Errorf("preset variable not merged: %v", s.Variables)
+		}
+		if keyword == "fail" {
+			return &models.PlayResult{Success: false, Message: "element not found", ExecutionID: executionID}, errors.New("element not found")
+		}
+		return &models.PlayResult{Success: true, ExtractedData: map[string]interface{}{"title": "result " + keyword, "keyword": keyword}}, nil
+	}
+
+	store := &memoryStore{}
+	runner := NewRunner(store, play)
+	run, err := runner.Start(script, ds, Options{Concurrency: 2, RetryCount: 1})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitDone(t, runner, run.ID)
+
+	final := store.get()
+	if final.Status != models.ExecutionStatusFailed || final.Succeeded != 3 || final.Failed != 1 || final.Skipped != 0 {
+		t.Fatalf("unexpected run: status=%s succeeded=%d failed=%d skipped=%d", final.Status, final.Succeeded, final.Failed, final.Skipped)
+	}
+	if row := final.Rows[1]; row.Attempts != 2 || attempts["fail"] != 2 || row.Error != "element not found" {
+		t.Errorf("failed row = %+v (attempts %d)", row, attempts["fail"])
+	}
+
+	var out bytes.Buffer
+	if err := WriteCSV(&out, &final); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[0] != "row,status,attempts,execution_id,error,keyword,extracted.keyword,title" {
+		t.Errorf("csv header = %q", lines[0])
+	}
+	if len(lines) != 5 || !strings.HasSuffix(lines[1], ",a,a,result a") {
+		t.Errorf("csv = %q", out.String())
+	}
+}
+
+func TestRunnerStopOnError(t *testing.T) {
+	ds := &Dataset{Format: FormatJSONL, Columns: []string{"n"}, Rows: []map[string]string{{"n": "1"}, {"n": "2"}, {"n": "3"}}}
+	play := func(ctx context.Context, s *models.Script, instanceID, executionID string) (*models.PlayResult, error) {
+		if s.Variables["n"] == "1" {
+			return nil, errors.New("boom")
+		}
+		return &models.PlayResult{Success: true}, nil
+	}
+
+	store := &memoryStore{}
+	runner := NewRunner(store, play)
+	run, err := runner.Start(&models.Script{ID: "s1"}, ds, Options{OnRowError: models.OnErrorStop})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitDone(t, runner, run.ID)
+
+	final := store.get()
+	if final.Failed != 1 || final.Skipped != 2 || final.Rows[2].Status != models.DataRowSkipped {
+		t.Errorf("unexpected run: %+v", final)
+	}
+
+	if _, err := runner.Start(&models.Script{}, ds, Options{Concurrency: MaxConcurrency + 1}); !errors.Is(err, ErrInvalidOptions) {
+		t.Errorf("expected ErrInvalidOptions, got %v", err)
+	}
+}
+
+func TestRunnerInvalidRowParams(t *testing.T) {
+	script := &models.Script{ID: "s1", Parameters: []models.ScriptParameter{{Name: "n", Type: models.ParamNumber, Required: true}}}
+	ds := &Dataset{Format: FormatCSV, Columns: []string{"n"}, Rows: []map[string]string{{"n": "1"}, {"n": "x"}}}
+	var played int32
+	play := func(ctx context.Context, s *models.Script, instanceID, executionID string) (*models.PlayResult, error) {
+		atomic.AddInt32(&played, 1)
+		return &models.PlayResult{Success: true}, nil
+	}
+
+	store := &memoryStore{}
+	runner := NewRunner(store, play)
+	run, err := runner.Start(script, ds, Options{RetryCount: 2})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitDone(t, runner, run.ID)
+
+	// 参数无效的行不回放也不重试
+	final := store.get()
+	if final.Succeeded != 1 || final.Failed != 1 || atomic.LoadInt32(&played) != 1 {
+		t.Fatalf("succeeded=%d failed=%d played=%d", final.Succeeded, final.Failed, played)
+	}
+	if row := final.Rows[1]; row.Attempts != 0 || !strings.Contains(row.Error, `parameter "n" must be a number`) {
+		t.Errorf("invalid row = %+v", row)
+	}
+}
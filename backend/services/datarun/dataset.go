@@ -0,0 +1,180 @@
+package datarun
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/browserwing/browserwing/pkg/interpolate"
+)
+
+// Format 数据集格式
+type Format string
+
+const (
+	FormatCSV   Format = "csv"   // 第一行为表头
+	FormatJSONL Format = "jsonl" // 每行一个 JSON 对象
+	FormatJSON  Format = "json"  // JSON 对象数组
+)
+
+var (
+	ErrUnknownFormat  = errors.New("unknown dataset format")
+	ErrInvalidDataset = errors.New("invalid dataset")
+	ErrEmptyDataset   = errors.New("dataset has no rows")
+)
+
+// Dataset 解析后的数据集，每行的列映射为脚本变量
+type Dataset struct {
+	Format  Format
+	Columns []string
+	Rows    []map[string]string
+}
+
+// ParseFormat 解析格式参数，为空时根据文件扩展名或内容识别
+func ParseFormat(s, fileName string, data []byte) (Format, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		s = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	}
+	switch s {
+	case "csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	case "json":
+		return FormatJSON, nil
+	case "":
+		// 根据内容识别：[ 开头为 JSON 数组，{ 开头为 JSONL，其他按 CSV 处理
+		trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+		switch {
+		case bytes.HasPrefix(trimmed, []byte("[")):
+			return FormatJSON, nil
+		case bytes.HasPrefix(trimmed, []byte("{")):
+			return FormatJSONL, nil
+		}
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownFormat, s)
+}
+
+// Parse 解析数据集
+func Parse(data []byte, format Format) (*Dataset, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var ds *Dataset
+	var err error
+	switch format {
+	case FormatCSV:
+		ds, err = parseCSV(data)
+	case FormatJSONL:
+		ds, err = parseJSONL(data)
+	case FormatJSON:
+		ds, err = parseJSON(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(ds.Rows) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	ds.Format = format
+	return ds, nil
+}
+
+func parseCSV(data []byte) (*Dataset, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDataset, err)
+	}
+	if len(records) == 0 {
+		return nil, ErrEmptyDataset
+	}
+
+	ds := &Dataset{}
+	seen := make(map[string]bool)
+	for i, name := range records[0] {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: column %d has no header", ErrInvalidDataset, i+1)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidDataset, name)
+		}
+		seen[name] = true
+		ds.Columns = append(ds.Columns, name)
+	}
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(ds.Columns))
+		for i, name := range ds.Columns {
+			row[name] = record[i]
+		}
+		ds.Rows = append(ds.Rows, row)
+	}
+	return ds, nil
+}
+
+func parseJSONL(data []byte) (*Dataset, error) {
+	ds := &Dataset{}
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal(text, &obj); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidDataset, line, err)
+		}
+		ds.Rows = append(ds.Rows, ds.addRow(obj, seen))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDataset, err)
+	}
+	return ds, nil
+}
+
+func parseJSON(data []byte) (*Dataset, error) {
+	var objs []map[string]interface{}
+	if err := json.Unmarshal(data, &objs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDataset, err)
+	}
+	ds := &Dataset{}
+	seen := make(map[string]bool)
+	for _, obj := range objs {
+		ds.Rows = append(ds.Rows, ds.addRow(obj, seen))
+	}
+	return ds, nil
+}
+
+// addRow 将 JSON 对象转换为变量（非字符串值使用 JSON 编码），并按首次出现的顺序记录列名
+func (ds *Dataset) addRow(obj map[string]interface{}, seen map[string]bool) map[string]string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	row := make(map[string]string, len(obj))
+	for _, k := range keys {
+		if !seen[k] {
+			seen[k] = true
+			ds.Columns = append(ds.Columns, k)
+		}
+		row[k] = interpolate.Stringify(obj[k])
+	}
+	return row
+}
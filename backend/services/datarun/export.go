@@ -0,0 +1,100 @@
+package datarun
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+)
+
+// WriteCSV 导出每行的执行状态、变量和抓取数据（抓取字段与数据集列重名时加 extracted. 前缀）
+func WriteCSV(w io.Writer, run *models.DataRun) error {
+	columns := make(map[string]bool, len(run.Columns))
+	for _, c := range run.Columns {
+		columns[c] = true
+	}
+	keys := extractedKeys(run)
+
+	header := []string{"row", "status", "attempts", "execution_id", "error"}
+	header = append(header, run.Columns...)
+	for _, k := range keys {
+		if columns[k] {
+			k = "extracted." + k
+		}
+		header = append(header, k)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, row := range run.Rows {
+		record := []string{strconv.Itoa(row.Index), string(row.Status), strconv.Itoa(row.Attempts), row.ExecutionID, row.Error}
+		for _, c := range run.Columns {
+			record = append(record, row.Variables[c])
+		}
+		for _, k := range keys {
+			value, ok := row.ExtractedData[k]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, interpolate.Stringify(value))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonlRow JSONL 导出的单行
+type jsonlRow struct {
+	Row           int                    `json:"row"`
+	Status        models.DataRowStatus   `json:"status"`
+	Attempts      int                    `json:"attempts"`
+	ExecutionID   string                 `json:"execution_id,omitempty"`
+	Error         string                 `json:"error,omitempty"`
+	Variables     map[string]string      `json:"variables"`
+	ExtractedData map[string]interface{} `json:"extracted_data,omitempty"`
+}
+
+// WriteJSONL 导出每行一个 JSON 对象（抓取数据保留原始结构）
+func WriteJSONL(w io.Writer, run *models.DataRun) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range run.Rows {
+		if err := encoder.Encode(jsonlRow{
+			Row:           row.Index,
+			Status:        row.Status,
+			Attempts:      row.Attempts,
+			ExecutionID:   row.ExecutionID,
+			Error:         row.Error,
+			Variables:     row.Variables,
+			ExtractedData: row.ExtractedData,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractedKeys 所有行抓取数据的字段名（排序）
+func extractedKeys(run *models.DataRun) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, row := range run.Rows {
+		for k := range row.ExtractedData {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
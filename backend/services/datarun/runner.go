@@ -0,0 +1,276 @@
+package datarun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/params"
+	"github.com/google/uuid"
+)
+
+// MaxConcurrency 单个数据驱动运行允许的最大并发行数
+const MaxConcurrency = 16
+
+var (
+	ErrInvalidOptions = errors.New("invalid data run options")
+	ErrRunNotActive   = errors.New("data run is not running")
+	ErrRunCancelled   = errors.New("data run cancelled")
+)
+
+// PlayFunc 在指定浏览器实例上执行一次脚本回放（executionID 为本次回放的执行记录 ID）
+type PlayFunc func(ctx context.Context, script *models.Script, instanceID, executionID string) (*models.PlayResult, error)
+
+// Store 运行记录存储
+type Store interface {
+	SaveDataRun(run *models.DataRun) error
+}
+
+// Options 运行配置
+type Options struct {
+	InstanceID  string
+	DatasetName string
+	Concurrency int                  // 默认 1
+	OnRowError  models.OnErrorAction // continue（默认）或 stop
+	RetryCount  int
+}
+
+// Runner 数据驱动运行调度器
+type Runner struct {
+	store Store
+	play  PlayFunc
+
+	mu     sync.Mutex
+	active map[string]context.CancelCauseFunc
+}
+
+// NewRunner 创建调度器
+func NewRunner(store Store, play PlayFunc) *Runner {
+	return &Runner{store: store, play: play, active: make(map[string]context.CancelCauseFunc)}
+}
+
+// Start 创建运行记录并在后台逐行执行脚本，返回初始的运行记录
+func (r *Runner) Start(script *models.Script, ds *Dataset, opts Options) (*models.DataRun, error) {
+	if opts.Concurrency == 0 {
+		opts.Concurrency = 1
+	}
+	if opts.OnRowError == "" {
+		opts.OnRowError = models.OnErrorContinue
+	}
+	switch {
+	case opts.Concurrency < 1 || opts.Concurrency > MaxConcurrency:
+		return nil, fmt.Errorf("%w: concurrency must be between 1 and %d", ErrInvalidOptions, MaxConcurrency)
+	case opts.OnRowError != models.OnErrorContinue && opts.OnRowError != models.OnErrorStop:
+		return nil, fmt.Errorf("%w: on_row_error must be continue or stop", ErrInvalidOptions)
+	case opts.RetryCount < 0:
+		return nil, fmt.Errorf("%w: retry_count must not be negative", ErrInvalidOptions)
+	}
+
+	now := time.Now()
+	run := &models.DataRun{
+		ID:             uuid.New().String(),
+		ScriptID:       script.ID,
+		ScriptName:     script.Name,
+		ScriptRevision: script.Revision,
+		InstanceID:     opts.InstanceID,
+		DatasetName:    opts.DatasetName,
+		Format:         string(ds.Format),
+		Columns:        ds.Columns,
+		Concurrency:    opts.Concurrency,
+		OnRowError:     opts.OnRowError,
+		RetryCount:     opts.RetryCount,
+		Status:         models.ExecutionStatusRunning,
+		TotalRows:      len(ds.Rows),
+		Rows:           make([]models.DataRunRow, len(ds.Rows)),
+		StartTime:      now,
+		CreatedAt:      now,
+	}
+	for i, vars := range ds.Rows {
+		run.Rows[i] = models.DataRunRow{Index: i, Variables: vars, Status: models.DataRowPending}
+	}
+	if err := r.store.SaveDataRun(run); err != nil {
+		return nil, fmt.Errorf("failed to save data run: %w", err)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	r.mu.Lock()
+	r.active[run.ID] = cancel
+	r.mu.Unlock()
+
+	snapshot := *run
+	snapshot.Rows = append([]models.DataRunRow(nil), run.Rows...)
+	go r.execute(ctx, run, script)
+	return &snapshot, nil
+}
+
+// Cancel 取消正在执行的运行（执行中的行随之中止，未开始的行标记为跳过）
+func (r *Runner) Cancel(id string) error {
+	r.mu.Lock()
+	cancel, ok := r.active[id]
+	r.mu.Unlock()
+	if !ok {
+		return ErrRunNotActive
+	}
+	cancel(ErrRunCancelled)
+	return nil
+}
+
+// IsActive 判断运行是否仍在执行
+func (r *Runner) IsActive(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.active[id]
+	return ok
+}
+
+// execute 按并发数分发数据行，所有行结束后写入最终状态
+func (r *Runner) execute(ctx context.Context, run *models.DataRun, script *models.Script) {
+	defer func() {
+		r.mu.Lock()
+		cancel := r.active[run.ID]
+		delete(r.active, run.ID)
+		r.mu.Unlock()
+		cancel(nil)
+	}()
+
+	var mu sync.Mutex // 保护 run 的并发更新和保存
+	stopped := false
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < run.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rows {
+				mu.Lock()
+				skip := stopped || ctx.Err() != nil
+				if skip {
+					run.Rows[i].Status = models.DataRowSkipped
+				} else {
+					run.Rows[i].Status = models.DataRowRunning
+					run.Rows[i].StartTime = time.Now()
+				}
+				mu.Unlock()
+				if skip {
+					continue
+				}
+
+				row := r.runRow(ctx, run, script, i)
+
+				mu.Lock()
+				run.Rows[i] = row
+				if row.Status == models.DataRowFailed && run.OnRowError == models.OnErrorStop {
+					stopped = true
+				}
+				r.saveLocked(run)
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range run.Rows {
+		rows <- i
+	}
+	close(rows)
+	wg.Wait()
+
+	run.EndTime = time.Now()
+	run.Duration = run.EndTime.Sub(run.StartTime).Milliseconds()
+	tally(run)
+	switch {
+	case ctx.Err() != nil:
+		run.Status = models.ExecutionStatusCancelled
+		run.Message = fmt.Sprintf("Data run cancelled after %d of %d rows", run.Succeeded+run.Failed, run.TotalRows)
+	case run.Failed > 0:
+		run.Status = models.ExecutionStatusFailed
+		run.Message = fmt.Sprintf("%d of %d rows failed", run.Failed, run.TotalRows)
+		if stopped {
+			run.Message += ", remaining rows skipped"
+		}
+	default:
+		run.Status = models.ExecutionStatusSuccess
+		run.Message = fmt.Sprintf("All %d rows succeeded", run.TotalRows)
+	}
+	r.saveLocked(run)
+}
+
+// runRow 执行单行（失败时按 RetryCount 重试）
+func (r *Runner) runRow(ctx context.Context, run *models.DataRun, script *models.Script, index int) models.DataRunRow {
+	row := run.Rows[index]
+	rowScript, err := prepareScript(script, row.Variables)
+	if err != nil {
+		// 行参数不符合脚本参数定义时不回放（重试也不会成功）
+		row.Error = err.Error()
+	}
+	for attempt := 1; err == nil && attempt <= run.RetryCount+1; attempt++ {
+		row.Attempts = attempt
+		row.ExecutionID = fmt.Sprintf("%s-%d-%d", run.ID, index, attempt)
+		result, err := r.play(ctx, rowScript, run.InstanceID, row.ExecutionID)
+
+		row.Error = ""
+		row.ExtractedData = nil
+		switch {
+		case err != nil:
+			row.Error = err.Error()
+		case result == nil:
+			row.Error = "script playback returned no result"
+		case !result.Success:
+			row.Error = result.Message
+		}
+		if result != nil {
+			row.ExtractedData = result.ExtractedData
+		}
+		if row.Error == "" || ctx.Err() != nil {
+			break
+		}
+	}
+
+	row.Status = models.DataRowSuccess
+	if row.Error != "" {
+		row.Status = models.DataRowFailed
+	}
+	row.EndTime = time.Now()
+	row.Duration = row.EndTime.Sub(row.StartTime).Milliseconds()
+	return row
+}
+
+// saveLocked 保存运行进度（调用方需持有锁或确保没有并发更新）
+func (r *Runner) saveLocked(run *models.DataRun) {
+	tally(run)
+	// 保存失败不影响执行，下次保存时会写入最新状态
+	if err := r.store.SaveDataRun(run); err != nil {
+		logger.Warn(context.Background(), "Failed to save data run %s: %v", run.ID, err)
+	}
+}
+
+// tally 统计各状态的行数
+func tally(run *models.DataRun) {
+	run.Succeeded, run.Failed, run.Skipped = 0, 0, 0
+	for _, row := range run.Rows {
+		switch row.Status {
+		case models.DataRowSuccess:
+			run.Succeeded++
+		case models.DataRowFailed:
+			run.Failed++
+		case models.DataRowSkipped:
+			run.Skipped++
+		}
+	}
+}
+
+// prepareScript 创建脚本副本并合并行变量（行变量覆盖预设变量，按参数定义校验，url 列作为起始 URL）
+func prepareScript(script *models.Script, vars map[string]string) (*models.Script, error) {
+	s := script.Copy()
+	merged, err := params.ResolveStrings(s, vars)
+	if err != nil {
+		return nil, err
+	}
+	s.Variables = merged
+	if u := vars["url"]; u != "" {
+		s.URL = u
+	}
+	return s, nil
+}
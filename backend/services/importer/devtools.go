@@ -0,0 +1,351 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// devtoolsRecording Chrome DevTools Recorder 导出的录制
+type devtoolsRecording struct {
+	Title string         `json:"title"`
+	Steps []devtoolsStep `json:"steps"`
+}
+
+// devtoolsStep 录制步骤（不同类型的步骤使用不同字段）
+type devtoolsStep struct {
+	Type      string            `json:"type"`
+	Target    string            `json:"target"`    // 页面标识，main 为初始页面，其他值为打开的新页面
+	Frame     []int             `json:"frame"`     // 从顶层页面依次进入的子 frame 序号
+	Selectors []json.RawMessage `json:"selectors"` // 候选选择器，每项为字符串或穿透 shadow root 的字符串数组
+	Timeout   int               `json:"timeout"`
+
+	URL        string  `json:"url"`        // navigate
+	Value      string  `json:"value"`      // change
+	Key        string  `json:"key"`        // keyDown / keyUp
+	Button     string  `json:"button"`     // click: primary, auxiliary, secondary
+	Expression string  `json:"expression"` // waitForExpression
+	Operator   string  `json:"operator"`   // waitForElement: >=, ==, <=
+	Count      *int    `json:"count"`      // waitForElement: 元素数量（默认 1）
+	Visible    *bool   `json:"visible"`    // waitForElement: 是否要求可见（默认 true）
+	X          float64 `json:"x"`          // scroll
+	Y          float64 `json:"y"`          // scroll
+
+	// setViewport
+	Width             float64 `json:"width"`
+	Height            float64 `json:"height"`
+	DeviceScaleFactor float64 `json:"deviceScaleFactor"`
+	IsMobile          bool    `json:"isMobile"`
+
+	AssertedEvents []struct {
+		Type  string `json:"type"`
+		URL   string `json:"url"`
+		Title string `json:"title"`
+	} `json:"assertedEvents"`
+}
+
+// modifierKeys DevTools 录制的修饰键（Meta 按 Ctrl 处理，回放时会根据平台选择）
+var modifierKeys = map[string]bool{"Control": true, "Meta": true}
+
+// importDevTools 转换 Chrome DevTools Recorder 录制
+func importDevTools(data []byte) (*Result, error) {
+	var rec devtoolsRecording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecording, err)
+	}
+	if rec.Steps == nil {
+		return nil, fmt.Errorf("%w: missing steps", ErrInvalidRecording)
+	}
+
+	name := strings.TrimSpace(rec.Title)
+	if name == "" {
+		name = "DevTools recording"
+	}
+	result := &Result{Format: FormatDevTools, Unmapped: []Issue{}}
+	c := newConverter(result, name, "Imported from Chrome DevTools Recorder")
+
+	target := "main"
+	ctrl := false
+	for i, step := range rec.Steps {
+		c.at(i+1, step.Type)
+
+		// 步骤属于其他页面时先切换标签页
+		if step.Target != "" && step.Target != target {
+			if step.Target == "main" {
+				c.emit(models.ScriptAction{Type: "switch_tab", Value: "0"})
+			} else {
+				c.emit(models.ScriptAction{Type: "switch_active_tab"})
+			}
+			target = step.Target
+		}
+
+		switch step.Type {
+		case "setViewport":
+			if step.Width <= 0 || step.Height <= 0 {
+				c.unmapped("viewport size is missing")
+				continue
+			}
+			c.emit(models.ScriptAction{
+				Type:              "set_viewport",
+				ViewportWidth:     int(step.Width),
+				ViewportHeight:    int(step.Height),
+				DeviceScaleFactor: step.DeviceScaleFactor,
+				Mobile:            step.IsMobile,
+			})
+
+		case "navigate":
+			if step.URL == "" {
+				c.unmapped("navigation URL is missing")
+				continue
+			}
+			c.emit(models.ScriptAction{Type: "navigate", URL: step.URL})
+
+		case "click":
+			if step.Button != "" && step.Button != "primary" {
+				c.unmapped("%s button clicks are not supported", step.Button)
+				continue
+			}
+			a := models.ScriptAction{Type: "click"}
+			if !c.locateDevTools(&a, step) {
+				continue
+			}
+			c.emit(a)
+			c.waitForNavigation(step)
+
+		case "change":
+			a := models.ScriptAction{Type: "input", Value: step.Value}
+			if !c.locateDevTools(&a, step) {
+				continue
+			}
+			c.emit(a)
+
+		case "keyDown":
+			if modifierKeys[step.Key] {
+				ctrl = true
+				continue
+			}
+			key := strings.ToLower(step.Key)
+			if ctrl {
+				key = "ctrl+" + key
+			}
+			if !supportedKeys[key] {
+				c.unmapped("key %q is not supported by keyboard actions", key)
+				continue
+			}
+			c.emit(models.ScriptAction{Type: "keyboard", Key: key})
+			c.waitForNavigation(step)
+
+		case "keyUp":
+			// 按键在 keyDown 时已完整执行
+			if modifierKeys[step.Key] {
+				ctrl = false
+			}
+
+		case "scroll":
+			if len(step.Selectors) > 0 {
+				c.unmapped("scrolling inside an element is not supported")
+				continue
+			}
+			c.emit(models.ScriptAction{Type: "scroll", ScrollX: int(step.X), ScrollY: int(step.Y)})
+
+		case "waitForElement":
+			c.waitForElement(step)
+
+		case "waitForExpression":
+			if len(step.Frame) > 0 {
+				c.unmapped("waiting for expressions inside iframes is not supported")
+				continue
+			}
+			c.emit(models.ScriptAction{Type: "wait_for", WaitFor: &models.WaitCondition{
+				Type:       models.WaitJSPredicate,
+				Expression: step.Expression,
+				Timeout:    step.Timeout,
+			}})
+
+		case "doubleClick":
+			c.unmapped("double clicks are not supported")
+		case "hover":
+			c.unmapped("hover is not supported")
+		case "close":
+			c.unmapped("closing pages is not supported")
+		case "emulateNetworkConditions":
+			c.unmapped("network emulation is not supported")
+		default:
+			c.unmapped("step type has no equivalent action")
+		}
+	}
+
+	c.finish("")
+	return result, nil
+}
+
+// waitForNavigation 步骤声明了导航事件时，等待页面跳转到录制时的地址
+func (c *converter) waitForNavigation(step devtoolsStep) {
+	for _, event := range step.AssertedEvents {
+		if event.Type != "navigation" || event.URL == "" {
+			continue
+		}
+		u, err := url.Parse(event.URL)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		c.emit(models.ScriptAction{Type: "wait_for", WaitFor: &models.WaitCondition{
+			Type:    models.WaitURLMatches,
+			Pattern: regexp.QuoteMeta(u.Scheme + "://" + u.Host + u.Path),
+			Timeout: step.Timeout,
+		}})
+		return
+	}
+}
+
+// waitForElement 转换 waitForElement 步骤
+func (c *converter) waitForElement(step devtoolsStep) {
+	if len(step.Frame) > 0 {
+		c.unmapped("waiting for elements inside iframes is not supported")
+		return
+	}
+	var a models.ScriptAction
+	if !c.locateDevTools(&a, step) {
+		return
+	}
+	if len(a.FramePath) > 0 {
+		c.unmapped("waiting for elements inside shadow roots is not supported")
+		return
+	}
+
+	operator := step.Operator
+	if operator == "" {
+		operator = ">="
+	}
+	count := 1
+	if step.Count != nil {
+		count = *step.Count
+	}
+	visible := step.Visible == nil || *step.Visible
+
+	cond := &models.WaitCondition{Selector: a.Selector, XPath: a.XPath, Timeout: step.Timeout}
+	switch {
+	case operator == ">=" && count == 1 && visible:
+		cond.Type = models.WaitSelectorVisible
+	case operator == "==" && count == 0:
+		cond.Type = models.WaitSelectorHidden
+	case a.Selector != "":
+		if operator == "==" {
+			operator = "==="
+		}
+		cond.Type = models.WaitJSPredicate
+		cond.Expression = fmt.Sprintf("document.querySelectorAll(%s).length %s %d", strconv.Quote(a.Selector), operator, count)
+		cond.Selector, cond.XPath = "", ""
+	default:
+		c.unmapped("element count conditions require a CSS selector")
+		return
+	}
+	c.emit(models.ScriptAction{Type: "wait_for", WaitFor: cond})
+}
+
+// locateDevTools 从候选选择器中选出 CSS 和 XPath 选择器，并生成 iframe / shadow 定位路径
+func (c *converter) locateDevTools(a *models.ScriptAction, step devtoolsStep) bool {
+	var css, pierce []string
+	var xpath, text string
+	for _, raw := range step.Selectors {
+		parts, ok := selectorParts(raw)
+		if !ok || len(parts) == 0 {
+			continue
+		}
+		last := parts[len(parts)-1]
+		switch {
+		case strings.HasPrefix(last, "aria/"):
+			if len(parts) == 1 && a.Accessibility == nil {
+				a.Accessibility = ariaInfo(strings.TrimPrefix(last, "aria/"))
+			}
+		case strings.HasPrefix(last, "xpath/"):
+			if len(parts) == 1 && xpath == "" {
+				xpath = strings.TrimPrefix(last, "xpath/")
+			}
+		case strings.HasPrefix(last, "pierce/"):
+			if len(parts) == 1 && pierce == nil {
+				pierce = []string{strings.TrimPrefix(last, "pierce/")}
+			}
+		case strings.HasPrefix(last, "text/"):
+			if len(parts) == 1 && text == "" {
+				text = strings.TrimPrefix(last, "text/")
+			}
+		default:
+			if css == nil && plainCSS(parts) {
+				css = parts
+			}
+		}
+	}
+
+	for _, index := range step.Frame {
+		a.FramePath = append(a.FramePath, models.FrameHop{Type: models.FrameHopIframe, Selector: frameIndexSelector(index)})
+	}
+	if len(step.Frame) > 0 {
+		c.warn("frame indexes %v are located by iframe position, check the frame path", step.Frame)
+	}
+
+	switch {
+	case css != nil:
+		for _, host := range css[:len(css)-1] {
+			a.FramePath = append(a.FramePath, models.FrameHop{Type: models.FrameHopShadow, Selector: host})
+		}
+		a.Selector = css[len(css)-1]
+		if len(css) == 1 && xpath != "" && !isPositionalXPath(xpath) {
+			a.XPath = xpath
+		}
+	case xpath != "":
+		a.XPath = xpath
+	case pierce != nil:
+		a.Selector = pierce[0]
+		c.warn("pierce selector %q is only searched outside shadow roots", pierce[0])
+	case text != "":
+		a.XPath = textXPath(text)
+		c.warn("element is located by its text %q", text)
+	default:
+		c.unmapped("no CSS, XPath or text selector in the selector list")
+		return false
+	}
+	return true
+}
+
+// selectorParts 解析候选选择器（字符串或字符串数组）
+func selectorParts(raw json.RawMessage) ([]string, bool) {
+	var parts []string
+	if err := json.Unmarshal(raw, &parts); err == nil {
+		return parts, true
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, true
+	}
+	return nil, false
+}
+
+// plainCSS 判断选择器的各部分是否都是普通 CSS 选择器
+func plainCSS(parts []string) bool {
+	for _, p := range parts {
+		for _, prefix := range []string{"aria/", "xpath/", "pierce/", "text/"} {
+			if strings.HasPrefix(p, prefix) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+var ariaRolePattern = regexp.MustCompile(`\[role="([^"]*)"\]$`)
+
+// ariaInfo 解析 aria 选择器（如 Search[role="searchbox"]）为无障碍语义信息，用于回放时自愈
+func ariaInfo(selector string) *models.AccessibilityInfo {
+	info := &models.AccessibilityInfo{Name: selector}
+	if m := ariaRolePattern.FindStringSubmatchIndex(selector); m != nil {
+		info.Role = selector[m[2]:m[3]]
+		info.Name = selector[:m[0]]
+	}
+	return info
+}
@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// Format 录制文件格式
+type Format string
+
+const (
+	FormatDevTools Format = "devtools" // Chrome DevTools Recorder 导出的 JSON
+	FormatSelenium Format = "selenium" // Selenium IDE 项目文件（.side）
+)
+
+var (
+	ErrUnknownFormat    = errors.New("unknown recording format")
+	ErrInvalidRecording = errors.New("invalid recording")
+)
+
+// Issue 导入时无法转换或需要人工确认的步骤
+type Issue struct {
+	Script string `json:"script"` // 所属脚本名称
+	Step   int    `json:"step"`   // 原始录制中的步骤序号（从 1 开始）
+	Type   string `json:"type"`   // 原始步骤类型或 Selenium 命令
+	Reason string `json:"reason"`
+}
+
+// Result 导入结果
+type Result struct {
+	Format   Format           `json:"format"`
+	Scripts  []*models.Script `json:"scripts"`
+	Unmapped []Issue          `json:"unmapped"`           // 未能转换而跳过的步骤
+	Warnings []Issue          `json:"warnings,omitempty"` // 已转换但回放行为可能与原工具不同的步骤
+}
+
+// ParseFormat 解析格式参数，空值或 auto 表示自动识别
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "auto":
+		return "", nil
+	case "devtools", "chrome", "recorder":
+		return FormatDevTools, nil
+	case "selenium", "side":
+		return FormatSelenium, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownFormat, s)
+}
+
+// Detect 根据文件内容识别录制格式
+func Detect(data []byte) (Format, error) {
+	var probe struct {
+		Steps json.RawMessage `json:"steps"`
+		Tests json.RawMessage `json:"tests"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidRecording, err)
+	}
+	switch {
+	case probe.Tests != nil:
+		return FormatSelenium, nil
+	case probe.Steps != nil:
+		return FormatDevTools, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Import 将录制文件转换为脚本（format 为空时自动识别），返回的脚本尚未分配 ID
+func Import(data []byte, format Format) (*Result, error) {
+	if format == "" {
+		detected, err := Detect(data)
+		if err != nil {
+			return nil, err
+		}
+		format = detected
+	}
+	switch format {
+	case FormatDevTools:
+		return importDevTools(data)
+	case FormatSelenium:
+		return importSelenium(data)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+// converter 单个脚本的转换上下文
+type converter struct {
+	result *Result
+	script *models.Script
+	out    *[]models.ScriptAction // 当前写入的操作列表（Selenium 控制流命令会切换到嵌套列表）
+	step   int                    // 当前原始步骤序号
+	kind   string                 // 当前原始步骤类型
+}
+
+func newConverter(result *Result, name, description string) *converter {
+	script := &models.Script{Name: name, Description: description, Actions: []models.ScriptAction{}}
+	result.Scripts = append(result.Scripts, script)
+	return &converter{result: result, script: script, out: &script.Actions}
+}
+
+// at 开始转换一个原始步骤
+func (c *converter) at(step int, kind string) {
+	c.step = step
+	c.kind = kind
+}
+
+// emit 写入转换后的操作
+func (c *converter) emit(a models.ScriptAction) {
+	*c.out = append(*c.out, a)
+}
+
+// unmapped 记录无法转换的步骤
+func (c *converter) unmapped(format string, args ...interface{}) {
+	c.result.Unmapped = append(c.result.Unmapped, c.issue(format, args...))
+}
+
+// warn 记录回放行为可能不同的步骤
+func (c *converter) warn(format string, args ...interface{}) {
+	c.result.Warnings = append(c.result.Warnings, c.issue(format, args...))
+}
+
+func (c *converter) issue(format string, args ...interface{}) Issue {
+	return Issue{Script: c.script.Name, Step: c.step, Type: c.kind, Reason: fmt.Sprintf(format, args...)}
+}
+
+// finish 将第一个导航步骤作为脚本起始 URL（回放时会先打开起始 URL）
+// 导航之前只有设置视口的步骤时移除该导航，避免重复打开页面
+func (c *converter) finish(defaultURL string) {
+	c.script.URL = defaultURL
+	for i, a := range c.script.Actions {
+		if a.Type == "set_viewport" {
+			continue
+		}
+		if a.Type == "navigate" {
+			c.script.URL = a.URL
+			c.script.StartWait = a.WaitFor
+			c.script.Actions = append(c.script.Actions[:i:i], c.script.Actions[i+1:]...)
+			return
+		}
+		break
+	}
+	for _, a := range c.script.Actions {
+		if a.Type == "navigate" {
+			c.script.URL = a.URL
+			return
+		}
+	}
+}
+
+// frameIndexSelector 按子 frame 序号生成 iframe 选择器（近似定位，序号按同级 iframe 计算）
+func frameIndexSelector(index int) string {
+	if index == 0 {
+		return "iframe"
+	}
+	return fmt.Sprintf("iframe:nth-of-type(%d)", index+1)
+}
+
+var cssIdentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// idSelector 生成按 id 查找的 CSS 选择器
+func idSelector(id string) string {
+	if cssIdentPattern.MatchString(id) {
+		return "#" + id
+	}
+	return attrSelector("id", id)
+}
+
+// attrSelector 生成按属性值查找的 CSS 选择器
+func attrSelector(name, value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return "[" + name + `="` + value + `"]`
+}
+
+// xpathLiteral 生成 XPath 字符串字面量
+func xpathLiteral(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+	parts := strings.Split(s, "'")
+	for i, p := range parts {
+		parts[i] = "'" + p + "'"
+	}
+	return "concat(" + strings.Join(parts, `, "'", `) + ")"
+}
+
+// textXPath 生成按文本内容查找元素的 XPath
+func textXPath(text string) string {
+	return "//*[text()[contains(normalize-space(.), " + xpathLiteral(strings.TrimSpace(text)) + ")]]"
+}
+
+// isPositionalXPath 判断 XPath 是否只依赖元素位置（页面结构变化时容易失效）
+func isPositionalXPath(xpath string) bool {
+	return !strings.Contains(xpath, "@") && !strings.Contains(xpath, "text()")
+}
+
+// supportedKeys keyboard 操作支持的按键
+var supportedKeys = map[string]bool{
+	"enter": true, "tab": true, "backspace": true,
+	"ctrl+a": true, "ctrl+c": true, "ctrl+v": true,
+}
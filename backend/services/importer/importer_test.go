@@ -0,0 +1,164 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+const devtoolsSample = `{
+  "title": "Search flow",
+  "steps": [
+    {"type": "setViewport", "width": 1280, "height": 720, "deviceScaleFactor": 1, "isMobile": false, "hasTouch": false, "isLandscape": false},
+    {"type": "navigate", "url": "https://example.com/", "assertedEvents": [{"type": "navigation", "url": "https://example.com/", "title": "Example"}]},
+    {"type": "click", "target": "main", "selectors": [["aria/Search[role=\"searchbox\"]"], ["#q"], ["xpath///*[@id=\"q\"]"], ["pierce/#q"]], "offsetX": 10, "offsetY": 5},
+    {"type": "change", "value": "laptop", "selectors": [["#q"]], "target": "main"},
+    {"type": "keyDown", "target": "main", "key": "Enter", "assertedEvents": [{"type": "navigation", "url": "https://example.com/search?q=laptop", "title": ""}]},
+    {"type": "keyUp", "target": "main", "key": "Enter"},
+    {"type": "click", "target": "main", "frame": [0], "selectors": [["my-app", "button.buy"]]},
+    {"type": "waitForElement", "selectors": [[".result"]], "operator": ">=", "count": 3},
+    {"type": "doubleClick", "selectors": [["#title"]]},
+    {"type": "click", "target": "https://example.com/help", "selectors": [["aria/Help"]]}
+  ]
+}`
+
+func TestImportDevTools(t *testing.T) {
+	result, err := Import([]byte(devtoolsSample), "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if result.Format != FormatDevTools || len(result.Scripts) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	script := result.Scripts[0]
+	if script.Name != "Search flow" || script.URL != "https://example.com/" {
+		t.Fatalf("unexpected script: name=%q url=%q", script.Name, script.URL)
+	}
+
+	types := actionTypes(script.Actions)
+	want := []string{"set_viewport", "click", "input", "keyboard", "wait_for", "click", "wait_for", "switch_active_tab"}
+	if !equal(types, want) {
+		t.Fatalf("action types = %v, want %v", types, want)
+	}
+
+	click := script.Actions[1]
+	if click.Selector != "#q" || click.XPath != `//*[@id="q"]` || click.Accessibility == nil || click.Accessibility.Role != "searchbox" {
+		t.Errorf("selector list not mapped: %+v", click)
+	}
+	if wait := script.Actions[4].WaitFor; wait.Type != models.WaitURLMatches || wait.Pattern != `https://example\.com/search` {
+		t.Errorf("navigation wait = %+v", wait)
+	}
+	nested := script.Actions[5]
+	if len(nested.FramePath) != 2 || nested.FramePath[0].Type != models.FrameHopIframe || nested.FramePath[1].Selector != "my-app" || nested.Selector != "button.buy" {
+		t.Errorf("frame path not mapped: %+v", nested)
+	}
+	if wait := script.Actions[6].WaitFor; wait.Type != models.WaitJSPredicate || wait.Expression != `document.querySelectorAll(".result").length >= 3` {
+		t.Errorf("count wait = %+v", wait)
+	}
+
+	// doubleClick 和只有 aria 选择器的点击无法转换
+	if len(result.Unmapped) != 2 || result.Unmapped[0].Step != 9 || result.Unmapped[1].Step != 10 {
+		t.Errorf("unmapped = %+v", result.Unmapped)
+	}
+}
+
+const seleniumSample = `{
+  "id": "p1",
+  "version": "2.0",
+  "name": "Shop",
+  "url": "https://shop.example.com",
+  "tests": [{
+    "id": "t1",
+    "name": "Checkout",
+    "commands": [
+      {"command": "open", "target": "/cart", "targets": [], "value": ""},
+      {"command": "setWindowSize", "target": "1024x768", "targets": [], "value": ""},
+      {"command": "type", "target": "id=coupon", "targets": [["id=coupon", "id"], ["css=#coupon", "css:finder"]], "value": "SAVE10${KEY_ENTER}"},
+      {"command": "store", "target": "3", "targets": [], "value": "count"},
+      {"command": "if", "target": "${count} > 2", "targets": [], "value": ""},
+      {"command": "click", "target": "linkText=Bulk order", "targets": [], "value": ""},
+      {"command": "elseIf", "target": "${count} > 1", "targets": [], "value": ""},
+      {"command": "click", "target": "name=pair", "targets": [], "value": ""},
+      {"command": "else", "target": "", "targets": [], "value": ""},
+      {"command": "echo", "target": "single", "targets": [], "value": ""},
+      {"command": "end", "target": "", "targets": [], "value": ""},
+      {"command": "selectFrame", "target": "index=0", "targets": [], "value": ""},
+      {"command": "select", "target": "css=select.country", "targets": [], "value": "label=Canada"},
+      {"command": "selectFrame", "target": "relative=top", "targets": [], "value": ""},
+      {"command": "assertText", "target": "xpath=//h1", "targets": [], "value": "Thanks"},
+      {"command": "mouseOver", "target": "css=.menu", "targets": [], "value": ""}
+    ]
+  }]
+}`
+
+func TestImportSelenium(t *testing.T) {
+	result, err := Import([]byte(seleniumSample), "")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if result.Format != FormatSelenium || len(result.Scripts) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	script := result.Scripts[0]
+	if script.URL != "https://shop.example.com/cart" || script.Variables["count"] != "3" {
+		t.Fatalf("unexpected script: url=%q variables=%v", script.URL, script.Variables)
+	}
+
+	types := actionTypes(script.Actions)
+	want := []string{"set_viewport", "input", "keyboard", "if", "select", "assert_text"}
+	if !equal(types, want) {
+		t.Fatalf("action types = %v, want %v", types, want)
+	}
+	if in, key := script.Actions[1], script.Actions[2]; in.Selector != "#coupon" || in.Value != "SAVE10" || key.Key != "enter" {
+		t.Errorf("type with keys not split: %+v / %+v", in, key)
+	}
+
+	branch := script.Actions[3]
+	if branch.Condition.Type != models.ConditionJS || len(branch.Actions) != 1 || branch.Actions[0].XPath != "//a[normalize-space(.)='Bulk order']" {
+		t.Errorf("if branch = %+v", branch)
+	}
+	if len(branch.ElseActions) != 1 || branch.ElseActions[0].Type != "if" || branch.ElseActions[0].Actions[0].Selector != `[name="pair"]` || len(branch.ElseActions[0].ElseActions) != 0 {
+		t.Errorf("elseIf branch = %+v", branch.ElseActions)
+	}
+
+	if sel := script.Actions[4]; sel.Value != "Canada" || len(sel.FramePath) != 1 || sel.FramePath[0].Selector != "iframe" {
+		t.Errorf("select in frame = %+v", sel)
+	}
+	if assert := script.Actions[5]; assert.XPath != "//h1" || len(assert.FramePath) != 0 {
+		t.Errorf("assert after relative=top = %+v", assert)
+	}
+
+	if len(result.Unmapped) != 2 || result.Unmapped[0].Type != "echo" || result.Unmapped[1].Type != "mouseOver" {
+		t.Errorf("unmapped = %+v", result.Unmapped)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	if _, err := Import([]byte(`{"foo": 1}`), ""); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+	if _, err := Import([]byte(`not json`), ""); !errors.Is(err, ErrInvalidRecording) {
+		t.Errorf("expected ErrInvalidRecording, got %v", err)
+	}
+}
+
+func actionTypes(actions []models.ScriptAction) []string {
+	types := make([]string, len(actions))
+	for i, a := range actions {
+		types[i] = a.Type
+	}
+	return types
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
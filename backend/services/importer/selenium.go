@@ -0,0 +1,480 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// sideProject Selenium IDE 项目文件（.side）
+type sideProject struct {
+	Name  string     `json:"name"`
+	URL   string     `json:"url"` // 项目基础 URL（open 命令的相对地址基于此解析）
+	Tests []sideTest `json:"tests"`
+}
+
+// sideTest 测试用例，每个用例导入为一个脚本
+type sideTest struct {
+	Name     string        `json:"name"`
+	Commands []sideCommand `json:"commands"`
+}
+
+// sideCommand 测试命令
+type sideCommand struct {
+	Command          string     `json:"command"`
+	Target           string     `json:"target"`
+	Targets          [][]string `json:"targets"` // 候选定位器：[定位器, 策略名]
+	Value            string     `json:"value"`
+	OpensWindow      bool       `json:"opensWindow"`
+	WindowHandleName string     `json:"windowHandleName"`
+}
+
+// sideBlock 控制流命令（if / while / times / forEach / do）打开的代码块
+type sideBlock struct {
+	command string
+	action  *models.ScriptAction   // 块对应的操作（为空时块内命令直接写入外层）
+	parent  *[]models.ScriptAction // 块结束后恢复写入的操作列表
+}
+
+// seleniumConverter Selenium IDE 测试用例的转换上下文
+type seleniumConverter struct {
+	*converter
+	baseURL string
+	frames  []models.FrameHop // selectFrame 选中的 frame 路径
+	blocks  []sideBlock
+	handles map[string]int // 窗口句柄变量 -> 标签页序号（-1 表示由点击打开的新标签页）
+	tab     int            // 当前标签页序号（-1 表示未知）
+}
+
+var sideKeyPattern = regexp.MustCompile(`\$\{KEY_[A-Z0-9_]+\}`)
+
+// sideKeys Selenium 按键变量 -> keyboard 操作按键
+var sideKeys = map[string]string{
+	"${KEY_ENTER}":     "enter",
+	"${KEY_TAB}":       "tab",
+	"${KEY_BACKSPACE}": "backspace",
+	"${KEY_BKSP}":      "backspace",
+}
+
+// importSelenium 转换 Selenium IDE 项目，每个测试用例生成一个脚本
+func importSelenium(data []byte) (*Result, error) {
+	var project sideProject
+	if err := json.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecording, err)
+	}
+	if len(project.Tests) == 0 {
+		return nil, fmt.Errorf("%w: project has no tests", ErrInvalidRecording)
+	}
+
+	result := &Result{Format: FormatSelenium, Unmapped: []Issue{}}
+	for _, test := range project.Tests {
+		name := strings.TrimSpace(test.Name)
+		if name == "" {
+			name = "Selenium test"
+		}
+		description := "Imported from Selenium IDE"
+		if project.Name != "" {
+			description += " project " + project.Name
+		}
+		c := &seleniumConverter{
+			converter: newConverter(result, name, description),
+			baseURL:   project.URL,
+			handles:   make(map[string]int),
+		}
+		for i, cmd := range test.Commands {
+			c.at(i+1, cmd.Command)
+			c.convert(cmd)
+		}
+		if len(c.blocks) > 0 {
+			c.warn("%d control flow block(s) are not closed with end", len(c.blocks))
+		}
+		c.finish(project.URL)
+	}
+	return result, nil
+}
+
+// convert 转换单条命令
+func (c *seleniumConverter) convert(cmd sideCommand) {
+	if strings.HasPrefix(cmd.Command, "//") {
+		c.warn("disabled command is skipped")
+		return
+	}
+
+	switch cmd.Command {
+	case "open":
+		c.emit(models.ScriptAction{Type: "navigate", URL: resolveURL(c.baseURL, cmd.Target)})
+
+	case "click", "clickAt":
+		if a, ok := c.element("click", cmd); ok {
+			c.emit(a)
+			if cmd.OpensWindow && cmd.WindowHandleName != "" {
+				c.handles[cmd.WindowHandleName] = -1
+			}
+		}
+
+	case "check", "uncheck":
+		if a, ok := c.element("click", cmd); ok {
+			c.warn("%s is replayed as a click, which toggles the current state", cmd.Command)
+			c.emit(a)
+		}
+
+	case "type", "sendKeys":
+		c.typeText(cmd)
+
+	case "select":
+		label := cmd.Value
+		if strings.HasPrefix(label, "label=") {
+			label = strings.TrimPrefix(label, "label=")
+		} else if i := strings.Index(label, "="); i > 0 && !strings.Contains(label[:i], " ") {
+			c.unmapped("option locator %q is not supported, only labels are", cmd.Value)
+			return
+		}
+		if a, ok := c.element("select", cmd); ok {
+			a.Value = label
+			c.emit(a)
+		}
+
+	case "pause":
+		ms, err := strconv.Atoi(strings.TrimSpace(cmd.Target))
+		if err != nil {
+			c.unmapped("invalid pause duration %q", cmd.Target)
+			return
+		}
+		c.emit(models.ScriptAction{Type: "sleep", Duration: ms})
+
+	case "setWindowSize":
+		var width, height int
+		if _, err := fmt.Sscanf(cmd.Target, "%dx%d", &width, &height); err != nil {
+			c.unmapped("invalid window size %q", cmd.Target)
+			return
+		}
+		c.warn("window size is applied as the viewport size")
+		c.emit(models.ScriptAction{Type: "set_viewport", ViewportWidth: width, ViewportHeight: height})
+
+	case "waitForElementPresent", "waitForElementVisible":
+		c.waitFor(cmd, models.WaitSelectorVisible)
+	case "waitForElementNotPresent", "waitForElementNotVisible":
+		c.waitFor(cmd, models.WaitSelectorHidden)
+
+	case "assertText", "verifyText":
+		if a, ok := c.element("assert_text", cmd); ok {
+			a.AssertOperator, a.Value = "equals", cmd.Value
+			c.emit(a)
+		}
+	case "assertNotText", "verifyNotText":
+		if a, ok := c.element("assert_text", cmd); ok {
+			a.AssertOperator, a.Value = "not_equals", cmd.Value
+			c.emit(a)
+		}
+	case "assertElementPresent", "verifyElementPresent":
+		if a, ok := c.element("assert_count", cmd); ok {
+			a.AssertOperator, a.Value = ">=", "1"
+			c.emit(a)
+		}
+	case "assertElementNotPresent", "verifyElementNotPresent":
+		if a, ok := c.element("assert_count", cmd); ok {
+			a.AssertOperator, a.Value = "equals", "0"
+			c.emit(a)
+		}
+	case "assertTitle", "verifyTitle":
+		c.emit(models.ScriptAction{Type: "execute_js", JSCode: "return document.title", VariableName: "title"})
+		c.emit(models.ScriptAction{Type: "assert_variable", VariableName: "title", AssertOperator: "equals", Value: cmd.Target})
+	case "assert", "verify":
+		c.emit(models.ScriptAction{Type: "assert_variable", VariableName: cmd.Target, AssertOperator: "equals", Value: cmd.Value})
+
+	case "store":
+		if c.script.Variables == nil {
+			c.script.Variables = make(map[string]string)
+		}
+		c.script.Variables[cmd.Value] = cmd.Target
+	case "storeText":
+		if a, ok := c.element("extract_text", cmd); ok {
+			a.VariableName = cmd.Value
+			c.emit(a)
+		}
+	case "storeAttribute":
+		i := strings.LastIndex(cmd.Target, "@")
+		if i <= 0 {
+			c.unmapped("attribute locator %q has no @attribute", cmd.Target)
+			return
+		}
+		attribute := cmd.Target[i+1:]
+		cmd.Target, cmd.Targets = cmd.Target[:i], nil
+		if a, ok := c.element("extract_attribute", cmd); ok {
+			a.AttributeName, a.VariableName = attribute, cmd.Value
+			c.emit(a)
+		}
+	case "storeTitle":
+		name := cmd.Value
+		if name == "" {
+			name = cmd.Target
+		}
+		c.emit(models.ScriptAction{Type: "execute_js", JSCode: "return document.title", VariableName: name})
+	case "executeScript", "runScript":
+		c.emit(models.ScriptAction{Type: "execute_js", JSCode: cmd.Target, VariableName: cmd.Value})
+
+	case "selectFrame":
+		c.selectFrame(cmd.Target)
+	case "selectWindow":
+		c.selectWindow(cmd.Target)
+	case "storeWindowHandle":
+		c.handles[cmd.Target] = c.tab
+
+	case "if", "elseIf", "else", "end", "while", "times", "forEach", "do", "repeatIf":
+		c.controlFlow(cmd)
+
+	default:
+		c.unmapped("command has no equivalent action")
+	}
+}
+
+// element 生成针对元素的操作，无法定位时记录未转换步骤
+func (c *seleniumConverter) element(actionType string, cmd sideCommand) (models.ScriptAction, bool) {
+	a := models.ScriptAction{Type: actionType}
+	if !c.locate(&a, cmd.Target, cmd.Targets) {
+		c.unmapped("locator %q is not supported", cmd.Target)
+		return a, false
+	}
+	if len(c.frames) > 0 {
+		a.FramePath = append([]models.FrameHop(nil), c.frames...)
+	}
+	return a, true
+}
+
+// typeText 转换 type / sendKeys，文本中的 ${KEY_*} 转换为键盘操作
+func (c *seleniumConverter) typeText(cmd sideCommand) {
+	el, ok := c.element("input", cmd)
+	if !ok {
+		return
+	}
+	input := func(text string) {
+		if text != "" {
+			a := el
+			a.Value = text
+			c.emit(a)
+		}
+	}
+
+	locs := sideKeyPattern.FindAllStringIndex(cmd.Value, -1)
+	start := 0
+	for _, loc := range locs {
+		input(cmd.Value[start:loc[0]])
+		start = loc[1]
+
+		token := cmd.Value[loc[0]:loc[1]]
+		key, ok := sideKeys[token]
+		if !ok {
+			c.unmapped("key %s is not supported by keyboard actions", token)
+			continue
+		}
+		a := el
+		a.Type, a.Key = "keyboard", key
+		c.emit(a)
+	}
+	input(cmd.Value[start:])
+
+	if cmd.Command == "sendKeys" && len(locs) > 0 && sideKeyPattern.ReplaceAllString(cmd.Value, "") != "" {
+		c.warn("text is entered with input actions, which replace the current value")
+	}
+}
+
+// waitFor 转换等待元素出现 / 消失的命令
+func (c *seleniumConverter) waitFor(cmd sideCommand, waitType models.WaitType) {
+	if len(c.frames) > 0 {
+		c.unmapped("waiting for elements inside frames is not supported")
+		return
+	}
+	a, ok := c.element("wait_for", cmd)
+	if !ok {
+		return
+	}
+	timeout, _ := strconv.Atoi(strings.TrimSpace(cmd.Value))
+	a.WaitFor = &models.WaitCondition{Type: waitType, Selector: a.Selector, XPath: a.XPath, Timeout: timeout}
+	a.Selector, a.XPath = "", ""
+	c.emit(a)
+}
+
+// selectFrame 更新后续命令的 frame 路径
+func (c *seleniumConverter) selectFrame(target string) {
+	switch {
+	case target == "relative=top":
+		c.frames = nil
+	case target == "relative=parent":
+		if len(c.frames) > 0 {
+			c.frames = c.frames[:len(c.frames)-1]
+		}
+	case strings.HasPrefix(target, "index="):
+		index, err := strconv.Atoi(strings.TrimPrefix(target, "index="))
+		if err != nil {
+			c.unmapped("invalid frame index %q", target)
+			return
+		}
+		c.frames = append(c.frames, models.FrameHop{Type: models.FrameHopIframe, Selector: frameIndexSelector(index)})
+		c.warn("frame index %d is located by iframe position, check the frame path", index)
+	default:
+		var hop models.ScriptAction
+		if !c.locate(&hop, target, nil) || hop.Selector == "" {
+			c.unmapped("frame locator %q is not supported, only CSS, id and name locators are", target)
+			return
+		}
+		c.frames = append(c.frames, models.FrameHop{Type: models.FrameHopIframe, Selector: hop.Selector})
+	}
+}
+
+// selectWindow 转换窗口切换命令
+func (c *seleniumConverter) selectWindow(target string) {
+	if strings.HasPrefix(target, "tab=") {
+		index, err := strconv.Atoi(strings.TrimPrefix(target, "tab="))
+		if err != nil {
+			c.unmapped("invalid tab index %q", target)
+			return
+		}
+		c.emit(models.ScriptAction{Type: "switch_tab", Value: strconv.Itoa(index)})
+		c.tab = index
+		c.frames = nil
+		return
+	}
+
+	name := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(target, "handle="), "${"), "}")
+	index, ok := c.handles[name]
+	switch {
+	case !ok:
+		c.unmapped("window handle %q was not stored by this test", target)
+		return
+	case index < 0:
+		c.emit(models.ScriptAction{Type: "switch_active_tab"})
+	default:
+		c.emit(models.ScriptAction{Type: "switch_tab", Value: strconv.Itoa(index)})
+	}
+	c.tab = index
+	c.frames = nil
+}
+
+// controlFlow 转换控制流命令，块内命令写入对应操作的嵌套列表
+func (c *seleniumConverter) controlFlow(cmd sideCommand) {
+	var top *sideBlock
+	if len(c.blocks) > 0 {
+		top = &c.blocks[len(c.blocks)-1]
+	}
+
+	switch cmd.Command {
+	case "if":
+		c.open(cmd.Command, models.ScriptAction{Type: "if", Condition: jsCondition(cmd.Target)})
+	case "while":
+		c.open(cmd.Command, models.ScriptAction{Type: "loop", LoopCondition: jsCondition(cmd.Target)})
+	case "times":
+		count, err := strconv.Atoi(strings.TrimSpace(cmd.Target))
+		if err != nil {
+			c.unmapped("times count %q must be a number, the body is imported once", cmd.Target)
+			c.blocks = append(c.blocks, sideBlock{command: cmd.Command, parent: c.out})
+			return
+		}
+		c.open(cmd.Command, models.ScriptAction{Type: "loop", LoopCount: count})
+	case "forEach":
+		c.open(cmd.Command, models.ScriptAction{Type: "foreach", ListVariable: cmd.Target, ItemVariable: cmd.Value})
+	case "do":
+		c.unmapped("do/repeatIf loops are not supported, the body is imported once")
+		c.blocks = append(c.blocks, sideBlock{command: cmd.Command, parent: c.out})
+
+	case "else", "elseIf":
+		if top == nil || top.command != "if" {
+			c.unmapped("%s without matching if", cmd.Command)
+			return
+		}
+		c.out = &top.action.ElseActions
+		if cmd.Command == "elseIf" {
+			// elseIf 转换为 else 分支中嵌套的 if，由同一个 end 结束
+			c.emit(models.ScriptAction{Type: "if", Condition: jsCondition(cmd.Target)})
+			top.action = &top.action.ElseActions[len(top.action.ElseActions)-1]
+			c.out = &top.action.Actions
+		}
+
+	case "end", "repeatIf":
+		if top == nil || (cmd.Command == "repeatIf") != (top.command == "do") {
+			c.unmapped("%s without matching block", cmd.Command)
+			return
+		}
+		c.out = top.parent
+		c.blocks = c.blocks[:len(c.blocks)-1]
+	}
+}
+
+// open 写入控制流操作并开始写入其嵌套列表
+func (c *seleniumConverter) open(command string, a models.ScriptAction) {
+	parent := c.out
+	c.emit(a)
+	action := &(*parent)[len(*parent)-1]
+	c.blocks = append(c.blocks, sideBlock{command: command, action: action, parent: parent})
+	c.out = &action.Actions
+}
+
+// jsCondition Selenium 控制流条件为 JS 表达式
+func jsCondition(expression string) *models.ActionCondition {
+	return &models.ActionCondition{Type: models.ConditionJS, Expression: expression}
+}
+
+// locate 将 Selenium 定位器转换为 CSS / XPath 选择器（主定位器不支持时尝试候选定位器）
+func (c *seleniumConverter) locate(a *models.ScriptAction, target string, targets [][]string) bool {
+	candidates := []string{target}
+	for _, t := range targets {
+		if len(t) > 0 && (len(t) < 2 || t[1] != "xpath:position") {
+			candidates = append(candidates, t[0])
+		}
+	}
+	for _, candidate := range candidates {
+		css, xpath := sideLocator(candidate)
+		if css == "" && xpath == "" {
+			continue
+		}
+		a.Selector, a.XPath = css, xpath
+		return true
+	}
+	return false
+}
+
+// sideLocator 转换单个 Selenium 定位器
+func sideLocator(locator string) (css, xpath string) {
+	strategy, value := "", locator
+	if i := strings.Index(locator, "="); i > 0 {
+		strategy, value = locator[:i], locator[i+1:]
+	}
+	switch strategy {
+	case "id":
+		return idSelector(value), ""
+	case "name":
+		return attrSelector("name", value), ""
+	case "css":
+		return value, ""
+	case "xpath":
+		return "", value
+	case "link", "linkText":
+		return "", "//a[normalize-space(.)=" + xpathLiteral(value) + "]"
+	case "partialLinkText":
+		return "", "//a[contains(normalize-space(.), " + xpathLiteral(value) + ")]"
+	}
+	if strings.HasPrefix(locator, "/") || strings.HasPrefix(locator, "(") {
+		return "", locator
+	}
+	return "", ""
+}
+
+// resolveURL 将 open 命令的相对地址解析为绝对地址
+func resolveURL(base, target string) string {
+	if base == "" {
+		return target
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return target
+	}
+	t, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	return b.ResolveReference(t).String()
+}
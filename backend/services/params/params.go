@@ -0,0 +1,283 @@
+package params
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+)
+
+// DefaultDateFormat date 类型参数的默认格式
+const DefaultDateFormat = "2006-01-02"
+
+var (
+	ErrInvalidDefinition = errors.New("invalid parameter definition")
+	ErrInvalidParams     = errors.New("invalid script parameters")
+)
+
+// 参数错误代码
+const (
+	CodeRequired = "required" // 必填参数没有值
+	CodeType     = "type"     // 值与参数类型不符
+	CodeEnum     = "enum"     // 值不在可选范围内
+	CodePattern  = "pattern"  // 值不匹配正则表达式
+	CodeFile     = "file"     // 文件不存在或不是普通文件
+)
+
+// FieldError 单个参数的校验错误
+type FieldError struct {
+	Name    string `json:"name"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError 参数校验失败（包含所有出错的参数）
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Message
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidParams, strings.Join(messages, "; "))
+}
+
+// Unwrap 支持 errors.Is(err, ErrInvalidParams)
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidParams
+}
+
+// FieldErrors 提取参数校验错误（不是参数校验错误时返回 nil）
+func FieldErrors(err error) []FieldError {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return verr.Fields
+	}
+	return nil
+}
+
+// ValidateDefinitions 检查参数定义（名称、类型、正则、枚举值、日期格式和默认值）
+func ValidateDefinitions(defs []models.ScriptParameter) error {
+	seen := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		if def.Name == "" {
+			return fmt.Errorf("%w: parameter name is required", ErrInvalidDefinition)
+		}
+		if seen[def.Name] {
+			return fmt.Errorf("%w: duplicate parameter %q", ErrInvalidDefinition, def.Name)
+		}
+		seen[def.Name] = true
+
+		switch typeOf(def) {
+		case models.ParamString, models.ParamNumber, models.ParamBoolean, models.ParamDate, models.ParamFile, models.ParamList:
+		case models.ParamEnum:
+			if len(def.Enum) == 0 {
+				return fmt.Errorf("%w: enum parameter %q has no values", ErrInvalidDefinition, def.Name)
+			}
+		default:
+			return fmt.Errorf("%w: parameter %q has unknown type %q", ErrInvalidDefinition, def.Name, def.Type)
+		}
+		if def.Pattern != "" {
+			if _, err := regexp.Compile(def.Pattern); err != nil {
+				return fmt.Errorf("%w: parameter %q has invalid pattern: %v", ErrInvalidDefinition, def.Name, err)
+			}
+		}
+		if def.Default != "" && typeOf(def) != models.ParamFile {
+			// 文件可能在回放时才存在，不检查默认文件路径
+			if _, ferr := normalize(def, def.Default); ferr != nil {
+				return fmt.Errorf("%w: parameter %q has invalid default: %s", ErrInvalidDefinition, def.Name, ferr.Message)
+			}
+		}
+	}
+	return nil
+}
+
+// Resolve 按参数定义校验并规范化参数值，返回回放使用的变量
+// 取值顺序：传入值 > 参数默认值 > 脚本预设变量；未定义参数的传入值和预设变量原样保留
+func Resolve(script *models.Script, values map[string]interface{}) (map[string]string, error) {
+	vars := make(map[string]string, len(script.Variables)+len(values))
+	for k, v := range script.Variables {
+		vars[k] = v
+	}
+	for k, v := range values {
+		vars[k] = interpolate.Stringify(v)
+	}
+	if len(script.Parameters) == 0 {
+		return vars, nil
+	}
+	if err := ValidateDefinitions(script.Parameters); err != nil {
+		return nil, err
+	}
+
+	var fields []FieldError
+	for _, def := range script.Parameters {
+		raw, provided := values[def.Name]
+		var value string
+		switch {
+		case provided && raw != nil:
+			// 列表参数可以直接传入数组
+			if list, ok := raw.([]interface{}); ok && typeOf(def) == models.ParamList {
+				normalized, ferr := normalizeList(def, list)
+				if ferr != nil {
+					fields = append(fields, *ferr)
+					continue
+				}
+				vars[def.Name] = normalized
+				continue
+			}
+			value = interpolate.Stringify(raw)
+		case def.Default != "":
+			value = def.Default
+		default:
+			value = script.Variables[def.Name]
+		}
+
+		if strings.TrimSpace(value) == "" {
+			if def.Required {
+				fields = append(fields, FieldError{Name: def.Name, Code: CodeRequired, Message: fmt.Sprintf("parameter %q is required", def.Name)})
+			} else {
+				vars[def.Name] = ""
+			}
+			continue
+		}
+
+		normalized, ferr := normalize(def, value)
+		if ferr != nil {
+			fields = append(fields, *ferr)
+			continue
+		}
+		vars[def.Name] = normalized
+	}
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+	return vars, nil
+}
+
+// ResolveStrings 使用字符串参数（HTTP 请求、定时任务、数据集行）调用 Resolve
+func ResolveStrings(script *models.Script, values map[string]string) (map[string]string, error) {
+	converted := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		converted[k] = v
+	}
+	return Resolve(script, converted)
+}
+
+func typeOf(def models.ScriptParameter) models.ParameterType {
+	if def.Type == "" {
+		return models.ParamString
+	}
+	return def.Type
+}
+
+// normalize 按类型校验并规范化单个值
+func normalize(def models.ScriptParameter, value string) (string, *FieldError) {
+	fail := func(code, format string, args ...interface{}) (string, *FieldError) {
+		return "", &FieldError{Name: def.Name, Code: code, Message: fmt.Sprintf("parameter %q ", def.Name) + fmt.Sprintf(format, args...)}
+	}
+
+	switch typeOf(def) {
+	case models.ParamNumber:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fail(CodeType, "must be a number, got %q", value)
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	case models.ParamBoolean:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fail(CodeType, "must be true or false, got %q", value)
+		}
+		return strconv.FormatBool(b), nil
+	case models.ParamEnum:
+		if !slices.Contains(def.Enum, value) {
+			return fail(CodeEnum, "must be one of %s, got %q", strings.Join(def.Enum, ", "), value)
+		}
+		return value, nil
+	case models.ParamDate:
+		layout := def.Format
+		if layout == "" {
+			layout = DefaultDateFormat
+		}
+		t, err := time.Parse(layout, strings.TrimSpace(value))
+		if err != nil {
+			return fail(CodeType, "must be a date in format %s, got %q", layout, value)
+		}
+		return t.Format(layout), nil
+	case models.ParamList:
+		return normalizeList(def, splitList(value))
+	case models.ParamFile:
+		if ferr := matchPattern(def, value); ferr != nil {
+			return "", ferr
+		}
+		info, err := os.Stat(value)
+		if err != nil || info.IsDir() {
+			return fail(CodeFile, "must be an existing file, got %q", value)
+		}
+		return value, nil
+	}
+
+	if ferr := matchPattern(def, value); ferr != nil {
+		return "", ferr
+	}
+	return value, nil
+}
+
+// normalizeList 校验列表的每一项并编码为 JSON 数组
+func normalizeList(def models.ScriptParameter, list []interface{}) (string, *FieldError) {
+	for _, item := range list {
+		if ferr := matchPattern(def, interpolate.Stringify(item)); ferr != nil {
+			return "", ferr
+		}
+	}
+	if def.Required && len(list) == 0 {
+		return "", &FieldError{Name: def.Name, Code: CodeRequired, Message: fmt.Sprintf("parameter %q is required", def.Name)}
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return "", &FieldError{Name: def.Name, Code: CodeType, Message: fmt.Sprintf("parameter %q must be a list: %v", def.Name, err)}
+	}
+	return string(data), nil
+}
+
+func matchPattern(def models.ScriptParameter, value string) *FieldError {
+	if def.Pattern == "" {
+		return nil
+	}
+	// 与 JSON Schema 的 pattern 一致：匹配值的任意部分即可，需要完整匹配时使用 ^...$
+	if !regexp.MustCompile(def.Pattern).MatchString(value) {
+		return &FieldError{Name: def.Name, Code: CodePattern, Message: fmt.Sprintf("parameter %q does not match pattern %s, got %q", def.Name, def.Pattern, value)}
+	}
+	return nil
+}
+
+// splitList 解析字符串形式的列表：JSON 数组，或按换行/逗号分隔（与 foreach 的列表变量规则一致）
+func splitList(value string) []interface{} {
+	value = strings.TrimSpace(value)
+	var list []interface{}
+	if strings.HasPrefix(value, "[") && json.Unmarshal([]byte(value), &list) == nil {
+		return list
+	}
+
+	sep := ","
+	if strings.Contains(value, "\n") {
+		sep = "\n"
+	}
+	list = []interface{}{}
+	for _, part := range strings.Split(value, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
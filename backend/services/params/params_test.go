@@ -0,0 +1,148 @@
+package params
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func testScript(t *testing.T) *models.Script {
+	t.Helper()
+	return &models.Script{
+		Variables: map[string]string{"region": "us", "note": "preset"},
+		Parameters: []models.ScriptParameter{
+			{Name: "keyword", Required: true, Pattern: `^\w+$`},
+			{Name: "pages", Type: models.ParamNumber, Default: "1"},
+			{Name: "headless", Type: models.ParamBoolean},
+			{Name: "region", Type: models.ParamEnum, Enum: []string{"us", "eu"}},
+			{Name: "since", Type: models.ParamDate, Format: "02/01/2006"},
+			{Name: "tags", Type: models.ParamList, Pattern: `^[a-z]+$`},
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	script := testScript(t)
+	vars, err := Resolve(script, map[string]interface{}{
+		"keyword":  "laptop",
+		"pages":    float64(3),
+		"headless": "1",
+		"since":    "05/03/2024",
+		"tags":     []interface{}{"a", "b"},
+		"extra":    "kept",
+	})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	want := map[string]string{
+		"keyword":  "laptop",
+		"pages":    "3",
+		"headless": "true",
+		"region":   "us", // 预设变量
+		"since":    "05/03/2024",
+		"tags":     `["a","b"]`,
+		"note":     "preset",
+		"extra":    "kept",
+	}
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("vars = %v\nwant %v", vars, want)
+	}
+
+	// 字符串参数：默认值、逗号分隔的列表
+	vars, err = ResolveStrings(script, map[string]string{"keyword": "tv", "tags": "x, y"})
+	if err != nil {
+		t.Fatalf("ResolveStrings: %v", err)
+	}
+	if vars["pages"] != "1" || vars["tags"] != `["x","y"]` || vars["headless"] != "" {
+		t.Errorf("vars = %v", vars)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	script := testScript(t)
+	_, err := ResolveStrings(script, map[string]string{
+		"pages":    "many",
+		"headless": "maybe",
+		"region":   "asia",
+		"since":    "2024-03-05",
+		"tags":     "ok,NotOk",
+	})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+	var codes []string
+	for _, f := range FieldErrors(err) {
+		codes = append(codes, f.Name+":"+f.Code)
+	}
+	want := []string{"keyword:required", "pages:type", "headless:type", "region:enum", "since:type", "tags:pattern"}
+	if !reflect.DeepEqual(codes, want) {
+		t.Errorf("errors = %v, want %v", codes, want)
+	}
+
+	if _, err := ResolveStrings(script, map[string]string{"keyword": "two words"}); FieldErrors(err)[0].Code != CodePattern {
+		t.Errorf("expected pattern error, got %v", err)
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(file, []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	upload := &models.Script{Parameters: []models.ScriptParameter{{Name: "file", Type: models.ParamFile, Pattern: `\.csv$`}}}
+	if _, err := ResolveStrings(upload, map[string]string{"file": file}); err != nil {
+		t.Errorf("existing file rejected: %v", err)
+	}
+	if _, err := ResolveStrings(upload, map[string]string{"file": dir + "/missing.csv"}); FieldErrors(err)[0].Code != CodeFile {
+		t.Errorf("expected file error, got %v", err)
+	}
+}
+
+func TestValidateDefinitions(t *testing.T) {
+	tests := []struct {
+		name string
+		defs []models.ScriptParameter
+	}{
+		{"empty name", []models.ScriptParameter{{Type: models.ParamString}}},
+		{"duplicate", []models.ScriptParameter{{Name: "a"}, {Name: "a"}}},
+		{"unknown type", []models.ScriptParameter{{Name: "a", Type: "json"}}},
+		{"enum without values", []models.ScriptParameter{{Name: "a", Type: models.ParamEnum}}},
+		{"bad pattern", []models.ScriptParameter{{Name: "a", Pattern: "("}}},
+		{"bad default", []models.ScriptParameter{{Name: "a", Type: models.ParamNumber, Default: "x"}}},
+	}
+	for _, tt := range tests {
+		if err := ValidateDefinitions(tt.defs); !errors.Is(err, ErrInvalidDefinition) {
+			t.Errorf("%s: expected ErrInvalidDefinition, got %v", tt.name, err)
+		}
+	}
+	if err := ValidateDefinitions(testScript(t).Parameters); err != nil {
+		t.Errorf("valid definitions rejected: %v", err)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema(testScript(t).Parameters)
+	props := schema["properties"].(map[string]interface{})
+
+	if !reflect.DeepEqual(schema["required"], []interface{}{"keyword"}) {
+		t.Errorf("required = %v", schema["required"])
+	}
+	checks := map[string]map[string]interface{}{
+		"keyword":  {"type": "string", "pattern": `^\w+$`},
+		"pages":    {"type": "number", "default": float64(1)},
+		"headless": {"type": "boolean"},
+		"region":   {"type": "string", "enum": []interface{}{"us", "eu"}},
+		"tags":     {"type": "array", "items": map[string]interface{}{"type": "string", "pattern": "^[a-z]+$"}},
+	}
+	for name, want := range checks {
+		if !reflect.DeepEqual(props[name], want) {
+			t.Errorf("%s = %v, want %v", name, props[name], want)
+		}
+	}
+	if since := props["since"].(map[string]interface{}); since["description"] != "Date in Go layout 02/01/2006" {
+		t.Errorf("since = %v", since)
+	}
+}
@@ -0,0 +1,97 @@
+package params
+
+import (
+	"strconv"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// Schema 由参数定义生成 JSON Schema（用作 MCP 工具的输入 schema）
+func Schema(defs []models.ScriptParameter) map[string]interface{} {
+	properties := make(map[string]interface{}, len(defs))
+	required := make([]interface{}, 0)
+	for _, def := range defs {
+		properties[def.Name] = propertySchema(def)
+		// 有默认值的参数调用方可以不传
+		if def.Required && def.Default == "" {
+			required = append(required, def.Name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func propertySchema(def models.ScriptParameter) map[string]interface{} {
+	prop := map[string]interface{}{}
+	if def.Description != "" {
+		prop["description"] = def.Description
+	}
+
+	switch typeOf(def) {
+	case models.ParamNumber:
+		prop["type"] = "number"
+		if f, err := strconv.ParseFloat(def.Default, 64); err == nil {
+			prop["default"] = f
+		}
+		return prop
+	case models.ParamBoolean:
+		prop["type"] = "boolean"
+		if b, err := strconv.ParseBool(def.Default); err == nil {
+			prop["default"] = b
+		}
+		return prop
+	case models.ParamList:
+		items := map[string]interface{}{"type": "string"}
+		if def.Pattern != "" {
+			items["pattern"] = def.Pattern
+		}
+		prop["type"] = "array"
+		prop["items"] = items
+		if def.Default != "" {
+			prop["default"] = splitList(def.Default)
+		}
+		return prop
+	case models.ParamEnum:
+		enum := make([]interface{}, len(def.Enum))
+		for i, v := range def.Enum {
+			enum[i] = v
+		}
+		prop["enum"] = enum
+	case models.ParamDate:
+		layout := def.Format
+		if layout == "" {
+			layout = DefaultDateFormat
+		}
+		// JSON Schema 的 date 格式为 YYYY-MM-DD，其他格式在描述中说明
+		if layout == DefaultDateFormat {
+			prop["format"] = "date"
+		} else {
+			prop["description"] = appendSentence(def.Description, "Date in Go layout "+layout)
+		}
+	case models.ParamFile:
+		prop["description"] = appendSentence(def.Description, "Path to an existing local file")
+	}
+
+	prop["type"] = "string"
+	if def.Pattern != "" {
+		prop["pattern"] = def.Pattern
+	}
+	if def.Default != "" {
+		prop["default"] = def.Default
+	}
+	return prop
+}
+
+func appendSentence(description, sentence string) string {
+	if description == "" {
+		return sentence
+	}
+	return description + ". " + sentence
+}
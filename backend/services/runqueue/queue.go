@@ -0,0 +1,243 @@
+package runqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 回放来源（同一来源内按提交顺序执行，不同来源之间轮流分配名额）
+const (
+	SourceAPI       = "api"
+	SourceScheduler = "scheduler"
+	SourceMCP       = "mcp"
+	SourceDataRun   = "data_run"
+)
+
+// AnyInstance 不指定实例，由队列分配到当前负载最低的运行中实例
+const AnyInstance = "*"
+
+// DefaultLimit 每个浏览器实例默认同时执行的回放数
+const DefaultLimit = 3
+
+type sourceKey struct{}
+
+// WithSource 标记回放来源，用于公平排队
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SourceFromContext 获取回放来源（未标记时为 api）
+func SourceFromContext(ctx context.Context) string {
+	if source, _ := ctx.Value(sourceKey{}).(string); source != "" {
+		return source
+	}
+	return SourceAPI
+}
+
+type runTimeoutKey struct{}
+
+// WithRunTimeout 设置回放的执行超时，从取得实例名额后开始计时（排队等待时间不计入）
+func WithRunTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, runTimeoutKey{}, timeout)
+}
+
+// RunTimeoutFromContext 获取回放的执行超时（未设置时为 0，表示不限制）
+func RunTimeoutFromContext(ctx context.Context) time.Duration {
+	timeout, _ := ctx.Value(runTimeoutKey{}).(time.Duration)
+	return timeout
+}
+
+// ticket 排队中的回放
+type ticket struct {
+	instanceID string      // 请求的实例 ID（AnyInstance 表示任意实例）
+	assigned   chan string // 分配到的实例 ID
+}
+
+// Queue 回放队列：限制每个实例同时执行的回放数，名额不足时排队，
+// 多个来源同时排队时轮流分配，避免批量任务占满实例后其他来源长时间等待
+type Queue struct {
+	limit     func(instanceID string) int // 实例的并发上限（<= 0 时使用 DefaultLimit）
+	instances func() []string             // 可分配的运行中实例（用于 AnyInstance）
+
+	mu      sync.Mutex
+	active  map[string]int       // 实例 ID -> 执行中的回放数
+	waiting map[string][]*ticket // 来源 -> 排队中的回放（按提交顺序）
+	sources []string             // 有回放排队的来源（轮转顺序）
+	next    int                  // 下一次优先分配的来源位置
+}
+
+// New 创建回放队列
+func New(limit func(instanceID string) int, instances func() []string) *Queue {
+	return &Queue{
+		limit:     limit,
+		instances: instances,
+		active:    make(map[string]int),
+		waiting:   make(map[string][]*ticket),
+	}
+}
+
+// Acquire 申请在实例上执行回放的名额，名额不足时阻塞排队，返回分配到的实例 ID。
+// ctx 取消时退出排队并返回取消原因；执行结束后需调用 Release 归还名额
+func (q *Queue) Acquire(ctx context.Context, source, instanceID string) (string, error) {
+	t := &ticket{instanceID: instanceID, assigned: make(chan string, 1)}
+
+	q.mu.Lock()
+	if len(q.waiting[source]) == 0 {
+		q.sources = append(q.sources, source)
+	}
+	q.waiting[source] = append(q.waiting[source], t)
+	q.dispatchLocked()
+	q.mu.Unlock()
+
+	select {
+	case id := <-t.assigned:
+		return id, nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	removed := q.removeLocked(source, t)
+	q.mu.Unlock()
+	if !removed {
+		// 取消的同时已分配到名额，归还
+		q.Release(<-t.assigned)
+	}
+	return "", context.Cause(ctx)
+}
+
+// Release 归还名额并分配给排队中的回放
+func (q *Queue) Release(instanceID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.active[instanceID] <= 1 {
+		delete(q.active, instanceID)
+	} else {
+		q.active[instanceID]--
+	}
+	q.dispatchLocked()
+}
+
+// Dispatch 重新分配名额（实例启动或并发上限修改后调用）
+func (q *Queue) Dispatch() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.dispatchLocked()
+}
+
+// Active 实例上执行中的回放数
+func (q *Queue) Active(instanceID string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.active[instanceID]
+}
+
+// Waiting 排队中的回放数
+func (q *Queue) Waiting() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, tickets := range q.waiting {
+		n += len(tickets)
+	}
+	return n
+}
+
+// dispatchLocked 从上次分配的下一个来源开始轮流分配，直到没有可分配的回放
+func (q *Queue) dispatchLocked() {
+	for len(q.sources) > 0 {
+		placed := false
+		for n := 0; n < len(q.sources); n++ {
+			i := (q.next + n) % len(q.sources)
+			source := q.sources[i]
+			if !q.placeLocked(source) {
+				continue
+			}
+			placed = true
+			q.next = i + 1
+			if len(q.waiting[source]) == 0 {
+				q.dropSourceLocked(i)
+			}
+			break
+		}
+		if !placed {
+			return
+		}
+	}
+}
+
+// placeLocked 为来源中第一个能分配到名额的回放分配实例
+// （同一来源中前面的回放等待的实例已满时，后面指定其他实例的回放可以先执行）
+func (q *Queue) placeLocked(source string) bool {
+	tickets := q.waiting[source]
+	for i, t := range tickets {
+		id, ok := q.pickLocked(t.instanceID)
+		if !ok {
+			continue
+		}
+		q.waiting[source] = append(tickets[:i:i], tickets[i+1:]...)
+		q.active[id]++
+		t.assigned <- id
+		return true
+	}
+	return false
+}
+
+// pickLocked 选择实例：指定实例时检查名额，AnyInstance 时选择执行中回放最少且有名额的实例
+func (q *Queue) pickLocked(instanceID string) (string, bool) {
+	if instanceID != AnyInstance {
+		return instanceID, q.active[instanceID] < q.limitOf(instanceID)
+	}
+	best, found := "", false
+	for _, id := range q.instances() {
+		if q.active[id] >= q.limitOf(id) {
+			continue
+		}
+		if !found || q.active[id] < q.active[best] {
+			best, found = id, true
+		}
+	}
+	return best, found
+}
+
+func (q *Queue) limitOf(instanceID string) int {
+	if q.limit != nil {
+		if n := q.limit(instanceID); n > 0 {
+			return n
+		}
+	}
+	return DefaultLimit
+}
+
+// removeLocked 移除排队中的回放（已分配时返回 false）
+func (q *Queue) removeLocked(source string, t *ticket) bool {
+	tickets := q.waiting[source]
+	for i, queued := range tickets {
+		if queued != t {
+			continue
+		}
+		q.waiting[source] = append(tickets[:i:i], tickets[i+1:]...)
+		if len(q.waiting[source]) == 0 {
+			for j, s := range q.sources {
+				if s == source {
+					q.dropSourceLocked(j)
+					break
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// dropSourceLocked 移除没有排队回放的来源，保持轮转位置不变
+func (q *Queue) dropSourceLocked(i int) {
+	delete(q.waiting, q.sources[i])
+	q.sources = append(q.sources[:i], q.sources[i+1:]...)
+	if i < q.next {
+		q.next--
+	}
+	if len(q.sources) == 0 || q.next >= len(q.sources) {
+		q.next = 0
+	}
+}
@@ -0,0 +1,172 @@
+package runqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// acquireAsync 在后台排队，分配到的实例通过通道返回
+func acquireAsync(q *Queue, source, instanceID string) <-chan string {
+	ch := make(chan string, 1)
+	go func() {
+		id, err := q.Acquire(context.Background(), source, instanceID)
+		if err != nil {
+			id = "error: " + err.Error()
+		}
+		ch <- id
+	}()
+	return ch
+}
+
+// waitQueued 等待指定数量的回放进入排队
+func waitQueued(t *testing.T, q *Queue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for q.Waiting() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("waiting = %d, want %d", q.Waiting(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func receive(t *testing.T, ch <-chan string) string {
+	t.Helper()
+	select {
+	case id := <-ch:
+		return id
+	case <-time.After(2 * time.Second):
+		t.Fatal("run was not dispatched")
+		return ""
+	}
+}
+
+func TestQueueLimitAndFairness(t *testing.T) {
+	q := New(func(string) int { return 1 }, nil)
+	ctx := context.Background()
+
+	if id, err := q.Acquire(ctx, SourceAPI, "a"); err != nil || id != "a" {
+		t.Fatalf("Acquire = %q, %v", id, err)
+	}
+
+	// 批量任务先排队 3 个，MCP 随后排队 1 个
+	batch := make([]<-chan string, 3)
+	for i := range batch {
+		batch[i] = acquireAsync(q, SourceDataRun, "a")
+		waitQueued(t, q, i+1)
+	}
+	mcp := acquireAsync(q, SourceMCP, "a")
+	waitQueued(t, q, 4)
+
+	// 名额释放后轮流分配：data_run 第一个，然后是 MCP，而不是 data_run 全部执行完
+	q.Release("a")
+	receive(t, batch[0])
+	q.Release("a")
+	receive(t, mcp)
+	q.Release("a")
+	receive(t, batch[1])
+	if q.Waiting() != 1 || q.Active("a") != 1 {
+		t.Errorf("waiting = %d, active = %d", q.Waiting(), q.Active("a"))
+	}
+	q.Release("a")
+	receive(t, batch[2])
+	q.Release("a")
+	if q.Active("a") != 0 {
+		t.Errorf("active = %d after all released", q.Active("a"))
+	}
+}
+
+func TestQueueAnyInstance(t *testing.T) {
+	var mu sync.Mutex
+	running := []string{"a", "b"}
+	q := New(func(id string) int {
+		if id == "b" {
+			return 2
+		}
+		return 1
+	}, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), running...)
+	})
+	ctx := context.Background()
+
+	got := map[string]int{}
+	for i := 0; i < 3; i++ {
+		id, err := q.Acquire(ctx, SourceAPI, AnyInstance)
+		if err != nil {
+			t.Fatalf("Acquire: %v", err)
+		}
+		got[id]++
+	}
+	if got["a"] != 1 || got["b"] != 2 {
+		t.Fatalf("dispatched = %v, want a:1 b:2", got)
+	}
+
+	// 所有实例已满，新启动的实例在 Dispatch 后接收排队的回放
+	pending := acquireAsync(q, SourceScheduler, AnyInstance)
+	waitQueued(t, q, 1)
+	mu.Lock()
+	running = append(running, "c")
+	mu.Unlock()
+	q.Dispatch()
+	if id := receive(t, pending); id != "c" {
+		t.Errorf("dispatched to %q, want c", id)
+	}
+
+	// 指定实例已满时不影响同一来源中指定其他实例的回放
+	blocked := acquireAsync(q, SourceAPI, "a")
+	waitQueued(t, q, 1)
+	if id, err := q.Acquire(ctx, SourceAPI, "d"); err != nil || id != "d" {
+		t.Errorf("Acquire d = %q, %v", id, err)
+	}
+	q.Release("a")
+	if id := receive(t, blocked); id != "a" {
+		t.Errorf("dispatched to %q, want a", id)
+	}
+}
+
+func TestQueueCancel(t *testing.T) {
+	q := New(func(string) int { return 1 }, nil)
+	if _, err := q.Acquire(context.Background(), SourceAPI, "a"); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	cause := errors.New("cancelled by user")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := q.Acquire(ctx, SourceMCP, "a")
+		done <- err
+	}()
+	waitQueued(t, q, 1)
+	cancel(cause)
+	if err := <-done; !errors.Is(err, cause) {
+		t.Errorf("Acquire error = %v, want %v", err, cause)
+	}
+	if q.Waiting() != 0 {
+		t.Errorf("cancelled run still queued")
+	}
+
+	// 取消的回放不占用名额
+	q.Release("a")
+	if id, err := q.Acquire(context.Background(), SourceAPI, "a"); err != nil || id != "a" {
+		t.Errorf("Acquire after cancel = %q, %v", id, err)
+	}
+
+	if got := SourceFromContext(WithSource(context.Background(), SourceScheduler)); got != SourceScheduler {
+		t.Errorf("SourceFromContext = %q", got)
+	}
+	if got := SourceFromContext(context.Background()); got != SourceAPI {
+		t.Errorf("default source = %q", got)
+	}
+	if got := RunTimeoutFromContext(WithRunTimeout(context.Background(), time.Minute)); got != time.Minute {
+		t.Errorf("RunTimeoutFromContext = %v", got)
+	}
+	if got := RunTimeoutFromContext(context.Background()); got != 0 {
+		t.Errorf("default run timeout = %v", got)
+	}
+}
@@ -0,0 +1,310 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/pkg/secretbox"
+)
+
+// RefPrefix 占位符中引用密钥的前缀：${secret:name}
+const RefPrefix = "secret:"
+
+// Mask 脱敏后替换密钥值的文本
+const Mask = "******"
+
+// minRedactLength 参与脱敏的最短密钥值（过短的值会误伤普通文本）
+const minRedactLength = 4
+
+var (
+	ErrDisabled    = errors.New("secrets store is not configured, set secrets.key or BROWSERWING_SECRETS_KEY")
+	ErrNotFound    = errors.New("secret not found")
+	ErrInvalidName = errors.New("invalid secret name")
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Store 密钥存储
+type Store interface {
+	SaveSecret(secret *models.Secret) error
+	GetSecret(name string) (*models.Secret, error)
+	ListSecrets() ([]*models.Secret, error)
+	DeleteSecret(name string) error
+}
+
+// Manager 管理加密保存的密钥：回放时解析 ${secret:name}，并从日志和执行记录中脱敏密钥值
+// 所有方法对 nil 接收者安全（未启用密钥时不解析也不脱敏）
+type Manager struct {
+	store Store
+	box   *secretbox.Box // 未配置密钥时为空
+
+	mu           sync.RWMutex
+	values       map[string]string // 名称 -> 明文
+	replacer     *strings.Replacer // 明文 -> Mask
+	jsonReplacer *strings.Replacer // JSON 转义后的明文 -> Mask
+}
+
+// NewManager 创建密钥管理器并解密已保存的密钥（box 为空时密钥功能不可用）
+func NewManager(store Store, box *secretbox.Box) (*Manager, error) {
+	m := &Manager{store: store, box: box, values: make(map[string]string)}
+	if box == nil {
+		return m, nil
+	}
+	list, err := store.ListSecrets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+	for _, secret := range list {
+		value, err := box.Open(secret.Value)
+		if err != nil {
+			// 密钥更换后旧值无法解密，引用时报错，重新设置即可
+			logger.Warn(context.Background(), "Failed to decrypt secret %s: %v", secret.Name, err)
+			continue
+		}
+		m.values[secret.Name] = value
+
+		// 旧版本格式使用固定盐值，重新加密
+		if secretbox.IsLegacy(secret.Value) {
+			if err := m.reseal(secret, value); err != nil {
+				logger.Warn(context.Background(), "Failed to re-encrypt secret %s: %v", secret.Name, err)
+			}
+		}
+	}
+	m.rebuild()
+	return m, nil
+}
+
+// reseal 使用当前格式重新加密并保存密钥
+func (m *Manager) reseal(secret *models.Secret, value string) error {
+	sealed, err := m.box.Seal(value)
+	if err != nil {
+		return err
+	}
+	updated := *secret
+	updated.Value = sealed
+	return m.store.SaveSecret(&updated)
+}
+
+// Enabled 是否已配置加密密钥
+func (m *Manager) Enabled() bool {
+	return m != nil && m.box != nil
+}
+
+// Set 加密保存密钥（已存在时更新值和描述）
+func (m *Manager) Set(name, value, description string) (*models.Secret, error) {
+	if !m.Enabled() {
+		return nil, ErrDisabled
+	}
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: %q (letters, digits, _, - and . only)", ErrInvalidName, name)
+	}
+	sealed, err := m.box.Seal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	now := time.Now()
+	secret := &models.Secret{Name: name, Description: description, Value: sealed, CreatedAt: now, UpdatedAt: now}
+	if existing, err := m.store.GetSecret(name); err == nil {
+		secret.CreatedAt = existing.CreatedAt
+	}
+	if err := m.store.SaveSecret(secret); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.values[name] = value
+	m.rebuild()
+	m.mu.Unlock()
+
+	secret.Value = ""
+	return secret, nil
+}
+
+// List 列出密钥（不包含值）
+func (m *Manager) List() ([]*models.Secret, error) {
+	if !m.Enabled() {
+		return nil, ErrDisabled
+	}
+	list, err := m.store.ListSecrets()
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range list {
+		secret.Value = ""
+	}
+	return list, nil
+}
+
+// Delete 删除密钥
+func (m *Manager) Delete(name string) error {
+	if !m.Enabled() {
+		return ErrDisabled
+	}
+	if _, err := m.store.GetSecret(name); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err := m.store.DeleteSecret(name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	delete(m.values, name)
+	m.rebuild()
+	m.mu.Unlock()
+	return nil
+}
+
+// Resolve 返回密钥明文（仅在回放时调用）
+func (m *Manager) Resolve(name string) (string, error) {
+	if !m.Enabled() {
+		return "", ErrDisabled
+	}
+	m.mu.RLock()
+	value, ok := m.values[name]
+	m.mu.RUnlock()
+	if ok {
+		return value, nil
+	}
+
+	// 启动时未能解密的密钥，返回具体原因
+	secret, err := m.store.GetSecret(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	value, err = m.box.Open(secret.Value)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", name, err)
+	}
+	return value, nil
+}
+
+// Lookup 占位符查找函数：解析 secret:name 形式的变量名
+func (m *Manager) Lookup() interpolate.Lookup {
+	if !m.Enabled() {
+		return nil
+	}
+	return func(name string) (interface{}, bool) {
+		secretName, ok := strings.CutPrefix(name, RefPrefix)
+		if !ok {
+			return nil, false
+		}
+		value, err := m.Resolve(secretName)
+		if err != nil {
+			return nil, false
+		}
+		return value, true
+	}
+}
+
+// Render 只解析文本中的 ${secret:name} 占位符，其他占位符保持原样
+func (m *Manager) Render(text string) string {
+	if !m.Enabled() || !strings.Contains(text, "${"+RefPrefix) {
+		return text
+	}
+	return interpolate.Render(text, m.Lookup())
+}
+
+// Redact 将文本中出现的密钥值替换为 Mask
+func (m *Manager) Redact(text string) string {
+	if m == nil {
+		return text
+	}
+	m.mu.RLock()
+	replacer := m.replacer
+	m.mu.RUnlock()
+	if replacer == nil {
+		return text
+	}
+	return replacer.Replace(text)
+}
+
+// RedactJSON 脱敏 v（指针）中所有字符串里的密钥值：序列化为 JSON 替换后再解析回 v
+func (m *Manager) RedactJSON(v interface{}) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	replacer := m.jsonReplacer
+	m.mu.RUnlock()
+	if replacer == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	redacted := replacer.Replace(string(data))
+	if redacted == string(data) {
+		return nil
+	}
+	return json.Unmarshal([]byte(redacted), v)
+}
+
+// RedactError 脱敏错误信息（保留原始错误，errors.Is/As 仍然有效）
+func (m *Manager) RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if msg := m.Redact(err.Error()); msg != err.Error() {
+		return &redactedError{err: err, msg: msg}
+	}
+	return err
+}
+
+// redactedError 脱敏后的错误
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+// References 返回文本中引用的密钥名称
+func References(text string) []string {
+	var names []string
+	for _, ref := range interpolate.References(text) {
+		if name, ok := strings.CutPrefix(ref.Name, RefPrefix); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// rebuild 重建脱敏替换器（调用方需持有写锁或确保没有并发访问）
+func (m *Manager) rebuild() {
+	values := make([]string, 0, len(m.values))
+	for _, value := range m.values {
+		if len(value) >= minRedactLength {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		m.replacer, m.jsonReplacer = nil, nil
+		return
+	}
+	// 较长的值优先匹配，避免只替换其中一部分
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	var plain, escaped []string
+	for _, value := range values {
+		plain = append(plain, value, Mask)
+		data, _ := json.Marshal(value)
+		escaped = append(escaped, string(data[1:len(data)-1]), Mask)
+	}
+	m.replacer = strings.NewReplacer(plain...)
+	m.jsonReplacer = strings.NewReplacer(escaped...)
+}
@@ -0,0 +1,161 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+	"github.com/browserwing/browserwing/pkg/secretbox"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	secrets map[string]models.Secret
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{secrets: make(map[string]models.Secret)}
+}
+
+func (s *memoryStore) SaveSecret(secret *models.Secret) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[secret.Name] = *secret
+	return nil
+}
+
+func (s *memoryStore) GetSecret(name string) (*models.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secret, ok := s.secrets[name]
+	if !ok {
+		return nil, fmt.Errorf("secret not found")
+	}
+	return &secret, nil
+}
+
+func (s *memoryStore) ListSecrets() ([]*models.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []*models.Secret
+	for _, secret := range s.secrets {
+		secret := secret
+		list = append(list, &secret)
+	}
+	return list, nil
+}
+
+func (s *memoryStore) DeleteSecret(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.secrets, name)
+	return nil
+}
+
+func newManager(t *testing.T, store Store, key string) *Manager {
+	t.Helper()
+	box, err := secretbox.New(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewManager(store, box)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return m
+}
+
+func TestManager(t *testing.T) {
+	store := newMemoryStore()
+	m := newManager(t, store, "key")
+
+	if _, err := m.Set("portal password", "x", ""); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("expected ErrInvalidName, got %v", err)
+	}
+	secret, err := m.Set("portal_pw", `p@ss"word`, "vendor portal")
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if secret.Value != "" || store.secrets["portal_pw"].Value == `p@ss"word` || !secretbox.IsSealed(store.secrets["portal_pw"].Value) {
+		t.Fatalf("secret stored in plain text: %+v", store.secrets["portal_pw"])
+	}
+
+	// 重新加载后仍可解析，占位符只解析 secret: 前缀
+	m = newManager(t, store, "key")
+	lookup := interpolate.Chain(interpolate.FromMap(map[string]string{"user": "bob"}), m.Lookup())
+	if got := interpolate.Render("${user}:${secret:portal_pw}", lookup); got != `bob:p@ss"word` {
+		t.Errorf("Render = %q", got)
+	}
+	if got := m.Render("${user}/${secret:portal_pw}"); got != `${user}/p@ss"word` {
+		t.Errorf("Render secrets only = %q", got)
+	}
+	if _, err := m.Resolve("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if names := References("${secret:a} ${b} ${secret:c|trim}"); len(names) != 2 || names[0] != "a" || names[1] != "c" {
+		t.Errorf("References = %v", names)
+	}
+
+	list, _ := m.List()
+	if len(list) != 1 || list[0].Value != "" || list[0].Description != "vendor portal" {
+		t.Errorf("List = %+v", list)
+	}
+
+	if err := m.Delete("portal_pw"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := m.Delete("portal_pw"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if m.Redact(`p@ss"word`) != `p@ss"word` {
+		t.Errorf("deleted secret still redacted")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	m := newManager(t, newMemoryStore(), "key")
+	m.Set("pw", "hunter2", "")
+	m.Set("quoted", `a"b<c>`, "")
+	m.Set("pin", "123", "") // 过短，不参与脱敏
+
+	if got := m.Redact("login with hunter2, pin 123"); got != "login with ******, pin 123" {
+		t.Errorf("Redact = %q", got)
+	}
+
+	record := struct {
+		Message string                 `json:"message"`
+		Data    map[string]interface{} `json:"data"`
+		Count   int                    `json:"count"`
+	}{
+		Message: `typed a"b<c> into #password`,
+		Data:    map[string]interface{}{"token": "Bearer hunter2", "n": float64(3)},
+		Count:   2,
+	}
+	if err := m.RedactJSON(&record); err != nil {
+		t.Fatalf("RedactJSON: %v", err)
+	}
+	if record.Message != "typed ****** into #password" || record.Data["token"] != "Bearer ******" || record.Data["n"] != float64(3) || record.Count != 2 {
+		t.Errorf("record = %+v", record)
+	}
+
+	err := m.RedactError(fmt.Errorf("wrapped: %w", errors.ErrUnsupported))
+	if err.Error() != "wrapped: "+errors.ErrUnsupported.Error() {
+		t.Errorf("unexpected redaction: %v", err)
+	}
+	err = m.RedactError(fmt.Errorf("typed hunter2: %w", errors.ErrUnsupported))
+	if err.Error() != "typed ******: unsupported operation" || !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("RedactError = %v", err)
+	}
+
+	// 未启用时不解析也不脱敏
+	var disabled *Manager
+	if disabled.Redact("hunter2") != "hunter2" || disabled.Lookup() != nil {
+		t.Errorf("nil manager should be a no-op")
+	}
+	if _, err := disabled.Set("a", "b", ""); !errors.Is(err, ErrDisabled) {
+		t.Errorf("expected ErrDisabled, got %v", err)
+	}
+}
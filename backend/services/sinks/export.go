@@ -0,0 +1,41 @@
+package sinks
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// WriteCSV 导出数据集记录（记录 ID、固定列加所有记录抓取字段的并集）
+func WriteCSV(w io.Writer, records []*models.OutputRecord) error {
+	header := append([]string{"id"}, csvHeader(dataKeys(records))...)
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		values := csvValues(rec)
+		row := make([]string, len(header))
+		for i, column := range header {
+			row[i] = values[column]
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSONL 导出每条记录一个 JSON 对象（抓取数据保留原始结构）
+func WriteJSONL(w io.Writer, records []*models.OutputRecord) error {
+	encoder := json.NewEncoder(w)
+	for _, rec := range records {
+		if err := encoder.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
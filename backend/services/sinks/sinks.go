@@ -0,0 +1,389 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/secrets"
+)
+
+// OutputDir 文件输出目标在资源目录下的子目录
+const OutputDir = "outputs"
+
+const (
+	DefaultRetryCount = 3  // webhook 默认重试次数
+	MaxRetryCount     = 10 // webhook 最大重试次数
+)
+
+var ErrInvalidSink = errors.New("invalid output sink")
+
+var datasetNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// DatasetStore 数据集存储
+type DatasetStore interface {
+	AppendDatasetRecord(name string, record *models.OutputRecord) error
+}
+
+// Writer 将执行结果写入输出目标
+// 文件和数据集同步写入，webhook 在后台投递（失败时按指数退避重试）
+type Writer struct {
+	assetsDir  string
+	store      DatasetStore
+	secrets    *secrets.Manager
+	client     *http.Client
+	retryDelay time.Duration // 第一次重试前的等待时长，之后每次翻倍
+
+	fileMu sync.Mutex // 串行化文件追加，避免并发回放写入交错的行
+	wg     sync.WaitGroup
+}
+
+// NewWriter 创建输出写入器
+func NewWriter(assetsDir string, store DatasetStore) *Writer {
+	return &Writer{
+		assetsDir:  assetsDir,
+		store:      store,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		retryDelay: time.Second,
+	}
+}
+
+// SetSecrets 设置密钥管理器（webhook 的 URL 和请求头支持 ${secret:name}）
+func (w *Writer) SetSecrets(m *secrets.Manager) {
+	w.secrets = m
+}
+
+// ValidDatasetName 判断数据集名称是否合法
+func ValidDatasetName(name string) bool {
+	return datasetNamePattern.MatchString(name)
+}
+
+// Validate 校验输出目标配置
+func Validate(sinks []models.OutputSink) error {
+	for i, sink := range sinks {
+		if err := validateSink(sink); err != nil {
+			return fmt.Errorf("%w: sink #%d (%s): %v", ErrInvalidSink, i+1, sink.Type, err)
+		}
+	}
+	return nil
+}
+
+func validateSink(sink models.OutputSink) error {
+	switch sink.Type {
+	case models.SinkCSV, models.SinkJSONL:
+		_, err := cleanPath(sink.Path)
+		return err
+	case models.SinkWebhook:
+		if n := retryCount(sink); n < 0 || n > MaxRetryCount {
+			return fmt.Errorf("retry_count must be between 0 and %d", MaxRetryCount)
+		}
+		if strings.Contains(sink.URL, "${") {
+			// 包含密钥引用的 URL 在投递时才能解析
+			return nil
+		}
+		u, err := url.Parse(sink.URL)
+		if err != nil || sink.URL == "" {
+			return fmt.Errorf("url is invalid")
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("url must use http or https")
+		}
+		return nil
+	case models.SinkDataset:
+		if !ValidDatasetName(sink.Dataset) {
+			return fmt.Errorf("dataset name must only contain letters, digits, '_', '-' and '.'")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown type")
+	}
+}
+
+// cleanPath 规范化文件输出路径（必须是 outputs 目录下的相对路径）
+func cleanPath(path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	clean := filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path must be relative to the outputs directory")
+	}
+	return clean, nil
+}
+
+// Write 将记录写入各输出目标，返回同步写入失败的错误（webhook 投递失败只记录日志）
+func (w *Writer) Write(ctx context.Context, sinks []models.OutputSink, record *models.OutputRecord) error {
+	if w == nil || len(sinks) == 0 {
+		return nil
+	}
+	var errs []error
+	for i, sink := range sinks {
+		rec := project(record, sink.Fields)
+		var err error
+		switch sink.Type {
+		case models.SinkCSV:
+			err = w.appendCSV(sink, rec)
+		case models.SinkJSONL:
+			err = w.appendJSONL(sink, rec)
+		case models.SinkDataset:
+			if !ValidDatasetName(sink.Dataset) {
+				err = fmt.Errorf("%w: invalid dataset name %q", ErrInvalidSink, sink.Dataset)
+			} else {
+				err = w.store.AppendDatasetRecord(sink.Dataset, rec)
+			}
+		case models.SinkWebhook:
+			w.wg.Add(1)
+			go func(sink models.OutputSink) {
+				defer w.wg.Done()
+				if err := w.post(sink, rec); err != nil {
+					logger.Warn(ctx, "Failed to deliver output to webhook: %v", err)
+				}
+			}(sink)
+		default:
+			err = fmt.Errorf("%w: unknown type %q", ErrInvalidSink, sink.Type)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s sink #%d: %w", sink.Type, i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Wait 等待后台 webhook 投递结束
+func (w *Writer) Wait() {
+	if w != nil {
+		w.wg.Wait()
+	}
+}
+
+// project 复制记录，只保留指定的抓取字段
+func project(record *models.OutputRecord, fields []string) *models.OutputRecord {
+	rec := *record
+	rec.Data = make(map[string]interface{}, len(record.Data))
+	if len(fields) == 0 {
+		for k, v := range record.Data {
+			rec.Data[k] = v
+		}
+		return &rec
+	}
+	for _, f := range fields {
+		if v, ok := record.Data[f]; ok {
+			rec.Data[f] = v
+		}
+	}
+	return &rec
+}
+
+// filePath 文件输出目标的绝对路径
+func (w *Writer) filePath(sink models.OutputSink) (string, error) {
+	clean, err := cleanPath(sink.Path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSink, err)
+	}
+	path := filepath.Join(w.assetsDir, OutputDir, clean)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// appendJSONL 追加一行 JSON
+func (w *Writer) appendJSONL(sink models.OutputSink, rec *models.OutputRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	w.fileMu.Lock()
+	defer w.fileMu.Unlock()
+
+	path, err := w.filePath(sink)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// appendCSV 追加一行 CSV
+// 新文件按 Fields（为空时按本条记录的字段）写入表头，已有文件沿用其表头，表头中没有的字段不会写入
+func (w *Writer) appendCSV(sink models.OutputSink, rec *models.OutputRecord) error {
+	w.fileMu.Lock()
+	defer w.fileMu.Unlock()
+
+	path, err := w.filePath(sink)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	header, err := csv.NewReader(f).Read()
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read csv header: %w", err)
+	}
+	writer := csv.NewWriter(f)
+	if len(header) == 0 {
+		keys := sink.Fields
+		if len(keys) == 0 {
+			keys = dataKeys([]*models.OutputRecord{rec})
+		}
+		header = csvHeader(keys)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+
+	values := csvValues(rec)
+	row := make([]string, len(header))
+	for i, column := range header {
+		row[i] = values[column]
+	}
+	if err := writer.Write(row); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// post 投递到 webhook，网络错误、5xx、408 和 429 时重试
+func (w *Writer) post(sink models.OutputSink, rec *models.OutputRecord) error {
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	retries := retryCount(sink)
+
+	delay := w.retryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := w.send(sink, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= retries {
+			return fmt.Errorf("%w (attempts: %d)", err, attempt+1)
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// retryCount webhook 的重试次数（未设置时使用 DefaultRetryCount）
+func retryCount(sink models.OutputSink) int {
+	if sink.RetryCount == nil {
+		return DefaultRetryCount
+	}
+	return *sink.RetryCount
+}
+
+// send 发送一次请求，返回是否值得重试
+func (w *Writer) send(sink models.OutputSink, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.secrets.Render(sink.URL), bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "BrowserWing")
+	for k, v := range sink.Headers {
+		req.Header.Set(k, w.secrets.Render(v))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
+
+// baseColumns 每条记录固定输出的列
+var baseColumns = []string{"timestamp", "script_id", "script_name", "execution_id", "task_id", "success", "error"}
+
+// csvHeader 固定列加抓取字段列（与固定列重名的字段加 data. 前缀）
+func csvHeader(keys []string) []string {
+	header := append([]string{}, baseColumns...)
+	for _, k := range keys {
+		header = append(header, dataColumn(k))
+	}
+	return header
+}
+
+func dataColumn(key string) string {
+	if key == "id" {
+		return "data." + key
+	}
+	for _, c := range baseColumns {
+		if c == key {
+			return "data." + key
+		}
+	}
+	return key
+}
+
+// csvValues 记录各列的文本值
+func csvValues(rec *models.OutputRecord) map[string]string {
+	values := map[string]string{
+		"id":           rec.ID,
+		"timestamp":    rec.Timestamp.Format(time.RFC3339),
+		"script_id":    rec.ScriptID,
+		"script_name":  rec.ScriptName,
+		"execution_id": rec.ExecutionID,
+		"task_id":      rec.TaskID,
+		"success":      fmt.Sprintf("%t", rec.Success),
+		"error":        rec.Error,
+	}
+	for k, v := range rec.Data {
+		values[dataColumn(k)] = interpolate.Stringify(v)
+	}
+	return values
+}
+
+// dataKeys 所有记录抓取字段的并集（排序）
+func dataKeys(records []*models.OutputRecord) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, rec := range records {
+		for k := range rec.Data {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,219 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string][]*models.OutputRecord
+}
+
+func (s *memoryStore) AppendDatasetRecord(name string, record *models.OutputRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.records == nil {
+		s.records = make(map[string][]*models.OutputRecord)
+	}
+	s.records[name] = append(s.records[name], record)
+	return nil
+}
+
+func newRecord(data map[string]interface{}) *models.OutputRecord {
+	return &models.OutputRecord{
+		Timestamp:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ScriptID:    "s1",
+		ScriptName:  "prices",
+		ExecutionID: "e1",
+		Success:     true,
+		Data:        data,
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestValidate(t *testing.T) {
+	valid := []models.OutputSink{
+		{Type: models.SinkCSV, Path: "prices/daily.csv"},
+		{Type: models.SinkJSONL, Path: "prices.jsonl"},
+		{Type: models.SinkWebhook, URL: "https://example.com/hook", RetryCount: intPtr(2)},
+		{Type: models.SinkWebhook, URL: "https://example.com/hook", RetryCount: intPtr(0)},
+		{Type: models.SinkWebhook, URL: "${secret:hook_url}"},
+		{Type: models.SinkDataset, Dataset: "prices"},
+	}
+	if err := Validate(valid); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	invalid := []models.OutputSink{
+		{Type: models.SinkCSV},
+		{Type: models.SinkCSV, Path: "../escape.csv"},
+		{Type: models.SinkJSONL, Path: "/tmp/abs.jsonl"},
+		{Type: models.SinkWebhook, URL: "ftp://example.com"},
+		{Type: models.SinkWebhook, URL: "https://example.com", RetryCount: intPtr(MaxRetryCount + 1)},
+		{Type: models.SinkWebhook, URL: "https://example.com", RetryCount: intPtr(-1)},
+		{Type: models.SinkDataset, Dataset: "bad name"},
+		{Type: "email"},
+	}
+	for _, sink := range invalid {
+		if err := Validate([]models.OutputSink{sink}); !errors.Is(err, ErrInvalidSink) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidSink", sink, err)
+		}
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, &memoryStore{})
+	sinks := []models.OutputSink{
+		{Type: models.SinkCSV, Path: "prices.csv"},
+		{Type: models.SinkJSONL, Path: "nested/prices.jsonl", Fields: []string{"price"}},
+	}
+
+	if err := w.Write(context.Background(), sinks, newRecord(map[string]interface{}{"price": 9.5, "title": "a, b"})); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	// 第二条记录多出的字段不在表头中，不会写入 CSV
+	if err := w.Write(context.Background(), sinks, newRecord(map[string]interface{}{"price": 10.0, "extra": "x"})); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, OutputDir, "prices.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "timestamp,script_id,script_name,execution_id,task_id,success,error,price,title\n" +
+		"2026-01-02T03:04:05Z,s1,prices,e1,,true,,9.5,\"a, b\"\n" +
+		"2026-01-02T03:04:05Z,s1,prices,e1,,true,,10,\n"
+	if string(data) != want {
+		t.Errorf("csv = %q, want %q", data, want)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, OutputDir, "nested", "prices.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("jsonl lines = %d", len(lines))
+	}
+	var rec models.OutputRecord
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Data) != 1 || rec.Data["price"] != 9.5 {
+		t.Errorf("jsonl data = %v, want only price", rec.Data)
+	}
+}
+
+func TestWriteDataset(t *testing.T) {
+	store := &memoryStore{}
+	w := NewWriter(t.TempDir(), store)
+	sinks := []models.OutputSink{{Type: models.SinkDataset, Dataset: "prices"}}
+	for i := 0; i < 2; i++ {
+		if err := w.Write(context.Background(), sinks, newRecord(map[string]interface{}{"price": float64(i)})); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if got := len(store.records["prices"]); got != 2 {
+		t.Errorf("dataset records = %d, want 2", got)
+	}
+
+	err := w.Write(context.Background(), []models.OutputSink{{Type: models.SinkDataset, Dataset: "../x"}}, newRecord(nil))
+	if !errors.Is(err, ErrInvalidSink) {
+		t.Errorf("expected ErrInvalidSink, got %v", err)
+	}
+}
+
+func TestWriteWebhookRetries(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	var calls atomic.Int32
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("X-Token") != "t" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var buf bytes.Buffer
+		buf.ReadFrom(r.Body)
+		body.Store(buf.String())
+	}))
+	defer server.Close()
+
+	w := NewWriter(t.TempDir(), &memoryStore{})
+	w.retryDelay = time.Millisecond
+	sinks := []models.OutputSink{{Type: models.SinkWebhook, URL: server.URL, Headers: map[string]string{"X-Token": "t"}}}
+	if err := w.Write(context.Background(), sinks, newRecord(map[string]interface{}{"price": 1})); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	w.Wait()
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+	if got, _ := body.Load().(string); !strings.Contains(got, `"price":1`) {
+		t.Errorf("body = %q", got)
+	}
+
+	// 4xx 不重试
+	calls.Store(10)
+	w.Write(context.Background(), []models.OutputSink{{Type: models.SinkWebhook, URL: server.URL}}, newRecord(nil))
+	w.Wait()
+	if got := calls.Load(); got != 11 {
+		t.Errorf("calls = %d, want 11 (no retry on 401)", got)
+	}
+
+	// retry_count 为 0 时失败也不重试
+	calls.Store(0)
+	w.Write(context.Background(), []models.OutputSink{{Type: models.SinkWebhook, URL: server.URL, RetryCount: intPtr(0)}}, newRecord(nil))
+	w.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1 (retry_count 0)", got)
+	}
+}
+
+func TestExport(t *testing.T) {
+	records := []*models.OutputRecord{
+		newRecord(map[string]interface{}{"price": 1.5}),
+		newRecord(map[string]interface{}{"id": "x", "tags": []interface{}{"a"}}),
+	}
+	records[0].ID, records[1].ID = "1", "2"
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, records); err != nil {
+		t.Fatal(err)
+	}
+	want := "id,timestamp,script_id,script_name,execution_id,task_id,success,error,data.id,price,tags\n" +
+		"1,2026-01-02T03:04:05Z,s1,prices,e1,,true,,,1.5,\n" +
+		"2,2026-01-02T03:04:05Z,s1,prices,e1,,true,,x,,\"[\"\"a\"\"]\"\n"
+	if buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := WriteJSONL(&buf, records); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Errorf("jsonl lines = %d, want 2", n)
+	}
+}
@@ -0,0 +1,514 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/interpolate"
+	"github.com/browserwing/browserwing/services/params"
+	"github.com/browserwing/browserwing/services/secrets"
+)
+
+// Severity 问题级别
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // 回放时必然失败或被跳过
+	SeverityWarning Severity = "warning" // 可能失败，取决于运行时传入的变量或页面状态
+)
+
+// 问题代码
+const (
+	CodeUnknownType       = "unknown_type"        // 未知操作类型（回放时会被忽略）
+	CodeEmptySelector     = "empty_selector"      // 需要定位元素但 Selector 和 XPath 都为空
+	CodeMissingField      = "missing_field"       // 缺少操作必需的字段
+	CodeUndefinedVariable = "undefined_variable"  // 占位符引用的变量没有默认值，也没有任何来源
+	CodeInvalidTabIndex   = "invalid_tab_index"   // switch_tab 的标签页序号无效或超出已打开的标签页
+	CodeUnsetConditionVar = "unset_condition_var" // 条件引用的变量从未被设置
+	CodeSchemaOutOfSync   = "schema_out_of_sync"  // MCP 输入 schema 与脚本中的占位符不一致
+	CodeUnsupportedParam  = "unsupported_param"   // MCP 输入 schema 中的参数类型无法注册为工具参数
+	CodeUndefinedList     = "undefined_list"      // foreach 遍历的列表变量从未被设置
+	CodeInvalidParameter  = "invalid_parameter"   // 脚本参数定义无效（类型、正则、枚举值或默认值）
+	CodeInvalidTransform  = "invalid_transform"   // 值转换过滤器链无效（未知过滤器或无效正则），或配置在不支持转换的操作上
+	CodeInvalidPolicy     = "invalid_policy"      // 失败处理策略的重试次数、重试间隔或超时超出范围
+)
+
+// Issue 校验发现的问题
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Index    int      `json:"index"`          // 顶层操作序号（从 0 开始，脚本级问题为 -1）
+	Step     string   `json:"step,omitempty"` // 步骤编号（从 1 开始，嵌套步骤如 "3.2"，else 分支如 "3.else.1"）
+	Type     string   `json:"type,omitempty"` // 操作类型
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+// Report 校验结果
+type Report struct {
+	Valid    bool    `json:"valid"` // 没有 error 级别的问题
+	Errors   []Issue `json:"errors"`
+	Warnings []Issue `json:"warnings"`
+}
+
+// actionTypes Player 支持的操作类型
+var actionTypes = map[string]bool{
+	"click": true, "input": true, "select": true, "navigate": true, "wait": true, "sleep": true, "wait_for": true,
+	"extract_text": true, "extract_html": true, "extract_attribute": true, "extract_list": true,
+	"execute_js": true, "upload_file": true, "scroll": true, "keyboard": true, "screenshot": true,
+	"capture_xhr": true, "ai_control": true, "open_tab": true, "switch_tab": true, "switch_active_tab": true,
+	"if": true, "loop": true, "foreach": true, "paginate": true, "call_script": true, "set_viewport": true,
+	"assert_text": true, "assert_visible": true, "assert_url": true, "assert_count": true,
+	"assert_attribute": true, "assert_variable": true,
+}
+
+// elementActions 需要定位元素的操作类型
+var elementActions = map[string]bool{
+	"click": true, "input": true, "select": true, "upload_file": true,
+	"extract_text": true, "extract_html": true, "extract_attribute": true, "extract_list": true,
+	"assert_text": true, "assert_visible": true, "assert_count": true, "assert_attribute": true,
+}
+
+// transformActions 支持 Transform 值转换的操作类型（保存抓取结果的操作）
+var transformActions = map[string]bool{
+	"extract_text": true, "extract_html": true, "extract_attribute": true, "extract_list": true,
+	"execute_js": true, "capture_xhr": true,
+}
+
+// Validate 静态校验脚本，返回每个操作的问题
+func Validate(script *models.Script) *Report {
+	return run(script).report
+}
+
+// UndefinedVariables 返回占位符引用、但没有任何来源（预设变量、输入 schema、抓取结果）且没有默认值的变量名（排序）
+func UndefinedVariables(script *models.Script) []string {
+	return sortedKeys(run(script).undefined)
+}
+
+// run 执行校验
+func run(script *models.Script) *validator {
+	v := &validator{
+		report:    &Report{Errors: []Issue{}, Warnings: []Issue{}},
+		preset:    script.Variables,
+		extracted: make(map[string]bool),
+		used:      make(map[string]bool),
+		undefined: make(map[string]bool),
+		tabs:      1,
+	}
+	v.collect(script)
+
+	v.index, v.step, v.kind = -1, "", ""
+	v.checkText(script.URL)
+	v.checkErrorPolicy(script.ErrorPolicy)
+	v.walk(script.Actions, "", -1)
+
+	v.index, v.step, v.kind = -1, "", ""
+	v.checkSchema(script)
+
+	v.report.Valid = len(v.report.Errors) == 0
+	return v
+}
+
+// validator 校验上下文
+type validator struct {
+	report    *Report
+	preset    map[string]string      // 脚本预设变量
+	schema    map[string]interface{} // MCP 输入 schema 声明的参数
+	extracted map[string]bool        // 操作设置的变量（抓取结果、子脚本输出）
+	scoped    []map[string]bool      // 循环内可用的变量（index、item 等）
+	used      map[string]bool        // 占位符引用过的变量
+	undefined map[string]bool        // 没有任何来源且没有默认值的变量
+	dynamic   bool                   // 存在无法静态确定输出变量的子脚本调用
+
+	tabs          int  // 已打开的标签页数量上限
+	unboundedTabs bool // 标签页数量无法静态确定（循环或子脚本中打开标签页）
+
+	index int
+	step  string
+	kind  string
+}
+
+// collect 收集 schema 参数和操作设置的变量
+func (v *validator) collect(script *models.Script) {
+	if props, ok := script.MCPInputSchema["properties"].(map[string]interface{}); ok {
+		v.schema = props
+	}
+	// 定义了参数时，输入 schema 由参数定义生成
+	if len(script.Parameters) > 0 {
+		v.schema = params.Schema(script.Parameters)["properties"].(map[string]interface{})
+	}
+	var visit func(actions []models.ScriptAction)
+	visit = func(actions []models.ScriptAction) {
+		for _, a := range actions {
+			if a.VariableName != "" && a.Type != "assert_variable" {
+				v.extracted[a.VariableName] = true
+			}
+			if a.Type == "call_script" {
+				if len(a.OutputMapping) == 0 {
+					v.dynamic = true
+				}
+				for _, name := range a.OutputMapping {
+					v.extracted[name] = true
+				}
+			}
+			visit(a.Actions)
+			visit(a.ElseActions)
+		}
+	}
+	visit(script.Actions)
+}
+
+// walk 按执行顺序校验操作列表
+func (v *validator) walk(actions []models.ScriptAction, prefix string, top int) {
+	for i, a := range actions {
+		index := top
+		if index < 0 {
+			index = i
+		}
+		step := prefix + strconv.Itoa(i+1)
+		v.index, v.step, v.kind = index, step, a.Type
+
+		v.checkAction(a)
+
+		switch a.Type {
+		case "if":
+			tabs, unbounded := v.tabs, v.unboundedTabs
+			v.walk(a.Actions, step+".", index)
+			thenTabs, thenUnbounded := v.tabs, v.unboundedTabs
+			v.tabs, v.unboundedTabs = tabs, unbounded
+			v.walk(a.ElseActions, step+".else.", index)
+			if thenTabs > v.tabs {
+				v.tabs = thenTabs
+			}
+			v.unboundedTabs = v.unboundedTabs || thenUnbounded
+		case "loop", "foreach", "paginate":
+			if opensTabs(a.Actions) {
+				v.unboundedTabs = true
+			}
+			v.scoped = append(v.scoped, loopVariables(a))
+			v.walk(a.Actions, step+".", index)
+			v.scoped = v.scoped[:len(v.scoped)-1]
+		}
+	}
+}
+
+// checkAction 校验单个操作（不含嵌套操作）
+func (v *validator) checkAction(a models.ScriptAction) {
+	if !actionTypes[a.Type] {
+		v.errorf(CodeUnknownType, "unknown action type %q is ignored during playback", a.Type)
+		return
+	}
+
+	if elementActions[a.Type] && strings.TrimSpace(a.Selector) == "" && strings.TrimSpace(a.XPath) == "" {
+		v.errorf(CodeEmptySelector, "%s requires a selector or xpath", a.Type)
+	}
+
+	switch a.Type {
+	case "navigate", "open_tab":
+		if a.URL == "" {
+			v.errorf(CodeMissingField, "%s requires url", a.Type)
+		}
+	case "capture_xhr":
+		if a.URL == "" || a.Method == "" {
+			v.errorf(CodeMissingField, "capture_xhr requires url and method")
+		}
+	case "extract_attribute", "assert_attribute":
+		if a.AttributeName == "" {
+			v.errorf(CodeMissingField, "%s requires attribute_name", a.Type)
+		}
+	case "extract_list":
+		if len(a.Fields) == 0 {
+			v.errorf(CodeMissingField, "extract_list requires at least one field")
+		}
+	case "execute_js":
+		if strings.TrimSpace(a.JSCode) == "" {
+			v.errorf(CodeMissingField, "execute_js requires js_code")
+		}
+	case "set_viewport":
+		if a.ViewportWidth <= 0 || a.ViewportHeight <= 0 {
+			v.errorf(CodeMissingField, "set_viewport requires positive viewport_width and viewport_height")
+		}
+	case "wait_for":
+		if a.WaitFor == nil {
+			v.errorf(CodeMissingField, "wait_for requires wait condition")
+		}
+	case "call_script":
+		if a.ScriptID == "" && a.ScriptName == "" {
+			v.errorf(CodeMissingField, "call_script requires script_id or script_name")
+		}
+		// 子脚本与当前脚本共享浏览器，可能打开新标签页
+		v.unboundedTabs = true
+	case "if":
+		if a.Condition == nil {
+			v.errorf(CodeMissingField, "if requires condition")
+		}
+	case "loop":
+		if a.LoopCount <= 0 && a.LoopCondition == nil {
+			v.errorf(CodeMissingField, "loop requires loop_count or loop_condition")
+		}
+	case "foreach":
+		if a.ListVariable == "" && a.Selector == "" && a.XPath == "" {
+			v.errorf(CodeEmptySelector, "foreach requires list_variable, selector or xpath")
+		}
+		if a.ListVariable != "" && len(interpolate.References(a.ListVariable)) == 0 && !v.isDefined(a.ListVariable) {
+			v.warnf(CodeUndefinedList, "list variable %q is never set", a.ListVariable)
+		}
+	case "paginate":
+		if a.Pagination == nil || (a.Pagination.NextSelector == "" && a.Pagination.NextXPath == "" && a.Pagination.URLTemplate == "") {
+			v.errorf(CodeMissingField, "paginate requires next_selector, next_xpath or url_template")
+		}
+	case "assert_variable":
+		if a.VariableName != "" && !v.isDefined(a.VariableName) {
+			v.warnf(CodeUnsetConditionVar, "asserted variable %q is never set", a.VariableName)
+		}
+	case "switch_tab":
+		v.checkTabIndex(a.Value)
+	}
+	if a.Type == "open_tab" || a.Type == "switch_active_tab" {
+		v.tabs++
+	}
+
+	if a.Transform != "" && !transformActions[a.Type] {
+		v.warnf(CodeInvalidTransform, "transform is ignored for %s", a.Type)
+	}
+	v.checkTransform(a.Transform)
+	for _, name := range sortedKeys(a.Fields) {
+		v.checkTransform(a.Fields[name].Transform)
+	}
+
+	v.checkErrorPolicy(a.ErrorPolicy)
+
+	if a.Type == "if" || (a.Condition != nil && a.Condition.Enabled) {
+		v.checkCondition(a.Condition)
+	}
+	v.checkCondition(a.LoopCondition)
+
+	for _, text := range actionTexts(a) {
+		v.checkText(text)
+	}
+}
+
+// checkTabIndex 校验 switch_tab 的标签页序号（序号从 0 开始，0 为初始页面）
+func (v *validator) checkTabIndex(value string) {
+	if value == "" {
+		v.errorf(CodeInvalidTabIndex, "switch_tab requires a tab index in value")
+		return
+	}
+	if len(interpolate.References(value)) > 0 {
+		return
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || index < 0 {
+		v.errorf(CodeInvalidTabIndex, "tab index %q is not a non-negative integer", value)
+		return
+	}
+	if !v.unboundedTabs && index >= v.tabs {
+		v.errorf(CodeInvalidTabIndex, "tab index %d does not exist, at most %d tab(s) are open at this step", index, v.tabs)
+	}
+}
+
+// checkCondition 校验条件（包括子条件）引用的变量
+func (v *validator) checkCondition(cond *models.ActionCondition) {
+	if cond == nil {
+		return
+	}
+	switch cond.Type {
+	case "", models.ConditionVariable:
+		if cond.Operator != "exists" && cond.Operator != "not_exists" && cond.Variable != "" && !v.isDefined(cond.Variable) {
+			v.warnf(CodeUnsetConditionVar, "condition variable %q is never set", cond.Variable)
+		}
+	case models.ConditionElementExists, models.ConditionElementVisible:
+		if cond.Selector == "" && cond.XPath == "" {
+			v.errorf(CodeEmptySelector, "%s condition requires a selector or xpath", cond.Type)
+		}
+	case models.ConditionAnd, models.ConditionOr:
+		if len(cond.Conditions) == 0 {
+			v.errorf(CodeMissingField, "%s condition requires sub-conditions", cond.Type)
+		}
+	}
+	v.checkTransform(cond.Transform)
+	for _, text := range []string{cond.Value, cond.Selector, cond.XPath, cond.Pattern, cond.Text} {
+		v.checkText(text)
+	}
+	for i := range cond.Conditions {
+		v.checkCondition(&cond.Conditions[i])
+	}
+}
+
+// checkErrorPolicy 校验失败处理策略的取值范围
+func (v *validator) checkErrorPolicy(policy *models.ErrorPolicy) {
+	if policy == nil {
+		return
+	}
+	if n := policy.Retries(); n < 0 || n > models.MaxRetryCount {
+		v.errorf(CodeInvalidPolicy, "retry_count must be between 0 and %d, got %d", models.MaxRetryCount, n)
+	}
+	if policy.RetryBackoff < 0 || policy.RetryBackoff > models.MaxRetryDelay {
+		v.errorf(CodeInvalidPolicy, "retry_backoff must be between 0 and %dms, got %d", models.MaxRetryDelay, policy.RetryBackoff)
+	}
+	if policy.TimeoutMs() < 0 {
+		v.errorf(CodeInvalidPolicy, "timeout must not be negative, got %d", policy.TimeoutMs())
+	}
+}
+
+// checkTransform 校验值转换过滤器链
+func (v *validator) checkTransform(chain string) {
+	if err := interpolate.ValidateChain(chain); err != nil {
+		v.errorf(CodeInvalidTransform, "%v", err)
+	}
+}
+
+// checkText 校验文本中的占位符
+func (v *validator) checkText(text string) {
+	for _, ref := range interpolate.References(text) {
+		// 密钥引用在回放时解析，存储中是否存在由回放前的检查报告
+		if strings.HasPrefix(ref.Name, secrets.RefPrefix) {
+			continue
+		}
+		v.used[ref.Name] = true
+		if ref.HasDefault || v.isDefined(ref.Name) {
+			continue
+		}
+		v.undefined[ref.Name] = true
+		v.warnf(CodeUndefinedVariable, "${%s} has no default value and is not defined in variables, the input schema or any extraction", ref.Name)
+	}
+}
+
+// checkSchema 校验 MCP 输入 schema 与占位符是否一致
+func (v *validator) checkSchema(script *models.Script) {
+	if err := params.ValidateDefinitions(script.Parameters); err != nil {
+		v.errorf(CodeInvalidParameter, "%v", err)
+	}
+	// 由参数定义生成的 schema 原样注册为工具参数，支持所有参数类型
+	typed := len(script.Parameters) > 0
+
+	for _, name := range sortedKeys(v.schema) {
+		def, _ := v.schema[name].(map[string]interface{})
+		switch t, _ := def["type"].(string); t {
+		case "string", "number", "integer", "boolean":
+		default:
+			if !typed {
+				v.warnf(CodeUnsupportedParam, "input schema parameter %q has type %q and is not exposed as an MCP tool argument", name, t)
+			}
+		}
+		// url 参数会直接作为起始 URL 使用
+		if !v.used[name] && name != "url" {
+			v.warnf(CodeSchemaOutOfSync, "input schema parameter %q is not referenced by any placeholder", name)
+		}
+	}
+
+	if required, ok := script.MCPInputSchema["required"].([]interface{}); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok && v.schema[name] == nil {
+				v.warnf(CodeSchemaOutOfSync, "required parameter %q is not declared in input schema properties", name)
+			}
+		}
+	}
+
+	// MCP 调用方只能通过 schema 声明的参数传值，预设值为空的变量需要声明
+	if !script.IsMCPCommand {
+		return
+	}
+	for _, name := range sortedKeys(v.used) {
+		value, preset := v.preset[name]
+		if preset && value == "" && v.schema[name] == nil && !v.extracted[name] {
+			v.warnf(CodeSchemaOutOfSync, "variable %q has an empty default and is not declared in the input schema", name)
+		}
+	}
+}
+
+// isDefined 判断变量在当前位置是否可能有值
+func (v *validator) isDefined(name string) bool {
+	if _, ok := v.preset[name]; ok || v.dynamic || v.extracted[name] || v.schema[name] != nil {
+		return true
+	}
+	for _, scope := range v.scoped {
+		if scope[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) errorf(code, format string, args ...interface{}) {
+	v.report.Errors = append(v.report.Errors, v.issue(SeverityError, code, format, args...))
+}
+
+func (v *validator) warnf(code, format string, args ...interface{}) {
+	v.report.Warnings = append(v.report.Warnings, v.issue(SeverityWarning, code, format, args...))
+}
+
+func (v *validator) issue(severity Severity, code, format string, args ...interface{}) Issue {
+	return Issue{Severity: severity, Index: v.index, Step: v.step, Type: v.kind, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// loopVariables 循环体内可用的变量（与回放时的默认变量名一致）
+func loopVariables(a models.ScriptAction) map[string]bool {
+	vars := make(map[string]bool)
+	switch a.Type {
+	case "loop":
+		vars[orDefault(a.IndexVariable, "index")] = true
+	case "foreach":
+		item := orDefault(a.ItemVariable, "item")
+		vars[orDefault(a.IndexVariable, "index")] = true
+		vars[item] = true
+		vars[item+"_selector"] = true
+	case "paginate":
+		vars[orDefault(a.IndexVariable, "page")] = true
+	}
+	return vars
+}
+
+// opensTabs 判断操作列表（包括嵌套操作）是否会打开新标签页
+func opensTabs(actions []models.ScriptAction) bool {
+	for _, a := range actions {
+		switch a.Type {
+		case "open_tab", "switch_active_tab", "call_script":
+			return true
+		}
+		if opensTabs(a.Actions) || opensTabs(a.ElseActions) {
+			return true
+		}
+	}
+	return false
+}
+
+// actionTexts 回放时会解析占位符的字段（JS 代码中的 ${} 可能是模板字符串，不参与校验）
+func actionTexts(a models.ScriptAction) []string {
+	texts := []string{
+		a.Selector, a.XPath, a.Value, a.URL, a.Text, a.Key, a.AttributeName,
+		a.ScriptID, a.ScriptName, a.AIControlPrompt, a.AIControlXPath,
+	}
+	texts = append(texts, a.FilePaths...)
+	for _, value := range a.InputMapping {
+		texts = append(texts, value)
+	}
+	for _, hop := range a.FramePath {
+		texts = append(texts, hop.Selector)
+	}
+	if a.WaitFor != nil {
+		texts = append(texts, a.WaitFor.Selector, a.WaitFor.XPath, a.WaitFor.Pattern, a.WaitFor.Text)
+	}
+	if a.Pagination != nil {
+		texts = append(texts, a.Pagination.URLTemplate)
+	}
+	return texts
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
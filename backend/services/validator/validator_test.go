@@ -0,0 +1,211 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestValidate(t *testing.T) {
+	script := &models.Script{
+		URL:       "https://example.com/search?q=${keyword}",
+		Variables: map[string]string{"keyword": "laptop", "token": ""},
+		Actions: []models.ScriptAction{
+			{Type: "clik", Selector: "#go"},
+			{Type: "input", Value: "${user}"},
+			{Type: "extract_text", Selector: ".price", VariableName: "price"},
+			{Type: "if", Condition: &models.ActionCondition{Variable: "stock", Operator: ">", Value: "0"}, Actions: []models.ScriptAction{
+				{Type: "click", Selector: "${missing|default:#buy}"},
+			}},
+			{Type: "switch_tab", Value: "1"},
+			{Type: "open_tab", URL: "https://example.com/help"},
+			{Type: "switch_tab", Value: "1"},
+			{Type: "foreach", Selector: ".row", Actions: []models.ScriptAction{
+				{Type: "extract_text", Selector: "${item_selector} .name", VariableName: "name_${index}"},
+			}},
+			{Type: "execute_js", JSCode: "return `${a || b}`"},
+			{Type: "assert_text", Value: "${token}", Selector: "h1"},
+		},
+		IsMCPCommand: true,
+		MCPInputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"keyword": map[string]interface{}{"type": "string"},
+				"limit":   map[string]interface{}{"type": "number"},
+				"tags":    map[string]interface{}{"type": "array"},
+			},
+			"required": []interface{}{"keyword", "user"},
+		},
+	}
+
+	report := Validate(script)
+	if report.Valid {
+		t.Fatalf("expected errors, got valid report")
+	}
+
+	wantErrors := []struct {
+		index int
+		code  string
+	}{
+		{0, CodeUnknownType},
+		{1, CodeEmptySelector},
+		{4, CodeInvalidTabIndex},
+	}
+	if len(report.Errors) != len(wantErrors) {
+		t.Fatalf("errors = %+v", report.Errors)
+	}
+	for i, want := range wantErrors {
+		if got := report.Errors[i]; got.Index != want.index || got.Code != want.code {
+			t.Errorf("errors[%d] = %+v, want index %d code %s", i, got, want.index, want.code)
+		}
+	}
+
+	wantWarnings := []struct {
+		index int
+		step  string
+		code  string
+	}{
+		{1, "2", CodeUndefinedVariable},
+		{3, "4", CodeUnsetConditionVar},
+		{-1, "", CodeSchemaOutOfSync}, // limit 未被引用
+		{-1, "", CodeUnsupportedParam},
+		{-1, "", CodeSchemaOutOfSync}, // tags 未被引用
+		{-1, "", CodeSchemaOutOfSync}, // required 中的 user 未声明
+		{-1, "", CodeSchemaOutOfSync}, // token 预设值为空且未声明
+	}
+	if len(report.Warnings) != len(wantWarnings) {
+		t.Fatalf("warnings = %+v", report.Warnings)
+	}
+	for i, want := range wantWarnings {
+		if got := report.Warnings[i]; got.Index != want.index || got.Step != want.step || got.Code != want.code {
+			t.Errorf("warnings[%d] = %+v, want index %d step %q code %s", i, got, want.index, want.step, want.code)
+		}
+	}
+}
+
+func TestValidateNestedAndDynamic(t *testing.T) {
+	script := &models.Script{
+		Actions: []models.ScriptAction{
+			{Type: "loop", LoopCount: 2, Actions: []models.ScriptAction{
+				{Type: "open_tab", URL: "https://example.com/${index}"},
+			}},
+			{Type: "switch_tab", Value: "5"},
+			{Type: "if", Condition: &models.ActionCondition{Type: models.ConditionElementVisible}, ElseActions: []models.ScriptAction{
+				{Type: "bogus"},
+			}},
+			{Type: "call_script", ScriptID: "child"},
+			{Type: "navigate", URL: "${from_child}"},
+		},
+	}
+
+	report := Validate(script)
+	if len(report.Errors) != 2 {
+		t.Fatalf("errors = %+v", report.Errors)
+	}
+	if got := report.Errors[0]; got.Index != 2 || got.Code != CodeEmptySelector {
+		t.Errorf("errors[0] = %+v", got)
+	}
+	if got := report.Errors[1]; got.Index != 2 || got.Step != "3.else.1" || got.Code != CodeUnknownType {
+		t.Errorf("errors[1] = %+v", got)
+	}
+	// 子脚本未配置输出映射时无法确定其输出变量，不报告未定义变量
+	if len(report.Warnings) != 0 {
+		t.Errorf("warnings = %+v", report.Warnings)
+	}
+}
+
+func TestValidateParameters(t *testing.T) {
+	script := &models.Script{
+		IsMCPCommand: true,
+		Parameters: []models.ScriptParameter{
+			{Name: "tags", Type: models.ParamList},
+			{Name: "pages", Type: models.ParamNumber, Default: "many"},
+		},
+		Actions: []models.ScriptAction{
+			{Type: "navigate", URL: "https://example.com/?tags=${tags}&pages=${pages}"},
+		},
+	}
+
+	report := Validate(script)
+	if len(report.Errors) != 1 || report.Errors[0].Code != CodeInvalidParameter {
+		t.Fatalf("errors = %+v", report.Errors)
+	}
+	// 参数生成的 schema 支持列表参数，占位符由参数定义提供
+	if len(report.Warnings) != 0 {
+		t.Errorf("warnings = %+v", report.Warnings)
+	}
+}
+
+func TestValidateSecretReferences(t *testing.T) {
+	script := &models.Script{
+		Actions: []models.ScriptAction{
+			{Type: "input", Selector: "#password", Value: "${secret:portal_pw}"},
+		},
+	}
+	if report := Validate(script); len(report.Errors) != 0 || len(report.Warnings) != 0 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestValidateTransforms(t *testing.T) {
+	script := &models.Script{
+		Actions: []models.ScriptAction{
+			{Type: "extract_text", Selector: ".price", VariableName: "price", Transform: `regex:([\d.,]+)|number`},
+			{Type: "capture_xhr", URL: "api.example.com/items", Method: "GET", VariableName: "items", Transform: "json:data.total|reverse"},
+			{Type: "extract_list", Selector: ".item", Fields: map[string]models.ExtractField{"date": {Transform: "regex:(["}}},
+			{Type: "click", Selector: "#next", Transform: "trim"},
+			{Type: "if", Condition: &models.ActionCondition{Variable: "price", Operator: ">", Value: "10", Transform: "number"}},
+		},
+	}
+
+	report := Validate(script)
+	if len(report.Errors) != 2 || report.Errors[0].Index != 1 || report.Errors[1].Index != 2 {
+		t.Fatalf("errors = %+v", report.Errors)
+	}
+	for _, issue := range report.Errors {
+		if issue.Code != CodeInvalidTransform {
+			t.Errorf("error code = %s, want %s", issue.Code, CodeInvalidTransform)
+		}
+	}
+	if len(report.Warnings) != 1 || report.Warnings[0].Index != 3 || report.Warnings[0].Code != CodeInvalidTransform {
+		t.Errorf("warnings = %+v", report.Warnings)
+	}
+}
+
+func TestValidateErrorPolicy(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	script := &models.Script{
+		ErrorPolicy: &models.ErrorPolicy{RetryCount: intPtr(models.MaxRetryCount + 1)},
+		Actions: []models.ScriptAction{
+			{Type: "navigate", URL: "https://example.com", ErrorPolicy: &models.ErrorPolicy{RetryCount: intPtr(0), Timeout: intPtr(0)}},
+			{Type: "navigate", URL: "https://example.com", ErrorPolicy: &models.ErrorPolicy{RetryBackoff: models.MaxRetryDelay + 1}},
+			{Type: "navigate", URL: "https://example.com", ErrorPolicy: &models.ErrorPolicy{Timeout: intPtr(-1)}},
+		},
+	}
+
+	report := Validate(script)
+	if len(report.Errors) != 3 || report.Errors[0].Index != -1 || report.Errors[1].Index != 1 || report.Errors[2].Index != 2 {
+		t.Fatalf("errors = %+v", report.Errors)
+	}
+	for _, issue := range report.Errors {
+		if issue.Code != CodeInvalidPolicy {
+			t.Errorf("error code = %s, want %s", issue.Code, CodeInvalidPolicy)
+		}
+	}
+}
+
+func TestUndefinedVariables(t *testing.T) {
+	script := &models.Script{
+		Variables: map[string]string{"user": "alice"},
+		Actions: []models.ScriptAction{
+			{Type: "input", Selector: "#a", Value: "${user} ${missing} ${other|default:x}"},
+			{Type: "extract_text", Selector: "h1", VariableName: "title"},
+			{Type: "input", Selector: "#b", Value: "${title} ${secret:pw} ${another}"},
+		},
+	}
+	got := UndefinedVariables(script)
+	if want := []string{"another", "missing"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UndefinedVariables() = %v, want %v", got, want)
+	}
+}
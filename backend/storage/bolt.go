@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
@@ -9,6 +11,8 @@ import (
 	"time"
 
 	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/pkg/secretbox"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -30,10 +34,21 @@ var (
 	apiKeysBucket           = []byte("api_keys")
 	scheduledTasksBucket    = []byte("scheduled_tasks")
 	taskExecutionsBucket    = []byte("task_executions")
+	scriptRevisionsBucket   = []byte("script_revisions")
+	dataRunsBucket          = []byte("data_runs")
+	secretsBucket           = []byte("secrets")
+	datasetsBucket          = []byte("datasets")
 )
 
 type BoltDB struct {
-	db *bolt.DB
+	db     *bolt.DB
+	cipher Cipher // 敏感字段（LLM API Key）的加密方式，为空时按明文保存
+}
+
+// Cipher 敏感字段的加解密
+type Cipher interface {
+	Seal(plaintext string) (string, error)
+	Open(sealed string) (string, error)
 }
 
 func NewBoltDB(dbPath string) (*BoltDB, error) {
@@ -110,6 +125,22 @@ func NewBoltDB(dbPath string) (*BoltDB, error) {
 			return err
 		}
 		_, err = tx.CreateBucketIfNotExists(taskExecutionsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(scriptRevisionsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(dataRunsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(secretsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(datasetsBucket)
 		return err
 	})
 	if err != nil {
@@ -170,8 +201,59 @@ func (b *BoltDB) DeleteCookies(id string) error {
 
 // SaveScript 保存脚本
 func (b *BoltDB) SaveScript(script *models.Script) error {
+	return b.SaveScriptWithRevision(script, "", "")
+}
+
+// SaveScriptWithRevision 保存脚本，内容有变化时追加一个历史版本
+// summary 为空时根据与上一版本的差异自动生成
+func (b *BoltDB) SaveScriptWithRevision(script *models.Script, author, summary string) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(scriptsBucket)
+		revisions, err := tx.Bucket(scriptRevisionsBucket).CreateBucketIfNotExists([]byte(script.ID))
+		if err != nil {
+			return err
+		}
+
+		latest, err := latestScriptRevision(revisions)
+		if err != nil {
+			return err
+		}
+		if latest == nil {
+			// 早于版本历史创建的脚本：先把当前内容记录为初始版本
+			if data := bucket.Get([]byte(script.ID)); data != nil {
+				var old models.Script
+				if err := json.Unmarshal(data, &old); err != nil {
+					return err
+				}
+				old.Revision = 1
+				latest = newScriptRevision(&old, "", "Initial revision")
+				if err := putScriptRevision(revisions, latest); err != nil {
+					return err
+				}
+			}
+		}
+
+		if latest != nil && latest.Script.SameContent(script) {
+			script.Revision = latest.Revision
+		} else {
+			var base *models.Script
+			script.Revision = 1
+			if latest != nil {
+				base = latest.Script
+				script.Revision = latest.Revision + 1
+			}
+			if summary == "" {
+				if base == nil {
+					summary = "Created"
+				} else {
+					summary = models.DiffScripts(base, script).Summary()
+				}
+			}
+			if err := putScriptRevision(revisions, newScriptRevision(script, author, summary)); err != nil {
+				return err
+			}
+		}
+
 		data, err := json.Marshal(script)
 		if err != nil {
 			return err
@@ -229,25 +311,210 @@ func (b *BoltDB) ListScripts() ([]*models.Script, error) {
 
 // UpdateScript 更新脚本
 func (b *BoltDB) UpdateScript(script *models.Script) error {
+	return b.UpdateScriptWithRevision(script, "", "")
+}
+
+// UpdateScriptWithRevision 更新脚本并记录修改人和变更摘要
+func (b *BoltDB) UpdateScriptWithRevision(script *models.Script, author, summary string) error {
 	script.UpdatedAt = time.Now()
-	return b.SaveScript(script)
+	return b.SaveScriptWithRevision(script, author, summary)
 }
 
-// DeleteScript 删除脚本
+// DeleteScript 删除脚本（同时删除其历史版本）
 func (b *BoltDB) DeleteScript(id string) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(scriptsBucket)
-		return bucket.Delete([]byte(id))
+		if err := bucket.Delete([]byte(id)); err != nil {
+			return err
+		}
+		err := tx.Bucket(scriptRevisionsBucket).DeleteBucket([]byte(id))
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		return nil
+	})
+}
+
+// ============= 脚本历史版本相关方法 =============
+
+// ListScriptRevisions 列出脚本的历史版本（按版本号倒序，不包含脚本快照）
+func (b *BoltDB) ListScriptRevisions(scriptID string) ([]*models.ScriptRevision, error) {
+	revisions := []*models.ScriptRevision{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(scriptRevisionsBucket).Bucket([]byte(scriptID))
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var revision models.ScriptRevision
+			if err := json.Unmarshal(v, &revision); err != nil {
+				return err
+			}
+			revision.Script = nil
+			revisions = append(revisions, &revision)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetScriptRevision 获取脚本的指定历史版本（包含脚本快照）
+func (b *BoltDB) GetScriptRevision(scriptID string, revision int) (*models.ScriptRevision, error) {
+	var result models.ScriptRevision
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(scriptRevisionsBucket).Bucket([]byte(scriptID))
+		if bucket == nil {
+			return fmt.Errorf("Script revision not found")
+		}
+		data := bucket.Get(scriptRevisionKey(revision))
+		if data == nil {
+			return fmt.Errorf("Script revision not found")
+		}
+		return json.Unmarshal(data, &result)
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// newScriptRevision 根据脚本内容创建历史版本记录
+func newScriptRevision(script *models.Script, author, summary string) *models.ScriptRevision {
+	return &models.ScriptRevision{
+		ScriptID:    script.ID,
+		Revision:    script.Revision,
+		Author:      author,
+		Summary:     summary,
+		ActionCount: len(script.Actions),
+		CreatedAt:   time.Now(),
+		Script:      script,
+	}
+}
+
+// latestScriptRevision 获取最新的历史版本，没有历史版本时返回 nil
+func latestScriptRevision(bucket *bolt.Bucket) (*models.ScriptRevision, error) {
+	k, v := bucket.Cursor().Last()
+	if k == nil {
+		return nil, nil
+	}
+	var revision models.ScriptRevision
+	if err := json.Unmarshal(v, &revision); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// putScriptRevision 写入历史版本（已存在的版本不允许覆盖）
+func putScriptRevision(bucket *bolt.Bucket, revision *models.ScriptRevision) error {
+	key := scriptRevisionKey(revision.Revision)
+	if bucket.Get(key) != nil {
+		return fmt.Errorf("script revision %d already exists", revision.Revision)
+	}
+	data, err := json.Marshal(revision)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
+
+// scriptRevisionKey 版本号编码为大端序，保证游标按版本号顺序遍历
+func scriptRevisionKey(revision int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(revision))
+	return key
 }
 
 // ============= LLM 配置相关方法 =============
 
+// SetCipher 设置敏感字段的加密方式，并加密已保存的明文 LLM API Key（旧版本格式的密文重新加密）
+func (b *BoltDB) SetCipher(cipher Cipher) error {
+	b.cipher = cipher
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(llmConfigsBucket)
+		updates := make(map[string][]byte)
+		err := bucket.ForEach(func(k, v []byte) error {
+			var config models.LLMConfigModel
+			if err := config.FromJSON(v); err != nil {
+				return err
+			}
+			if secretbox.IsLegacy(config.APIKey) {
+				apiKey, err := cipher.Open(config.APIKey)
+				if err != nil {
+					// 无法解密时保留原密文，读取时会提示
+					return nil
+				}
+				config.APIKey = apiKey
+			}
+			if config.APIKey == "" || secretbox.IsSealed(config.APIKey) {
+				return nil
+			}
+			data, err := b.encodeLLMConfig(&config)
+			if err != nil {
+				return err
+			}
+			updates[string(k)] = data
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for k, data := range updates {
+			if err := bucket.Put([]byte(k), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// encodeLLMConfig 序列化 LLM 配置（设置了加密方式时加密 API Key）
+func (b *BoltDB) encodeLLMConfig(config *models.LLMConfigModel) ([]byte, error) {
+	if b.cipher == nil || config.APIKey == "" || secretbox.IsSealed(config.APIKey) {
+		return config.ToJSON()
+	}
+	stored := *config
+	sealed, err := b.cipher.Seal(config.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt api key: %w", err)
+	}
+	stored.APIKey = sealed
+	return stored.ToJSON()
+}
+
+// decodeLLMConfig 解析 LLM 配置并解密 API Key
+// 无法解密（未配置密钥或密钥已更换）时保留密文并将配置标记为未启用：
+// 保存时已加密的值原样写回，恢复密钥后即可重新解密，API Key 不会被覆盖丢失
+func (b *BoltDB) decodeLLMConfig(data []byte, config *models.LLMConfigModel) error {
+	if err := config.FromJSON(data); err != nil {
+		return err
+	}
+	if !secretbox.IsSealed(config.APIKey) {
+		return nil
+	}
+	if b.cipher == nil {
+		logger.Warn(context.Background(), "LLM config %s has an encrypted API key but BROWSERWING_SECRETS_KEY is not set, the config is disabled", config.Name)
+		config.IsActive = false
+		return nil
+	}
+	apiKey, err := b.cipher.Open(config.APIKey)
+	if err != nil {
+		logger.Warn(context.Background(), "Failed to decrypt API key of LLM config %s, the config is disabled: %v", config.Name, err)
+		config.IsActive = false
+		return nil
+	}
+	config.APIKey = apiKey
+	return nil
+}
+
 // SaveLLMConfig 保存 LLM 配置
 func (b *BoltDB) SaveLLMConfig(config *models.LLMConfigModel) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(llmConfigsBucket)
-		data, err := config.ToJSON()
+		data, err := b.encodeLLMConfig(config)
 		if err != nil {
 			return err
 		}
@@ -264,7 +531,7 @@ func (b *BoltDB) GetLLMConfig(id string) (*models.LLMConfigModel, error) {
 		if data == nil {
 			return fmt.Errorf("LLM config not found")
 		}
-		return config.FromJSON(data)
+		return b.decodeLLMConfig(data, &config)
 	})
 	if err != nil {
 		return nil, err
@@ -279,7 +546,7 @@ func (b *BoltDB) ListLLMConfigs() ([]*models.LLMConfigModel, error) {
 		bucket := tx.Bucket(llmConfigsBucket)
 		return bucket.ForEach(func(k, v []byte) error {
 			var config models.LLMConfigModel
-			if err := config.FromJSON(v); err != nil {
+			if err := b.decodeLLMConfig(v, &config); err != nil {
 				return err
 			}
 			configs = append(configs, &config)
@@ -323,7 +590,7 @@ func (b *BoltDB) GetDefaultLLMConfig() (*models.LLMConfigModel, error) {
 		bucket := tx.Bucket(llmConfigsBucket)
 		return bucket.ForEach(func(k, v []byte) error {
 			var config models.LLMConfigModel
-			if err := config.FromJSON(v); err != nil {
+			if err := b.decodeLLMConfig(v, &config); err != nil {
 				return err
 			}
 			if config.IsDefault && config.IsActive {
@@ -749,6 +1016,261 @@ func (b *BoltDB) DeleteScriptExecutionsByScriptID(scriptID string) error {
 	})
 }
 
+// ============= 数据驱动运行记录相关方法 =============
+
+// SaveDataRun 保存数据驱动运行记录
+func (b *BoltDB) SaveDataRun(run *models.DataRun) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(dataRunsBucket).Put([]byte(run.ID), data)
+	})
+}
+
+// GetDataRun 获取数据驱动运行记录
+func (b *BoltDB) GetDataRun(id string) (*models.DataRun, error) {
+	var run models.DataRun
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(dataRunsBucket).Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("data run not found")
+		}
+		return json.Unmarshal(data, &run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListDataRuns 列出数据驱动运行记录（支持按脚本ID过滤，最新的在前）
+func (b *BoltDB) ListDataRuns(scriptID string) ([]*models.DataRun, error) {
+	runs := []*models.DataRun{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(dataRunsBucket).ForEach(func(k, v []byte) error {
+			var run models.DataRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			if scriptID == "" || run.ScriptID == scriptID {
+				runs = append(runs, &run)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartTime.After(runs[j].StartTime)
+	})
+	return runs, nil
+}
+
+// DeleteDataRun 删除数据驱动运行记录
+func (b *BoltDB) DeleteDataRun(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(dataRunsBucket).Delete([]byte(id))
+	})
+}
+
+// ============= 密钥相关方法 =============
+
+// SaveSecret 保存密钥（Value 由调用方加密）
+func (b *BoltDB) SaveSecret(secret *models.Secret) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(secret)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(secretsBucket).Put([]byte(secret.Name), data)
+	})
+}
+
+// GetSecret 获取密钥
+func (b *BoltDB) GetSecret(name string) (*models.Secret, error) {
+	var secret models.Secret
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(secretsBucket).Get([]byte(name))
+		if data == nil {
+			return fmt.Errorf("secret not found")
+		}
+		return json.Unmarshal(data, &secret)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// ListSecrets 列出所有密钥（按名称排序）
+func (b *BoltDB) ListSecrets() ([]*models.Secret, error) {
+	secrets := []*models.Secret{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(secretsBucket).ForEach(func(k, v []byte) error {
+			var secret models.Secret
+			if err := json.Unmarshal(v, &secret); err != nil {
+				return err
+			}
+			secrets = append(secrets, &secret)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// DeleteSecret 删除密钥
+func (b *BoltDB) DeleteSecret(name string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(secretsBucket).Delete([]byte(name))
+	})
+}
+
+// ============= 数据集相关方法 =============
+
+// ErrDatasetNotFound 数据集不存在
+var ErrDatasetNotFound = fmt.Errorf("dataset not found")
+
+// AppendDatasetRecord 向数据集追加一条记录（数据集不存在时自动创建），记录 ID 按写入顺序递增
+func (b *BoltDB) AppendDatasetRecord(name string, record *models.OutputRecord) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(datasetsBucket).CreateBucketIfNotExists([]byte(name))
+		if err != nil {
+			return err
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		record.ID = fmt.Sprintf("%d", seq)
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		return bucket.Put(key, data)
+	})
+}
+
+// ListDatasets 列出所有数据集（按名称排序）
+func (b *BoltDB) ListDatasets() ([]*models.DatasetInfo, error) {
+	datasets := []*models.DatasetInfo{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(datasetsBucket).ForEachBucket(func(k []byte) error {
+			info, err := datasetInfo(tx.Bucket(datasetsBucket).Bucket(k), string(k))
+			if err != nil {
+				return err
+			}
+			datasets = append(datasets, info)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return datasets, nil
+}
+
+// GetDataset 获取数据集概要
+func (b *BoltDB) GetDataset(name string) (*models.DatasetInfo, error) {
+	var info *models.DatasetInfo
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(datasetsBucket).Bucket([]byte(name))
+		if bucket == nil {
+			return ErrDatasetNotFound
+		}
+		var err error
+		info, err = datasetInfo(bucket, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// datasetInfo 统计数据集的记录数和时间范围（记录按写入顺序存储）
+func datasetInfo(bucket *bolt.Bucket, name string) (*models.DatasetInfo, error) {
+	info := &models.DatasetInfo{Name: name, Count: bucket.Stats().KeyN}
+	cursor := bucket.Cursor()
+	if _, v := cursor.First(); v != nil {
+		var first models.OutputRecord
+		if err := json.Unmarshal(v, &first); err != nil {
+			return nil, err
+		}
+		info.FirstAt = first.Timestamp
+	}
+	if _, v := cursor.Last(); v != nil {
+		var last models.OutputRecord
+		if err := json.Unmarshal(v, &last); err != nil {
+			return nil, err
+		}
+		info.LastAt = last.Timestamp
+	}
+	return info, nil
+}
+
+// QueryDatasetRecords 按条件查询数据集记录，返回分页后的记录和符合条件的总数
+func (b *BoltDB) QueryDatasetRecords(name string, query models.DatasetQuery) ([]*models.OutputRecord, int, error) {
+	records := []*models.OutputRecord{}
+	total := 0
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(datasetsBucket).Bucket([]byte(name))
+		if bucket == nil {
+			return ErrDatasetNotFound
+		}
+		cursor := bucket.Cursor()
+		k, v := cursor.First()
+		next := cursor.Next
+		if query.Desc {
+			k, v = cursor.Last()
+			next = cursor.Prev
+		}
+		for ; k != nil; k, v = next() {
+			var record models.OutputRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+			if query.ScriptID != "" && record.ScriptID != query.ScriptID {
+				continue
+			}
+			if !query.Since.IsZero() && record.Timestamp.Before(query.Since) {
+				continue
+			}
+			if !query.Until.IsZero() && record.Timestamp.After(query.Until) {
+				continue
+			}
+			total++
+			if total <= query.Offset || (query.Limit > 0 && len(records) >= query.Limit) {
+				continue
+			}
+			records = append(records, &record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// DeleteDataset 删除数据集及其全部记录
+func (b *BoltDB) DeleteDataset(name string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(datasetsBucket).DeleteBucket([]byte(name))
+		if err == bolt.ErrBucketNotFound {
+			return ErrDatasetNotFound
+		}
+		return err
+	})
+}
+
 // ============= 录制配置相关方法 =============
 
 // SaveRecordingConfig 保存录制配置
@@ -165,6 +165,7 @@ export interface BrowserInstance {
   headless?: boolean | null
   launch_args?: string[]
   proxy?: string
+  max_concurrent_runs?: number  // 同时执行的回放数上限（0 表示使用全局配置）
   created_at: string
   updated_at: string
 }
@@ -248,6 +249,7 @@ export interface ScriptAction {
   js_code?: string
   variable_name?: string
   extracted_data?: string
+  transform?: string  // 抓取结果的转换链，例如 "regex:([\\d.,]+)|number|default:0"
   // 文件上传相关字段
   file_paths?: string[]
   file_names?: string[]
@@ -269,11 +271,23 @@ export interface ScriptAction {
   screenshot_width?: number
   screenshot_height?: number
 
+  // 视口相关字段（用于 set_viewport 类型）
+  viewport_width?: number
+  viewport_height?: number
+  device_scale_factor?: number  // 默认 1
+  mobile?: boolean
+
   // AI 控制相关字段（用于 ai_control 类型）
   ai_control_prompt?: string         // AI 控制的提示词
   ai_control_xpath?: string          // 可选的元素 XPath（用于提示词上下文）
   ai_control_llm_config_id?: string  // AI 控制使用的 LLM 配置 ID（为空则使用默认）
 
+  // 定位路径（元素位于嵌套 iframe 或 Shadow DOM 中时，selector/xpath 相对于最内层上下文）
+  frame_path?: {
+    type: 'iframe' | 'shadow'
+    selector: string
+  }[]
+
   // 语义信息字段（用于自愈）
   intent?: {
     verb?: string
@@ -295,13 +309,42 @@ export interface ScriptAction {
     confidence?: number
   }
   
-  // 条件执行
-  condition?: {
-    variable: string      // 变量名
-    operator: string      // 操作符: =, !=, >, <, >=, <=, in, not_in, contains, not_contains, exists, not_exists
-    value: string         // 比较值
-    enabled?: boolean     // 是否启用条件
-  }
+  // 条件执行（if 类型：条件成立时执行 actions，否则执行 else_actions）
+  condition?: ActionCondition
+  actions?: ScriptAction[]
+  else_actions?: ScriptAction[]
+}
+
+export interface ActionCondition {
+  type?: 'variable' | 'element_exists' | 'element_visible' | 'url_matches' | 'text_present' | 'js' | 'and' | 'or'
+  variable?: string      // 变量名
+  operator?: string      // 操作符: =, !=, >, <, >=, <=, in, not_in, contains, not_contains, exists, not_exists
+  value?: string         // 比较值
+  transform?: string     // 比较前对变量值应用的转换链（与抓取操作的 transform 相同）
+  enabled?: boolean      // 是否启用条件
+  selector?: string      // 页面状态条件: CSS 选择器
+  xpath?: string         // 页面状态条件: XPath
+  pattern?: string       // url_matches: URL 正则
+  text?: string          // text_present: 文本
+  expression?: string    // js: JS 表达式
+  timeout?: number       // 页面状态条件最多等待时长（毫秒）
+  negate?: boolean       // 对结果取反
+  conditions?: ActionCondition[]  // and / or: 子条件
+}
+
+// 脚本参数类型
+export type ParameterType = 'string' | 'number' | 'boolean' | 'enum' | 'date' | 'file' | 'list'
+
+// 脚本参数定义（回放前校验，MCP 输入 schema 由参数定义生成）
+export interface ScriptParameter {
+  name: string
+  type?: ParameterType   // 为空时为 string
+  description?: string
+  required?: boolean
+  default?: string
+  pattern?: string       // 正则表达式（list 校验每一项）
+  enum?: string[]        // enum 类型的可选值
+  format?: string        // date 类型的日期格式（Go 时间格式，默认 2006-01-02）
 }
 
 export interface Script {
@@ -322,6 +365,105 @@ export interface Script {
   mcp_command_description?: string
   mcp_input_schema?: Record<string, any>
   variables?: Record<string, string>  // 预设变量
+  parameters?: ScriptParameter[]  // 参数定义
+  output_sinks?: OutputSink[]  // 抓取数据的输出目标
+  revision?: number  // 当前版本号
+}
+
+export interface ScriptRevision {
+  script_id: string
+  revision: number
+  author?: string
+  summary: string
+  action_count: number
+  created_at: string
+  script?: Script
+}
+
+export interface ActionDiff {
+  op: 'added' | 'removed' | 'modified'
+  old_index: number  // 新增时为 -1
+  new_index: number  // 删除时为 -1
+  fields?: string[]
+  old?: ScriptAction
+  new?: ScriptAction
+}
+
+export interface ScriptDiff {
+  from_revision: number
+  to_revision: number
+  fields?: string[]
+  actions: ActionDiff[]
+  added: number
+  removed: number
+  modified: number
+}
+
+export type BundleConflictMode = 'skip' | 'overwrite' | 'rename'
+
+export interface BundleImportItem {
+  kind: 'script' | 'scheduled_task' | 'browser_config' | 'file'
+  source_id: string
+  id: string
+  name: string
+  action: 'created' | 'overwritten' | 'renamed' | 'skipped'
+}
+
+export interface BundleImportReport {
+  items: BundleImportItem[]
+  created: number
+  overwritten: number
+  renamed: number
+  skipped: number
+  warnings?: string[]
+}
+
+export type RecordingFormat = 'devtools' | 'selenium'
+
+export interface RecordingImportIssue {
+  script: string
+  step: number
+  type: string
+  reason: string
+}
+
+export interface RecordingImportResult {
+  message: string
+  format: RecordingFormat
+  scripts: Script[]
+  unmapped: RecordingImportIssue[]
+  warnings?: RecordingImportIssue[]
+  saved: boolean
+}
+
+export interface ValidationIssue {
+  severity: 'error' | 'warning'
+  index: number  // 顶层操作序号（从 0 开始，脚本级问题为 -1）
+  step?: string  // 步骤编号（从 1 开始，嵌套步骤如 "3.2"，else 分支如 "3.else.1"）
+  type?: string
+  code: string
+  message: string
+}
+
+export interface ValidationReport {
+  valid: boolean
+  errors: ValidationIssue[]
+  warnings: ValidationIssue[]
+}
+
+export type CodeTarget = 'go-rod' | 'playwright-ts' | 'playwright-python' | 'puppeteer'
+
+export interface CodeTODO {
+  step: string
+  type: string
+  reason: string
+}
+
+export interface CodeExportResult {
+  target: CodeTarget
+  file_name: string
+  code: string
+  todos: CodeTODO[]
 }
 
 export interface SaveScriptRequest {
@@ -334,6 +476,9 @@ export interface SaveScriptRequest {
   can_publish?: boolean
   can_fetch?: boolean
   variables?: Record<string, string>  // 预设变量
+  parameters?: ScriptParameter[]  // 参数定义
+  output_sinks?: OutputSink[]  // 抓取数据的输出目标
+  summary?: string  // 变更摘要（仅更新时使用）
 }
 
 export interface PlayResult {
@@ -347,6 +492,7 @@ export interface ScriptExecution {
   id: string
   script_id: string
   script_name: string
+  script_revision?: number
   start_time: string
   end_time: string
   duration: number
@@ -361,6 +507,108 @@ export interface ScriptExecution {
   created_at: string
 }
 
+export type DataRunStatus = 'running' | 'success' | 'failed' | 'cancelled'
+export type DataRowStatus = 'pending' | 'running' | 'success' | 'failed' | 'skipped'
+
+export interface DataRunRow {
+  index: number  // 行号（从 0 开始，不含表头）
+  variables: Record<string, string>
+  status: DataRowStatus
+  attempts?: number
+  execution_id?: string
+  error?: string
+  extracted_data?: Record<string, any>
+  start_time?: string
+  end_time?: string
+  duration?: number
+}
+
+export interface DataRunSummary {
+  id: string
+  script_id: string
+  script_name: string
+  dataset_name?: string
+  status: DataRunStatus
+  total_rows: number
+  succeeded: number
+  failed: number
+  skipped: number
+  start_time: string
+  duration: number
+}
+
+export interface DataRun extends DataRunSummary {
+  script_revision?: number
+  instance_id?: string
+  format: 'csv' | 'jsonl' | 'json'
+  columns: string[]
+  concurrency: number
+  on_row_error: 'continue' | 'stop'
+  retry_count?: number
+  message?: string
+  rows: DataRunRow[]
+  end_time?: string
+  created_at: string
+}
+
+export interface DataRunOptions {
+  format?: 'csv' | 'jsonl' | 'json'  // 为空时根据文件扩展名或内容识别
+  concurrency?: number
+  onRowError?: 'continue' | 'stop'
+  retryCount?: number
+  instanceId?: string
+}
+
+// 抓取数据的输出目标（脚本或定时任务每次执行完成后追加写入）
+export type OutputSinkType = 'csv' | 'jsonl' | 'webhook' | 'dataset'
+
+export interface OutputSink {
+  type: OutputSinkType
+  path?: string  // csv、jsonl：资源目录下 outputs 目录中的相对路径
+  url?: string  // webhook：请求地址（支持 ${secret:name}）
+  headers?: Record<string, string>  // webhook：附加请求头
+  retry_count?: number  // webhook：失败后的重试次数（不填时默认 3，0 表示不重试）
+  dataset?: string  // dataset：数据集名称
+  fields?: string[]  // 只输出这些抓取字段（为空时输出全部）
+}
+
+// 数据集中的一条记录
+export interface OutputRecord {
+  id?: string
+  timestamp: string
+  script_id: string
+  script_name: string
+  execution_id?: string
+  task_id?: string
+  success: boolean
+  error?: string  // 执行失败时的错误信息
+  data: Record<string, any>
+}
+
+export interface DatasetInfo {
+  name: string
+  count: number
+  first_at?: string
+  last_at?: string
+}
+
+export interface DatasetQuery {
+  script_id?: string
+  since?: string  // RFC3339
+  until?: string  // RFC3339
+  page?: number
+  page_size?: number
+  order?: 'asc' | 'desc'
+}
+
+// 加密保存的密钥（接口不返回值，脚本中通过 ${secret:name} 引用）
+export interface Secret {
+  name: string
+  description?: string
+  created_at: string
+  updated_at: string
+}
+
 export interface RecordingConfig {
   id: string
   enabled: boolean
@@ -663,17 +911,33 @@ export const api = {
     client.get<Script>(`/scripts/${id}`),
 
   saveScript: (data: SaveScriptRequest) =>
-    client.post<{ message: string; script: Script }>('/scripts', data),
+    client.post<{ message: string; script: Script; validation: ValidationReport }>('/scripts', data),
 
   createScript: (data: SaveScriptRequest) =>
-    client.post<{ message: string; script: Script }>('/scripts', data),
+    client.post<{ message: string; script: Script; validation: ValidationReport }>('/scripts', data),
 
   updateScript: (id: string, data: Partial<SaveScriptRequest>) =>
-    client.put<{ message: string; script: Script }>(`/scripts/${id}`, data),
+    client.put<{ message: string; script: Script; validation: ValidationReport }>(`/scripts/${id}`, data),
+
+  // 静态校验脚本（draft 为编辑中尚未保存的修改）
+  validateScript: (id: string, draft?: Partial<Script>) =>
+    client.post<ValidationReport>(`/scripts/${id}/validate`, draft),
 
   deleteScript: (id: string) =>
     client.delete<{ message: string }>(`/scripts/${id}`),
 
+  listScriptRevisions: (id: string) =>
+    client.get<{ revisions: ScriptRevision[]; total: number }>(`/scripts/${id}/revisions`),
+
+  getScriptRevision: (id: string, revision: number) =>
+    client.get<ScriptRevision>(`/scripts/${id}/revisions/${revision}`),
+
+  diffScriptRevisions: (id: string, from?: number, to?: number) =>
+    client.get<{ diff: ScriptDiff; summary: string }>(`/scripts/${id}/revisions/diff`, { params: { from, to } }),
+
+  restoreScriptRevision: (id: string, revision: number) =>
+    client.post<{ message: string; script: Script }>(`/scripts/${id}/revisions/${revision}/restore`),
+
   playScript: (id: string, params?: Record<string, string>, instanceId?: string) =>
     client.post<{ message: string; script: string; result: PlayResult }>(`/scripts/${id}/play`, { 
       params,
@@ -694,6 +958,41 @@ export const api = {
   exportScriptsSkill: (scriptIds?: string[]) =>
     client.post('/scripts/export/skill', { script_ids: scriptIds || [] }, { responseType: 'blob' }),
 
+  // 导出脚本为可运行源码
+  exportScriptCode: (id: string, target: CodeTarget) =>
+    client.get<CodeExportResult>(`/scripts/${id}/export/code`, { params: { target } }),
+
+  downloadScriptCode: (id: string, target: CodeTarget) =>
+    client.get(`/scripts/${id}/export/code`, { params: { target, download: true }, responseType: 'blob' }),
+
+  // 脚本包导出/导入（在不同服务之间迁移脚本）
+  exportScriptsBundle: (options?: { scriptIds?: string[]; format?: 'zip' | 'json'; includeTasks?: boolean; includeBrowserConfigs?: boolean }) =>
+    client.get('/scripts/export', {
+      params: {
+        ids: options?.scriptIds?.join(','),
+        format: options?.format,
+        include_tasks: options?.includeTasks,
+        include_browser_configs: options?.includeBrowserConfigs,
+      },
+      responseType: 'blob',
+    }),
+
+  importScriptsBundle: (file: File, conflict: BundleConflictMode = 'skip') => {
+    const formData = new FormData()
+    formData.append('file', file)
+    formData.append('conflict', conflict)
+    return client.post<{ message: string; report: BundleImportReport }>('/scripts/import', formData)
+  },
+
+  // 导入 Chrome DevTools Recorder / Selenium IDE 录制文件
+  importRecording: (file: File, options?: { format?: RecordingFormat; dryRun?: boolean }) => {
+    const formData = new FormData()
+    formData.append('file', file)
+    if (options?.format) formData.append('format', options.format)
+    if (options?.dryRun) formData.append('dry_run', 'true')
+    return client.post<RecordingImportResult>('/scripts/import/recording', formData)
+  },
+
   // AI 提取相关
   generateExtractionJS: (data: { html: string; description?: string }) =>
     client.post<{ javascript: string; used_model: string; message: string }>('/browser/generate-extraction-js', data),
@@ -791,6 +1090,56 @@ export const api = {
   batchDeleteScriptExecutions: (ids: string[]) =>
     client.post<{ message: string; count: number }>('/script-executions/batch/delete', { ids }),
 
+  // 数据驱动运行（按数据集逐行执行脚本）
+  startDataRun: (scriptId: string, file: File, options?: DataRunOptions) => {
+    const formData = new FormData()
+    formData.append('file', file)
+    if (options?.format) formData.append('format', options.format)
+    if (options?.concurrency) formData.append('concurrency', String(options.concurrency))
+    if (options?.onRowError) formData.append('on_row_error', options.onRowError)
+    if (options?.retryCount) formData.append('retry_count', String(options.retryCount))
+    if (options?.instanceId) formData.append('instance_id', options.instanceId)
+    return client.post<{ message: string; run: DataRun }>(`/scripts/${scriptId}/data-runs`, formData)
+  },
+
+  listDataRuns: (scriptId?: string) =>
+    client.get<{ runs: DataRunSummary[]; total: number }>('/data-runs', { params: { script_id: scriptId } }),
+
+  getDataRun: (id: string) =>
+    client.get<DataRun>(`/data-runs/${id}`),
+
+  downloadDataRun: (id: string, format: 'csv' | 'jsonl' = 'csv') =>
+    client.get(`/data-runs/${id}/export`, { params: { format }, responseType: 'blob' }),
+
+  cancelDataRun: (id: string) =>
+    client.post<{ message: string }>(`/data-runs/${id}/cancel`),
+
+  deleteDataRun: (id: string) =>
+    client.delete<{ message: string }>(`/data-runs/${id}`),
+
+  // 数据集（输出目标写入的历史记录）
+  listDatasets: () =>
+    client.get<{ datasets: DatasetInfo[] }>('/datasets'),
+
+  queryDatasetRecords: (name: string, params?: DatasetQuery) =>
+    client.get<{ records: OutputRecord[]; total: number; page: number; page_size: number }>(`/datasets/${encodeURIComponent(name)}/records`, { params }),
+
+  downloadDataset: (name: string, format: 'csv' | 'jsonl' = 'csv', params?: Omit<DatasetQuery, 'page' | 'page_size' | 'order'>) =>
+    client.get(`/datasets/${encodeURIComponent(name)}/export`, { params: { ...params, format }, responseType: 'blob' }),
+
+  deleteDataset: (name: string) =>
+    client.delete<{ message: string }>(`/datasets/${encodeURIComponent(name)}`),
+
+  // 密钥相关
+  listSecrets: () =>
+    client.get<{ secrets: Secret[]; enabled: boolean }>('/secrets'),
+
+  setSecret: (name: string, value: string, description?: string) =>
+    client.put<{ message: string; secret: Secret }>(`/secrets/${encodeURIComponent(name)}`, { value, description }),
+
+  deleteSecret: (name: string) =>
+    client.delete<{ message: string }>(`/secrets/${encodeURIComponent(name)}`),
+
   // 录制配置相关
   getRecordingConfig: () => client.get<RecordingConfig>('/recording-config'),
   updateRecordingConfig: (config: RecordingConfig) => client.put('/recording-config', config),
@@ -977,6 +1326,7 @@ export interface ScheduledTask {
   script_name?: string
   script_variables?: Record<string, string>
   browser_instance_id?: string
+  output_sinks?: OutputSink[]  // 执行结果的输出目标
   agent_prompt?: string
   agent_llm_id?: string
   agent_llm_name?: string
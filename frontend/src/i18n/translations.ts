@@ -60,6 +60,57 @@ export const translations = {
     'error.scriptNotFound': '脚本未找到',
     'error.updateScriptFailed': '更新脚本失败',
     'error.playScriptFailed': '脚本播放失败',
+    'error.scriptExecutionCancelled': '脚本执行已取消',
+    'error.executionNotRunning': '执行记录不存在或已结束',
+    'error.executionArtifactNotFound': '执行现场文件不存在',
+    'error.debugSessionNotFound': '调试会话不存在或已过期',
+    'error.debugNotPaused': '调试会话未处于暂停状态',
+    'success.debugSessionStarted': '调试回放已启动',
+    'success.debugCommandSent': '调试命令已发送',
+    'success.debugBreakpointsUpdated': '断点已更新',
+    'error.scriptRevisionNotFound': '脚本版本不存在',
+    'error.listScriptRevisionsFailed': '获取脚本版本列表失败',
+    'error.restoreScriptRevisionFailed': '恢复脚本版本失败',
+    'success.scriptRevisionRestored': '脚本已恢复到指定版本',
+    'error.exportBundleFailed': '导出脚本包失败',
+    'error.exportCodeFailed': '导出源码失败',
+    'error.importBundleFailed': '导入脚本包失败',
+    'error.invalidBundle': '无效的脚本包文件',
+    'error.unsupportedBundleVersion': '不支持的脚本包版本',
+    'success.bundleImported': '脚本包导入完成',
+    'error.invalidRecording': '无效的录制文件',
+    'error.unknownRecordingFormat': '无法识别的录制文件格式',
+    'error.importRecordingFailed': '导入录制文件失败',
+    'error.scriptValidationFailed': '脚本校验未通过',
+    'error.invalidScriptParams': '脚本参数校验失败',
+    'error.invalidParameterDefinition': '脚本参数定义无效',
+    'error.invalidDataset': '无效的数据集文件',
+    'error.startDataRunFailed': '启动数据驱动运行失败',
+    'error.getDataRunsFailed': '获取数据驱动运行记录失败',
+    'error.dataRunNotFound': '数据驱动运行记录不存在',
+    'error.exportDataRunFailed': '导出运行结果失败',
+    'error.dataRunNotRunning': '数据驱动运行未在执行',
+    'error.dataRunStillRunning': '数据驱动运行仍在执行，请先取消',
+    'error.deleteDataRunFailed': '删除数据驱动运行记录失败',
+    'success.dataRunStarted': '数据驱动运行已开始',
+    'success.dataRunCancelled': '数据驱动运行已取消',
+    'success.dataRunDeleted': '数据驱动运行记录已删除',
+    'error.listSecretsFailed': '获取密钥列表失败',
+    'error.secretsDisabled': '密钥存储未启用，请配置 secrets.key 或 BROWSERWING_SECRETS_KEY',
+    'error.invalidSecretName': '密钥名称只能包含字母、数字、_、- 和 .',
+    'error.saveSecretFailed': '保存密钥失败',
+    'error.secretNotFound': '密钥不存在',
+    'error.deleteSecretFailed': '删除密钥失败',
+    'success.secretSaved': '密钥已保存',
+    'success.secretDeleted': '密钥已删除',
+    'error.invalidOutputSink': '输出目标配置无效',
+    'error.listDatasetsFailed': '获取数据集列表失败',
+    'error.datasetNotFound': '数据集不存在',
+    'error.queryDatasetFailed': '查询数据集失败',
+    'error.exportDatasetFailed': '导出数据集失败',
+    'error.deleteDatasetFailed': '删除数据集失败',
+    'success.datasetDeleted': '数据集已删除',
+    'success.recordingImported': '录制文件导入完成',
     'error.getLLMConfigsFailed': '获取LLM配置失败',
     'error.llmConfigNotFound': 'LLM配置未找到',
     'error.llmConfigRequiredFields': '名称、提供商和模型是必填的',
@@ -147,6 +198,7 @@ export const translations = {
     'success.scriptUpdated': '脚本已更新',
     'success.scriptDeleted': '脚本已删除',
     'success.scriptPlaybackCompleted': '脚本播放完成',
+    'success.scriptExecutionCancelled': '已取消脚本执行',
     'success.llmConfigCreated': 'LLM配置已创建',
     'success.llmConfigUpdated': 'LLM配置已更新',
     'success.llmConfigDeleted': 'LLM配置已删除',
@@ -948,6 +1000,8 @@ export const translations = {
     'browser.config.proxy': '代理地址',
     'browser.config.proxyPlaceholder': 'http://127.0.0.1:7890 或 socks5://127.0.0.1:1080',
     'browser.config.proxyHint': '支持 HTTP/HTTPS 和 SOCKS5 代理。带认证：http://user:pass@ip:port 或 socks5://user:pass@ip:port',
+    'browser.instance.maxConcurrentRuns': '最大并发回放数',
+    'browser.instance.maxConcurrentRunsHint': '该实例同时执行的脚本回放数上限，超出时排队等待；0 表示使用全局配置（默认 3），重新启动实例后生效',
     // 浏览器实例管理
     'browser.instance.title': '浏览器实例管理',
     'browser.instance.manage': '管理实例',
@@ -1261,6 +1315,57 @@ export const translations = {
     'error.scriptNotFound': '腳本未找到',
     'error.updateScriptFailed': '更新腳本失敗',
     'error.playScriptFailed': '腳本播放失敗',
+    'error.scriptExecutionCancelled': '腳本執行已取消',
+    'error.executionNotRunning': '執行記錄不存在或已結束',
+    'error.executionArtifactNotFound': '執行現場檔案不存在',
+    'error.debugSessionNotFound': '調試會話不存在或已過期',
+    'error.debugNotPaused': '調試會話未處於暫停狀態',
+    'success.debugSessionStarted': '調試回放已啟動',
+    'success.debugCommandSent': '調試命令已發送',
+    'success.debugBreakpointsUpdated': '斷點已更新',
+    'error.scriptRevisionNotFound': '腳本版本不存在',
+    'error.listScriptRevisionsFailed': '獲取腳本版本列表失敗',
+    'error.restoreScriptRevisionFailed': '恢復腳本版本失敗',
+    'success.scriptRevisionRestored': '腳本已恢復到指定版本',
+    'error.exportBundleFailed': '匯出腳本包失敗',
+    'error.exportCodeFailed': '匯出原始碼失敗',
+    'error.importBundleFailed': '匯入腳本包失敗',
+    'error.invalidBundle': '無效的腳本包檔案',
+    'error.unsupportedBundleVersion': '不支援的腳本包版本',
+    'success.bundleImported': '腳本包匯入完成',
+    'error.invalidRecording': '無效的錄製檔案',
+    'error.unknownRecordingFormat': '無法識別的錄製檔案格式',
+    'error.importRecordingFailed': '匯入錄製檔案失敗',
+    'error.scriptValidationFailed': '腳本校驗未通過',
+    'error.invalidScriptParams': '腳本參數校驗失敗',
+    'error.invalidParameterDefinition': '腳本參數定義無效',
+    'error.invalidDataset': '無效的資料集檔案',
+    'error.startDataRunFailed': '啟動資料驅動執行失敗',
+    'error.getDataRunsFailed': '取得資料驅動執行記錄失敗',
+    'error.dataRunNotFound': '資料驅動執行記錄不存在',
+    'error.exportDataRunFailed': '匯出執行結果失敗',
+    'error.dataRunNotRunning': '資料驅動執行未在執行',
+    'error.dataRunStillRunning': '資料驅動執行仍在執行，請先取消',
+    'error.deleteDataRunFailed': '刪除資料驅動執行記錄失敗',
+    'success.dataRunStarted': '資料驅動執行已開始',
+    'success.dataRunCancelled': '資料驅動執行已取消',
+    'success.dataRunDeleted': '資料驅動執行記錄已刪除',
+    'error.listSecretsFailed': '獲取密鑰列表失敗',
+    'error.secretsDisabled': '密鑰儲存未啟用，請設定 secrets.key 或 BROWSERWING_SECRETS_KEY',
+    'error.invalidSecretName': '密鑰名稱只能包含字母、數字、_、- 和 .',
+    'error.saveSecretFailed': '儲存密鑰失敗',
+    'error.secretNotFound': '密鑰不存在',
+    'error.deleteSecretFailed': '刪除密鑰失敗',
+    'success.secretSaved': '密鑰已儲存',
+    'success.secretDeleted': '密鑰已刪除',
+    'error.invalidOutputSink': '輸出目標設定無效',
+    'error.listDatasetsFailed': '取得資料集列表失敗',
+    'error.datasetNotFound': '資料集不存在',
+    'error.queryDatasetFailed': '查詢資料集失敗',
+    'error.exportDatasetFailed': '匯出資料集失敗',
+    'error.deleteDatasetFailed': '刪除資料集失敗',
+    'success.datasetDeleted': '資料集已刪除',
+    'success.recordingImported': '錄製檔案匯入完成',
     'error.getLLMConfigsFailed': '取得LLM設定失敗',
     'error.llmConfigNotFound': 'LLM設定未找到',
     'error.llmConfigRequiredFields': '名稱、提供商和模型是必填的',
@@ -1343,6 +1448,7 @@ export const translations = {
     'success.scriptUpdated': '腳本已更新',
     'success.scriptDeleted': '腳本已刪除',
     'success.scriptPlaybackCompleted': '腳本播放完成',
+    'success.scriptExecutionCancelled': '已取消腳本執行',
     'success.llmConfigCreated': 'LLM設定已建立',
     'success.llmConfigUpdated': 'LLM設定已更新',
     'success.llmConfigDeleted': 'LLM設定已刪除',
@@ -2080,6 +2186,8 @@ export const translations = {
     'browser.config.proxy': '代理地址',
     'browser.config.proxyPlaceholder': 'http://127.0.0.1:7890 或 socks5://127.0.0.1:1080',
     'browser.config.proxyHint': '支持 HTTP/HTTPS 和 SOCKS5 代理。帶認證：http://user:pass@ip:port 或 socks5://user:pass@ip:port',
+    'browser.instance.maxConcurrentRuns': '最大並發回放數',
+    'browser.instance.maxConcurrentRunsHint': '該實例同時執行的腳本回放數上限，超出時排隊等待；0 表示使用全域設定（預設 3），重新啟動實例後生效',
     'browser.messages.recordingStopped': '錄製已停止',
 
     // 瀏覽器實例管理
@@ -2488,6 +2596,57 @@ export const translations = {
     'error.scriptNotFound': 'Script not found',
     'error.updateScriptFailed': 'Failed to update script',
     'error.playScriptFailed': 'Failed to play script',
+    'error.scriptExecutionCancelled': 'Script execution cancelled',
+    'error.executionNotRunning': 'Execution not found or already finished',
+    'error.executionArtifactNotFound': 'Execution artifact not found',
+    'error.debugSessionNotFound': 'Debug session not found or expired',
+    'error.debugNotPaused': 'Debug session is not paused',
+    'success.debugSessionStarted': 'Debug playback started',
+    'success.debugCommandSent': 'Debug command sent',
+    'success.debugBreakpointsUpdated': 'Breakpoints updated',
+    'error.scriptRevisionNotFound': 'Script revision not found',
+    'error.listScriptRevisionsFailed': 'Failed to list script revisions',
+    'error.restoreScriptRevisionFailed': 'Failed to restore script revision',
+    'success.scriptRevisionRestored': 'Script restored to the selected revision',
+    'error.exportBundleFailed': 'Failed to export script bundle',
+    'error.exportCodeFailed': 'Failed to export source code',
+    'error.importBundleFailed': 'Failed to import script bundle',
+    'error.invalidBundle': 'Invalid script bundle file',
+    'error.unsupportedBundleVersion': 'Unsupported script bundle version',
+    'success.bundleImported': 'Script bundle imported',
+    'error.invalidRecording': 'Invalid recording file',
+    'error.unknownRecordingFormat': 'Unrecognized recording format',
+    'error.importRecordingFailed': 'Failed to import recording',
+    'error.scriptValidationFailed': 'Script validation failed',
+    'error.invalidScriptParams': 'Invalid script parameters',
+    'error.invalidParameterDefinition': 'Invalid script parameter definition',
+    'error.invalidDataset': 'Invalid dataset file',
+    'error.startDataRunFailed': 'Failed to start data run',
+    'error.getDataRunsFailed': 'Failed to get data runs',
+    'error.dataRunNotFound': 'Data run not found',
+    'error.exportDataRunFailed': 'Failed to export data run results',
+    'error.dataRunNotRunning': 'Data run is not running',
+    'error.dataRunStillRunning': 'Data run is still running, cancel it first',
+    'error.deleteDataRunFailed': 'Failed to delete data run',
+    'success.dataRunStarted': 'Data run started',
+    'success.dataRunCancelled': 'Data run cancelled',
+    'success.dataRunDeleted': 'Data run deleted',
+    'error.listSecretsFailed': 'Failed to list secrets',
+    'error.secretsDisabled': 'Secrets store is not enabled, set secrets.key or BROWSERWING_SECRETS_KEY',
+    'error.invalidSecretName': 'Secret names may only contain letters, digits, _, - and .',
+    'error.saveSecretFailed': 'Failed to save secret',
+    'error.secretNotFound': 'Secret not found',
+    'error.deleteSecretFailed': 'Failed to delete secret',
+    'success.secretSaved': 'Secret saved',
+    'success.secretDeleted': 'Secret deleted',
+    'error.invalidOutputSink': 'Invalid output sink',
+    'error.listDatasetsFailed': 'Failed to list datasets',
+    'error.datasetNotFound': 'Dataset not found',
+    'error.queryDatasetFailed': 'Failed to query dataset',
+    'error.exportDatasetFailed': 'Failed to export dataset',
+    'error.deleteDatasetFailed': 'Failed to delete dataset',
+    'success.datasetDeleted': 'Dataset deleted',
+    'success.recordingImported': 'Recording imported',
     'error.getLLMConfigsFailed': 'Failed to get LLM configs',
     'error.llmConfigNotFound': 'LLM config not found',
     'error.llmConfigRequiredFields': 'Name, provider, and model are required',
@@ -2575,6 +2734,7 @@ export const translations = {
     'success.scriptUpdated': 'Script updated',
     'success.scriptDeleted': 'Script deleted',
     'success.scriptPlaybackCompleted': 'Script playback completed',
+    'success.scriptExecutionCancelled': 'Script execution cancelled',
     'success.llmConfigCreated': 'LLM config created',
     'success.llmConfigUpdated': 'LLM config updated',
     'success.llmConfigDeleted': 'LLM config deleted',
@@ -3354,6 +3514,8 @@ export const translations = {
     'browser.config.proxy': 'Proxy Address',
     'browser.config.proxyPlaceholder': 'http://127.0.0.1:7890 or socks5://127.0.0.1:1080',
     'browser.config.proxyHint': 'Supports HTTP/HTTPS and SOCKS5 proxies. With auth: http://user:pass@ip:port or socks5://user:pass@ip:port',
+    'browser.instance.maxConcurrentRuns': 'Max concurrent playbacks',
+    'browser.instance.maxConcurrentRunsHint': 'Maximum number of script playbacks running on this instance at once; extra runs wait in a queue. 0 uses the global setting (default 3). Takes effect after restarting the instance',
     // Browser Instance Management
     'browser.instance.title': 'Browser Instance Management',
     'browser.instance.manage': 'Manage Instances',
@@ -3691,6 +3853,57 @@ export const translations = {
     'error.scriptNotFound': 'Script no encontrado',
     'error.updateScriptFailed': 'Error al actualizar el script',
     'error.playScriptFailed': 'Error al reproducir el script',
+    'error.scriptExecutionCancelled': 'Ejecución del script cancelada',
+    'error.executionNotRunning': 'La ejecución no existe o ya ha finalizado',
+    'error.executionArtifactNotFound': 'Archivo de ejecución no encontrado',
+    'error.debugSessionNotFound': 'La sesión de depuración no existe o ha caducado',
+    'error.debugNotPaused': 'La sesión de depuración no está en pausa',
+    'success.debugSessionStarted': 'Reproducción de depuración iniciada',
+    'success.debugCommandSent': 'Comando de depuración enviado',
+    'success.debugBreakpointsUpdated': 'Puntos de interrupción actualizados',
+    'error.scriptRevisionNotFound': 'Revisión del script no encontrada',
+    'error.listScriptRevisionsFailed': 'Error al listar las revisiones del script',
+    'error.restoreScriptRevisionFailed': 'Error al restaurar la revisión del script',
+    'success.scriptRevisionRestored': 'Script restaurado a la revisión seleccionada',
+    'error.exportBundleFailed': 'Error al exportar el paquete de scripts',
+    'error.exportCodeFailed': 'Error al exportar el código fuente',
+    'error.importBundleFailed': 'Error al importar el paquete de scripts',
+    'error.invalidBundle': 'Archivo de paquete de scripts no válido',
+    'error.unsupportedBundleVersion': 'Versión de paquete de scripts no compatible',
+    'success.bundleImported': 'Paquete de scripts importado',
+    'error.invalidRecording': 'Archivo de grabación no válido',
+    'error.unknownRecordingFormat': 'Formato de grabación no reconocido',
+    'error.importRecordingFailed': 'Error al importar la grabación',
+    'error.scriptValidationFailed': 'La validación del script falló',
+    'error.invalidScriptParams': 'Parámetros de script no válidos',
+    'error.invalidParameterDefinition': 'Definición de parámetro de script no válida',
+    'error.invalidDataset': 'Archivo de conjunto de datos no válido',
+    'error.startDataRunFailed': 'Error al iniciar la ejecución por datos',
+    'error.getDataRunsFailed': 'Error al obtener las ejecuciones por datos',
+    'error.dataRunNotFound': 'Ejecución por datos no encontrada',
+    'error.exportDataRunFailed': 'Error al exportar los resultados',
+    'error.dataRunNotRunning': 'La ejecución por datos no está en curso',
+    'error.dataRunStillRunning': 'La ejecución sigue en curso, cancélela primero',
+    'error.deleteDataRunFailed': 'Error al eliminar la ejecución por datos',
+    'success.dataRunStarted': 'Ejecución por datos iniciada',
+    'success.dataRunCancelled': 'Ejecución por datos cancelada',
+    'success.dataRunDeleted': 'Ejecución por datos eliminada',
+    'error.listSecretsFailed': 'Error al listar los secretos',
+    'error.secretsDisabled': 'El almacén de secretos no está habilitado, configure secrets.key o BROWSERWING_SECRETS_KEY',
+    'error.invalidSecretName': 'Los nombres de secretos solo pueden contener letras, dígitos, _, - y .',
+    'error.saveSecretFailed': 'Error al guardar el secreto',
+    'error.secretNotFound': 'Secreto no encontrado',
+    'error.deleteSecretFailed': 'Error al eliminar el secreto',
+    'success.secretSaved': 'Secreto guardado',
+    'success.secretDeleted': 'Secreto eliminado',
+    'error.invalidOutputSink': 'Destino de salida no válido',
+    'error.listDatasetsFailed': 'Error al listar los conjuntos de datos',
+    'error.datasetNotFound': 'Conjunto de datos no encontrado',
+    'error.queryDatasetFailed': 'Error al consultar el conjunto de datos',
+    'error.exportDatasetFailed': 'Error al exportar el conjunto de datos',
+    'error.deleteDatasetFailed': 'Error al eliminar el conjunto de datos',
+    'success.datasetDeleted': 'Conjunto de datos eliminado',
+    'success.recordingImported': 'Grabación importada',
     'error.getLLMConfigsFailed': 'Error al obtener configuraciones LLM',
     'error.llmConfigNotFound': 'Configuración LLM no encontrada',
     'error.llmConfigRequiredFields': 'Nombre, proveedor y modelo son obligatorios',
@@ -3773,6 +3986,7 @@ export const translations = {
     'success.scriptUpdated': 'Script actualizado',
     'success.scriptDeleted': 'Script eliminado',
     'success.scriptPlaybackCompleted': 'Reproducción de script completada',
+    'success.scriptExecutionCancelled': 'Ejecución del script cancelada',
     'success.llmConfigCreated': 'Configuración LLM creada',
     'success.llmConfigUpdated': 'Configuración LLM actualizada',
     'success.llmConfigDeleted': 'Configuración LLM eliminada',
@@ -4525,6 +4739,8 @@ export const translations = {
     'browser.config.proxy': 'Dirección de Proxy (HTTP/HTTPS/SOCKS5)',
     'browser.config.proxyPlaceholder': 'http://127.0.0.1:7890 o socks5://127.0.0.1:1080',
     'browser.config.proxyHint': 'Soporta proxies HTTP/HTTPS y SOCKS5. Con autenticación: http://user:pass@ip:port o socks5://user:pass@ip:port',
+    'browser.instance.maxConcurrentRuns': 'Máximo de reproducciones simultáneas',
+    'browser.instance.maxConcurrentRunsHint': 'Número máximo de reproducciones de scripts simultáneas en esta instancia; las demás esperan en cola. 0 usa la configuración global (3 por defecto). Se aplica al reiniciar la instancia',
     'browser.messages.recordingStopped': 'La grabación se detuvo automáticamente debido a la detención del navegador',
 
     // Gestión de Instancias del Navegador
@@ -4910,6 +5126,57 @@ export const translations = {
     'error.scriptNotFound': 'スクリプトが見つかりません',
     'error.updateScriptFailed': 'スクリプトの更新に失敗しました',
     'error.playScriptFailed': 'スクリプトの再生に失敗しました',
+    'error.scriptExecutionCancelled': 'スクリプトの実行がキャンセルされました',
+    'error.executionNotRunning': '実行が見つからないか、既に終了しています',
+    'error.executionArtifactNotFound': '実行アーティファクトが見つかりません',
+    'error.debugSessionNotFound': 'デバッグセッションが存在しないか期限切れです',
+    'error.debugNotPaused': 'デバッグセッションは一時停止していません',
+    'success.debugSessionStarted': 'デバッグ再生を開始しました',
+    'success.debugCommandSent': 'デバッグコマンドを送信しました',
+    'success.debugBreakpointsUpdated': 'ブレークポイントを更新しました',
+    'error.scriptRevisionNotFound': 'スクリプトのリビジョンが見つかりません',
+    'error.listScriptRevisionsFailed': 'スクリプトのリビジョン一覧の取得に失敗しました',
+    'error.restoreScriptRevisionFailed': 'スクリプトのリビジョンの復元に失敗しました',
+    'success.scriptRevisionRestored': 'スクリプトを指定したリビジョンに復元しました',
+    'error.exportBundleFailed': 'スクリプトパッケージのエクスポートに失敗しました',
+    'error.exportCodeFailed': 'ソースコードのエクスポートに失敗しました',
+    'error.importBundleFailed': 'スクリプトパッケージのインポートに失敗しました',
+    'error.invalidBundle': '無効なスクリプトパッケージファイルです',
+    'error.unsupportedBundleVersion': 'サポートされていないスクリプトパッケージのバージョンです',
+    'success.bundleImported': 'スクリプトパッケージをインポートしました',
+    'error.invalidRecording': '無効な録画ファイルです',
+    'error.unknownRecordingFormat': '認識できない録画形式です',
+    'error.importRecordingFailed': '録画のインポートに失敗しました',
+    'error.scriptValidationFailed': 'スクリプトの検証に失敗しました',
+    'error.invalidScriptParams': 'スクリプトパラメータが無効です',
+    'error.invalidParameterDefinition': 'スクリプトパラメータの定義が無効です',
+    'error.invalidDataset': '無効なデータセットファイルです',
+    'error.startDataRunFailed': 'データ駆動実行の開始に失敗しました',
+    'error.getDataRunsFailed': 'データ駆動実行の取得に失敗しました',
+    'error.dataRunNotFound': 'データ駆動実行が見つかりません',
+    'error.exportDataRunFailed': '実行結果のエクスポートに失敗しました',
+    'error.dataRunNotRunning': 'データ駆動実行は実行中ではありません',
+    'error.dataRunStillRunning': 'データ駆動実行はまだ実行中です。先にキャンセルしてください',
+    'error.deleteDataRunFailed': 'データ駆動実行の削除に失敗しました',
+    'success.dataRunStarted': 'データ駆動実行を開始しました',
+    'success.dataRunCancelled': 'データ駆動実行をキャンセルしました',
+    'success.dataRunDeleted': 'データ駆動実行を削除しました',
+    'error.listSecretsFailed': 'シークレット一覧の取得に失敗しました',
+    'error.secretsDisabled': 'シークレットストアが無効です。secrets.key または BROWSERWING_SECRETS_KEY を設定してください',
+    'error.invalidSecretName': 'シークレット名には英数字、_、-、. のみ使用できます',
+    'error.saveSecretFailed': 'シークレットの保存に失敗しました',
+    'error.secretNotFound': 'シークレットが見つかりません',
+    'error.deleteSecretFailed': 'シークレットの削除に失敗しました',
+    'success.secretSaved': 'シークレットを保存しました',
+    'success.secretDeleted': 'シークレットを削除しました',
+    'error.invalidOutputSink': '出力先の設定が無効です',
+    'error.listDatasetsFailed': 'データセット一覧の取得に失敗しました',
+    'error.datasetNotFound': 'データセットが見つかりません',
+    'error.queryDatasetFailed': 'データセットの検索に失敗しました',
+    'error.exportDatasetFailed': 'データセットのエクスポートに失敗しました',
+    'error.deleteDatasetFailed': 'データセットの削除に失敗しました',
+    'success.datasetDeleted': 'データセットを削除しました',
+    'success.recordingImported': '録画をインポートしました',
     'error.getLLMConfigsFailed': 'LLM設定の取得に失敗しました',
     'error.llmConfigNotFound': 'LLM設定が見つかりません',
     'error.llmConfigRequiredFields': '名前、プロバイダー、モデルは必須です',
@@ -4992,6 +5259,7 @@ export const translations = {
     'success.scriptUpdated': 'スクリプトが更新されました',
     'success.scriptDeleted': 'スクリプトが削除されました',
     'success.scriptPlaybackCompleted': 'スクリプトの再生が完了しました',
+    'success.scriptExecutionCancelled': 'スクリプトの実行をキャンセルしました',
     'success.llmConfigCreated': 'LLM設定が作成されました',
     'success.llmConfigUpdated': 'LLM設定が更新されました',
     'success.llmConfigDeleted': 'LLM設定が削除されました',
@@ -5743,6 +6011,8 @@ export const translations = {
     'browser.config.proxy': 'プロキシアドレス',
     'browser.config.proxyPlaceholder': 'http://127.0.0.1:7890 または socks5://127.0.0.1:1080',
     'browser.config.proxyHint': 'HTTP/HTTPS および SOCKS5 プロキシをサポート。認証付き: http://user:pass@ip:port または socks5://user:pass@ip:port',
+    'browser.instance.maxConcurrentRuns': '最大同時再生数',
+    'browser.instance.maxConcurrentRunsHint': 'このインスタンスで同時に実行するスクリプト再生の上限。超えた分はキューで待機します。0 はグローバル設定（既定 3）を使用。インスタンスの再起動後に反映されます',
     'browser.messages.recordingStopped': '録画が手動で停止されました',
 
     // ブラウザインスタンス管理
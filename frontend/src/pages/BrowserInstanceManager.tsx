@@ -34,6 +34,7 @@ export default function BrowserInstanceManager() {
     headless: null as boolean | null,
     launch_args: [] as string[],
     proxy: '',
+    max_concurrent_runs: 0,
     is_default: false,
   })
 
@@ -184,6 +185,7 @@ export default function BrowserInstanceManager() {
       headless: null,
       launch_args: [],
       proxy: '',
+      max_concurrent_runs: 0,
       is_default: false,
     })
     setShowModal(true)
@@ -203,6 +205,7 @@ export default function BrowserInstanceManager() {
       headless: instance.headless ?? null,
       launch_args: instance.launch_args || [],
       proxy: instance.proxy || '',
+      max_concurrent_runs: instance.max_concurrent_runs || 0,
       is_default: instance.is_default,
     })
     setShowModal(true)
@@ -518,6 +521,23 @@ export default function BrowserInstanceManager() {
                   </p>
                 </div>
 
+                {/* Max concurrent runs */}
+                <div className="mb-4">
+                  <label className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">
+                    {t('browser.instance.maxConcurrentRuns')}
+                  </label>
+                  <input
+                    type="number"
+                    min={0}
+                    value={instanceForm.max_concurrent_runs}
+                    onChange={(e) => setInstanceForm({ ...instanceForm, max_concurrent_runs: Math.max(0, parseInt(e.target.value, 10) || 0) })}
+                    className="input w-full text-sm"
+                  />
+                  <p className="text-xs text-gray-500 dark:text-gray-400 mt-1">
+                    {t('browser.instance.maxConcurrentRunsHint')}
+                  </p>
+                </div>
+
                 {/* Headless */}
                 <div className="mb-4">
                   <label className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">